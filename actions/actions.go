@@ -0,0 +1,339 @@
+/*
+Package actions maps named actions, such as "jump" or "fire", to the keys,
+mouse buttons and gamepad controls that trigger them, so games ask for
+actions rather than for devices and players can rebind their controls:
+
+    actions.Bind("jump", actions.Key(glfw.KeySpace), actions.PadButton(0))
+    actions.Bind("left", actions.Key(glfw.KeyA), actions.PadAxis(0, true))
+    actions.Bind("right", actions.Key(glfw.KeyD), actions.PadAxis(0, false))
+    // the bindings saved by the player replace the defaults
+    actions.Load(settingsPath)
+
+    for app.Tick() {
+        if actions.Pressed("jump") {
+            player.Jump()
+        }
+        player.Walk(actions.Axis("left", "right"))
+    }
+
+Bindings are stored as JSON objects from action names to lists of inputs:
+
+    {
+        "jump": ["key:Space", "button:0"],
+        "left": ["key:A", "axis:0-"]
+    }
+
+The input state is that of the gome main window and of the gamepads polled
+by gome.App.Tick.
+*/
+package actions
+
+import (
+    "encoding/json"
+    "github.com/snorredc/gome"
+    "io"
+    "os"
+    "sort"
+)
+
+// Map is a set of bindings from actions to inputs.
+type Map struct {
+    Gamepad   int     // the gamepad read by the map
+    Deadzone  float32 // axis positions up to Deadzone count as 0
+    Threshold float32 // an action with a value of at least Threshold is down
+
+    bindings map[string][]Input
+    axes     map[Input]*axisState
+}
+
+// The down state of half an axis, sampled once per frame so it has edges
+// like a button.
+type axisState struct {
+    frame uint64
+    down  bool
+    prev  bool
+}
+
+// NewMap returns an empty Map reading gamepad 0.
+func NewMap() *Map {
+    return &Map{
+        Deadzone:  0.25,
+        Threshold: 0.5,
+        bindings:  map[string][]Input{},
+        axes:      map[Input]*axisState{},
+    }
+}
+
+// Bind adds inputs to the bindings of action.
+func (m *Map) Bind(action string, inputs ...Input) {
+    have := m.bindings[action]
+    for _, in := range inputs {
+        if !contains(have, in) {
+            have = append(have, in)
+        }
+    }
+    m.bindings[action] = have
+}
+
+// Set replaces the bindings of action by inputs.
+func (m *Map) Set(action string, inputs ...Input) {
+    m.bindings[action] = nil
+    m.Bind(action, inputs...)
+}
+
+// Unbind removes inputs from the bindings of action, or all of its bindings
+// if no inputs are given.
+func (m *Map) Unbind(action string, inputs ...Input) {
+    if len(inputs) == 0 {
+        delete(m.bindings, action)
+        return
+    }
+    var keep []Input
+    for _, in := range m.bindings[action] {
+        if !contains(inputs, in) {
+            keep = append(keep, in)
+        }
+    }
+    m.bindings[action] = keep
+}
+
+// Bindings returns the inputs bound to action.
+func (m *Map) Bindings(action string) []Input {
+    return append([]Input(nil), m.bindings[action]...)
+}
+
+// Actions returns the names of the actions of the map, sorted.
+func (m *Map) Actions() []string {
+    names := make([]string, 0, len(m.bindings))
+    for name := range m.bindings {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+func contains(inputs []Input, in Input) bool {
+    for _, have := range inputs {
+        if have == in {
+            return true
+        }
+    }
+    return false
+}
+
+// Value returns how far action is actuated, between 0 and 1: 1 while a key
+// or button bound to it is held down, or the position of a bound axis.
+func (m *Map) Value(action string) float32 {
+    v := float32(0)
+    for _, in := range m.bindings[action] {
+        if iv := in.value(m.Gamepad, m.Deadzone); iv > v {
+            v = iv
+        }
+    }
+    return v
+}
+
+// Axis returns Value(pos) - Value(neg), e.g. Axis("left", "right") for
+// walking with either the arrow keys or a stick.
+func (m *Map) Axis(neg, pos string) float32 {
+    return m.Value(pos) - m.Value(neg)
+}
+
+// Down reports whether action is held down.
+func (m *Map) Down(action string) bool {
+    for _, in := range m.bindings[action] {
+        if m.down(in) {
+            return true
+        }
+    }
+    return false
+}
+
+// Pressed reports whether action started during the last Tick: one of its
+// inputs was pressed while no other was already held.
+func (m *Map) Pressed(action string) bool {
+    pressed := false
+    for _, in := range m.bindings[action] {
+        switch {
+        case m.pressed(in):
+            pressed = true
+        case m.down(in):
+            return false
+        }
+    }
+    return pressed
+}
+
+// Released reports whether action ended during the last Tick: one of its
+// inputs was released and none is held any more.
+func (m *Map) Released(action string) bool {
+    released := false
+    for _, in := range m.bindings[action] {
+        if m.down(in) {
+            return false
+        }
+        if m.released(in) {
+            released = true
+        }
+    }
+    return released
+}
+
+func (m *Map) down(in Input) bool {
+    if in.Kind == AxisInput {
+        return m.axis(in).down
+    }
+    return in.value(m.Gamepad, m.Deadzone) > 0
+}
+
+func (m *Map) pressed(in Input) bool {
+    if in.Kind == AxisInput {
+        s := m.axis(in)
+        return s.down && !s.prev
+    }
+    return in.pressed(m.Gamepad)
+}
+
+func (m *Map) released(in Input) bool {
+    if in.Kind == AxisInput {
+        s := m.axis(in)
+        return !s.down && s.prev
+    }
+    return in.released(m.Gamepad)
+}
+
+// axis returns the state of half an axis in the current frame. The previous
+// state is only known if the axis was also looked at in the previous frame,
+// which it is when an action is checked every frame; otherwise there is no
+// edge.
+func (m *Map) axis(in Input) *axisState {
+    s := m.axes[in]
+    if s == nil {
+        s = &axisState{}
+        m.axes[in] = s
+    }
+    frame := gome.FrameCount() + 1
+    if s.frame != frame {
+        down := in.value(m.Gamepad, m.Deadzone) >= m.Threshold
+        if s.frame == frame-1 {
+            s.prev = s.down
+        } else {
+            s.prev = down
+        }
+        s.down = down
+        s.frame = frame
+    }
+    return s
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m *Map) MarshalJSON() ([]byte, error) {
+    return json.Marshal(m.bindings)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The bindings of the actions in
+// data replace the ones in m; other actions keep theirs, so defaults set
+// before loading the bindings of the player stay for actions the player
+// never rebound.
+func (m *Map) UnmarshalJSON(data []byte) error {
+    var bindings map[string][]Input
+    if err := json.Unmarshal(data, &bindings); err != nil {
+        return err
+    }
+    if m.bindings == nil {
+        m.bindings = map[string][]Input{}
+    }
+    if m.axes == nil {
+        m.axes = map[Input]*axisState{}
+    }
+    for action, inputs := range bindings {
+        m.Set(action, inputs...)
+    }
+    return nil
+}
+
+// Decode reads bindings in JSON from r into m (see UnmarshalJSON).
+func (m *Map) Decode(r io.Reader) error {
+    return json.NewDecoder(r).Decode(m)
+}
+
+// Encode writes the bindings of m to w as indented JSON.
+func (m *Map) Encode(w io.Writer) error {
+    data, err := json.MarshalIndent(m, "", "    ")
+    if err != nil {
+        return err
+    }
+    _, err = w.Write(append(data, '\n'))
+    return err
+}
+
+// Load reads the bindings in the file at path into m. Bindings are settings
+// of the player rather than assets, so path is a file of the operating
+// system; a missing file is not an error and leaves m unchanged.
+func (m *Map) Load(path string) error {
+    f, err := os.Open(path)
+    if os.IsNotExist(err) {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+    return m.Decode(f)
+}
+
+// Save writes the bindings of m as JSON to the file at path.
+func (m *Map) Save(path string) error {
+    f, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    if err := m.Encode(f); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
+
+// Default is the Map used by the functions of the package.
+var Default = NewMap()
+
+// Bind adds inputs to the bindings of action in Default.
+func Bind(action string, inputs ...Input) {
+    Default.Bind(action, inputs...)
+}
+
+// Down reports whether action is held down in Default.
+func Down(action string) bool {
+    return Default.Down(action)
+}
+
+// Pressed reports whether action started during the last Tick in Default.
+func Pressed(action string) bool {
+    return Default.Pressed(action)
+}
+
+// Released reports whether action ended during the last Tick in Default.
+func Released(action string) bool {
+    return Default.Released(action)
+}
+
+// Value returns how far action is actuated in Default, between 0 and 1.
+func Value(action string) float32 {
+    return Default.Value(action)
+}
+
+// Axis returns Value(pos) - Value(neg) in Default.
+func Axis(neg, pos string) float32 {
+    return Default.Axis(neg, pos)
+}
+
+// Load reads the bindings in the file at path into Default.
+func Load(path string) error {
+    return Default.Load(path)
+}
+
+// Save writes the bindings of Default to the file at path.
+func Save(path string) error {
+    return Default.Save(path)
+}
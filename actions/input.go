@@ -0,0 +1,187 @@
+package actions
+
+import (
+    "fmt"
+    "github.com/snorredc/gome"
+    "github.com/snorredc/gome/internal/glfw"
+    "strconv"
+    "strings"
+)
+
+// InputKind is the kind of device control of an Input.
+type InputKind int
+
+const (
+    KeyInput    InputKind = iota // a key of the keyboard
+    MouseInput                   // a mouse button
+    ButtonInput                  // a gamepad button
+    AxisInput                    // one direction of a gamepad axis
+)
+
+// Input is a key, mouse button or gamepad control that an action is bound
+// to. Its text form, used in JSON, is "key:Space", "mouse:Left", "button:0"
+// or "axis:1+"/"axis:1-" for the positive or negative half of an axis.
+type Input struct {
+    Kind InputKind
+    Code int  // the glfw.Key, glfw.MouseButton, button or axis number
+    Neg  bool // for AxisInput, whether the negative half of the axis is meant
+}
+
+// Key returns the Input for a key.
+func Key(k glfw.Key) Input {
+    return Input{Kind: KeyInput, Code: int(k)}
+}
+
+// Mouse returns the Input for a mouse button.
+func Mouse(b glfw.MouseButton) Input {
+    return Input{Kind: MouseInput, Code: int(b)}
+}
+
+// PadButton returns the Input for a gamepad button.
+func PadButton(button int) Input {
+    return Input{Kind: ButtonInput, Code: button}
+}
+
+// PadAxis returns the Input for the positive half of a gamepad axis, or for its
+// negative half if neg is true.
+func PadAxis(axis int, neg bool) Input {
+    return Input{Kind: AxisInput, Code: axis, Neg: neg}
+}
+
+// String returns the text form of in.
+func (in Input) String() string {
+    switch in.Kind {
+    case KeyInput:
+        if name, ok := keyNames[glfw.Key(in.Code)]; ok {
+            return "key:" + name
+        }
+        return "key:#" + strconv.Itoa(in.Code)
+    case MouseInput:
+        if name, ok := mouseNames[glfw.MouseButton(in.Code)]; ok {
+            return "mouse:" + name
+        }
+        return "mouse:#" + strconv.Itoa(in.Code)
+    case ButtonInput:
+        return "button:" + strconv.Itoa(in.Code)
+    case AxisInput:
+        if in.Neg {
+            return "axis:" + strconv.Itoa(in.Code) + "-"
+        }
+        return "axis:" + strconv.Itoa(in.Code) + "+"
+    }
+    return "invalid"
+}
+
+// ParseInput parses the text form of an Input.
+func ParseInput(s string) (Input, error) {
+    i := strings.IndexByte(s, ':')
+    if i < 0 || i == len(s)-1 {
+        return Input{}, fmt.Errorf("actions: invalid input %q", s)
+    }
+    kind, name := s[:i], s[i+1:]
+    // "#n" is a raw code without a name
+    code := func() (int, bool) {
+        if !strings.HasPrefix(name, "#") {
+            return 0, false
+        }
+        n, err := strconv.Atoi(name[1:])
+        return n, err == nil
+    }
+    switch kind {
+    case "key":
+        if k, ok := keysByName[name]; ok {
+            return Key(k), nil
+        }
+        if n, ok := code(); ok {
+            return Input{Kind: KeyInput, Code: n}, nil
+        }
+    case "mouse":
+        if b, ok := mouseByName[name]; ok {
+            return Mouse(b), nil
+        }
+        if n, ok := code(); ok {
+            return Input{Kind: MouseInput, Code: n}, nil
+        }
+    case "button":
+        if n, err := strconv.Atoi(name); err == nil && n >= 0 {
+            return PadButton(n), nil
+        }
+    case "axis":
+        sign := name[len(name)-1]
+        if sign == '+' || sign == '-' {
+            if n, err := strconv.Atoi(name[:len(name)-1]); err == nil && n >= 0 {
+                return PadAxis(n, sign == '-'), nil
+            }
+        }
+    }
+    return Input{}, fmt.Errorf("actions: invalid input %q", s)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (in Input) MarshalText() ([]byte, error) {
+    return []byte(in.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (in *Input) UnmarshalText(text []byte) error {
+    parsed, err := ParseInput(string(text))
+    if err != nil {
+        return err
+    }
+    *in = parsed
+    return nil
+}
+
+// value returns how far in is actuated on gamepad pad, between 0 and 1.
+func (in Input) value(pad int, deadzone float32) float32 {
+    down := false
+    switch in.Kind {
+    case KeyInput:
+        down = gome.KeyDown(glfw.Key(in.Code))
+    case MouseInput:
+        down = gome.MouseButtonDown(glfw.MouseButton(in.Code))
+    case ButtonInput:
+        down = gome.GamepadButtonDown(pad, in.Code)
+    case AxisInput:
+        v := gome.GamepadAxis(pad, in.Code)
+        if in.Neg {
+            v = -v
+        }
+        if v <= deadzone {
+            return 0
+        }
+        // rescale so the value starts at 0 at the edge of the dead zone
+        return (v - deadzone) / (1 - deadzone)
+    }
+    if down {
+        return 1
+    }
+    return 0
+}
+
+// pressed reports whether a key or button was pressed during the last Tick.
+func (in Input) pressed(pad int) bool {
+    switch in.Kind {
+    case KeyInput:
+        return gome.KeyPressed(glfw.Key(in.Code))
+    case MouseInput:
+        return gome.MouseButtonPressed(glfw.MouseButton(in.Code))
+    case ButtonInput:
+        return gome.GamepadButtonPressed(pad, in.Code)
+    }
+    return false
+}
+
+// released reports whether a key or button was released during the last
+// Tick.
+func (in Input) released(pad int) bool {
+    switch in.Kind {
+    case KeyInput:
+        return gome.KeyReleased(glfw.Key(in.Code))
+    case MouseInput:
+        return gome.MouseButtonReleased(glfw.MouseButton(in.Code))
+    case ButtonInput:
+        return gome.GamepadButtonReleased(pad, in.Code)
+    }
+    return false
+}
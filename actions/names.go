@@ -0,0 +1,93 @@
+package actions
+
+import (
+    "github.com/snorredc/gome/internal/glfw"
+    "strconv"
+)
+
+// Names of the keys in bindings; letters, digits and function keys are
+// added by init.
+var keyNames = map[glfw.Key]string{
+    glfw.KeySpace:        "Space",
+    glfw.KeyApostrophe:   "Apostrophe",
+    glfw.KeyComma:        "Comma",
+    glfw.KeyMinus:        "Minus",
+    glfw.KeyPeriod:       "Period",
+    glfw.KeySlash:        "Slash",
+    glfw.KeySemicolon:    "Semicolon",
+    glfw.KeyEqual:        "Equal",
+    glfw.KeyLeftBracket:  "LeftBracket",
+    glfw.KeyBackslash:    "Backslash",
+    glfw.KeyRightBracket: "RightBracket",
+    glfw.KeyGraveAccent:  "GraveAccent",
+    glfw.KeyEscape:       "Escape",
+    glfw.KeyEnter:        "Enter",
+    glfw.KeyTab:          "Tab",
+    glfw.KeyBackspace:    "Backspace",
+    glfw.KeyInsert:       "Insert",
+    glfw.KeyDelete:       "Delete",
+    glfw.KeyRight:        "Right",
+    glfw.KeyLeft:         "Left",
+    glfw.KeyDown:         "Down",
+    glfw.KeyUp:           "Up",
+    glfw.KeyPageUp:       "PageUp",
+    glfw.KeyPageDown:     "PageDown",
+    glfw.KeyHome:         "Home",
+    glfw.KeyEnd:          "End",
+    glfw.KeyCapsLock:     "CapsLock",
+    glfw.KeyScrollLock:   "ScrollLock",
+    glfw.KeyNumLock:      "NumLock",
+    glfw.KeyPrintScreen:  "PrintScreen",
+    glfw.KeyPause:        "Pause",
+    glfw.KeyKPDecimal:    "KpDecimal",
+    glfw.KeyKPDivide:     "KpDivide",
+    glfw.KeyKPMultiply:   "KpMultiply",
+    glfw.KeyKPSubtract:   "KpSubtract",
+    glfw.KeyKPAdd:        "KpAdd",
+    glfw.KeyKPEnter:      "KpEnter",
+    glfw.KeyKPEqual:      "KpEqual",
+    glfw.KeyLeftShift:    "LeftShift",
+    glfw.KeyLeftControl:  "LeftControl",
+    glfw.KeyLeftAlt:      "LeftAlt",
+    glfw.KeyLeftSuper:    "LeftSuper",
+    glfw.KeyRightShift:   "RightShift",
+    glfw.KeyRightControl: "RightControl",
+    glfw.KeyRightAlt:     "RightAlt",
+    glfw.KeyRightSuper:   "RightSuper",
+    glfw.KeyMenu:         "Menu",
+}
+
+var mouseNames = map[glfw.MouseButton]string{
+    glfw.MouseButtonLeft:   "Left",
+    glfw.MouseButtonRight:  "Right",
+    glfw.MouseButtonMiddle: "Middle",
+}
+
+var (
+    keysByName  = map[string]glfw.Key{}
+    mouseByName = map[string]glfw.MouseButton{}
+)
+
+func init() {
+    // the key codes of letters, digits, function keys and the keypad digits
+    // are contiguous
+    for i := 0; i < 26; i++ {
+        keyNames[glfw.KeyA+glfw.Key(i)] = string(rune('A' + i))
+    }
+    for i := 0; i < 10; i++ {
+        keyNames[glfw.Key0+glfw.Key(i)] = strconv.Itoa(i)
+        keyNames[glfw.KeyKP0+glfw.Key(i)] = "Kp" + strconv.Itoa(i)
+    }
+    for i := 0; i < 25; i++ {
+        keyNames[glfw.KeyF1+glfw.Key(i)] = "F" + strconv.Itoa(i+1)
+    }
+    for k, name := range keyNames {
+        keysByName[name] = k
+    }
+    for i := glfw.MouseButtonMiddle + 1; i <= glfw.MouseButtonLast; i++ {
+        mouseNames[i] = strconv.Itoa(int(i) + 1)
+    }
+    for b, name := range mouseNames {
+        mouseByName[name] = b
+    }
+}
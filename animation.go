@@ -0,0 +1,205 @@
+package gome
+
+import (
+    "fmt"
+    "image/color"
+    "time"
+)
+
+// LoopMode determines what an animation does after its last frame.
+type LoopMode int
+
+const (
+    LoopRepeat   LoopMode = iota // start again from the first frame
+    LoopOnce                     // stop at the last frame
+    LoopPingPong                 // play backwards to the first frame, and so on
+)
+
+// AnimationFrame is a frame of an Animation.
+type AnimationFrame struct {
+    Region   Region
+    Duration time.Duration // must be positive
+    Event    string        // if not empty, passed to Animator.OnEvent when the frame is shown
+}
+
+// Animation is a sequence of sprite frames. It holds no playback state, so
+// one animation can be shared by many animators.
+type Animation struct {
+    Name   string
+    Frames []AnimationFrame
+    Mode   LoopMode
+}
+
+// NewAnimation creates an animation showing every region for the same
+// duration.
+func NewAnimation(name string, regions []Region, frameDuration time.Duration, mode LoopMode) *Animation {
+    a := &Animation{Name: name, Mode: mode}
+    for _, r := range regions {
+        a.Frames = append(a.Frames, AnimationFrame{Region: r, Duration: frameDuration})
+    }
+    return a
+}
+
+// Duration returns the total duration of one pass through the frames.
+func (a *Animation) Duration() time.Duration {
+    var d time.Duration
+    for _, f := range a.Frames {
+        d += f.Duration
+    }
+    return d
+}
+
+// Animation creates an animation from the frames of the tag with the given
+// name, using the frame durations and the direction exported by Aseprite.
+// Frames without a duration are shown for 100 ms, Aseprite's default.
+func (s *SpriteSheet) Animation(tag string, mode LoopMode) (*Animation, error) {
+    t, ok := s.Tag(tag)
+    if !ok {
+        return nil, fmt.Errorf("sprite sheet has no tag %q", tag)
+    }
+    a := &Animation{Name: t.Name, Mode: mode}
+    for i := t.From; i <= t.To; i++ {
+        f := s.Frames[i]
+        if f.Duration <= 0 {
+            f.Duration = 100 * time.Millisecond
+        }
+        a.Frames = append(a.Frames, AnimationFrame{Region: f.Region, Duration: f.Duration})
+    }
+    switch t.Direction {
+    case "reverse":
+        for i, j := 0, len(a.Frames)-1; i < j; i, j = i+1, j-1 {
+            a.Frames[i], a.Frames[j] = a.Frames[j], a.Frames[i]
+        }
+    case "pingpong":
+        if mode == LoopRepeat {
+            a.Mode = LoopPingPong
+        }
+    }
+    return a, nil
+}
+
+// Animator plays an animation. It should be advanced with Update from a
+// FixedTimestep, or with the frame time; the time that goes beyond a frame is
+// carried over to the next one, so playback does not drift.
+type Animator struct {
+    Animation *Animation
+    Speed     float64 // playback speed; zero is treated as 1
+    Paused    bool
+
+    OnEvent  func(event string) // called with the events of the frames shown
+    OnFinish func()             // called when a LoopOnce animation ends
+
+    frame    int
+    elapsed  time.Duration
+    reverse  bool
+    started  bool
+    finished bool
+}
+
+// NewAnimator creates an animator playing a from the start.
+func NewAnimator(a *Animation) *Animator {
+    return &Animator{Animation: a}
+}
+
+// Play switches to anim and plays it from the start, unless it is already
+// playing.
+func (a *Animator) Play(anim *Animation) {
+    if anim == a.Animation {
+        return
+    }
+    a.Animation = anim
+    a.Reset()
+}
+
+// Reset rewinds the animation to its first frame.
+func (a *Animator) Reset() {
+    a.frame, a.elapsed = 0, 0
+    a.reverse, a.started, a.finished = false, false, false
+}
+
+// Update advances the animation by dt.
+func (a *Animator) Update(dt time.Duration) {
+    anim := a.Animation
+    if anim == nil || len(anim.Frames) == 0 || a.Paused || a.finished {
+        return
+    }
+    if !a.started {
+        a.started = true
+        a.event()
+    }
+    speed := a.Speed
+    if speed == 0 {
+        speed = 1
+    }
+    a.elapsed += time.Duration(float64(dt) * speed)
+    for !a.finished {
+        d := anim.Frames[a.frame].Duration
+        if d <= 0 || a.elapsed < d {
+            break
+        }
+        a.elapsed -= d
+        a.advance()
+    }
+}
+
+// advance moves to the next frame according to the loop mode.
+func (a *Animator) advance() {
+    anim := a.Animation
+    last := len(anim.Frames) - 1
+    switch {
+    case a.reverse && a.frame > 0:
+        a.frame--
+    case a.reverse:
+        a.reverse = false
+        if last > 0 {
+            a.frame = 1
+        }
+    case a.frame < last:
+        a.frame++
+    case anim.Mode == LoopOnce:
+        a.finished = true
+        a.elapsed = 0
+        if a.OnFinish != nil {
+            a.OnFinish()
+        }
+        return
+    case anim.Mode == LoopPingPong:
+        if last > 0 {
+            a.reverse = true
+            a.frame = last - 1
+        }
+    default:
+        a.frame = 0
+    }
+    a.event()
+}
+
+func (a *Animator) event() {
+    if e := a.Animation.Frames[a.frame].Event; e != "" && a.OnEvent != nil {
+        a.OnEvent(e)
+    }
+}
+
+// Frame returns the index of the current frame.
+func (a *Animator) Frame() int {
+    return a.frame
+}
+
+// Finished reports whether a LoopOnce animation has ended.
+func (a *Animator) Finished() bool {
+    return a.finished
+}
+
+// Region returns the region of the current frame.
+func (a *Animator) Region() Region {
+    return a.Animation.Frames[a.frame].Region
+}
+
+// Draw draws the current frame with the sprite batch b into the rectangle
+// dst, tinted by c (see SpriteBatch.DrawRegion).
+func (a *Animator) Draw(b *SpriteBatch, dst Rect, c color.Color) {
+    if a.Animation == nil || len(a.Animation.Frames) == 0 {
+        return
+    }
+    b.DrawRegion(a.Region(), dst, c)
+}
@@ -0,0 +1,287 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/gl"
+    "github.com/snorredc/gome/internal/glfw"
+    "log/slog"
+    "runtime"
+    "time"
+)
+
+// App is a gome application: the main window with its OpenGL context and the
+// state of the main loop. Since GLFW and OpenGL are used from the main
+// thread of the process, only one App can be initialised at a time; the
+// other functions of gome act on that one.
+//
+// The main loop looks like this:
+//
+//     app := gome.NewApp(gome.Config{Title: "Game"})
+//     if err := app.Init(); err != nil {
+//         // handle error
+//     }
+//     defer app.Terminate()
+//
+//     for app.Tick() {
+//         update()
+//         render()
+//     }
+//     if err := app.GetError(); err != nil {
+//         // handle error
+//     }
+type App struct {
+    Config Config // the settings for Init; changing them later has no effect
+
+    window    *glfw.Window
+    closing   bool  // RequestClose was called
+    tickError error // the error that made Tick return false
+}
+
+// app is the initialised App, or nil.
+var app *App
+
+// NewApp returns an App with the settings of c. The zero Config gives the
+// defaults. Nothing is created until Init is called.
+func NewApp(c Config) *App {
+    return &App{Config: c}
+}
+
+// Init initialises GLFW and OpenGL and creates the main window (see Window).
+// After this has returned OpenGL functions as well as Tick can be used. It
+// also locks the current OS thread (see runtime.LockOSThread). It returns
+// ErrAppRunning if another App has been initialised and not terminated.
+//
+// If Init fails, everything it created is destroyed again. After Terminate,
+// Init can be called again, on this App or another one, e.g. to recreate the
+// window with new display settings; GL objects of the previous context must
+// not be used anymore then.
+func (a *App) Init() error {
+    if app != nil {
+        return ErrAppRunning
+    }
+    c := &a.Config
+    c.setDefaults()
+    runtime.LockOSThread()
+    mainGoroutine = goroutineID()
+    if c.RenderDoc {
+        loadRenderDoc()
+    }
+
+    if err := glfw.Init(); err != nil {
+        return ErrGLFW3Initialize
+    }
+
+    window, err := createWindow(c)
+    if err != nil {
+        glfw.Terminate()
+        return err
+    }
+    window.MakeContextCurrent()
+    a.window = window
+    app = a
+    Window = window
+    installCallbacks(window)
+    cursor.x, cursor.y = window.GetCursorPos()
+    initWindowState(window, c.Title)
+    if c.WindowStateFile != "" {
+        if err := RestoreWindowState(c.WindowStateFile); err != nil {
+            logAt(slog.LevelWarn, "restoring window state", "path", c.WindowStateFile, "err", err)
+        }
+        if !c.Hidden {
+            window.Show()
+        }
+    }
+
+    glfw.SwapInterval(1)
+
+    if err := a.initGL(); err != nil {
+        a.destroy()
+        return err
+    }
+    clearScreen()
+    return nil
+}
+
+// initGL loads the OpenGL functions and queries the context.
+func (a *App) initGL() error {
+    c := &a.Config
+    if err := gl.InitWithProcAddrFunc(glfw.GetProcAddress); err != nil {
+        return ErrGLEWInitialize
+    }
+
+    errcode := gl.GetError()
+    for errcode == gl.INVALID_ENUM {
+        errcode = gl.GetError()
+    }
+    if errcode != 0 {
+        return glError(errcode)
+    }
+    queryGLInfo()
+    // OpenGL ES has neither switch; sRGB conversion and multisampling are
+    // always on there
+    es := obtainedContext.Profile == ESProfile
+    if c.SRGB && !es {
+        Enable(gl.FRAMEBUFFER_SRGB)
+    }
+    var samples [1]int32
+    gl.GetIntegerv(gl.SAMPLES, &samples[0])
+    obtainedSamples = int(samples[0])
+    if obtainedSamples > 0 && !es {
+        Enable(gl.MULTISAMPLE)
+    }
+    logContext()
+    if c.DebugContext {
+        enableDebugOutput()
+    }
+    applyClearColor()
+    return nil
+}
+
+// Window returns the main window, or nil if the App is not initialised.
+func (a *App) Window() *glfw.Window {
+    return a.window
+}
+
+// RequestClose makes the next Tick return false, which should end the main
+// loop. Unlike a close by the user, it cannot be cancelled by the handlers
+// of OnCloseRequest.
+func (a *App) RequestClose() {
+    a.closing = true
+}
+
+// shouldClose reports whether the main loop should end.
+func (a *App) shouldClose() bool {
+    return a.closing || ShouldClose || a.window.ShouldClose()
+}
+
+// GetError returns the error that made Tick return false, if any, or else
+// polls OpenGL for an error and returns that.
+func (a *App) GetError() error {
+    if e := a.tickError; e != nil {
+        a.tickError = nil
+        return e
+    }
+    return pollGLError()
+}
+
+// Tick swaps the buffers of the main window and polls GLFW for events. It
+// returns true if the main loop should continue and false otherwise. It only
+// returns false if RequestClose was called, the window is being closed or if
+// OpenGL reports an error (see GetError). If a virtual resolution is set, it
+// is scaled to the window first (see SetVirtualResolution). The windows of
+// Gui and, if enabled, the debug overlay are drawn on top of the frame
+// before swapping. With SetAutoClear, the screen is cleared for the next
+// frame afterwards.
+func (a *App) Tick() bool {
+    if a == nil || a.window == nil {
+        return false
+    }
+    if err := a.GetError(); err != nil {
+        a.tickError = err
+        return false
+    }
+    if a.shouldClose() {
+        return false
+    }
+    if err := presentVirtual(); err != nil {
+        a.tickError = err
+        return false
+    }
+    if err := Gui.draw(); err != nil {
+        a.tickError = err
+        return false
+    }
+    if err := Overlay.draw(); err != nil {
+        a.tickError = err
+        return false
+    }
+    cpuProf.endFrame()
+    gpuProf.endFrame()
+    a.window.SwapBuffers()
+    state.endFrame()
+    stats.endFrame()
+    textInput.beginFrame()
+    beginCursorFrame()
+    input.beginFrame()
+    pollEvents()
+    input.pollGamepads()
+    replayFrame()
+    recorder.endFrame()
+    deliverDrops()
+    runQueued()
+    updatePause()
+    runTimers()
+    Overlay.update()
+    reloadPrograms()
+    beginVirtualFrame()
+    clearScreen()
+    return true
+}
+
+// Terminate cleans up and terminates GLFW. It should be called after the main
+// loop has finished, e.g. by deferring it in the main function. It calls the
+// handlers of OnTerminate, and if DebugObjects is set, logs any GL objects
+// that were never deleted afterwards. It does nothing if the App is not
+// initialised, so it is safe to call after a failed Init or twice, e.g.
+// after Recover.
+func (a *App) Terminate() {
+    if a == nil || a.window == nil {
+        return
+    }
+    deleteVirtualTarget()
+    for _, f := range terminateHandlers {
+        f()
+    }
+    reportLeaks()
+    if a.Config.WindowStateFile != "" {
+        if err := SaveWindowState(a.Config.WindowStateFile); err != nil {
+            logAt(slog.LevelWarn, "saving window state", "path", a.Config.WindowStateFile, "err", err)
+        }
+    }
+    a.destroy()
+}
+
+// destroy destroys the window, terminates GLFW and resets the state of gome,
+// so that an App can be initialised again.
+func (a *App) destroy() {
+    a.window.Destroy()
+    glfw.Terminate()
+    a.window = nil
+    a.closing, a.tickError = false, nil
+    app = nil
+    Window = nil
+    ShouldClose = false
+    resetState()
+}
+
+// resetState forgets the state tied to the window and the GL context, whose
+// objects died with them. Settings such as the idle behaviour and handlers
+// such as those of OnCloseRequest are kept.
+func resetState() {
+    InvalidateState()
+    state.frame, state.last = StateStats{}, StateStats{}
+    liveObjects = map[objectKey]string{}
+    uniformBindings = nil
+    gpuProf = gpuProfiler{}
+    programWatches = nil
+    defaultFont = nil
+    Overlay.batch, Overlay.font, Overlay.keyDown = nil, nil, false
+    Gui.batch, Gui.font = nil, nil
+    Gui.frameBegun, Gui.current, Gui.hot, Gui.active = false, nil, nil, ""
+    virtual.target, virtual.width, virtual.height = nil, 0, 0
+    virtual.program, virtual.vao = nil, nil
+
+    idle := windowState.idle
+    windowState = windowStatus{idle: idle}
+    cursor = cursorState{}
+    input.reset()
+    textInput.events = nil
+    drops = drops[:0]
+    pause.requested, pause.stepping = 0, false
+    // the first frame of the next App is timed from its first Tick
+    stats.last = time.Time{}
+    scheduler.paused, scheduler.lastPaused = 0, 0
+
+    obtainedContext = ContextVersion{}
+    obtainedSamples = 0
+    glInfo, glExtensions = ContextInfo{}, nil
+}
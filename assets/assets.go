@@ -0,0 +1,248 @@
+/*
+Package assets loads textures, shaders and fonts for gome applications and
+caches them, so that every asset is only loaded once no matter how often it is
+requested.
+
+Assets are read from the mounted roots, which may be directories, zip files or
+any fs.FS. Each request for an asset increments its reference count and each
+call to Release decrements it; Purge deletes all assets that are no longer
+referenced:
+
+    if err := assets.Mount("data"); err != nil {
+        // handle error
+    }
+    tex, err := assets.Texture("sprites/player.png")
+    ...
+    assets.Release(tex)
+    assets.Purge()
+
+Like the rest of gome, the functions of this package create GL objects and must
+be called on the main OS thread after gome.App.Init. App.Terminate deletes all
+cached assets, as they cannot outlive the GL context.
+*/
+package assets
+
+import (
+    "archive/zip"
+    "bytes"
+    "errors"
+    "fmt"
+    "github.com/snorredc/gome"
+    "github.com/snorredc/gome/text"
+    "image"
+    "io/fs"
+    "os"
+)
+
+var ErrNotLoaded = errors.New("asset was not loaded by the asset manager")
+
+// roots are searched from the last mounted to the first.
+var roots []fs.FS
+
+// zips are the files opened by MountZip, closed by Unmount.
+var zips []*zip.ReadCloser
+
+// Mount adds the directory dir as an asset root.
+func Mount(dir string) error {
+    info, err := os.Stat(dir)
+    if err != nil {
+        return err
+    }
+    if !info.IsDir() {
+        return fmt.Errorf("assets: %s is not a directory", dir)
+    }
+    MountFS(os.DirFS(dir))
+    return nil
+}
+
+// MountZip adds the contents of the zip file at path as an asset root.
+func MountZip(path string) error {
+    r, err := zip.OpenReader(path)
+    if err != nil {
+        return err
+    }
+    zips = append(zips, r)
+    MountFS(&r.Reader)
+    return nil
+}
+
+// MountZipData adds the contents of a zip archive held in memory as an asset
+// root, e.g. one embedded into the executable.
+func MountZipData(data []byte) error {
+    r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+    if err != nil {
+        return err
+    }
+    MountFS(r)
+    return nil
+}
+
+// MountFS adds fsys as an asset root. Roots mounted later take precedence over
+// earlier ones, so they can be used to override individual assets.
+func MountFS(fsys fs.FS) {
+    roots = append(roots, fsys)
+}
+
+// Unmount removes all asset roots and closes the zip files opened by
+// MountZip. Cached assets are not affected.
+func Unmount() {
+    for _, z := range zips {
+        z.Close()
+    }
+    roots, zips = nil, nil
+}
+
+// ReadFile reads the file name from the first asset root containing it. If no
+// roots are mounted, it reads from gome's asset file system (see
+// gome.SetAssetFS).
+func ReadFile(name string) ([]byte, error) {
+    if len(roots) == 0 {
+        return gome.ReadAsset(name)
+    }
+    for i := len(roots) - 1; i >= 0; i-- {
+        data, err := fs.ReadFile(roots[i], name)
+        if err == nil {
+            return data, nil
+        }
+        if !errors.Is(err, fs.ErrNotExist) {
+            return nil, err
+        }
+    }
+    return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+type key struct {
+    kind string
+    name string
+}
+
+type entry struct {
+    value  interface{}
+    refs   int
+    delete func()
+}
+
+var (
+    cache   = map[key]*entry{}
+    byValue = map[interface{}]*entry{}
+)
+
+// load returns the cached asset for k, or creates it with create.
+func load(k key, create func() (interface{}, func(), error)) (interface{}, error) {
+    if e, ok := cache[k]; ok {
+        e.refs++
+        return e.value, nil
+    }
+    value, del, err := create()
+    if err != nil {
+        return nil, err
+    }
+    e := &entry{value, 1, del}
+    cache[k] = e
+    byValue[value] = e
+    return value, nil
+}
+
+// Texture returns the texture from the image file name, loading it with the
+// default texture options if it is not cached yet.
+func Texture(name string) (*gome.Texture, error) {
+    v, err := load(key{"texture", name}, func() (interface{}, func(), error) {
+        data, err := ReadFile(name)
+        if err != nil {
+            return nil, nil, err
+        }
+        img, _, err := image.Decode(bytes.NewReader(data))
+        if err != nil {
+            return nil, nil, fmt.Errorf("assets: %s: %v", name, err)
+        }
+        t := gome.NewTextureFromImage(img, nil)
+        return t, t.Delete, nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return v.(*gome.Texture), nil
+}
+
+// Shader returns the program linked from the vertex shader file vs and the
+// fragment shader file fs, compiling it if it is not cached yet.
+func Shader(vs, fs string) (*gome.Program, error) {
+    v, err := load(key{"shader", vs + "\x00" + fs}, func() (interface{}, func(), error) {
+        vert, err := ReadFile(vs)
+        if err != nil {
+            return nil, nil, err
+        }
+        frag, err := ReadFile(fs)
+        if err != nil {
+            return nil, nil, err
+        }
+        p, err := gome.NewProgram(string(vert), string(frag))
+        if err != nil {
+            return nil, nil, fmt.Errorf("assets: %s, %s: %v", vs, fs, err)
+        }
+        return p, p.Delete, nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return v.(*gome.Program), nil
+}
+
+// Font returns the font file name rasterised at size pixels, loading it if it
+// is not cached yet.
+func Font(name string, size float64) (*gome.Font, error) {
+    k := key{"font", fmt.Sprintf("%s\x00%g", name, size)}
+    v, err := load(k, func() (interface{}, func(), error) {
+        data, err := ReadFile(name)
+        if err != nil {
+            return nil, nil, err
+        }
+        f, err := text.Parse(data, size)
+        if err != nil {
+            return nil, nil, fmt.Errorf("assets: %s: %v", name, err)
+        }
+        return f, f.Delete, nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return v.(*gome.Font), nil
+}
+
+// Release decrements the reference count of an asset returned by this
+// package. Assets that are no longer referenced stay cached until Purge.
+func Release(asset interface{}) error {
+    e, ok := byValue[asset]
+    if !ok {
+        return ErrNotLoaded
+    }
+    if e.refs > 0 {
+        e.refs--
+    }
+    return nil
+}
+
+// Purge deletes all cached assets that are no longer referenced.
+func Purge() {
+    for k, e := range cache {
+        if e.refs == 0 {
+            e.delete()
+            delete(cache, k)
+            delete(byValue, e.value)
+        }
+    }
+}
+
+func init() {
+    gome.OnTerminate(PurgeAll)
+}
+
+// PurgeAll deletes all cached assets regardless of their reference counts.
+// gome.App.Terminate calls it.
+func PurgeAll() {
+    for k, e := range cache {
+        e.delete()
+        delete(cache, k)
+        delete(byValue, e.value)
+    }
+}
@@ -0,0 +1,55 @@
+// +build gomemock
+
+package assets
+
+import (
+    "bytes"
+    "fmt"
+    "github.com/snorredc/gome"
+    "image"
+    "image/png"
+    "testing"
+    "testing/fstest"
+)
+
+func testFS(t *testing.T) fstest.MapFS {
+    var b bytes.Buffer
+    if err := png.Encode(&b, image.NewNRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+        t.Fatal(err)
+    }
+    return fstest.MapFS{"white.png": {Data: b.Bytes()}}
+}
+
+func TestMockTextureAfterReinit(t *testing.T) {
+    MountFS(testFS(t))
+    defer Unmount()
+
+    load := func() (*gome.Texture, []gome.MockCommand) {
+        app := gome.NewApp(gome.Config{})
+        if err := app.Init(); err != nil {
+            t.Fatal(err)
+        }
+        tex, err := Texture("white.png")
+        if err != nil {
+            t.Fatal(err)
+        }
+        if again, _ := Texture("white.png"); again != tex {
+            t.Error("texture loaded twice")
+        }
+        gome.ResetMockCommands()
+        app.Terminate()
+        return tex, gome.MockCommands("gl.DeleteTextures")
+    }
+    first, deleted := load()
+    want := fmt.Sprintf("gl.DeleteTextures(1, [%d])", first.ID)
+    if len(deleted) != 1 || deleted[0].String() != want {
+        t.Errorf("deleted %v on Terminate, want %s", deleted, want)
+    }
+    if len(cache) != 0 {
+        t.Errorf("%d assets cached after Terminate", len(cache))
+    }
+    second, _ := load()
+    if second == first {
+        t.Error("texture of the terminated App handed out again")
+    }
+}
@@ -0,0 +1,46 @@
+package assets
+
+import (
+    "archive/zip"
+    "errors"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestMountZip(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "assets.zip")
+    f, err := os.Create(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    w := zip.NewWriter(f)
+    fw, err := w.Create("hello.txt")
+    if err != nil {
+        t.Fatal(err)
+    }
+    fw.Write([]byte("hello"))
+    if err := w.Close(); err != nil {
+        t.Fatal(err)
+    }
+    f.Close()
+
+    for i := 0; i < 2; i++ {
+        if err := MountZip(path); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if data, err := ReadFile("hello.txt"); err != nil || string(data) != "hello" {
+        t.Fatalf("read %q, %v, want hello", data, err)
+    }
+    opened := zips
+    Unmount()
+    if len(zips) != 0 || len(roots) != 0 {
+        t.Error("roots left after Unmount")
+    }
+    for i, z := range opened {
+        if err := z.Close(); !errors.Is(err, os.ErrClosed) {
+            t.Errorf("zip %d not closed by Unmount: closing again gives %v", i, err)
+        }
+    }
+}
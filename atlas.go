@@ -0,0 +1,147 @@
+package gome
+
+import (
+    "errors"
+    "github.com/snorredc/gome/internal/gl"
+    "image"
+    "image/draw"
+)
+
+var ErrAtlasFull = errors.New("image does not fit into the atlas")
+
+// Region is an area of a texture, such as a sprite in an atlas or a frame of
+// a sprite sheet. Sprites may have been trimmed when they were packed, in
+// which case Src only covers the opaque part of the original image and
+// OffsetX and OffsetY give its position within it.
+type Region struct {
+    Texture          *Texture
+    Src              Rect    // area of the texture in pixels
+    OffsetX, OffsetY float32 // position of Src within the untrimmed sprite
+    Width, Height    float32 // size of the untrimmed sprite
+}
+
+// atlasPadding is the number of transparent pixels left between images so
+// that linear filtering does not bleed neighbouring images into each other.
+const atlasPadding = 1
+
+// Atlas packs images into a single texture at run time, so that sprites from
+// many images can be drawn by a SpriteBatch without switching textures.
+type Atlas struct {
+    Texture *Texture
+
+    size    int
+    skyline []skylineNode
+}
+
+// skylineNode is a segment of the top edge of the packed area.
+type skylineNode struct {
+    x, y, w int
+}
+
+// NewAtlas creates an empty atlas with a square texture of size × size
+// pixels.
+func NewAtlas(size int) *Atlas {
+    t := NewTexture()
+    t.Width, t.Height = size, size
+    BindTexture(0, gl.TEXTURE_2D, t)
+    // zero the texture so that the padding is transparent
+    gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+    gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, int32(size), int32(size), 0,
+        gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(make([]byte, 4*size*size)))
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+    return &Atlas{
+        Texture: t,
+        size:    size,
+        skyline: []skylineNode{{0, 0, size}},
+    }
+}
+
+// Add copies img into the atlas and returns its region. It returns
+// ErrAtlasFull if there is no space left for the image.
+func (a *Atlas) Add(img image.Image) (Region, error) {
+    b := img.Bounds()
+    x, y, ok := a.pack(b.Dx()+atlasPadding, b.Dy()+atlasPadding)
+    if !ok {
+        return Region{}, ErrAtlasFull
+    }
+    rgba, ok := img.(*image.NRGBA)
+    if !ok || rgba.Stride != 4*b.Dx() {
+        rgba = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+        draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+    }
+    BindTexture(0, gl.TEXTURE_2D, a.Texture)
+    gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+    gl.TexSubImage2D(gl.TEXTURE_2D, 0, int32(x), int32(y), int32(b.Dx()), int32(b.Dy()),
+        gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+
+    w, h := float32(b.Dx()), float32(b.Dy())
+    return Region{
+        Texture: a.Texture,
+        Src:     Rect{float32(x), float32(y), w, h},
+        Width:   w,
+        Height:  h,
+    }, nil
+}
+
+// pack finds a place for a w × h rectangle with the skyline bottom-left
+// heuristic: the lowest position, and of those the leftmost one.
+func (a *Atlas) pack(w, h int) (x, y int, ok bool) {
+    best, bestX, bestY := -1, 0, a.size
+    for i := range a.skyline {
+        nx := a.skyline[i].x
+        if nx+w > a.size {
+            break
+        }
+        // the rectangle rests on the highest segment it spans
+        ny, width := 0, 0
+        for j := i; width < w; j++ {
+            if a.skyline[j].y > ny {
+                ny = a.skyline[j].y
+            }
+            width += a.skyline[j].w
+        }
+        if ny+h <= a.size && ny < bestY {
+            best, bestX, bestY = i, nx, ny
+        }
+    }
+    if best < 0 {
+        return 0, 0, false
+    }
+
+    // replace the covered segments by the top of the new rectangle
+    node := skylineNode{bestX, bestY + h, w}
+    end := best
+    for end < len(a.skyline) && a.skyline[end].x+a.skyline[end].w <= bestX+w {
+        end++
+    }
+    rest := a.skyline[end:]
+    if len(rest) > 0 && rest[0].x < bestX+w {
+        // shrink the partially covered segment
+        rest[0].w -= bestX + w - rest[0].x
+        rest[0].x = bestX + w
+    }
+    skyline := append([]skylineNode{}, a.skyline[:best]...)
+    skyline = append(skyline, node)
+    skyline = append(skyline, rest...)
+
+    // merge neighbouring segments of the same height
+    merged := skyline[:1]
+    for _, n := range skyline[1:] {
+        last := &merged[len(merged)-1]
+        if last.y == n.y {
+            last.w += n.w
+        } else {
+            merged = append(merged, n)
+        }
+    }
+    a.skyline = merged
+    return bestX, bestY, true
+}
+
+// Delete deletes the texture of the atlas.
+func (a *Atlas) Delete() {
+    a.Texture.Delete()
+}
@@ -0,0 +1,242 @@
+/*
+Package audio plays sounds for gome applications through a small software
+mixer. All voices are mixed in Go and sent to the audio device of the system
+as a single stream, so there are no limits on the number of sounds playing at
+once and every voice can have its volume, panning and pitch changed at any
+time:
+
+    if err := audio.Init(); err != nil {
+        // handle error
+    }
+    defer audio.Terminate()
+
+    boom, err := audio.Load("sounds/explosion.wav")
+    if err != nil {
+        // handle error
+    }
+    boom.Play().SetPan(-0.5)
+
+    for app.Tick() {
+        audio.Update()
+        ...
+    }
+
+Sounds are decoded into memory when they are loaded, while music is streamed
+from OGG/Vorbis files (see OpenMusic). Update should be called
+once per frame; it runs the OnEnd callbacks of voices that have finished, on
+the goroutine calling it.
+
+Output goes through github.com/ebitengine/oto, which needs the ALSA
+development headers to build on Linux. Built with the gomemock tag, there is
+no audio device, and the mixer only runs when MockMix is called.
+*/
+package audio
+
+import (
+    "encoding/binary"
+    "errors"
+    "math"
+    "sync"
+    "time"
+)
+
+var ErrNotInitialized = errors.New("audio: Init has not been called")
+
+// SampleRate is the sample rate of the output in Hz. It must be set before
+// Init; sounds with other rates are resampled while they play.
+var SampleRate = 44100
+
+// Latency is the approximate delay between starting a voice and hearing it.
+// Lower values make the output more likely to stutter. It must be set before
+// Init.
+var Latency = 50 * time.Millisecond
+
+// the mixer state is shared with the goroutine of the audio device
+var mixer struct {
+    sync.Mutex
+    out    output
+    rate   int
+    voices []*Voice
+    ended  []*Voice
+    master float32
+    paused bool
+    buf    []float32
+}
+
+func init() {
+    mixer.master = 1
+}
+
+// Init opens the audio device and starts the mixer.
+func Init() error {
+    if mixer.out != nil {
+        return nil
+    }
+    rate := mixer.rate
+    if rate == 0 {
+        rate = SampleRate
+    }
+    mixer.Lock()
+    mixer.paused = false
+    mixer.Unlock()
+    out, err := openOutput(rate, Latency, mixReader{})
+    if err != nil {
+        return err
+    }
+    mixer.out = out
+    mixer.rate = rate
+    return nil
+}
+
+// Terminate stops all voices and the mixer. Init may be called again
+// afterwards, but the sample rate of the output stays the same.
+func Terminate() {
+    if mixer.out == nil {
+        return
+    }
+    mixer.out.Close()
+    mixer.out = nil
+    mixer.Lock()
+    mixer.voices = nil
+    mixer.ended = nil
+    mixer.Unlock()
+}
+
+// Err returns the first error reported by the audio device, if any.
+func Err() error {
+    if mixer.rate == 0 {
+        return ErrNotInitialized
+    }
+    if mixer.out != nil {
+        return mixer.out.Err()
+    }
+    return nil
+}
+
+// SetMasterVolume sets the volume all voices are multiplied by. 1 is the
+// original volume.
+func SetMasterVolume(volume float32) {
+    mixer.Lock()
+    mixer.master = volume
+    mixer.Unlock()
+}
+
+// MasterVolume returns the master volume.
+func MasterVolume() float32 {
+    mixer.Lock()
+    defer mixer.Unlock()
+    return mixer.master
+}
+
+// Pause pauses all voices, e.g. while the application is in the background.
+func Pause() {
+    mixer.Lock()
+    mixer.paused = true
+    mixer.Unlock()
+}
+
+// Resume resumes the voices after Pause.
+func Resume() {
+    mixer.Lock()
+    mixer.paused = false
+    mixer.Unlock()
+}
+
+// Playing returns the number of voices that are playing or paused.
+func Playing() int {
+    mixer.Lock()
+    defer mixer.Unlock()
+    return len(mixer.voices)
+}
+
+// StopAll stops all voices.
+func StopAll() {
+    mixer.Lock()
+    for _, v := range mixer.voices {
+        v.done = true
+    }
+    mixer.Unlock()
+}
+
+// Update runs the OnEnd callbacks of the voices that have finished since the
+// last call. It should be called once per frame.
+func Update() {
+    mixer.Lock()
+    ended := mixer.ended
+    mixer.ended = nil
+    // voices stopped while the device is not pulling samples are removed here
+    mixer.voices = removeDone(mixer.voices)
+    mixer.Unlock()
+    for _, v := range ended {
+        if v.OnEnd != nil {
+            v.OnEnd()
+        }
+    }
+}
+
+// removeDone removes the finished voices from voices and queues them for
+// their OnEnd callbacks. The mixer must be locked.
+func removeDone(voices []*Voice) []*Voice {
+    live := voices[:0]
+    for _, v := range voices {
+        if v.done {
+            if v.stream != nil {
+                v.stream.stop()
+            }
+            mixer.ended = append(mixer.ended, v)
+        } else {
+            live = append(live, v)
+        }
+    }
+    for i := len(live); i < len(voices); i++ {
+        voices[i] = nil
+    }
+    return live
+}
+
+func addVoice(v *Voice) {
+    mixer.Lock()
+    mixer.voices = append(mixer.voices, v)
+    mixer.Unlock()
+}
+
+// output is the stream the mixer plays through: the audio device, or nothing in
+// the mock backend (see output.go and output_mock.go).
+type output interface {
+    Close() error
+    Err() error
+}
+
+// mixReader is read by the audio device and mixes the voices.
+type mixReader struct{}
+
+func (mixReader) Read(p []byte) (int, error) {
+    frames := len(p) / 8
+    mixer.Lock()
+    if cap(mixer.buf) < 2*frames {
+        mixer.buf = make([]float32, 2*frames)
+    }
+    buf := mixer.buf[:2*frames]
+    for i := range buf {
+        buf[i] = 0
+    }
+    if !mixer.paused {
+        for _, v := range mixer.voices {
+            v.mix(buf)
+        }
+        mixer.voices = removeDone(mixer.voices)
+    }
+    master := mixer.master
+    for i, s := range buf {
+        s *= master
+        // clip instead of wrapping around
+        if s > 1 {
+            s = 1
+        } else if s < -1 {
+            s = -1
+        }
+        binary.LittleEndian.PutUint32(p[4*i:], math.Float32bits(s))
+    }
+    mixer.Unlock()
+    return frames * 8, nil
+}
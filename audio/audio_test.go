@@ -0,0 +1,48 @@
+// +build gomemock
+
+package audio
+
+import (
+    "testing"
+)
+
+func TestMockMix(t *testing.T) {
+    if err := Init(); err != nil {
+        t.Fatal(err)
+    }
+    defer Terminate()
+    if err := Err(); err != nil {
+        t.Fatal(err)
+    }
+
+    // 100 frames of a constant 0.5 on both channels, at the output rate
+    samples := make([]float32, 200)
+    for i := range samples {
+        samples[i] = 0.5
+    }
+    ended := false
+    v := NewSound(samples, SampleRate).Play()
+    v.OnEnd = func() { ended = true }
+    v.SetPan(1)
+
+    out := MockMix(50)
+    if len(out) != 100 {
+        t.Fatalf("%d samples, want 100", len(out))
+    }
+    if out[0] != 0 || out[1] != 0.5 {
+        t.Errorf("panned right, frame 0 is %v, %v, want 0, 0.5", out[0], out[1])
+    }
+    Update()
+    if ended || !v.Playing() {
+        t.Error("voice ended halfway")
+    }
+
+    MockMix(100)
+    Update()
+    if !ended || v.Playing() {
+        t.Error("voice still playing after its end")
+    }
+    if n := Playing(); n != 0 {
+        t.Errorf("%d voices playing, want 0", n)
+    }
+}
@@ -0,0 +1,386 @@
+package audio
+
+import (
+    "bytes"
+    "github.com/jfreymuth/oggvorbis"
+    "github.com/snorredc/gome"
+    "io"
+    "io/fs"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// Music is a long track that is decoded while it plays instead of being
+// loaded into memory. Decoding happens on a background goroutine, which
+// keeps a few buffers ahead of the mixer.
+//
+// A Music can only be played by one voice at a time.
+type Music struct {
+    // LoopStart and LoopEnd are the loop points in sample frames: a looping
+    // voice jumps from LoopEnd back to LoopStart, exactly at the sample.
+    // LoopEnd is zero to loop at the end of the track. They are initialised
+    // from the LOOPSTART and LOOPLENGTH or LOOPEND comments of the file, if
+    // present, and must not be changed while the music plays.
+    LoopStart, LoopEnd int64
+
+    file     io.Closer
+    dec      *oggvorbis.Reader
+    rate     int
+    channels int
+
+    mu     sync.Mutex // guards stream
+    stream *stream
+    errMu  sync.Mutex
+    err    error
+}
+
+// OpenMusic opens the OGG/Vorbis file at path in gome's asset file system
+// (see gome.SetAssetFS) for streaming.
+func OpenMusic(path string) (*Music, error) {
+    return OpenMusicFS(gome.AssetFS(), path)
+}
+
+// OpenMusicFS is like OpenMusic but opens the file in fsys.
+func OpenMusicFS(fsys fs.FS, name string) (*Music, error) {
+    var (
+        f   fs.File
+        err error
+    )
+    if fsys == nil {
+        f, err = os.Open(name)
+    } else {
+        f, err = fsys.Open(name)
+    }
+    if err != nil {
+        return nil, err
+    }
+    m, err := ReadMusic(f)
+    if err != nil {
+        f.Close()
+        return nil, err
+    }
+    m.file = f
+    return m, nil
+}
+
+// ReadMusic prepares streaming OGG/Vorbis data from r. Seeking, and thereby
+// looping, requires r to be an io.ReadSeeker; other readers are read into
+// memory first.
+func ReadMusic(r io.Reader) (*Music, error) {
+    if _, ok := r.(io.ReadSeeker); !ok {
+        data, err := io.ReadAll(r)
+        if err != nil {
+            return nil, err
+        }
+        r = bytes.NewReader(data)
+    }
+    dec, err := oggvorbis.NewReader(r)
+    if err != nil {
+        return nil, err
+    }
+    m := &Music{dec: dec, rate: dec.SampleRate(), channels: dec.Channels()}
+
+    comments := map[string]int64{}
+    for _, c := range dec.CommentHeader().Comments {
+        kv := strings.SplitN(c, "=", 2)
+        if len(kv) != 2 {
+            continue
+        }
+        if n, err := strconv.ParseInt(strings.TrimSpace(kv[1]), 10, 64); err == nil {
+            comments[strings.ToUpper(kv[0])] = n
+        }
+    }
+    m.LoopStart = comments["LOOPSTART"]
+    if n, ok := comments["LOOPLENGTH"]; ok {
+        m.LoopEnd = m.LoopStart + n
+    } else if n, ok := comments["LOOPEND"]; ok {
+        m.LoopEnd = n
+    }
+    return m, nil
+}
+
+// Duration returns the length of the track, or zero if it is unknown.
+func (m *Music) Duration() time.Duration {
+    return time.Duration(m.dec.Length()) * time.Second / time.Duration(m.rate)
+}
+
+// Err returns the first error that occurred while decoding.
+func (m *Music) Err() error {
+    m.errMu.Lock()
+    defer m.errMu.Unlock()
+    return m.err
+}
+
+// Play starts playing the track from the beginning on a new voice, which
+// loops by default. It stops the voice previously playing m.
+func (m *Music) Play() *Voice {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    if m.stream != nil {
+        m.stream.voice.Stop()
+        m.stream.stop()
+        <-m.stream.finished
+    }
+    s := newStream(m.rate)
+    v := &Voice{stream: s, volume: 1, pitch: 1, loop: true}
+    s.voice = v
+    m.stream = s
+    go m.decode(s)
+    addVoice(v)
+    return v
+}
+
+// Close stops the music and closes its file.
+func (m *Music) Close() error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    if m.stream != nil {
+        m.stream.voice.Stop()
+        m.stream.stop()
+        <-m.stream.finished
+        m.stream = nil
+    }
+    if m.file != nil {
+        return m.file.Close()
+    }
+    return nil
+}
+
+// CrossFade fades out the voice from while fading in the music to over d,
+// and returns the new voice. from may be nil.
+func CrossFade(from *Voice, to *Music, d time.Duration) *Voice {
+    v := to.Play().SetVolume(0).Fade(1, d)
+    if from != nil {
+        from.FadeOut(d)
+    }
+    return v
+}
+
+// decode fills the buffers of s until the track ends or s is stopped.
+func (m *Music) decode(s *stream) {
+    defer close(s.finished)
+    defer close(s.full)
+
+    ch := m.channels
+    if err := m.dec.SetPosition(0); err != nil {
+        m.setErr(err)
+        return
+    }
+    pos := int64(0)
+    tmp := make([]float32, streamChunk*ch)
+    for end := false; !end; {
+        var c *chunk
+        select {
+        case c = <-s.free:
+        case <-s.quit:
+            return
+        }
+        c.samples, c.start = c.samples[:0], pos
+        mixer.Lock()
+        loop := s.voice.loop
+        mixer.Unlock()
+
+        for len(c.samples) < 2*streamChunk {
+            want := streamChunk - len(c.samples)/2
+            if loop && m.LoopEnd > 0 && pos+int64(want) > m.LoopEnd {
+                want = int(m.LoopEnd - pos)
+            }
+            var (
+                n   int
+                err error
+            )
+            if want > 0 {
+                n, err = m.dec.Read(tmp[:want*ch])
+            }
+            for i := 0; i+ch <= n; i += ch {
+                r := tmp[i]
+                if ch > 1 {
+                    r = tmp[i+1]
+                }
+                c.samples = append(c.samples, tmp[i], r)
+            }
+            pos += int64(n / ch)
+            if err != nil && err != io.EOF {
+                m.setErr(err)
+                end = true
+                break
+            }
+            if want > 0 && err == nil {
+                continue
+            }
+
+            // at the loop end or the end of the file; the chunk ends here so
+            // that the next one starts at the loop start
+            if !loop || (m.LoopEnd > 0 && m.LoopEnd <= m.LoopStart) {
+                end = true
+            } else if err := m.dec.SetPosition(m.LoopStart); err != nil {
+                m.setErr(err)
+                end = true
+            } else {
+                pos = m.LoopStart
+            }
+            break
+        }
+        if len(c.samples) == 0 {
+            s.free <- c
+            continue
+        }
+        select {
+        case s.full <- c:
+        case <-s.quit:
+            return
+        }
+    }
+}
+
+func (m *Music) setErr(err error) {
+    m.errMu.Lock()
+    if m.err == nil {
+        m.err = err
+    }
+    m.errMu.Unlock()
+}
+
+// streamChunk is the number of frames per stream buffer.
+const streamChunk = 4096
+
+// streamBuffers is the number of buffers of a stream: one for the mixer to
+// play, one queued as the next and one for the decoder to fill.
+const streamBuffers = 3
+
+type chunk struct {
+    samples []float32 // interleaved stereo
+    start   int64     // position of the first frame in the track
+}
+
+// stream connects a decoding goroutine with the mixer. The mixer side is
+// only used with the mixer locked.
+type stream struct {
+    voice    *Voice
+    rate     int
+    full     chan *chunk // decoded buffers, closed after the last one
+    free     chan *chunk // buffers to be filled
+    quit     chan struct{}
+    finished chan struct{}
+    once     sync.Once
+
+    cur, next *chunk
+    ended     bool
+}
+
+func newStream(rate int) *stream {
+    s := &stream{
+        rate:     rate,
+        full:     make(chan *chunk, streamBuffers),
+        free:     make(chan *chunk, streamBuffers),
+        quit:     make(chan struct{}),
+        finished: make(chan struct{}),
+    }
+    for i := 0; i < streamBuffers; i++ {
+        s.free <- &chunk{samples: make([]float32, 0, 2*streamChunk)}
+    }
+    return s
+}
+
+// stop asks the decoder to stop; it may be called more than once.
+func (s *stream) stop() {
+    s.once.Do(func() { close(s.quit) })
+}
+
+// fetch takes the next decoded buffer without blocking. It returns nil if
+// the decoder has not kept up or the stream has ended.
+func (s *stream) fetch() *chunk {
+    if s.next != nil {
+        c := s.next
+        s.next = nil
+        return c
+    }
+    select {
+    case c, ok := <-s.full:
+        if !ok {
+            s.ended = true
+            return nil
+        }
+        return c
+    default:
+        return nil
+    }
+}
+
+// frames returns the frame at pos in the current buffer and the one after
+// it. On a buffer underrun it returns silence; it reports false when the
+// stream has ended.
+func (s *stream) frames(pos float64) (l0, r0, l1, r1 float32, ok bool) {
+    if s.cur == nil {
+        if s.cur = s.fetch(); s.cur == nil {
+            return 0, 0, 0, 0, !s.ended
+        }
+    }
+    j := int(pos)
+    c := s.cur.samples
+    l0, r0 = c[2*j], c[2*j+1]
+    if 2*j+3 < len(c) {
+        return l0, r0, c[2*j+2], c[2*j+3], true
+    }
+    if s.next == nil {
+        s.next = s.fetch()
+    }
+    if s.next == nil {
+        return l0, r0, l0, r0, true
+    }
+    return l0, r0, s.next.samples[0], s.next.samples[1], true
+}
+
+// advance moves past the buffers played at pos and returns the position in
+// the new current buffer.
+func (s *stream) advance(pos float64) float64 {
+    for s.cur != nil && pos >= float64(len(s.cur.samples)/2) {
+        pos -= float64(len(s.cur.samples) / 2)
+        s.free <- s.cur
+        s.cur = s.fetch()
+    }
+    if s.cur == nil {
+        return 0
+    }
+    return pos
+}
+
+// position returns the position in the track of the frame at pos in the
+// current buffer.
+func (s *stream) position(pos float64) time.Duration {
+    if s.cur == nil {
+        return 0
+    }
+    frames := float64(s.cur.start) + pos
+    return time.Duration(frames * float64(time.Second) / float64(s.rate))
+}
+
+// decodeOgg decodes a whole OGG/Vorbis file into interleaved stereo samples.
+func decodeOgg(r io.Reader) ([]float32, int, error) {
+    dec, err := oggvorbis.NewReader(r)
+    if err != nil {
+        return nil, 0, err
+    }
+    ch := dec.Channels()
+    var samples []float32
+    tmp := make([]float32, streamChunk*ch)
+    for {
+        n, err := dec.Read(tmp)
+        for i := 0; i+ch <= n; i += ch {
+            r := tmp[i]
+            if ch > 1 {
+                r = tmp[i+1]
+            }
+            samples = append(samples, tmp[i], r)
+        }
+        if err == io.EOF {
+            return samples, dec.SampleRate(), nil
+        }
+        if err != nil {
+            return nil, 0, err
+        }
+    }
+}
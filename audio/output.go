@@ -0,0 +1,46 @@
+// +build !gomemock
+
+package audio
+
+import (
+    "github.com/ebitengine/oto/v3"
+    "io"
+    "time"
+)
+
+// a process can only create one context, so it is kept by Terminate
+var otoContext *oto.Context
+
+type otoOutput struct {
+    *oto.Player
+}
+
+// Err returns the error of the context or of the player.
+func (o otoOutput) Err() error {
+    if err := otoContext.Err(); err != nil {
+        return err
+    }
+    return o.Player.Err()
+}
+
+// openOutput starts playing r, stereo float32 samples at rate, on the audio
+// device of the system. The rate cannot change after the first call.
+func openOutput(rate int, latency time.Duration, r io.Reader) (output, error) {
+    if otoContext == nil {
+        ctx, ready, err := oto.NewContext(&oto.NewContextOptions{
+            SampleRate:   rate,
+            ChannelCount: 2,
+            Format:       oto.FormatFloat32LE,
+            BufferSize:   latency,
+        })
+        if err != nil {
+            return nil, err
+        }
+        <-ready
+        otoContext = ctx
+    }
+    p := otoContext.NewPlayer(r)
+    p.SetBufferSize(int(latency.Seconds()*float64(rate)) * 8)
+    p.Play()
+    return otoOutput{p}, nil
+}
@@ -0,0 +1,73 @@
+package audio
+
+import (
+    "bufio"
+    "bytes"
+    "github.com/snorredc/gome"
+    "io"
+    "time"
+)
+
+// Sound is a sound decoded into memory, which can be played by any number of
+// voices at once.
+type Sound struct {
+    samples []float32 // interleaved stereo
+    rate    int
+}
+
+// Load decodes the WAV or OGG/Vorbis file at path in gome's asset file system (see
+// gome.SetAssetFS).
+func Load(path string) (*Sound, error) {
+    data, err := gome.ReadAsset(path)
+    if err != nil {
+        return nil, err
+    }
+    return Decode(bytes.NewReader(data))
+}
+
+// Decode decodes a sound in the WAV or OGG/Vorbis format from r. Mono sounds
+// are played on both channels; sounds with more than two channels only keep
+// the first two.
+func Decode(r io.Reader) (*Sound, error) {
+    br := bufio.NewReader(r)
+    magic, _ := br.Peek(4)
+    var (
+        samples []float32
+        rate    int
+        err     error
+    )
+    switch string(magic) {
+    case "RIFF":
+        samples, rate, err = decodeWAV(br)
+    case "OggS":
+        samples, rate, err = decodeOgg(br)
+    default:
+        err = ErrFormat
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &Sound{samples, rate}, nil
+}
+
+// NewSound creates a sound from interleaved stereo samples between -1 and 1
+// at the given sample rate, e.g. for generated sounds.
+func NewSound(samples []float32, rate int) *Sound {
+    return &Sound{samples, rate}
+}
+
+// Duration returns the length of the sound.
+func (s *Sound) Duration() time.Duration {
+    return time.Duration(len(s.samples)/2) * time.Second / time.Duration(s.rate)
+}
+
+// Play starts playing the sound on a new voice with the original volume,
+// pitch and centered panning.
+func (s *Sound) Play() *Voice {
+    v := &Voice{sound: s, volume: 1, pitch: 1}
+    if len(s.samples) == 0 {
+        v.done = true
+    }
+    addVoice(v)
+    return v
+}
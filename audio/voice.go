@@ -0,0 +1,230 @@
+package audio
+
+import (
+    "time"
+)
+
+// Voice is a sound or a music track that is playing. Its methods may be
+// called at any time, also after it has finished, which has no effect.
+type Voice struct {
+    // OnEnd, if not nil, is called by Update after the voice has finished or
+    // was stopped.
+    OnEnd func()
+
+    sound  *Sound
+    stream *stream // set instead of sound for music
+    pos    float64 // in frames of the sound, or of the current stream buffer
+    volume float32
+    pan    float32
+    pitch  float32
+    loop   bool
+    paused bool
+    done   bool
+
+    // volume fades
+    fadeStep   float32 // change of the volume per output frame
+    fadeTarget float32
+    fadeStop   bool // stop when the target is reached
+}
+
+// SetVolume sets the volume of the voice; 1 is the original volume. It
+// cancels a fade in progress.
+func (v *Voice) SetVolume(volume float32) *Voice {
+    mixer.Lock()
+    v.volume = volume
+    v.fadeStep, v.fadeStop = 0, false
+    mixer.Unlock()
+    return v
+}
+
+// Volume returns the current volume of the voice.
+func (v *Voice) Volume() float32 {
+    mixer.Lock()
+    defer mixer.Unlock()
+    return v.volume
+}
+
+// Fade changes the volume of the voice linearly to volume over d.
+func (v *Voice) Fade(volume float32, d time.Duration) *Voice {
+    mixer.Lock()
+    v.fade(volume, d, false)
+    mixer.Unlock()
+    return v
+}
+
+// FadeOut fades the voice out over d and then stops it.
+func (v *Voice) FadeOut(d time.Duration) {
+    mixer.Lock()
+    v.fade(0, d, true)
+    mixer.Unlock()
+}
+
+func (v *Voice) fade(volume float32, d time.Duration, stop bool) {
+    frames := float32(d.Seconds() * float64(mixer.rate))
+    if frames < 1 {
+        v.volume = volume
+        v.fadeStep = 0
+        v.done = v.done || stop
+        return
+    }
+    v.fadeTarget, v.fadeStop = volume, stop
+    v.fadeStep = (volume - v.volume) / frames
+}
+
+// SetPan sets the balance between the left (-1) and the right (1) channel.
+func (v *Voice) SetPan(pan float32) *Voice {
+    if pan < -1 {
+        pan = -1
+    } else if pan > 1 {
+        pan = 1
+    }
+    mixer.Lock()
+    v.pan = pan
+    mixer.Unlock()
+    return v
+}
+
+// SetPitch sets the playback speed, which also changes the pitch; 2 is an
+// octave higher.
+func (v *Voice) SetPitch(pitch float32) *Voice {
+    if pitch < 0 {
+        pitch = 0
+    }
+    mixer.Lock()
+    v.pitch = pitch
+    mixer.Unlock()
+    return v
+}
+
+// SetLoop sets whether the voice starts again at the end of the sound. Music
+// loops between its loop points.
+func (v *Voice) SetLoop(loop bool) *Voice {
+    mixer.Lock()
+    v.loop = loop
+    mixer.Unlock()
+    return v
+}
+
+// Pause pauses the voice.
+func (v *Voice) Pause() {
+    mixer.Lock()
+    v.paused = true
+    mixer.Unlock()
+}
+
+// Resume resumes the voice after Pause.
+func (v *Voice) Resume() {
+    mixer.Lock()
+    v.paused = false
+    mixer.Unlock()
+}
+
+// Stop stops the voice for good.
+func (v *Voice) Stop() {
+    mixer.Lock()
+    v.done = true
+    mixer.Unlock()
+}
+
+// Playing reports whether the voice has neither finished nor been stopped.
+// Paused voices are playing.
+func (v *Voice) Playing() bool {
+    mixer.Lock()
+    defer mixer.Unlock()
+    return !v.done
+}
+
+// Position returns how far the voice has played into the sound, or into the
+// track for music.
+func (v *Voice) Position() time.Duration {
+    mixer.Lock()
+    defer mixer.Unlock()
+    if v.stream != nil {
+        return v.stream.position(v.pos)
+    }
+    return time.Duration(v.pos * float64(time.Second) / float64(v.sound.rate))
+}
+
+// mix adds the voice to the stereo frames in buf. The mixer must be locked.
+func (v *Voice) mix(buf []float32) {
+    if v.paused || v.done {
+        return
+    }
+    rate := 0
+    if v.stream != nil {
+        rate = v.stream.rate
+    } else {
+        rate = v.sound.rate
+    }
+    step := float64(v.pitch) * float64(rate) / float64(mixer.rate)
+
+    for i := 0; i < len(buf); i += 2 {
+        var l0, r0, l1, r1 float32
+        var ok bool
+        if v.stream != nil {
+            l0, r0, l1, r1, ok = v.stream.frames(v.pos)
+        } else {
+            l0, r0, l1, r1, ok = v.soundFrames()
+        }
+        if !ok {
+            v.done = true
+            return
+        }
+
+        // interpolate linearly between neighbouring frames
+        f := float32(v.pos - float64(int(v.pos)))
+        l, r := l0+(l1-l0)*f, r0+(r1-r0)*f
+
+        // linear panning, keeping the full volume in the center
+        left, right := v.volume, v.volume
+        if v.pan > 0 {
+            left *= 1 - v.pan
+        } else {
+            right *= 1 + v.pan
+        }
+        buf[i] += l * left
+        buf[i+1] += r * right
+
+        if v.stream != nil {
+            v.pos = v.stream.advance(v.pos + step)
+        } else {
+            v.pos += step
+        }
+        if v.fadeStep != 0 {
+            v.volume += v.fadeStep
+            if (v.fadeStep > 0) == (v.volume >= v.fadeTarget) {
+                v.volume, v.fadeStep = v.fadeTarget, 0
+                if v.fadeStop {
+                    v.done = true
+                    return
+                }
+            }
+        }
+    }
+}
+
+// soundFrames returns the frame of the sound at the position of the voice
+// and the one after it, wrapping around the end of the sound when looping.
+// It reports false when the sound has ended.
+func (v *Voice) soundFrames() (l0, r0, l1, r1 float32, ok bool) {
+    samples := v.sound.samples
+    frames := len(samples) / 2
+    if v.pos >= float64(frames) {
+        if !v.loop {
+            return 0, 0, 0, 0, false
+        }
+        for v.pos >= float64(frames) {
+            v.pos -= float64(frames)
+        }
+    }
+    j := int(v.pos)
+    next := j + 1
+    if next >= frames {
+        if v.loop {
+            next = 0
+        } else {
+            next = j
+        }
+    }
+    return samples[2*j], samples[2*j+1], samples[2*next], samples[2*next+1], true
+}
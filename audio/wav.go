@@ -0,0 +1,94 @@
+package audio
+
+import (
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "io"
+    "math"
+)
+
+var ErrFormat = errors.New("audio: unknown format")
+
+// decodeWAV decodes a RIFF WAVE file with integer PCM samples of 8, 16, 24 or
+// 32 bits or IEEE float samples of 32 bits into interleaved stereo samples.
+func decodeWAV(r io.Reader) (samples []float32, rate int, err error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return nil, 0, err
+    }
+    if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+        return nil, 0, ErrFormat
+    }
+    var (
+        format, channels, bits int
+        pcm                    []byte
+        haveFormat             bool
+    )
+    for p := data[12:]; len(p) >= 8; {
+        id, size := string(p[0:4]), int(binary.LittleEndian.Uint32(p[4:8]))
+        p = p[8:]
+        if size > len(p) {
+            size = len(p)
+        }
+        chunk := p[:size]
+        switch id {
+        case "fmt ":
+            if size < 16 {
+                return nil, 0, fmt.Errorf("audio: invalid WAVE format chunk")
+            }
+            format = int(binary.LittleEndian.Uint16(chunk[0:2]))
+            channels = int(binary.LittleEndian.Uint16(chunk[2:4]))
+            rate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+            bits = int(binary.LittleEndian.Uint16(chunk[14:16]))
+            if format == 0xfffe && size >= 26 {
+                // WAVE_FORMAT_EXTENSIBLE: the format is the start of the GUID
+                format = int(binary.LittleEndian.Uint16(chunk[24:26]))
+            }
+            haveFormat = true
+        case "data":
+            pcm = chunk
+        }
+        // chunks are padded to an even size
+        p = p[size+size&1:]
+    }
+    if !haveFormat || pcm == nil {
+        return nil, 0, fmt.Errorf("audio: WAVE file without format or data")
+    }
+    if channels < 1 || rate <= 0 {
+        return nil, 0, fmt.Errorf("audio: invalid WAVE format")
+    }
+
+    var sample func(b []byte) float32
+    switch {
+    case format == 1 && bits == 8:
+        sample = func(b []byte) float32 { return float32(int(b[0])-128) / 128 }
+    case format == 1 && bits == 16:
+        sample = func(b []byte) float32 { return float32(int16(binary.LittleEndian.Uint16(b))) / (1 << 15) }
+    case format == 1 && bits == 24:
+        sample = func(b []byte) float32 {
+            return float32(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24)) / (1 << 31)
+        }
+    case format == 1 && bits == 32:
+        sample = func(b []byte) float32 { return float32(int32(binary.LittleEndian.Uint32(b))) / (1 << 31) }
+    case format == 3 && bits == 32:
+        sample = func(b []byte) float32 { return math.Float32frombits(binary.LittleEndian.Uint32(b)) }
+    default:
+        return nil, 0, fmt.Errorf("audio: unsupported WAVE format %d with %d bits", format, bits)
+    }
+
+    width := bits / 8
+    frames := len(pcm) / (width * channels)
+    samples = make([]float32, 2*frames)
+    for i := 0; i < frames; i++ {
+        frame := pcm[i*width*channels:]
+        l := sample(frame)
+        r := l
+        if channels > 1 {
+            // further channels are dropped
+            r = sample(frame[width:])
+        }
+        samples[2*i], samples[2*i+1] = l, r
+    }
+    return samples, rate, nil
+}
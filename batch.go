@@ -0,0 +1,254 @@
+package gome
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome/internal/gl"
+    "image"
+    "image/color"
+)
+
+// Rect is an axis-aligned rectangle. It is used both for positions on screen
+// and for regions of textures, both measured in pixels.
+type Rect struct {
+    X, Y, W, H float32
+}
+
+const spriteVertexShader = `#version 150
+uniform mat4 projection;
+in vec2 position;
+in vec2 texcoord;
+in vec4 color;
+out vec2 fragTexcoord;
+out vec4 fragColor;
+void main() {
+    fragTexcoord = texcoord;
+    fragColor = color;
+    gl_Position = projection * vec4(position, 0.0, 1.0);
+}
+`
+
+const spriteFragmentShader = `#version 150
+uniform sampler2D tex;
+in vec2 fragTexcoord;
+in vec4 fragColor;
+out vec4 outColor;
+void main() {
+    outColor = fragColor * texture(tex, fragTexcoord);
+}
+`
+
+// floats per vertex: position, texture coordinates and color
+const spriteVertexSize = 8
+
+// SpriteBatch draws textured and colored rectangles in as few draw calls as
+// possible. All drawing happens between Begin and End, and consecutive
+// sprites using the same texture are drawn with a single draw call.
+type SpriteBatch struct {
+    program    *Program
+    vao        *VertexArray
+    vbo        *Buffer
+    white      *Texture
+    projection int32
+
+    texture  *Texture
+    vertices []float32
+    blend    BlendMode
+}
+
+// BlendMode is a way of blending sprites with what is behind them.
+type BlendMode int
+
+const (
+    BlendAlpha    BlendMode = iota // regular alpha blending
+    BlendAdditive                  // adds the colors, weighted by alpha; used for glow and fire
+)
+
+// NewSpriteBatch creates a sprite batch.
+func NewSpriteBatch() (*SpriteBatch, error) {
+    program, err := NewProgram(spriteVertexShader, spriteFragmentShader)
+    if err != nil {
+        return nil, err
+    }
+    b := &SpriteBatch{
+        program: program,
+        vao:     NewVertexArray(),
+        vbo:     NewBuffer(),
+    }
+    b.projection = program.GetUniformLocation("projection")
+
+    BindVertexArray(b.vao)
+    BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+    stride := spriteVertexSize * 4
+    attribs := []struct {
+        name   string
+        size   int32
+        offset uintptr
+    }{{"position", 2, 0}, {"texcoord", 2, 8}, {"color", 4, 16}}
+    for _, a := range attribs {
+        loc := uint32(program.GetAttribLocation(a.name))
+        gl.EnableVertexAttribArray(loc)
+        gl.VertexAttribPointerWithOffset(loc, a.size, gl.FLOAT, false, int32(stride), a.offset)
+    }
+    BindVertexArray(nil)
+
+    white := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+    white.Set(0, 0, White)
+    b.white = NewTextureFromImage(white, &TextureOptions{
+        MinFilter: gl.NEAREST,
+        MagFilter: gl.NEAREST,
+    })
+    return b, nil
+}
+
+// Begin starts a batch. Coordinates are in pixels with the origin in the
+// top left corner of a viewport of the given size. Begin enables alpha
+// blending and disables depth testing.
+func (b *SpriteBatch) Begin(width, height float32) {
+    b.BeginMatrix(mgl32.Mat4{
+        2 / width, 0, 0, 0,
+        0, -2 / height, 0, 0,
+        0, 0, 1, 0,
+        -1, 1, 0, 1,
+    })
+}
+
+// BeginCamera starts a batch drawn in the world coordinates of cam, for a
+// viewport of the given size in pixels.
+func (b *SpriteBatch) BeginCamera(cam *Camera2D, width, height float32) {
+    b.BeginMatrix(cam.Matrix(width, height))
+}
+
+// BeginMatrix starts a batch whose coordinates are transformed into clip
+// coordinates by m.
+func (b *SpriteBatch) BeginMatrix(m mgl32.Mat4) {
+    UseProgram(b.program)
+    gl.UniformMatrix4fv(b.projection, 1, false, &m[0])
+    Enable(gl.BLEND)
+    Disable(gl.DEPTH_TEST)
+    gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+    b.blend = BlendAlpha
+    b.texture = nil
+    b.vertices = b.vertices[:0]
+}
+
+// SetBlendMode changes how the following sprites are blended with what is
+// behind them. Batches start with BlendAlpha.
+func (b *SpriteBatch) SetBlendMode(mode BlendMode) {
+    if mode == b.blend {
+        return
+    }
+    b.flush()
+    b.blend = mode
+    switch mode {
+    case BlendAdditive:
+        gl.BlendFunc(gl.SRC_ALPHA, gl.ONE)
+    default:
+        gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+    }
+}
+
+// Draw draws the region src of t into the rectangle dst, tinted by c.
+func (b *SpriteBatch) Draw(t *Texture, src, dst Rect, c color.Color) {
+    if t != b.texture {
+        b.flush()
+        b.texture = t
+    }
+    w, h := float32(t.Width), float32(t.Height)
+    u0, v0 := src.X/w, src.Y/h
+    u1, v1 := (src.X+src.W)/w, (src.Y+src.H)/h
+    x0, y0 := dst.X, dst.Y
+    x1, y1 := dst.X+dst.W, dst.Y+dst.H
+
+    r, g, bl, a := colorFloats(c)
+    b.vertices = append(b.vertices,
+        x0, y0, u0, v0, r, g, bl, a,
+        x1, y0, u1, v0, r, g, bl, a,
+        x1, y1, u1, v1, r, g, bl, a,
+        x0, y0, u0, v0, r, g, bl, a,
+        x1, y1, u1, v1, r, g, bl, a,
+        x0, y1, u0, v1, r, g, bl, a,
+    )
+}
+
+// DrawRegion draws r into the rectangle dst, tinted by c. dst is the
+// rectangle of the whole sprite, so trimmed regions are drawn at the position
+// they had before trimming.
+func (b *SpriteBatch) DrawRegion(r Region, dst Rect, c color.Color) {
+    sx, sy := dst.W/r.Width, dst.H/r.Height
+    b.Draw(r.Texture, r.Src, Rect{
+        dst.X + r.OffsetX*sx,
+        dst.Y + r.OffsetY*sy,
+        r.Src.W * sx,
+        r.Src.H * sy,
+    }, c)
+}
+
+// DrawRect fills the rectangle dst with c.
+func (b *SpriteBatch) DrawRect(dst Rect, c color.Color) {
+    b.Draw(b.white, Rect{0, 0, 1, 1}, dst, c)
+}
+
+// DrawText draws s with the top left corner of the first line at (x, y) and
+// returns the width of the longest line. Newlines start a new line.
+func (b *SpriteBatch) DrawText(f *Font, s string, x, y float32, c color.Color) float32 {
+    penX, baseline := x, y+f.Ascent
+    var width float32
+    for _, r := range s {
+        if r == '\n' {
+            penX = x
+            baseline += f.Height
+            continue
+        }
+        g, ok := f.glyphs[r]
+        if !ok {
+            g, ok = f.glyphs['?']
+            if !ok {
+                continue
+            }
+        }
+        if g.src.W > 0 {
+            b.Draw(f.Texture, g.src, Rect{penX + g.x, baseline + g.y, g.src.W, g.src.H}, c)
+        }
+        penX += g.advance
+        if penX-x > width {
+            width = penX - x
+        }
+    }
+    return width
+}
+
+// End draws everything that has not been drawn yet.
+func (b *SpriteBatch) End() {
+    b.flush()
+    b.texture = nil
+}
+
+func (b *SpriteBatch) flush() {
+    if len(b.vertices) == 0 {
+        return
+    }
+    BindTexture(0, gl.TEXTURE_2D, b.texture)
+    BindVertexArray(b.vao)
+    BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+    gl.BufferData(gl.ARRAY_BUFFER, len(b.vertices)*4, gl.Ptr(b.vertices), gl.STREAM_DRAW)
+    gl.DrawArrays(gl.TRIANGLES, 0, int32(len(b.vertices)/spriteVertexSize))
+    b.vertices = b.vertices[:0]
+}
+
+// Delete deletes the GL objects of the batch.
+func (b *SpriteBatch) Delete() {
+    b.program.Delete()
+    b.vao.Delete()
+    b.vbo.Delete()
+    b.white.Delete()
+}
+
+// colorFloats converts c to non-premultiplied float components. A Color is
+// used as it is, without clamping.
+func colorFloats(c color.Color) (r, g, b, a float32) {
+    if f, ok := c.(Color); ok {
+        return f.R, f.G, f.B, f.A
+    }
+    f := ColorOf(c)
+    return f.R, f.G, f.B, f.A
+}
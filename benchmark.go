@@ -0,0 +1,223 @@
+package gome
+
+import (
+    "encoding/json"
+    "fmt"
+    "github.com/snorredc/gome/internal/glfw"
+    "io"
+    "math"
+    "sort"
+    "strings"
+)
+
+// BenchmarkOptions controls a run of BenchmarkWith.
+type BenchmarkOptions struct {
+    Frames int // the number of frames measured
+    Warmup int // frames run before measuring, to fill caches and load lazily; defaults to 10
+
+    // Offscreen hides the main window during the run and renders to a
+    // target of the same size instead (see SetVirtualResolution), so a run
+    // on a build machine neither shows up nor depends on the window being
+    // visible, which some drivers throttle.
+    Offscreen bool
+}
+
+// BenchmarkResult holds the statistics of a benchmark run. The timings are
+// in milliseconds. It encodes to JSON, to be compared across runs by
+// performance regression tests.
+type BenchmarkResult struct {
+    Frames   int     `json:"frames"`
+    TotalMs  float64 `json:"total_ms"`
+    FPS      float64 `json:"fps"`
+    MeanMs   float64 `json:"mean_ms"`
+    StdDevMs float64 `json:"stddev_ms"`
+    MinMs    float64 `json:"min_ms"`
+    MedianMs float64 `json:"median_ms"`
+    P95Ms    float64 `json:"p95_ms"`
+    P99Ms    float64 `json:"p99_ms"`
+    MaxMs    float64 `json:"max_ms"`
+
+    // the zone timings averaged over the frames they were measured in (see
+    // Zone and BeginGPUZone)
+    CPUZones []ZoneTiming `json:"cpu_zones,omitempty"`
+    GPUZones []ZoneTiming `json:"gpu_zones,omitempty"`
+
+    GLErrors   int       `json:"gl_errors"`
+    Context    string    `json:"context"`
+    Renderer   string    `json:"renderer"`
+    FrameTimes []float64 `json:"frame_times_ms"`
+}
+
+// Benchmark runs frames frames of the main loop as fast as possible, calling
+// render once per frame, and returns the statistics of the frame times (see
+// BenchmarkWith).
+func Benchmark(frames int, render func()) BenchmarkResult {
+    return BenchmarkWith(BenchmarkOptions{Frames: frames}, render)
+}
+
+// BenchmarkWith runs the main loop of the initialised App as fast as
+// possible, with vsync off, calling render once per frame like
+//
+//     for app.Tick() {
+//         render()
+//     }
+//
+// and returns the statistics of the frame times, measured from Tick to Tick
+// in real time like UnscaledDelta. It stops early if the App is asked to
+// close; Frames then tells how many frames were measured. Vsync is turned
+// on again afterwards.
+func BenchmarkWith(opts BenchmarkOptions, render func()) BenchmarkResult {
+    warmup := opts.Warmup
+    if warmup <= 0 {
+        warmup = 10
+    }
+    glfw.SwapInterval(0)
+    defer glfw.SwapInterval(1)
+    if opts.Offscreen {
+        defer benchmarkOffscreen()()
+    }
+
+    r := BenchmarkResult{
+        Context:  Context().String(),
+        Renderer: GLInfo().Renderer,
+    }
+    errs := GLErrorCount()
+    running := app.Tick()
+    for i := 0; i < warmup && running; i++ {
+        render()
+        running = app.Tick()
+    }
+    var cpu, gpu zoneSums
+    for i := 0; i < opts.Frames && running; i++ {
+        render()
+        if running = app.Tick(); !running {
+            break
+        }
+        r.FrameTimes = append(r.FrameTimes, UnscaledDelta().Seconds()*1000)
+        cpu.add(CPUZones())
+        gpu.add(GPUZones())
+    }
+    r.Frames = len(r.FrameTimes)
+    r.GLErrors = GLErrorCount() - errs
+    r.CPUZones, r.GPUZones = cpu.average(), gpu.average()
+    r.summarise()
+    return r
+}
+
+// benchmarkOffscreen hides the main window and redirects rendering to a
+// target of its size, and returns a function that undoes it.
+func benchmarkOffscreen() func() {
+    visible := app.window.GetAttrib(glfw.Visible) == glfw.True
+    app.window.Hide()
+    restore := func() {
+        if visible {
+            app.window.Show()
+        }
+    }
+    if virtual.target != nil {
+        // already rendering offscreen
+        return restore
+    }
+    w, h := app.window.GetFramebufferSize()
+    if err := SetVirtualResolution(w, h, Stretch); err != nil {
+        return restore
+    }
+    return func() {
+        SetVirtualResolution(0, 0, Fit)
+        restore()
+    }
+}
+
+// summarise computes the statistics of the frame times.
+func (r *BenchmarkResult) summarise() {
+    if r.Frames == 0 {
+        return
+    }
+    sorted := append([]float64(nil), r.FrameTimes...)
+    sort.Float64s(sorted)
+    for _, t := range sorted {
+        r.TotalMs += t
+    }
+    n := float64(r.Frames)
+    r.MeanMs = r.TotalMs / n
+    var variance float64
+    for _, t := range sorted {
+        variance += (t - r.MeanMs) * (t - r.MeanMs)
+    }
+    r.StdDevMs = math.Sqrt(variance / n)
+    r.FPS = 1000 / r.MeanMs
+    // nearest rank percentiles
+    percentile := func(p float64) float64 {
+        i := int(math.Ceil(p*n)) - 1
+        if i < 0 {
+            i = 0
+        }
+        return sorted[i]
+    }
+    r.MinMs, r.MaxMs = sorted[0], sorted[len(sorted)-1]
+    r.MedianMs = percentile(0.5)
+    r.P95Ms = percentile(0.95)
+    r.P99Ms = percentile(0.99)
+}
+
+// String returns a one-line summary of r.
+func (r BenchmarkResult) String() string {
+    return fmt.Sprintf("%d frames, %.1f fps, mean %.3f ms (±%.3f), min %.3f, median %.3f, p95 %.3f, p99 %.3f, max %.3f",
+        r.Frames, r.FPS, r.MeanMs, r.StdDevMs, r.MinMs, r.MedianMs, r.P95Ms, r.P99Ms, r.MaxMs)
+}
+
+// WriteJSON writes r to w as indented JSON.
+func (r BenchmarkResult) WriteJSON(w io.Writer) error {
+    data, err := json.MarshalIndent(r, "", "    ")
+    if err != nil {
+        return err
+    }
+    _, err = w.Write(append(data, '\n'))
+    return err
+}
+
+// zoneSums sums up the zone timings of frames, keeping the order in which
+// the zones first appeared.
+type zoneSums struct {
+    zones  []ZoneTiming
+    frames []int // the number of frames each zone was measured in
+    index  map[string]int
+}
+
+func (s *zoneSums) add(zones []ZoneTiming) {
+    if s.index == nil {
+        s.index = map[string]int{}
+    }
+    // CPU zones of the same name may be nested in different places, so they
+    // are told apart by their path, which follows from the depth first order
+    var path []string
+    for _, z := range zones {
+        if z.Depth > len(path) {
+            z.Depth = len(path)
+        }
+        path = append(path[:z.Depth], z.Name)
+        key := strings.Join(path, "/")
+        i, ok := s.index[key]
+        if !ok {
+            i = len(s.zones)
+            s.index[key] = i
+            s.zones = append(s.zones, ZoneTiming{Name: z.Name, Depth: z.Depth})
+            s.frames = append(s.frames, 0)
+        }
+        s.zones[i].Milliseconds += z.Milliseconds
+        s.zones[i].Calls += z.Calls
+        s.frames[i]++
+    }
+}
+
+// average returns the timings per frame.
+func (s *zoneSums) average() []ZoneTiming {
+    var zones []ZoneTiming
+    for i, z := range s.zones {
+        n := s.frames[i]
+        z.Milliseconds /= float64(n)
+        z.Calls = (z.Calls + n/2) / n
+        zones = append(zones, z)
+    }
+    return zones
+}
@@ -0,0 +1,191 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/glfw"
+)
+
+// GLFW only allows one callback of each kind per window, so gome installs its
+// own on the main window and dispatches the events to its subsystems. The
+// callbacks run during glfw.PollEvents in Tick. Input events go through
+// handleInput, so they can be recorded and replayed (see RecordInput).
+
+func installCallbacks(w *glfw.Window) {
+    w.SetCharCallback(onChar)
+    w.SetKeyCallback(onKey)
+    w.SetDropCallback(onDrop)
+    w.SetCursorPosCallback(onCursorPosition)
+    w.SetMouseButtonCallback(onMouseButton)
+    w.SetScrollCallback(onScroll)
+    w.SetSizeCallback(onSize)
+    w.SetPosCallback(onPos)
+    w.SetFocusCallback(onFocus)
+    w.SetIconifyCallback(onIconify)
+    w.SetCloseCallback(onClose)
+}
+
+// Kinds of input events.
+const (
+    keyEvent uint8 = iota
+    charEvent
+    mouseButtonEvent
+    cursorEvent
+    scrollEvent
+    dropEvent
+)
+
+// inputEvent is an input event as received from GLFW. Its fields are
+// exported for encoding/gob.
+type inputEvent struct {
+    Kind     uint8
+    Code     int // the key, mouse button or character
+    Scancode int
+    Action   glfw.Action
+    Mods     glfw.ModifierKey
+    X, Y     float64  // the cursor position or the scroll offset
+    Paths    []string // the dropped files
+}
+
+func onChar(w *glfw.Window, char rune) {
+    handleInput(inputEvent{Kind: charEvent, Code: int(char)})
+}
+
+func onKey(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+    handleInput(inputEvent{Kind: keyEvent, Code: int(key), Scancode: scancode, Action: action, Mods: mods})
+}
+
+func onMouseButton(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+    handleInput(inputEvent{Kind: mouseButtonEvent, Code: int(button), Action: action, Mods: mods})
+}
+
+func onCursorPosition(w *glfw.Window, x, y float64) {
+    handleInput(inputEvent{Kind: cursorEvent, X: x, Y: y})
+}
+
+func onScroll(w *glfw.Window, x, y float64) {
+    handleInput(inputEvent{Kind: scrollEvent, X: x, Y: y})
+}
+
+func onDrop(w *glfw.Window, names []string) {
+    handleInput(inputEvent{Kind: dropEvent, Paths: names})
+}
+
+func onSize(w *glfw.Window, width, height int) {
+    if w.GetAttrib(glfw.Maximized) != glfw.True {
+        windowState.normalWidth, windowState.normalHeight = width, height
+    }
+    fbWidth, fbHeight := w.GetFramebufferSize()
+    sendEvent(Resize{width, height, fbWidth, fbHeight})
+}
+
+func onPos(w *glfw.Window, x, y int) {
+    if w.GetAttrib(glfw.Maximized) != glfw.True {
+        windowState.normalX, windowState.normalY = x, y
+    }
+}
+
+func onFocus(w *glfw.Window, focused bool) {
+    windowState.focused = focused
+    sendEvent(Focus{focused})
+}
+
+func onIconify(w *glfw.Window, iconified bool) {
+    windowState.minimized = iconified
+    sendEvent(Minimize{iconified})
+}
+
+func onClose(w *glfw.Window) {
+    sendEvent(CloseRequested{})
+    for _, f := range closeHandlers {
+        if !f() {
+            w.SetShouldClose(false)
+            return
+        }
+    }
+}
+
+// handleInput dispatches an event of the window, unless a replay is running,
+// in which case the input comes from the replay instead.
+func handleInput(e inputEvent) {
+    if replay.active {
+        return
+    }
+    recorder.event(e)
+    dispatchInput(e)
+}
+
+// dispatchInput passes e on to the subsystems that use it and to Events.
+func dispatchInput(e inputEvent) {
+    switch e.Kind {
+    case keyEvent:
+        input.key(glfw.Key(e.Code), e.Action)
+        if e.Action == glfw.Press || e.Action == glfw.Repeat {
+            textInput.key(glfw.Key(e.Code), e.Mods)
+        }
+        sendEvent(KeyEvent{glfw.Key(e.Code), e.Scancode, e.Action, e.Mods})
+    case charEvent:
+        textInput.char(rune(e.Code))
+        sendEvent(CharEvent{rune(e.Code)})
+    case mouseButtonEvent:
+        input.mouseButton(glfw.MouseButton(e.Code), e.Action)
+        x, y := toVirtual(cursor.x, cursor.y)
+        sendEvent(MouseButtonEvent{glfw.MouseButton(e.Code), e.Action, e.Mods, x, y})
+    case cursorEvent:
+        var move MouseMove
+        move.X, move.Y = toVirtual(e.X, e.Y)
+        if cursor.havePosition {
+            move.DX, move.DY = toVirtualDelta(e.X-cursor.x, e.Y-cursor.y)
+        }
+        moveCursor(e.X, e.Y)
+        sendEvent(move)
+    case scrollEvent:
+        input.scroll(e.X, e.Y)
+        sendEvent(Scroll{e.X, e.Y})
+    case dropEvent:
+        drops = append(drops, e.Paths)
+        sendEvent(Drop{e.Paths})
+    }
+}
+
+var (
+    dropHandlers []func(paths []string)
+    drops        [][]string
+)
+
+// OnFileDrop registers f to be called with the paths of the files and
+// directories dropped onto the main window. Drops are delivered by Tick,
+// after the events of the frame have been polled.
+func OnFileDrop(f func(paths []string)) {
+    dropHandlers = append(dropHandlers, f)
+}
+
+var closeHandlers []func() bool
+
+// OnCloseRequest registers f to be called when the user asks to close the
+// main window, e.g. with its close button. If f returns false, the close is
+// cancelled and the main loop goes on, so the application can ask whether to
+// save first and call App.RequestClose once that is done. Handlers are called
+// in the order they were registered until one of them cancels. Closing the
+// window with App.RequestClose does not call them.
+func OnCloseRequest(f func() bool) {
+    closeHandlers = append(closeHandlers, f)
+}
+
+var terminateHandlers []func()
+
+// OnTerminate registers f to be called by App.Terminate while the GL context
+// still exists, before leaked objects are reported, so that caches of GL
+// objects can delete them instead of handing out their names after the App is
+// initialised again. Handlers are called in the order they were registered.
+func OnTerminate(f func()) {
+    terminateHandlers = append(terminateHandlers, f)
+}
+
+// deliverDrops calls the drop handlers for the drops of the frame.
+func deliverDrops() {
+    for _, paths := range drops {
+        for _, f := range dropHandlers {
+            f(paths)
+        }
+    }
+    drops = drops[:0]
+}
@@ -0,0 +1,75 @@
+package gome
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "math"
+)
+
+// Camera2D is a camera for 2D scenes. World coordinates are measured like
+// screen coordinates: x grows to the right and y grows downwards, and at a
+// zoom of 1 a world unit is a pixel.
+type Camera2D struct {
+    Position mgl32.Vec2 // world position at the center of the view
+    Zoom     float32    // magnification; zero is treated as 1
+    Rotation float32    // rotation of the view in radians
+}
+
+func (c *Camera2D) zoom() float32 {
+    if c.Zoom == 0 {
+        return 1
+    }
+    return c.Zoom
+}
+
+// View returns the matrix transforming world coordinates into view
+// coordinates, which are pixels relative to the center of the view.
+func (c *Camera2D) View() mgl32.Mat4 {
+    z := c.zoom()
+    return mgl32.Scale3D(z, z, 1).
+        Mul4(mgl32.HomogRotate3DZ(c.Rotation)).
+        Mul4(mgl32.Translate3D(-c.Position[0], -c.Position[1], 0))
+}
+
+// Matrix returns the matrix transforming world coordinates into clip
+// coordinates for a viewport of the given size in pixels.
+func (c *Camera2D) Matrix(width, height float32) mgl32.Mat4 {
+    return mgl32.Ortho2D(-width/2, width/2, height/2, -height/2).Mul4(c.View())
+}
+
+// ScreenToWorld converts a position in a viewport of the given size, in
+// pixels from the top left corner, into world coordinates.
+func (c *Camera2D) ScreenToWorld(x, y, width, height float32) mgl32.Vec2 {
+    v := mgl32.Vec4{x - width/2, y - height/2, 0, 1}
+    w := c.View().Inv().Mul4x1(v)
+    return mgl32.Vec2{w[0], w[1]}
+}
+
+// WorldToScreen converts world coordinates into a position in a viewport of
+// the given size, in pixels from the top left corner.
+func (c *Camera2D) WorldToScreen(p mgl32.Vec2, width, height float32) mgl32.Vec2 {
+    v := c.View().Mul4x1(mgl32.Vec4{p[0], p[1], 0, 1})
+    return mgl32.Vec2{v[0] + width/2, v[1] + height/2}
+}
+
+// Bounds returns the smallest axis-aligned rectangle in world coordinates
+// that contains everything visible in a viewport of the given size.
+func (c *Camera2D) Bounds(width, height float32) Rect {
+    z := c.zoom()
+    hw, hh := width/2/z, height/2/z
+    if c.Rotation != 0 {
+        sin, cos := math.Sincos(float64(c.Rotation))
+        sin, cos = math.Abs(sin), math.Abs(cos)
+        hw, hh = float32(float64(hw)*cos+float64(hh)*sin), float32(float64(hw)*sin+float64(hh)*cos)
+    }
+    return Rect{c.Position[0] - hw, c.Position[1] - hh, 2 * hw, 2 * hh}
+}
+
+// Intersects reports whether r and s overlap.
+func (r Rect) Intersects(s Rect) bool {
+    return r.X < s.X+s.W && s.X < r.X+r.W && r.Y < s.Y+s.H && s.Y < r.Y+r.H
+}
+
+// Contains reports whether the point (x, y) lies within r.
+func (r Rect) Contains(x, y float32) bool {
+    return x >= r.X && x < r.X+r.W && y >= r.Y && y < r.Y+r.H
+}
@@ -0,0 +1,71 @@
+package gome
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "math"
+)
+
+// Camera3D is a perspective camera for 3D scenes, looking from Position at
+// Target.
+type Camera3D struct {
+    Position mgl32.Vec3
+    Target   mgl32.Vec3
+    Up       mgl32.Vec3 // the upwards direction; zero is treated as +Y
+    FOV      float32    // vertical field of view in radians; zero is treated as 60°
+    Near     float32    // distance of the near clipping plane; zero is treated as 0.1
+    Far      float32    // distance of the far clipping plane; zero is treated as 1000
+}
+
+// View returns the matrix transforming world coordinates into view
+// coordinates.
+func (c *Camera3D) View() mgl32.Mat4 {
+    up := c.Up
+    if up == (mgl32.Vec3{}) {
+        up = mgl32.Vec3{0, 1, 0}
+    }
+    return mgl32.LookAtV(c.Position, c.Target, up)
+}
+
+// Projection returns the perspective projection for a viewport of the given
+// size.
+func (c *Camera3D) Projection(width, height float32) mgl32.Mat4 {
+    fov, near, far := c.FOV, c.Near, c.Far
+    if fov == 0 {
+        fov = math.Pi / 3
+    }
+    if near == 0 {
+        near = 0.1
+    }
+    if far == 0 {
+        far = 1000
+    }
+    return mgl32.Perspective(fov, width/height, near, far)
+}
+
+// Matrix returns the matrix transforming world coordinates into clip
+// coordinates for a viewport of the given size.
+func (c *Camera3D) Matrix(width, height float32) mgl32.Mat4 {
+    return c.Projection(width, height).Mul4(c.View())
+}
+
+// Ray returns the ray from the near plane through the point (x, y) of a
+// viewport of the given size, in pixels from the top left corner.
+func (c *Camera3D) Ray(x, y, width, height float32) Ray {
+    inv := c.Matrix(width, height).Inv()
+    nx, ny := 2*x/width-1, 1-2*y/height
+    near := inv.Mul4x1(mgl32.Vec4{nx, ny, -1, 1})
+    far := inv.Mul4x1(mgl32.Vec4{nx, ny, 1, 1})
+    from, to := near.Vec3().Mul(1/near[3]), far.Vec3().Mul(1/far[3])
+    return Ray{Origin: from, Direction: to.Sub(from).Normalize()}
+}
+
+// WorldToScreen converts world coordinates into a position in a viewport of
+// the given size, in pixels from the top left corner. ok is false for points
+// behind the camera.
+func (c *Camera3D) WorldToScreen(p mgl32.Vec3, width, height float32) (pos mgl32.Vec2, ok bool) {
+    v := c.Matrix(width, height).Mul4x1(p.Vec4(1))
+    if v[3] <= 0 {
+        return mgl32.Vec2{}, false
+    }
+    return mgl32.Vec2{(v[0]/v[3] + 1) / 2 * width, (1 - v[1]/v[3]) / 2 * height}, true
+}
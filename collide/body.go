@@ -0,0 +1,161 @@
+package collide
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "github.com/snorredc/gome/spatial"
+    "time"
+)
+
+// World provides the solid rectangles bodies collide with.
+type World interface {
+    // Solids appends to dst the solids that may overlap area; more do no
+    // harm, but cost time.
+    Solids(area gome.Rect, dst []gome.Rect) []gome.Rect
+}
+
+// Rects is a World of a few solids, which it tests one by one.
+type Rects []gome.Rect
+
+// Solids implements World.
+func (rs Rects) Solids(area gome.Rect, dst []gome.Rect) []gome.Rect {
+    for _, r := range rs {
+        if r.Intersects(area) {
+            dst = append(dst, r)
+        }
+    }
+    return dst
+}
+
+// Indexed is a World of the rectangles in a spatial index.
+type Indexed struct {
+    Index spatial.Index
+    Solid func(id int) bool // reports which IDs are solid; nil for all
+
+    ids []int
+}
+
+// Solids implements World.
+func (w *Indexed) Solids(area gome.Rect, dst []gome.Rect) []gome.Rect {
+    w.ids = w.Index.Query(area, w.ids[:0])
+    for _, id := range w.ids {
+        if w.Solid != nil && !w.Solid(id) {
+            continue
+        }
+        if r, ok := w.Index.Bounds(id); ok {
+            dst = append(dst, r)
+        }
+    }
+    return dst
+}
+
+// maxSlides is how often a motion can be redirected along a surface in one
+// move, enough for a corner and a little more.
+const maxSlides = 4
+
+// MoveAndSlide moves r by motion until it hits a solid of w, then slides
+// along the solid with the rest of the motion, keeping its part along the
+// surface, and so on. It returns the rectangle moved and the normals of the
+// surfaces hit, appended to normals.
+func MoveAndSlide(r gome.Rect, motion mgl32.Vec2, w World, normals []mgl32.Vec2) (gome.Rect, []mgl32.Vec2) {
+    var solids []gome.Rect
+    return moveAndSlide(r, motion, w, &solids, normals)
+}
+
+func moveAndSlide(r gome.Rect, motion mgl32.Vec2, w World, solids *[]gome.Rect, normals []mgl32.Vec2) (gome.Rect, []mgl32.Vec2) {
+    for n := 0; n < maxSlides && motion != (mgl32.Vec2{}); n++ {
+        *solids = w.Solids(sweptArea(r, motion), (*solids)[:0])
+        first, hit := Hit{Time: 2}, false
+        for _, s := range *solids {
+            if h, ok := Sweep(r, motion, s); ok && h.Time < first.Time {
+                first, hit = h, true
+            }
+        }
+        if !hit {
+            r.X, r.Y = r.X+motion[0], r.Y+motion[1]
+            break
+        }
+        r.X, r.Y = r.X+motion[0]*first.Time, r.Y+motion[1]*first.Time
+        normals = append(normals, first.Normal)
+        // the normals are axis-aligned, so sliding drops one component
+        motion = motion.Mul(1 - first.Time)
+        motion = motion.Sub(first.Normal.Mul(motion.Dot(first.Normal)))
+    }
+    return r, normals
+}
+
+// sweptArea returns the area covered by r moving by motion.
+func sweptArea(r gome.Rect, motion mgl32.Vec2) gome.Rect {
+    if motion[0] < 0 {
+        r.X += motion[0]
+    }
+    if motion[1] < 0 {
+        r.Y += motion[1]
+    }
+    r.W += abs(motion[0])
+    r.H += abs(motion[1])
+    return r
+}
+
+func abs(v float32) float32 {
+    if v < 0 {
+        return -v
+    }
+    return v
+}
+
+// Body is a rectangle moving with a velocity, which stops at and slides along
+// solids. Its Move is meant to be called from the update of a
+// gome.FixedTimestep, and Interpolate from the rendering after it.
+type Body struct {
+    Rect     gome.Rect
+    Velocity mgl32.Vec2 // in units per second
+
+    // the surfaces touched by the last Move
+    OnFloor   bool
+    OnCeiling bool
+    OnWall    bool
+
+    previous gome.Rect
+    moved    bool
+    solids   []gome.Rect
+    normals  []mgl32.Vec2
+}
+
+// Move moves the body by its velocity for dt, sliding along the solids of w,
+// and sets the flags of the surfaces it touches. The velocity into the
+// surfaces hit is dropped, so a falling body stops on the floor and one
+// jumping into the ceiling stops rising.
+func (b *Body) Move(dt time.Duration, w World) {
+    b.previous, b.moved = b.Rect, true
+    motion := b.Velocity.Mul(float32(dt.Seconds()))
+    b.Rect, b.normals = moveAndSlide(b.Rect, motion, w, &b.solids, b.normals[:0])
+    b.OnFloor, b.OnCeiling, b.OnWall = false, false, false
+    for _, n := range b.normals {
+        switch {
+        case n[1] < 0:
+            b.OnFloor = true
+        case n[1] > 0:
+            b.OnCeiling = true
+        default:
+            b.OnWall = true
+        }
+        if d := b.Velocity.Dot(n); d < 0 {
+            b.Velocity = b.Velocity.Sub(n.Mul(d))
+        }
+    }
+}
+
+// Interpolate returns the rectangle between its position before and after
+// the last Move, by alpha from 0 to 1 as returned by FixedTimestep.Update.
+// Before the first Move it is Rect.
+func (b *Body) Interpolate(alpha float64) gome.Rect {
+    if !b.moved {
+        return b.Rect
+    }
+    a := float32(alpha)
+    r := b.Rect
+    r.X = b.previous.X + (b.Rect.X-b.previous.X)*a
+    r.Y = b.previous.Y + (b.Rect.Y-b.previous.Y)*a
+    return r
+}
@@ -0,0 +1,100 @@
+package collide
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "testing"
+    "time"
+)
+
+// step is a time step whose fractions of the motions below are exact.
+const step = 125 * time.Millisecond
+
+// floor returns a floor of n 16x16 tiles with its top at y.
+func floor(n int, y float32) Rects {
+    var tiles Rects
+    for i := 0; i < n; i++ {
+        tiles = append(tiles, gome.Rect{X: float32(16 * i), Y: y, W: 16, H: 16})
+    }
+    return tiles
+}
+
+func TestBodySurfaces(t *testing.T) {
+    tests := []struct {
+        name     string
+        body     Body
+        solids   Rects
+        rect     gome.Rect
+        velocity mgl32.Vec2
+        floor    bool
+        ceiling  bool
+        wall     bool
+    }{
+        {"landing",
+            Body{Rect: gome.Rect{W: 8, H: 8}, Velocity: mgl32.Vec2{0, 80}},
+            floor(1, 12), gome.Rect{Y: 4, W: 8, H: 8}, mgl32.Vec2{0, 0}, true, false, false},
+        {"bumping the ceiling",
+            Body{Rect: gome.Rect{W: 8, H: 8}, Velocity: mgl32.Vec2{0, -80}},
+            floor(1, -20), gome.Rect{Y: -4, W: 8, H: 8}, mgl32.Vec2{0, 0}, false, true, false},
+        {"sliding down a wall",
+            Body{Rect: gome.Rect{W: 8, H: 8}, Velocity: mgl32.Vec2{80, 40}},
+            Rects{{X: 12, Y: -32, W: 4, H: 64}}, gome.Rect{X: 4, Y: 5, W: 8, H: 8}, mgl32.Vec2{0, 40}, false, false, true},
+        {"landing on a corner",
+            Body{Rect: gome.Rect{W: 8, H: 8}, Velocity: mgl32.Vec2{128, 128}},
+            Rects{{X: 16, Y: 16, W: 8, H: 8}}, gome.Rect{X: 16, Y: 8, W: 8, H: 8}, mgl32.Vec2{128, 0}, true, false, false},
+        {"falling past",
+            Body{Rect: gome.Rect{W: 8, H: 8}, Velocity: mgl32.Vec2{0, 80}},
+            Rects{{X: 8, W: 8, H: 64}}, gome.Rect{Y: 10, W: 8, H: 8}, mgl32.Vec2{0, 80}, false, false, false},
+    }
+    for _, tt := range tests {
+        b := tt.body
+        b.Move(step, tt.solids)
+        if b.Rect != tt.rect || b.Velocity != tt.velocity {
+            t.Errorf("%s: moved to %+v at %v, want %+v at %v", tt.name, b.Rect, b.Velocity, tt.rect, tt.velocity)
+        }
+        if b.OnFloor != tt.floor || b.OnCeiling != tt.ceiling || b.OnWall != tt.wall {
+            t.Errorf("%s: on the floor %v, ceiling %v, wall %v, want %v, %v, %v", tt.name,
+                b.OnFloor, b.OnCeiling, b.OnWall, tt.floor, tt.ceiling, tt.wall)
+        }
+    }
+}
+
+func TestBodyTileSeams(t *testing.T) {
+    tests := []struct {
+        name string
+        y    float32
+        fall float32
+    }{
+        {"walking", 24, 60},
+        {"walking without gravity", 24, 0},
+        // sunk into the floor by rounding errors
+        {"walking within Epsilon", 24 + 1.0/2048, 0},
+        {"walking within Epsilon with gravity", 24 + 1.0/2048, 60},
+    }
+    for _, tt := range tests {
+        tiles := floor(6, 32)
+        b := &Body{Rect: gome.Rect{X: 2, Y: tt.y, W: 8, H: 8}}
+        // across the seams at 16, 32, 48 and 64
+        for i := 1; i <= 4; i++ {
+            b.Velocity = mgl32.Vec2{120, tt.fall}
+            b.Move(step, tiles)
+            if b.OnWall || b.Rect.X != float32(2+15*i) || b.Rect.Y != tt.y {
+                t.Errorf("%s: step %d to %+v, on a wall %v", tt.name, i, b.Rect, b.OnWall)
+            }
+            if tt.fall != 0 && !b.OnFloor {
+                t.Errorf("%s: step %d not on the floor", tt.name, i)
+            }
+        }
+    }
+}
+
+func TestBodyInterpolate(t *testing.T) {
+    b := &Body{Rect: gome.Rect{W: 8, H: 8}, Velocity: mgl32.Vec2{64, 0}}
+    if r := b.Interpolate(0.5); r != b.Rect {
+        t.Errorf("before any move %+v, want %+v", r, b.Rect)
+    }
+    b.Move(step, Rects{})
+    if r := b.Interpolate(0.25); r != (gome.Rect{X: 2, W: 8, H: 8}) {
+        t.Errorf("a quarter into the move %+v, want x 2", r)
+    }
+}
@@ -0,0 +1,141 @@
+/*
+Package collide detects and resolves collisions between axis-aligned
+rectangles and circles in 2D: overlap tests with the contact to push shapes
+apart, a swept test finding when a moving rectangle first touches another,
+and a Body that moves and slides along solid rectangles in steps of a
+gome.FixedTimestep. It is not a physics engine; there is no mass, rotation or
+stacking, which most 2D games do not need.
+
+Rectangles are gome.Rect values with y growing downwards, as in Camera2D, so
+a floor has the normal (0, -1).
+
+    player := &collide.Body{Rect: gome.Rect{X: 32, Y: 32, W: 14, H: 24}}
+    solids := collide.Rects(level.Walls)
+    loop := &gome.FixedTimestep{}
+
+    for app.Tick() {
+        loop.Update(func(dt time.Duration) {
+            player.Velocity[1] += gravity * float32(dt.Seconds())
+            if player.OnFloor && jumpPressed {
+                player.Velocity[1] = -jumpSpeed
+            }
+            player.Move(dt, solids)
+        })
+        render()
+    }
+*/
+package collide
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "math"
+)
+
+// Epsilon is the distance below which shapes count as touching rather than
+// overlapping, so that rounding errors do not make a body catch on the seams
+// between adjacent solids, such as the tiles of a floor.
+var Epsilon float32 = 1e-3
+
+// Circle is a circle with its center at (X, Y) and radius R.
+type Circle struct {
+    X, Y, R float32
+}
+
+// Bounds returns the smallest rectangle containing c.
+func (c Circle) Bounds() gome.Rect {
+    return gome.Rect{X: c.X - c.R, Y: c.Y - c.R, W: 2 * c.R, H: 2 * c.R}
+}
+
+// Contact describes how two overlapping shapes are best separated: moving
+// the first by Normal times Depth, or the second by the opposite, makes them
+// touch.
+type Contact struct {
+    Normal mgl32.Vec2 // unit vector pointing out of the second shape
+    Depth  float32    // the distance by which the shapes overlap
+}
+
+// RectRect reports whether a and b overlap, by more than touching, and if
+// so the contact along the axis of least overlap.
+func RectRect(a, b gome.Rect) (Contact, bool) {
+    ox := min(a.X+a.W, b.X+b.W) - max(a.X, b.X)
+    oy := min(a.Y+a.H, b.Y+b.H) - max(a.Y, b.Y)
+    if ox <= 0 || oy <= 0 {
+        return Contact{}, false
+    }
+    if ox < oy {
+        if a.X+a.W/2 < b.X+b.W/2 {
+            return Contact{mgl32.Vec2{-1, 0}, ox}, true
+        }
+        return Contact{mgl32.Vec2{1, 0}, ox}, true
+    }
+    if a.Y+a.H/2 < b.Y+b.H/2 {
+        return Contact{mgl32.Vec2{0, -1}, oy}, true
+    }
+    return Contact{mgl32.Vec2{0, 1}, oy}, true
+}
+
+// CircleCircle reports whether a and b overlap, and if so their contact.
+// Circles with the same center are separated along x.
+func CircleCircle(a, b Circle) (Contact, bool) {
+    d := mgl32.Vec2{a.X - b.X, a.Y - b.Y}
+    r := a.R + b.R
+    l2 := d.Dot(d)
+    if l2 >= r*r {
+        return Contact{}, false
+    }
+    if l2 == 0 {
+        return Contact{mgl32.Vec2{1, 0}, r}, true
+    }
+    l := float32(math.Sqrt(float64(l2)))
+    return Contact{d.Mul(1 / l), r - l}, true
+}
+
+// RectCircle reports whether r and c overlap, and if so their contact, with
+// the normal pointing from c towards r.
+func RectCircle(r gome.Rect, c Circle) (Contact, bool) {
+    // the point of r closest to the center
+    px := clamp(c.X, r.X, r.X+r.W)
+    py := clamp(c.Y, r.Y, r.Y+r.H)
+    d := mgl32.Vec2{px - c.X, py - c.Y}
+    l2 := d.Dot(d)
+    if l2 >= c.R*c.R {
+        return Contact{}, false
+    }
+    if l2 > 0 {
+        l := float32(math.Sqrt(float64(l2)))
+        return Contact{d.Mul(1 / l), c.R - l}, true
+    }
+
+    // the center is inside r, which leaves by its nearest edge
+    left, right := c.X-r.X, r.X+r.W-c.X
+    top, bottom := c.Y-r.Y, r.Y+r.H-c.Y
+    switch min(min(left, right), min(top, bottom)) {
+    case left:
+        return Contact{mgl32.Vec2{1, 0}, left + c.R}, true
+    case right:
+        return Contact{mgl32.Vec2{-1, 0}, right + c.R}, true
+    case top:
+        return Contact{mgl32.Vec2{0, 1}, top + c.R}, true
+    default:
+        return Contact{mgl32.Vec2{0, -1}, bottom + c.R}, true
+    }
+}
+
+func clamp(v, lo, hi float32) float32 {
+    return max(lo, min(v, hi))
+}
+
+func min(a, b float32) float32 {
+    if a < b {
+        return a
+    }
+    return b
+}
+
+func max(a, b float32) float32 {
+    if a > b {
+        return a
+    }
+    return b
+}
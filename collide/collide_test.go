@@ -0,0 +1,74 @@
+package collide
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "testing"
+)
+
+func TestRectRect(t *testing.T) {
+    a := gome.Rect{W: 4, H: 4}
+    tests := []struct {
+        name    string
+        b       gome.Rect
+        overlap bool
+        want    Contact
+    }{
+        {"apart", gome.Rect{X: 5, W: 4, H: 4}, false, Contact{}},
+        {"touching", gome.Rect{X: 4, W: 4, H: 4}, false, Contact{}},
+        {"touching corners", gome.Rect{X: 4, Y: 4, W: 4, H: 4}, false, Contact{}},
+        {"on the right", gome.Rect{X: 3, Y: 1, W: 4, H: 2}, true, Contact{mgl32.Vec2{-1, 0}, 1}},
+        {"on the left", gome.Rect{X: -3, W: 4, H: 4}, true, Contact{mgl32.Vec2{1, 0}, 1}},
+        {"below", gome.Rect{X: 1, Y: 3.5, W: 2, H: 4}, true, Contact{mgl32.Vec2{0, -1}, 0.5}},
+        {"above", gome.Rect{Y: -2, W: 4, H: 4}, true, Contact{mgl32.Vec2{0, 1}, 2}},
+    }
+    for _, tt := range tests {
+        c, ok := RectRect(a, tt.b)
+        if ok != tt.overlap || c != tt.want {
+            t.Errorf("%s: overlap %v with %+v, want %v with %+v", tt.name, ok, c, tt.overlap, tt.want)
+        }
+    }
+}
+
+func TestCircleCircle(t *testing.T) {
+    tests := []struct {
+        name    string
+        b       Circle
+        overlap bool
+        want    Contact
+    }{
+        {"touching", Circle{X: 3, R: 1}, false, Contact{}},
+        {"overlapping", Circle{Y: 2, R: 1}, true, Contact{mgl32.Vec2{0, -1}, 1}},
+        {"same center", Circle{R: 1}, true, Contact{mgl32.Vec2{1, 0}, 3}},
+    }
+    for _, tt := range tests {
+        c, ok := CircleCircle(Circle{R: 2}, tt.b)
+        if ok != tt.overlap || c != tt.want {
+            t.Errorf("%s: overlap %v with %+v, want %v with %+v", tt.name, ok, c, tt.overlap, tt.want)
+        }
+    }
+}
+
+func TestRectCircle(t *testing.T) {
+    r := gome.Rect{W: 4, H: 4}
+    tests := []struct {
+        name    string
+        c       Circle
+        overlap bool
+        want    Contact
+    }{
+        {"touching an edge", Circle{X: 6, Y: 2, R: 2}, false, Contact{}},
+        {"clear of a corner", Circle{X: 5, Y: 5, R: 1.25}, false, Contact{}},
+        {"on an edge", Circle{X: 2, Y: 5, R: 2}, true, Contact{mgl32.Vec2{0, -1}, 1}},
+        {"on a corner", Circle{X: 7, Y: 8, R: 6}, true, Contact{mgl32.Vec2{-0.6, -0.8}, 1}},
+        // a center inside leaves by the nearest edge
+        {"center inside near the top", Circle{X: 2, Y: 1, R: 1}, true, Contact{mgl32.Vec2{0, 1}, 2}},
+        {"center inside near the right", Circle{X: 3.5, Y: 2, R: 1}, true, Contact{mgl32.Vec2{-1, 0}, 1.5}},
+    }
+    for _, tt := range tests {
+        c, ok := RectCircle(r, tt.c)
+        if ok != tt.overlap || !c.Normal.ApproxEqual(tt.want.Normal) || !mgl32.FloatEqual(c.Depth, tt.want.Depth) {
+            t.Errorf("%s: overlap %v with %+v, want %v with %+v", tt.name, ok, c, tt.overlap, tt.want)
+        }
+    }
+}
@@ -0,0 +1,79 @@
+package collide
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "math"
+)
+
+// Hit describes where a swept rectangle first touches an obstacle.
+type Hit struct {
+    Time   float32    // the fraction of the motion made before the contact, from 0 to 1
+    Normal mgl32.Vec2 // the normal of the surface hit, pointing out of the obstacle
+}
+
+// Sweep reports whether r, moving by motion, hits obstacle, and if so when
+// and where. Only motion into the obstacle counts: shapes that merely
+// touch, or slide along each other, do not hit, and a rectangle that already
+// overlaps the obstacle hits it at time 0 only while moving further in, so
+// that it can always get out. If it reaches an edge and a corner at the same
+// time, the hit is on the top or bottom, so a body landing exactly on a
+// corner stands on it.
+func Sweep(r gome.Rect, motion mgl32.Vec2, obstacle gome.Rect) (Hit, bool) {
+    lo := [2]float32{r.X, r.Y}
+    hi := [2]float32{r.X + r.W, r.Y + r.H}
+    olo := [2]float32{obstacle.X, obstacle.Y}
+    ohi := [2]float32{obstacle.X + obstacle.W, obstacle.Y + obstacle.H}
+
+    entry := [2]float32{}
+    exit := [2]float32{}
+    overlapping := true
+    for i := 0; i < 2; i++ {
+        m := motion[i]
+        if m == 0 {
+            // no motion on this axis, so it has to overlap all the way
+            if hi[i] <= olo[i]+Epsilon || lo[i] >= ohi[i]-Epsilon {
+                return Hit{}, false
+            }
+            entry[i], exit[i] = float32(math.Inf(-1)), float32(math.Inf(1))
+            continue
+        }
+        // the distances to the near and far side of the obstacle
+        near, far := olo[i]-hi[i], ohi[i]-lo[i]
+        if m < 0 {
+            near, far = lo[i]-ohi[i], hi[i]-olo[i]
+        }
+        if near < 0 && near > -Epsilon {
+            // touching within rounding errors
+            near = 0
+        }
+        if near >= 0 {
+            overlapping = false
+        }
+        abs := float32(math.Abs(float64(m)))
+        entry[i], exit[i] = near/abs, far/abs
+    }
+
+    if overlapping {
+        c, ok := RectRect(r, obstacle)
+        if !ok || motion.Dot(c.Normal) >= 0 {
+            return Hit{}, false
+        }
+        return Hit{0, c.Normal}, true
+    }
+    t := max(entry[0], entry[1])
+    if t > min(exit[0], exit[1]) || t > 1 || t < 0 {
+        return Hit{}, false
+    }
+    axis := 1
+    if entry[0] > entry[1] {
+        axis = 0
+    }
+    var n mgl32.Vec2
+    if motion[axis] > 0 {
+        n[axis] = -1
+    } else {
+        n[axis] = 1
+    }
+    return Hit{t, n}, true
+}
@@ -0,0 +1,50 @@
+package collide
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "testing"
+)
+
+func TestSweep(t *testing.T) {
+    unit := gome.Rect{W: 1, H: 1}
+    // less than Epsilon
+    const sliver = 1.0 / 2048
+    tests := []struct {
+        name     string
+        r        gome.Rect
+        motion   mgl32.Vec2
+        obstacle gome.Rect
+        hit      bool
+        want     Hit
+    }{
+        {"from the left", unit, mgl32.Vec2{2, 0}, gome.Rect{X: 2, W: 1, H: 1}, true, Hit{0.5, mgl32.Vec2{-1, 0}}},
+        {"from above", unit, mgl32.Vec2{0, 4}, gome.Rect{Y: 2, W: 1, H: 1}, true, Hit{0.25, mgl32.Vec2{0, -1}}},
+        {"from the right", unit, mgl32.Vec2{-4, 0}, gome.Rect{X: -3, W: 1, H: 1}, true, Hit{0.5, mgl32.Vec2{1, 0}}},
+        {"missing", unit, mgl32.Vec2{2, 0}, gome.Rect{Y: 2, W: 1, H: 1}, false, Hit{}},
+        {"stopping short", unit, mgl32.Vec2{0.5, 0}, gome.Rect{X: 2, W: 1, H: 1}, false, Hit{}},
+
+        // touching is not overlapping
+        {"sliding along", unit, mgl32.Vec2{2, 0}, gome.Rect{Y: 1, W: 4, H: 1}, false, Hit{}},
+        {"sliding along within Epsilon", unit, mgl32.Vec2{2, 0}, gome.Rect{Y: 1 - sliver, W: 4, H: 1}, false, Hit{}},
+        {"touching, moving in", unit, mgl32.Vec2{1, 0}, gome.Rect{X: 1, W: 1, H: 1}, true, Hit{0, mgl32.Vec2{-1, 0}}},
+        {"touching within Epsilon, moving in", unit, mgl32.Vec2{1, 0}, gome.Rect{X: 1 - sliver, W: 1, H: 1}, true, Hit{0, mgl32.Vec2{-1, 0}}},
+        {"touching, moving away", unit, mgl32.Vec2{-1, 0}, gome.Rect{X: 1, W: 1, H: 1}, false, Hit{}},
+
+        // edges reached at the same time go to the top or bottom
+        {"corner from above", unit, mgl32.Vec2{2, 2}, gome.Rect{X: 2, Y: 2, W: 1, H: 1}, true, Hit{0.5, mgl32.Vec2{0, -1}}},
+        {"corner from below", unit, mgl32.Vec2{2, -2}, gome.Rect{X: 2, Y: -2, W: 1, H: 1}, true, Hit{0.5, mgl32.Vec2{0, 1}}},
+        {"corner from the left", unit, mgl32.Vec2{2, 1}, gome.Rect{X: 2, Y: 1.25, W: 1, H: 1}, true, Hit{0.5, mgl32.Vec2{-1, 0}}},
+
+        // an overlapping rectangle can always get out
+        {"escaping an overlap", gome.Rect{W: 2, H: 2}, mgl32.Vec2{-1, 0}, gome.Rect{X: 1, W: 2, H: 2}, false, Hit{}},
+        {"escaping an overlap sideways", gome.Rect{W: 2, H: 2}, mgl32.Vec2{0, 1}, gome.Rect{X: 1, W: 2, H: 2}, false, Hit{}},
+        {"moving into an overlap", gome.Rect{W: 2, H: 2}, mgl32.Vec2{1, 0}, gome.Rect{X: 1, W: 2, H: 2}, true, Hit{0, mgl32.Vec2{-1, 0}}},
+    }
+    for _, tt := range tests {
+        h, ok := Sweep(tt.r, tt.motion, tt.obstacle)
+        if ok != tt.hit || h != tt.want {
+            t.Errorf("%s: hit %v at %+v, want %v at %+v", tt.name, ok, h, tt.hit, tt.want)
+        }
+    }
+}
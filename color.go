@@ -0,0 +1,262 @@
+package gome
+
+import (
+    "errors"
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome/internal/gl"
+    "image/color"
+    "math"
+    "strconv"
+    "strings"
+)
+
+var ErrHexColor = errors.New("invalid hex colour")
+
+// Color is a colour with red, green, blue and alpha components from 0 to 1,
+// not premultiplied by alpha. It implements color.Color, so it can be used
+// wherever gome takes one, such as SpriteBatch.Draw, without conversion.
+type Color struct {
+    R, G, B, A float32
+}
+
+// Common colours.
+var (
+    Transparent = Color{0, 0, 0, 0}
+    Black       = Color{0, 0, 0, 1}
+    White       = Color{1, 1, 1, 1}
+)
+
+// RGB returns the opaque colour with the components r, g and b.
+func RGB(r, g, b float32) Color {
+    return Color{r, g, b, 1}
+}
+
+// Hex parses a colour in CSS hex notation: #rgb, #rgba, #rrggbb or
+// #rrggbbaa, with or without the leading #. It returns ErrHexColor if s is
+// not in one of these forms.
+func Hex(s string) (Color, error) {
+    s = strings.TrimPrefix(s, "#")
+    if len(s) == 3 || len(s) == 4 {
+        // a single digit d stands for dd
+        long := make([]byte, 0, 8)
+        for i := 0; i < len(s); i++ {
+            long = append(long, s[i], s[i])
+        }
+        s = string(long)
+    }
+    if len(s) == 6 {
+        s += "ff"
+    }
+    if len(s) != 8 {
+        return Color{}, ErrHexColor
+    }
+    v, err := strconv.ParseUint(s, 16, 32)
+    if err != nil {
+        return Color{}, ErrHexColor
+    }
+    return Color{
+        float32(v>>24&0xff) / 255,
+        float32(v>>16&0xff) / 255,
+        float32(v>>8&0xff) / 255,
+        float32(v&0xff) / 255,
+    }, nil
+}
+
+// MustHex is like Hex, but panics if s is invalid. It is meant for colour
+// constants in the source.
+func MustHex(s string) Color {
+    c, err := Hex(s)
+    if err != nil {
+        panic("gome: invalid hex colour " + strconv.Quote(s))
+    }
+    return c
+}
+
+// HSV returns the opaque colour with the hue h in degrees, wrapping around
+// at 360, and the saturation s and value v from 0 to 1.
+func HSV(h, s, v float32) Color {
+    h = float32(math.Mod(float64(h), 360))
+    if h < 0 {
+        h += 360
+    }
+    c := v * s
+    x := c * (1 - float32(math.Abs(math.Mod(float64(h)/60, 2)-1)))
+    var r, g, b float32
+    switch {
+    case h < 60:
+        r, g = c, x
+    case h < 120:
+        r, g = x, c
+    case h < 180:
+        g, b = c, x
+    case h < 240:
+        g, b = x, c
+    case h < 300:
+        r, b = x, c
+    default:
+        r, b = c, x
+    }
+    m := v - c
+    return Color{r + m, g + m, b + m, 1}
+}
+
+// ColorOf converts c to a Color.
+func ColorOf(c color.Color) Color {
+    if g, ok := c.(Color); ok {
+        return g
+    }
+    n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+    return Color{
+        float32(n.R) / 0xffff,
+        float32(n.G) / 0xffff,
+        float32(n.B) / 0xffff,
+        float32(n.A) / 0xffff,
+    }
+}
+
+// RGBA implements color.Color. The components are clamped to 0..1 first.
+func (c Color) RGBA() (r, g, b, a uint32) {
+    c = c.Clamp()
+    a = uint32(c.A*0xffff + 0.5)
+    r = uint32(c.R*c.A*0xffff + 0.5)
+    g = uint32(c.G*c.A*0xffff + 0.5)
+    b = uint32(c.B*c.A*0xffff + 0.5)
+    return
+}
+
+// NRGBA returns c as an 8-bit color.NRGBA, clamped to 0..1.
+func (c Color) NRGBA() color.NRGBA {
+    c = c.Clamp()
+    return color.NRGBA{
+        uint8(c.R*255 + 0.5),
+        uint8(c.G*255 + 0.5),
+        uint8(c.B*255 + 0.5),
+        uint8(c.A*255 + 0.5),
+    }
+}
+
+// Vec4 returns the components of c as a vector, e.g. for a uniform.
+func (c Color) Vec4() mgl32.Vec4 {
+    return mgl32.Vec4{c.R, c.G, c.B, c.A}
+}
+
+// WithAlpha returns c with the alpha a.
+func (c Color) WithAlpha(a float32) Color {
+    c.A = a
+    return c
+}
+
+// Lerp returns the colour a fraction t of the way from c to d, interpolating
+// every component linearly.
+func (c Color) Lerp(d Color, t float32) Color {
+    return Color{
+        c.R + (d.R-c.R)*t,
+        c.G + (d.G-c.G)*t,
+        c.B + (d.B-c.B)*t,
+        c.A + (d.A-c.A)*t,
+    }
+}
+
+// Premultiply returns c with the red, green and blue components multiplied
+// by alpha, for blending with ONE, ONE_MINUS_SRC_ALPHA.
+func (c Color) Premultiply() Color {
+    return Color{c.R * c.A, c.G * c.A, c.B * c.A, c.A}
+}
+
+// Clamp returns c with every component clamped to 0..1.
+func (c Color) Clamp() Color {
+    return Color{clamp01(c.R), clamp01(c.G), clamp01(c.B), clamp01(c.A)}
+}
+
+func clamp01(f float32) float32 {
+    if f < 0 {
+        return 0
+    } else if f > 1 {
+        return 1
+    }
+    return f
+}
+
+// Palette is a list of colours, e.g. the colours of a gradient or of a
+// limited colour scheme.
+type Palette []Color
+
+// At returns the colour a fraction t of the way along the gradient through
+// the colours of p, evenly spaced, with t clamped to 0..1. An empty palette
+// gives Transparent.
+func (p Palette) At(t float32) Color {
+    switch len(p) {
+    case 0:
+        return Transparent
+    case 1:
+        return p[0]
+    }
+    f := clamp01(t) * float32(len(p)-1)
+    i := int(f)
+    if i >= len(p)-1 {
+        return p[len(p)-1]
+    }
+    return p[i].Lerp(p[i+1], f-float32(i))
+}
+
+// Nearest returns the index of the colour of p closest to c, by the distance
+// of the components, or -1 if p is empty.
+func (p Palette) Nearest(c Color) int {
+    best, bestDist := -1, float32(math.Inf(1))
+    for i, q := range p {
+        dr, dg, db, da := q.R-c.R, q.G-c.G, q.B-c.B, q.A-c.A
+        if d := dr*dr + dg*dg + db*db + da*da; d < bestDist {
+            best, bestDist = i, d
+        }
+    }
+    return best
+}
+
+var clearing struct {
+    color Color
+    auto  bool
+}
+
+// SetClearColor sets the colour the screen is cleared to by glClear, as
+// glClearColor does, and by Tick if SetAutoClear is on. It is kept when the
+// App is initialised again.
+func SetClearColor(c Color) {
+    clearing.color = c
+    if app != nil {
+        gl.ClearColor(c.R, c.G, c.B, c.A)
+    }
+}
+
+// ClearColor returns the colour set by SetClearColor, Transparent by default.
+func ClearColor() Color {
+    return clearing.color
+}
+
+// SetAutoClear sets whether the screen (see BindScreen) is cleared to the
+// clear colour, with the depth and stencil buffers, at the start of every
+// frame, so the application only has to draw: by App.Init for the first
+// frame and then at the end of every Tick for the next one.
+func SetAutoClear(on bool) {
+    clearing.auto = on
+}
+
+// AutoClear returns the setting of SetAutoClear.
+func AutoClear() bool {
+    return clearing.auto
+}
+
+// applyClearColor sets the clear colour of a new context.
+func applyClearColor() {
+    c := clearing.color
+    gl.ClearColor(c.R, c.G, c.B, c.A)
+}
+
+// clearScreen clears the screen for the next frame if SetAutoClear is on. It
+// is called by App.Init and at the end of Tick.
+func clearScreen() {
+    if !clearing.auto {
+        return
+    }
+    BindScreen()
+    gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
+}
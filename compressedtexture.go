@@ -0,0 +1,213 @@
+package gome
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+    "io"
+    "io/fs"
+)
+
+var (
+    ErrTextureContainer = errors.New("not a KTX, KTX2 or DDS file")
+    ErrTextureFormat    = errors.New("compressed texture format not supported by the context")
+)
+
+// compressedImage is the data of a compressed texture read from a container,
+// with the mipmap levels from the largest down.
+type compressedImage struct {
+    format        uint32 // the GL internal format
+    width, height int
+    levels        [][]byte
+}
+
+// blockFormat describes a block-compressed format.
+type blockFormat struct {
+    blockSize int    // bytes per 4x4 block
+    srgb      uint32 // the sRGB variant, if there is one
+}
+
+var blockFormats = map[uint32]blockFormat{
+    gl.COMPRESSED_RGB_S3TC_DXT1_EXT:              {8, gl.COMPRESSED_SRGB_S3TC_DXT1_EXT},
+    gl.COMPRESSED_RGBA_S3TC_DXT1_EXT:             {8, gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT},
+    gl.COMPRESSED_RGBA_S3TC_DXT3_EXT:             {16, gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT},
+    gl.COMPRESSED_RGBA_S3TC_DXT5_EXT:             {16, gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT},
+    gl.COMPRESSED_SRGB_S3TC_DXT1_EXT:             {8, 0},
+    gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT:       {8, 0},
+    gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT:       {16, 0},
+    gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT:       {16, 0},
+    gl.COMPRESSED_RED_RGTC1:                      {8, 0},
+    gl.COMPRESSED_SIGNED_RED_RGTC1:               {8, 0},
+    gl.COMPRESSED_RG_RGTC2:                       {16, 0},
+    gl.COMPRESSED_SIGNED_RG_RGTC2:                {16, 0},
+    gl.COMPRESSED_RGBA_BPTC_UNORM_ARB:            {16, gl.COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB},
+    gl.COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB:      {16, 0},
+    gl.COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB:    {16, 0},
+    gl.COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB:      {16, 0},
+    gl.COMPRESSED_RGB8_ETC2:                      {8, gl.COMPRESSED_SRGB8_ETC2},
+    gl.COMPRESSED_SRGB8_ETC2:                     {8, 0},
+    gl.COMPRESSED_RGB8_PUNCHTHROUGH_ALPHA1_ETC2:  {8, gl.COMPRESSED_SRGB8_PUNCHTHROUGH_ALPHA1_ETC2},
+    gl.COMPRESSED_SRGB8_PUNCHTHROUGH_ALPHA1_ETC2: {8, 0},
+    gl.COMPRESSED_RGBA8_ETC2_EAC:                 {16, gl.COMPRESSED_SRGB8_ALPHA8_ETC2_EAC},
+    gl.COMPRESSED_SRGB8_ALPHA8_ETC2_EAC:          {16, 0},
+    gl.COMPRESSED_R11_EAC:                        {8, 0},
+    gl.COMPRESSED_SIGNED_R11_EAC:                 {8, 0},
+    gl.COMPRESSED_RG11_EAC:                       {16, 0},
+    gl.COMPRESSED_SIGNED_RG11_EAC:                {16, 0},
+}
+
+// levelSize returns the size in bytes of a mipmap level of width by height
+// pixels in format.
+func (f blockFormat) levelSize(width, height int) int {
+    return (width + 3) / 4 * ((height + 3) / 4) * f.blockSize
+}
+
+// CompressedFormatSupported reports whether the context can use textures in
+// the compressed GL internal format, e.g. gl.COMPRESSED_RGBA_S3TC_DXT5_EXT.
+// Desktop drivers usually have the BC formats (DXT, RGTC and BPTC) and newer
+// ones ETC2 too, while mobile and ES drivers usually only have ETC2, so
+// applications shipping to both need both.
+func CompressedFormatSupported(format uint32) bool {
+    i := glInfo
+    es := i.Profile == ESProfile
+    atLeast := func(major, minor int) bool {
+        return i.Major > major || i.Major == major && i.Minor >= minor
+    }
+    switch format {
+    case gl.COMPRESSED_RGB_S3TC_DXT1_EXT, gl.COMPRESSED_RGBA_S3TC_DXT1_EXT,
+        gl.COMPRESSED_RGBA_S3TC_DXT3_EXT, gl.COMPRESSED_RGBA_S3TC_DXT5_EXT:
+        return HasExtension("GL_EXT_texture_compression_s3tc")
+    case gl.COMPRESSED_SRGB_S3TC_DXT1_EXT, gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT,
+        gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT, gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT:
+        return HasExtension("GL_EXT_texture_compression_s3tc") &&
+            (HasExtension("GL_EXT_texture_sRGB") || HasExtension("GL_EXT_texture_compression_s3tc_srgb"))
+    case gl.COMPRESSED_RED_RGTC1, gl.COMPRESSED_SIGNED_RED_RGTC1,
+        gl.COMPRESSED_RG_RGTC2, gl.COMPRESSED_SIGNED_RG_RGTC2:
+        if es {
+            return HasExtension("GL_EXT_texture_compression_rgtc")
+        }
+        return true
+    case gl.COMPRESSED_RGBA_BPTC_UNORM_ARB, gl.COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB,
+        gl.COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB, gl.COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB:
+        if es {
+            return HasExtension("GL_EXT_texture_compression_bptc")
+        }
+        return atLeast(4, 2) || HasExtension("GL_ARB_texture_compression_bptc")
+    }
+    if _, ok := blockFormats[format]; ok {
+        // ETC2 and EAC
+        return es || atLeast(4, 3) || HasExtension("GL_ARB_ES3_compatibility")
+    }
+    return false
+}
+
+// LoadCompressedTexture reads the KTX, KTX2 or DDS file at path in the asset
+// file system (see SetAssetFS) and uploads its block-compressed data as is,
+// which takes a fraction of the time of decoding a PNG and uses less video
+// memory. The mipmaps stored in the file are uploaded too; they cannot be
+// generated for compressed textures, so the mipmap options only pick a
+// mipmap filter if there are any. With opts.SRGB, a format that has an sRGB
+// variant is uploaded as that. ErrTextureFormat is returned if the context
+// does not support the format (see CompressedFormatSupported), and
+// ErrTextureContainer if the file is none of the containers. KTX2 files with
+// supercompression, such as Basis Universal, are not supported.
+func LoadCompressedTexture(path string, opts *TextureOptions) (*Texture, error) {
+    return LoadCompressedTextureFS(assetFS, path, opts)
+}
+
+// LoadCompressedTextureFS is like LoadCompressedTexture but reads the file
+// from fsys.
+func LoadCompressedTextureFS(fsys fs.FS, path string, opts *TextureOptions) (*Texture, error) {
+    data, err := readFile(fsys, path)
+    if err != nil {
+        return nil, err
+    }
+    t, err := newCompressedTexture(data, opts)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %v", path, err)
+    }
+    return t, nil
+}
+
+// ReadCompressedTexture reads a KTX, KTX2 or DDS file from r and uploads it
+// (see LoadCompressedTexture).
+func ReadCompressedTexture(r io.Reader, opts *TextureOptions) (*Texture, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return nil, err
+    }
+    return newCompressedTexture(data, opts)
+}
+
+// parseCompressedTexture parses the container of data.
+func parseCompressedTexture(data []byte) (*compressedImage, error) {
+    switch {
+    case bytes.HasPrefix(data, ktx1Identifier):
+        return parseKTX(data)
+    case bytes.HasPrefix(data, ktx2Identifier):
+        return parseKTX2(data)
+    case bytes.HasPrefix(data, []byte("DDS ")):
+        return parseDDS(data)
+    }
+    return nil, ErrTextureContainer
+}
+
+func newCompressedTexture(data []byte, opts *TextureOptions) (*Texture, error) {
+    img, err := parseCompressedTexture(data)
+    if err != nil {
+        return nil, err
+    }
+    o := TextureOptions{}
+    if opts != nil {
+        o = *opts
+    }
+    format := img.format
+    if f := blockFormats[format]; o.SRGB && f.srgb != 0 {
+        format = f.srgb
+    }
+    if !CompressedFormatSupported(format) {
+        return nil, ErrTextureFormat
+    }
+    // the mipmaps come from the file, or there are none
+    if !o.mipmapped() {
+        img.levels = img.levels[:1]
+    } else if len(img.levels) > 1 && o.MinFilter == 0 {
+        o.MinFilter = gl.LINEAR_MIPMAP_LINEAR
+    }
+    o.Mipmaps, o.CPUMipmaps = false, false
+
+    t := NewTexture()
+    t.Width, t.Height = img.width, img.height
+    BindTexture(0, gl.TEXTURE_2D, t)
+    w, h := img.width, img.height
+    for level, pix := range img.levels {
+        gl.CompressedTexImage2D(gl.TEXTURE_2D, int32(level), format, int32(w), int32(h), 0,
+            int32(len(pix)), gl.Ptr(pix))
+        w, h = maxInt(w/2, 1), maxInt(h/2, 1)
+    }
+    // a partial chain of levels is complete up to the last one
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, int32(len(img.levels)-1))
+    o.apply(gl.TEXTURE_2D)
+    return t, nil
+}
+
+// splitLevels cuts the mipmap levels of an image of width by height pixels
+// in format out of data, which holds count of them one after another.
+func splitLevels(format uint32, width, height, count int, data []byte) ([][]byte, error) {
+    f, ok := blockFormats[format]
+    if !ok {
+        return nil, fmt.Errorf("unsupported format 0x%04X", format)
+    }
+    var levels [][]byte
+    for i := 0; i < count; i++ {
+        n := f.levelSize(width, height)
+        if n > len(data) {
+            return nil, errors.New("truncated image data")
+        }
+        levels = append(levels, data[:n:n])
+        data = data[n:]
+        width, height = maxInt(width/2, 1), maxInt(height/2, 1)
+    }
+    return levels, nil
+}
@@ -0,0 +1,129 @@
+package gome
+
+import (
+    "errors"
+    "github.com/snorredc/gome/internal/gl"
+)
+
+var ErrComputeUnsupported = errors.New("compute shaders need OpenGL 4.3, OpenGL ES 3.1 or GL_ARB_compute_shader")
+
+// ComputeSupported reports whether the context can run compute shaders and
+// has shader storage buffers: OpenGL 4.3 or later, OpenGL ES 3.1 or later, or
+// an older context with the extensions. ModernContexts asks for contexts new
+// enough; the default 3.2 core context usually only has them as extensions.
+func ComputeSupported() bool {
+    i := glInfo
+    if i.Profile == ESProfile {
+        return i.Major > 3 || i.Major == 3 && i.Minor >= 1
+    }
+    if i.Major > 4 || i.Major == 4 && i.Minor >= 3 {
+        return true
+    }
+    return HasExtension("GL_ARB_compute_shader") && HasExtension("GL_ARB_shader_storage_buffer_object")
+}
+
+// ComputeProgram is a program of a single compute shader.
+type ComputeProgram struct {
+    *Program
+    LocalSize [3]int // the work group size declared by the shader
+}
+
+// NewComputeProgram compiles the compute shader source and links it into a
+// program. A source without a #version line gets the one for the context,
+// "#version 430" or "#version 310 es". ErrComputeUnsupported is returned if
+// the context has no compute shaders (see ComputeSupported), and a
+// *ShaderError if the shader fails to compile or link.
+func NewComputeProgram(source string) (*ComputeProgram, error) {
+    if !ComputeSupported() {
+        return nil, ErrComputeUnsupported
+    }
+    s, err := compileShader(gl.COMPUTE_SHADER, "compute", source)
+    if err != nil {
+        return nil, err
+    }
+    defer gl.DeleteShader(s)
+    p := gl.CreateProgram()
+    gl.AttachShader(p, s)
+    gl.LinkProgram(p)
+    var status int32
+    gl.GetProgramiv(p, gl.LINK_STATUS, &status)
+    if status != gl.TRUE {
+        err := &ShaderError{"link", programInfoLog(p)}
+        gl.DeleteProgram(p)
+        return nil, err
+    }
+    logShaderWarnings("link", programInfoLog(p))
+
+    prog := &ComputeProgram{Program: &Program{p}}
+    trackObject("program", uint(p))
+    var size [3]int32
+    gl.GetProgramiv(p, gl.COMPUTE_WORK_GROUP_SIZE, &size[0])
+    for i, n := range size {
+        prog.LocalSize[i] = int(n)
+    }
+    return prog, nil
+}
+
+// Dispatch uses the program and runs x by y by z work groups of LocalSize
+// invocations each. The shader runs asynchronously; a Barrier orders its
+// writes before the commands that read them.
+func (p *ComputeProgram) Dispatch(x, y, z int) {
+    UseProgram(p.Program)
+    gl.DispatchCompute(uint32(x), uint32(y), uint32(z))
+}
+
+// DispatchSize is like Dispatch but takes the number of invocations on each
+// axis, such as the size of an image, rounded up to whole work groups. The
+// shader has to skip the invocations beyond the size.
+func (p *ComputeProgram) DispatchSize(width, height, depth int) {
+    groups := func(n, local int) int {
+        if local <= 0 {
+            local = 1
+        }
+        return (n + local - 1) / local
+    }
+    p.Dispatch(groups(width, p.LocalSize[0]), groups(height, p.LocalSize[1]), groups(depth, p.LocalSize[2]))
+}
+
+// Barrier is a set of ways of reading memory written by shaders, for
+// MemoryBarrier.
+type Barrier uint32
+
+const (
+    VertexBarrier  Barrier = gl.VERTEX_ATTRIB_ARRAY_BARRIER_BIT // vertex attributes from buffers
+    IndexBarrier   Barrier = gl.ELEMENT_ARRAY_BARRIER_BIT       // indices from buffers
+    TextureBarrier Barrier = gl.TEXTURE_FETCH_BARRIER_BIT       // sampling textures
+    ImageBarrier   Barrier = gl.SHADER_IMAGE_ACCESS_BARRIER_BIT // image load and store in shaders
+    StorageBarrier Barrier = gl.SHADER_STORAGE_BARRIER_BIT      // storage buffers in shaders
+    BufferBarrier  Barrier = gl.BUFFER_UPDATE_BARRIER_BIT       // reading buffers back and mapping them
+    PixelBarrier   Barrier = gl.TEXTURE_UPDATE_BARRIER_BIT      // reading textures back
+    AllBarriers    Barrier = gl.ALL_BARRIER_BITS
+)
+
+// MemoryBarrier makes the writes of earlier shaders to storage buffers and
+// images visible to later commands reading them in the ways b, e.g.
+// MemoryBarrier(VertexBarrier) between a compute shader updating particles
+// and drawing them.
+func MemoryBarrier(b Barrier) {
+    gl.MemoryBarrier(uint32(b))
+}
+
+// ImageAccess is how a shader accesses an image bound by BindImage.
+type ImageAccess uint32
+
+const (
+    ReadOnly  ImageAccess = gl.READ_ONLY
+    WriteOnly ImageAccess = gl.WRITE_ONLY
+    ReadWrite ImageAccess = gl.READ_WRITE
+)
+
+// BindImage binds level 0 of t to the image unit for image load and store in
+// shaders, as format, such as gl.RGBA8 or gl.RGBA32F, which has to match the
+// format qualifier of the image in the shader. A nil t unbinds the unit.
+func BindImage(unit int, t *Texture, access ImageAccess, format uint32) {
+    var id uint32
+    if t != nil {
+        id = t.ID
+    }
+    gl.BindImageTexture(uint32(unit), id, 0, false, 0, uint32(access), format)
+}
@@ -0,0 +1,73 @@
+package gome
+
+// Config holds the settings of an App (see NewApp). The zero value gives the
+// defaults.
+type Config struct {
+    Width, Height int    // size of the main window; defaults to 800x600
+    Title         string // title of the main window; defaults to "Gome"
+
+    // Hidden keeps the main window from being shown, for tests and tools
+    // that only render offscreen. Window.Show shows it after all.
+    Hidden bool
+
+    // WindowStateFile, if set, is the path of a file that the position and
+    // size of the main window are restored from by App.Init and saved to by
+    // App.Terminate (see RestoreWindowState).
+    WindowStateFile string
+
+    // SRGB requests a default framebuffer that converts linear colours to
+    // sRGB on writes and enables GL_FRAMEBUFFER_SRGB, for a gamma-correct
+    // pipeline: shaders then output linear colours, and colour textures
+    // should be uploaded with TextureOptions.SRGB so they are converted to
+    // linear colours when sampled.
+    SRGB bool
+
+    // Samples requests a multisampled default framebuffer with that many
+    // samples per pixel for antialiasing. Drivers may give fewer or more;
+    // Samples reports the number obtained.
+    Samples int
+
+    // Contexts lists the OpenGL versions and profiles to try, in order of
+    // preference; App.Init uses the first one the driver can create (see
+    // Context). It defaults to DefaultContexts, 3.2 core; ModernContexts
+    // tries newer versions first and falls back to a compatibility profile.
+    Contexts []ContextVersion
+
+    // CrashReportFile is the path Recover writes crash reports to. It
+    // defaults to gome-crash-<date>-<time>.txt in the working directory.
+    // CrashScreenshot additionally saves the last frame shown next to it,
+    // as a PNG file with the same name.
+    CrashReportFile string
+    CrashScreenshot bool
+
+    // RenderDoc loads the RenderDoc library at startup if it is installed,
+    // so that frames can be captured without launching the application from
+    // RenderDoc (see TriggerCapture). The library has to be on the library
+    // search path, e.g. in LD_LIBRARY_PATH or next to the executable.
+    RenderDoc bool
+
+    // DebugContext requests an OpenGL debug context and logs its debug
+    // output (see SetLogger): errors, undefined behaviour and performance
+    // warnings the driver reports as they happen, which helps most when a
+    // window stays black. It needs OpenGL 4.3, GL_KHR_debug or OpenGL ES
+    // 3.2, and slows the driver down, so it is meant for development.
+    DebugContext bool
+}
+
+// The number of samples of the default framebuffer, queried by App.Init.
+var obtainedSamples int
+
+// Samples returns the number of samples per pixel of the default framebuffer
+// of the main window, or 0 if it is not multisampled (see Config.Samples).
+func Samples() int {
+    return obtainedSamples
+}
+
+func (c *Config) setDefaults() {
+    if c.Width <= 0 || c.Height <= 0 {
+        c.Width, c.Height = 800, 600
+    }
+    if c.Title == "" {
+        c.Title = "Gome"
+    }
+}
@@ -0,0 +1,130 @@
+package gome
+
+import (
+    "fmt"
+    "github.com/snorredc/gome/internal/glfw"
+    "log/slog"
+)
+
+// ContextProfile is the kind of OpenGL context of a ContextVersion.
+type ContextProfile int
+
+const (
+    CoreProfile          ContextProfile = iota // forward-compatible core profile
+    CompatibilityProfile                       // compatibility profile, or any profile before 3.2
+    ESProfile                                  // OpenGL ES
+)
+
+func (p ContextProfile) String() string {
+    switch p {
+    case CoreProfile:
+        return "core"
+    case CompatibilityProfile:
+        return "compatibility"
+    case ESProfile:
+        return "ES"
+    }
+    return fmt.Sprintf("ContextProfile(%d)", int(p))
+}
+
+// ContextVersion is an OpenGL version and profile to request.
+type ContextVersion struct {
+    Major, Minor int
+    Profile      ContextProfile
+}
+
+func (v ContextVersion) String() string {
+    return fmt.Sprintf("%d.%d %s", v.Major, v.Minor, v.Profile)
+}
+
+// DefaultContexts is the context requested when Config.Contexts is empty.
+var DefaultContexts = []ContextVersion{{3, 2, CoreProfile}}
+
+// ModernContexts tries the core profiles from 4.6 down to 3.2, then 3.2 with
+// the compatibility profile for drivers that refuse forward-compatible
+// contexts.
+var ModernContexts = []ContextVersion{
+    {4, 6, CoreProfile},
+    {4, 5, CoreProfile},
+    {4, 3, CoreProfile},
+    {4, 1, CoreProfile},
+    {3, 3, CoreProfile},
+    {3, 2, CoreProfile},
+    {3, 2, CompatibilityProfile},
+}
+
+// ESContexts requests OpenGL ES 3.0. Building with the gles tag makes it the
+// default. Shaders get the ES version and precision headers (see
+// NewProgram), and features ES lacks, such as GPU zones, are left out.
+//
+// This is not a full ES backend: gome is built with the desktop OpenGL 3.2
+// core bindings, which need every entry point of desktop OpenGL 3.2, so ES
+// contexts only work with drivers that provide those as well, such as Mesa.
+// Drivers that only implement ES, such as ANGLE and those of most ARM
+// single-board computers, are not supported.
+var ESContexts = []ContextVersion{{3, 0, ESProfile}}
+
+// The context version obtained by Init.
+var obtainedContext ContextVersion
+
+// Context returns the version and profile of the context of the main window:
+// the first one of Config.Contexts that could be created.
+func Context() ContextVersion {
+    return obtainedContext
+}
+
+// windowHints sets the window hints for the context v and the options of c.
+func windowHints(c *Config, v ContextVersion) {
+    glfw.DefaultWindowHints()
+    glfw.WindowHint(glfw.ContextVersionMajor, v.Major)
+    glfw.WindowHint(glfw.ContextVersionMinor, v.Minor)
+    switch v.Profile {
+    case CoreProfile:
+        glfw.WindowHint(glfw.ClientAPI, glfw.OpenGLAPI)
+        glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+        glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+    case CompatibilityProfile:
+        glfw.WindowHint(glfw.ClientAPI, glfw.OpenGLAPI)
+        if v.Major > 3 || v.Major == 3 && v.Minor >= 2 {
+            glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCompatProfile)
+        }
+    case ESProfile:
+        glfw.WindowHint(glfw.ClientAPI, glfw.OpenGLESAPI)
+    }
+
+    if c.SRGB {
+        glfw.WindowHint(glfw.SRGBCapable, glfw.True)
+    }
+    if c.Samples > 0 {
+        glfw.WindowHint(glfw.Samples, c.Samples)
+    }
+    if c.DebugContext {
+        glfw.WindowHint(glfw.OpenGLDebugContext, glfw.True)
+    }
+    if c.Hidden || c.WindowStateFile != "" {
+        // a restored window is shown once restored, not to jump
+        glfw.WindowHint(glfw.Visible, glfw.False)
+    }
+}
+
+// createWindow creates the main window with the first context of c.Contexts
+// that the driver supports.
+func createWindow(c *Config) (*glfw.Window, error) {
+    versions := c.Contexts
+    if len(versions) == 0 {
+        versions = DefaultContexts
+    }
+    var err error
+    for _, v := range versions {
+        windowHints(c, v)
+        var w *glfw.Window
+        w, err = glfw.CreateWindow(c.Width, c.Height, c.Title, nil, nil)
+        if err == nil {
+            obtainedContext = v
+            logAt(slog.LevelInfo, "window created", "context", v, "width", c.Width, "height", c.Height)
+            return w, nil
+        }
+        logAt(slog.LevelDebug, "OpenGL context not available", "context", v, "err", err)
+    }
+    return nil, fmt.Errorf("no OpenGL context among %v: %v", versions, err)
+}
@@ -0,0 +1,7 @@
+// +build gles
+
+package gome
+
+func init() {
+    DefaultContexts = ESContexts
+}
@@ -0,0 +1,128 @@
+package gome
+
+import (
+    "bytes"
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+    "image/png"
+    "log/slog"
+    "os"
+    "path/filepath"
+    "runtime/debug"
+    "strings"
+    "time"
+)
+
+// recentErrors is the number of GL errors kept for crash reports.
+const recentErrors = 16
+
+type glErrorRecord struct {
+    frame uint64
+    err   glError
+}
+
+var glErrorLog struct {
+    records [recentErrors]glErrorRecord
+    n       int // the number of errors logged, of which the last recentErrors are kept
+}
+
+func noteGLError(e glError) {
+    glErrorLog.records[glErrorLog.n%recentErrors] = glErrorRecord{stats.frames, e}
+    glErrorLog.n++
+}
+
+// Recover writes a crash report if the program panics and then terminates the
+// App, so the window and context are torn down cleanly. It has to be
+// deferred directly in main, after App.Terminate so that it runs first:
+//
+//     func main() {
+//         app := gome.NewApp(gome.Config{CrashScreenshot: true})
+//         if err := app.Init(); err != nil {
+//             log.Fatal(err)
+//         }
+//         defer app.Terminate()
+//         defer gome.Recover()
+//         ...
+//     }
+//
+// The report, written to Config.CrashReportFile, holds the panic value and
+// stack, the most recent OpenGL errors and GLInfo, and if
+// Config.CrashScreenshot is set, the last frame is saved as well. Afterwards
+// the panic continues, so the program still exits with the stack trace.
+func Recover() {
+    r := recover()
+    if r == nil {
+        return
+    }
+    stack := debug.Stack()
+    path := crashReportPath()
+    if err := writeCrashReport(path, r, stack); err != nil {
+        logAt(slog.LevelError, "writing crash report", "err", err)
+    } else {
+        logAt(slog.LevelError, "crash report written", "path", path, "panic", r)
+    }
+    if app != nil {
+        if app.Config.CrashScreenshot && app.window != nil {
+            shot := strings.TrimSuffix(path, filepath.Ext(path)) + ".png"
+            if err := saveScreenshot(shot); err != nil {
+                logAt(slog.LevelError, "saving crash screenshot", "err", err)
+            }
+        }
+        app.Terminate()
+    }
+    panic(r)
+}
+
+func crashReportPath() string {
+    if app != nil && app.Config.CrashReportFile != "" {
+        return app.Config.CrashReportFile
+    }
+    return time.Now().Format("gome-crash-20060102-150405.txt")
+}
+
+func writeCrashReport(path string, r interface{}, stack []byte) error {
+    var b bytes.Buffer
+    fmt.Fprintf(&b, "gome crash report, %s\n\n", time.Now().Format(time.RFC3339))
+    fmt.Fprintf(&b, "panic: %v\n\n%s\n", r, stack)
+    fmt.Fprintf(&b, "frame: %d\n", stats.frames)
+    if app != nil && app.window != nil {
+        fmt.Fprintf(&b, "context: %v\n", Context())
+        fmt.Fprintf(&b, "%v\n", GLInfo())
+    } else {
+        fmt.Fprintf(&b, "no App was initialised\n")
+    }
+
+    fmt.Fprintf(&b, "\nOpenGL errors: %d\n", stats.glErrors)
+    start := 0
+    if glErrorLog.n > recentErrors {
+        start = glErrorLog.n - recentErrors
+    }
+    for i := start; i < glErrorLog.n; i++ {
+        rec := glErrorLog.records[i%recentErrors]
+        fmt.Fprintf(&b, "    frame %d: %v\n", rec.frame, rec.err)
+    }
+    return os.WriteFile(path, b.Bytes(), 0666)
+}
+
+// saveScreenshot saves the front buffer of the main window, the last frame
+// shown, as a PNG file.
+func saveScreenshot(path string) error {
+    w, h := app.window.GetFramebufferSize()
+    if w <= 0 || h <= 0 {
+        return nil
+    }
+    BindFramebuffer(nil)
+    gl.ReadBuffer(gl.FRONT)
+    img := readPixels(w, h)
+    gl.ReadBuffer(gl.BACK)
+
+    f, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    if err := png.Encode(f, img); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
@@ -0,0 +1,164 @@
+package gome
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "math"
+)
+
+// AABB is an axis-aligned bounding box from the corner Min to the corner Max.
+type AABB struct {
+    Min, Max mgl32.Vec3
+}
+
+// Center returns the centre of b.
+func (b AABB) Center() mgl32.Vec3 {
+    return b.Min.Add(b.Max).Mul(0.5)
+}
+
+// Size returns the extent of b along each axis.
+func (b AABB) Size() mgl32.Vec3 {
+    return b.Max.Sub(b.Min)
+}
+
+// Contains reports whether p lies within b.
+func (b AABB) Contains(p mgl32.Vec3) bool {
+    return p[0] >= b.Min[0] && p[1] >= b.Min[1] && p[2] >= b.Min[2] &&
+        p[0] <= b.Max[0] && p[1] <= b.Max[1] && p[2] <= b.Max[2]
+}
+
+// Intersects reports whether b and c overlap.
+func (b AABB) Intersects(c AABB) bool {
+    return b.Min[0] <= c.Max[0] && c.Min[0] <= b.Max[0] &&
+        b.Min[1] <= c.Max[1] && c.Min[1] <= b.Max[1] &&
+        b.Min[2] <= c.Max[2] && c.Min[2] <= b.Max[2]
+}
+
+// Union returns the smallest box containing b and c.
+func (b AABB) Union(c AABB) AABB {
+    for i := 0; i < 3; i++ {
+        b.Min[i] = float32(math.Min(float64(b.Min[i]), float64(c.Min[i])))
+        b.Max[i] = float32(math.Max(float64(b.Max[i]), float64(c.Max[i])))
+    }
+    return b
+}
+
+// Transform returns the axis-aligned box around b transformed by m, e.g.
+// the box of a mesh in world coordinates from its box in model coordinates.
+func (b AABB) Transform(m mgl32.Mat4) AABB {
+    // the columns of m scaled by the extent of b along each axis, with the
+    // smaller and the larger end of each added to the translation
+    out := AABB{m.Col(3).Vec3(), m.Col(3).Vec3()}
+    for col := 0; col < 3; col++ {
+        for row := 0; row < 3; row++ {
+            e := m.At(row, col)
+            lo, hi := e*b.Min[col], e*b.Max[col]
+            if lo > hi {
+                lo, hi = hi, lo
+            }
+            out.Min[row] += lo
+            out.Max[row] += hi
+        }
+    }
+    return out
+}
+
+// BoundingSphere returns the sphere around b.
+func (b AABB) BoundingSphere() Sphere {
+    return Sphere{b.Center(), b.Size().Len() / 2}
+}
+
+// Sphere is a sphere around Center.
+type Sphere struct {
+    Center mgl32.Vec3
+    Radius float32
+}
+
+// Contains reports whether p lies within s.
+func (s Sphere) Contains(p mgl32.Vec3) bool {
+    d := p.Sub(s.Center)
+    return d.Dot(d) <= s.Radius*s.Radius
+}
+
+// Intersects reports whether s and t overlap.
+func (s Sphere) Intersects(t Sphere) bool {
+    d := t.Center.Sub(s.Center)
+    r := s.Radius + t.Radius
+    return d.Dot(d) <= r*r
+}
+
+// IntersectsAABB reports whether s and b overlap.
+func (s Sphere) IntersectsAABB(b AABB) bool {
+    // the point of b nearest to the centre
+    var p mgl32.Vec3
+    for i := 0; i < 3; i++ {
+        p[i] = float32(math.Max(float64(b.Min[i]), math.Min(float64(s.Center[i]), float64(b.Max[i]))))
+    }
+    return s.Contains(p)
+}
+
+// Frustum is the view volume of a camera as six planes, left, right, bottom,
+// top, near and far, each a normal pointing inwards and a distance: a point
+// p is on the inner side of a plane if normal·p + distance >= 0.
+type Frustum [6]mgl32.Vec4
+
+// NewFrustum extracts the frustum of a view-projection matrix, such as the
+// one returned by Camera3D.Matrix, in world coordinates. For a matrix that
+// includes a model matrix, the frustum is in the coordinates of the model.
+func NewFrustum(viewProjection mgl32.Mat4) Frustum {
+    m := viewProjection
+    r0, r1, r2, r3 := m.Row(0), m.Row(1), m.Row(2), m.Row(3)
+    f := Frustum{
+        r3.Add(r0), r3.Sub(r0),
+        r3.Add(r1), r3.Sub(r1),
+        r3.Add(r2), r3.Sub(r2),
+    }
+    // unit normals, so the sphere test can compare distances
+    for i, p := range f {
+        if l := p.Vec3().Len(); l > 0 {
+            f[i] = p.Mul(1 / l)
+        }
+    }
+    return f
+}
+
+// ContainsPoint reports whether p lies within f.
+func (f *Frustum) ContainsPoint(p mgl32.Vec3) bool {
+    for _, pl := range f {
+        if pl.Vec3().Dot(p)+pl[3] < 0 {
+            return false
+        }
+    }
+    return true
+}
+
+// IntersectsAABB reports whether b may intersect f. Boxes near the edges of
+// the frustum may be reported as intersecting although they do not, which is
+// the usual trade-off for culling: it only costs drawing them.
+func (f *Frustum) IntersectsAABB(b AABB) bool {
+    for _, pl := range f {
+        // the corner of the box furthest along the normal
+        var c mgl32.Vec3
+        for i := 0; i < 3; i++ {
+            if pl[i] >= 0 {
+                c[i] = b.Max[i]
+            } else {
+                c[i] = b.Min[i]
+            }
+        }
+        if pl.Vec3().Dot(c)+pl[3] < 0 {
+            return false
+        }
+    }
+    return true
+}
+
+// IntersectsSphere reports whether s may intersect f, with the same
+// trade-off as IntersectsAABB.
+func (f *Frustum) IntersectsSphere(s Sphere) bool {
+    for _, pl := range f {
+        if pl.Vec3().Dot(s.Center)+pl[3] < -s.Radius {
+            return false
+        }
+    }
+    return true
+}
@@ -0,0 +1,108 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/glfw"
+    "image"
+)
+
+// CursorMode determines how the mouse cursor behaves over the main window.
+type CursorMode int
+
+const (
+    CursorNormal   CursorMode = iota // visible and free to leave the window
+    CursorHidden                     // invisible over the window, but free to leave it
+    CursorCaptured                   // invisible and locked to the window, for mouse look
+)
+
+type cursorState struct {
+    mode         CursorMode
+    current      *glfw.Cursor
+    raw          bool
+    x, y         float64
+    dx, dy       float64
+    havePosition bool
+}
+
+var cursor cursorState
+
+// SetCursorMode sets the cursor mode of the main window. While the cursor is
+// captured, CursorDelta keeps reporting motion no matter how far the mouse
+// moves.
+func SetCursorMode(mode CursorMode) {
+    switch mode {
+    case CursorHidden:
+        app.window.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
+    case CursorCaptured:
+        app.window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+    default:
+        mode = CursorNormal
+        app.window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+    }
+    cursor.mode = mode
+    // the cursor jumps when it is captured or released
+    cursor.havePosition = false
+}
+
+// GetCursorMode returns the cursor mode of the main window.
+func GetCursorMode() CursorMode {
+    return cursor.mode
+}
+
+// SetCursor replaces the cursor shown over the main window by img, with the
+// hot spot, the pixel that points, at (hotX, hotY) relative to the top left
+// corner of img. A nil img restores the default cursor.
+func SetCursor(img image.Image, hotX, hotY int) {
+    var c *glfw.Cursor
+    if img != nil {
+        c = glfw.CreateCursor(img, hotX, hotY)
+    }
+    app.window.SetCursor(c)
+    if cursor.current != nil {
+        cursor.current.Destroy()
+    }
+    cursor.current = c
+}
+
+// SetRawMouseMotion requests unaccelerated mouse motion while the cursor is
+// captured, so camera controls are not affected by the pointer acceleration
+// of the system. It reports whether raw motion is supported.
+func SetRawMouseMotion(enabled bool) bool {
+    cursor.raw = enabled
+    if !glfw.RawMouseMotionSupported() {
+        return false
+    }
+    value := glfw.False
+    if enabled {
+        value = glfw.True
+    }
+    app.window.SetInputMode(glfw.RawMouseMotion, value)
+    return true
+}
+
+// CursorPosition returns the position of the cursor in screen coordinates
+// relative to the top left corner of the client area of the main window, or
+// in virtual pixels if a virtual resolution is set (see
+// SetVirtualResolution).
+func CursorPosition() (x, y float64) {
+    return toVirtual(cursor.x, cursor.y)
+}
+
+// CursorDelta returns how far the cursor moved during the last Tick, in the
+// units of CursorPosition.
+func CursorDelta() (dx, dy float64) {
+    return toVirtualDelta(cursor.dx, cursor.dy)
+}
+
+// beginCursorFrame is called by Tick before polling events.
+func beginCursorFrame() {
+    cursor.dx, cursor.dy = 0, 0
+}
+
+func moveCursor(x, y float64) {
+    if cursor.havePosition {
+        cursor.dx += x - cursor.x
+        cursor.dy += y - cursor.y
+    }
+    cursor.x, cursor.y = x, y
+    cursor.havePosition = true
+}
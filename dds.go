@@ -0,0 +1,100 @@
+package gome
+
+import (
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+)
+
+// ddsFourCCs are the GL formats of the compressed formats of DDS files
+// without a DX10 header.
+var ddsFourCCs = map[string]uint32{
+    "DXT1": gl.COMPRESSED_RGBA_S3TC_DXT1_EXT, // as DXT1 may have 1-bit alpha
+    "DXT2": gl.COMPRESSED_RGBA_S3TC_DXT3_EXT,
+    "DXT3": gl.COMPRESSED_RGBA_S3TC_DXT3_EXT,
+    "DXT4": gl.COMPRESSED_RGBA_S3TC_DXT5_EXT,
+    "DXT5": gl.COMPRESSED_RGBA_S3TC_DXT5_EXT,
+    "ATI1": gl.COMPRESSED_RED_RGTC1,
+    "BC4U": gl.COMPRESSED_RED_RGTC1,
+    "BC4S": gl.COMPRESSED_SIGNED_RED_RGTC1,
+    "ATI2": gl.COMPRESSED_RG_RGTC2,
+    "BC5U": gl.COMPRESSED_RG_RGTC2,
+    "BC5S": gl.COMPRESSED_SIGNED_RG_RGTC2,
+}
+
+// dxgiFormats are the GL formats of the DXGI formats of the DX10 header.
+var dxgiFormats = map[uint32]uint32{
+    71: gl.COMPRESSED_RGBA_S3TC_DXT1_EXT,          // DXGI_FORMAT_BC1_UNORM
+    72: gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT,    // DXGI_FORMAT_BC1_UNORM_SRGB
+    74: gl.COMPRESSED_RGBA_S3TC_DXT3_EXT,          // DXGI_FORMAT_BC2_UNORM
+    75: gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT,    // DXGI_FORMAT_BC2_UNORM_SRGB
+    77: gl.COMPRESSED_RGBA_S3TC_DXT5_EXT,          // DXGI_FORMAT_BC3_UNORM
+    78: gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT,    // DXGI_FORMAT_BC3_UNORM_SRGB
+    80: gl.COMPRESSED_RED_RGTC1,                   // DXGI_FORMAT_BC4_UNORM
+    81: gl.COMPRESSED_SIGNED_RED_RGTC1,            // DXGI_FORMAT_BC4_SNORM
+    83: gl.COMPRESSED_RG_RGTC2,                    // DXGI_FORMAT_BC5_UNORM
+    84: gl.COMPRESSED_SIGNED_RG_RGTC2,             // DXGI_FORMAT_BC5_SNORM
+    95: gl.COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB, // DXGI_FORMAT_BC6H_UF16
+    96: gl.COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB,   // DXGI_FORMAT_BC6H_SF16
+    98: gl.COMPRESSED_RGBA_BPTC_UNORM_ARB,         // DXGI_FORMAT_BC7_UNORM
+    99: gl.COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB,   // DXGI_FORMAT_BC7_UNORM_SRGB
+}
+
+// parseDDS parses a DDS file holding a single compressed 2D image.
+func parseDDS(data []byte) (*compressedImage, error) {
+    const (
+        headerSize      = 128
+        dx10Size        = 20
+        flagMipmapCount = 0x20000
+        flagFourCC      = 0x4
+        caps2Cubemap    = 0x200
+        caps2Volume     = 0x200000
+    )
+    if len(data) < headerSize {
+        return nil, errors.New("truncated DDS header")
+    }
+    le := binary.LittleEndian
+    flags := le.Uint32(data[8:])
+    height, width := int(le.Uint32(data[12:])), int(le.Uint32(data[16:]))
+    levels := 1
+    if flags&flagMipmapCount != 0 && le.Uint32(data[28:]) > 0 {
+        levels = int(le.Uint32(data[28:]))
+    }
+    if le.Uint32(data[112:])&(caps2Cubemap|caps2Volume) != 0 {
+        return nil, errors.New("only 2D DDS textures are supported")
+    }
+    if le.Uint32(data[80:])&flagFourCC == 0 {
+        return nil, errors.New("uncompressed DDS files are not supported")
+    }
+
+    fourCC := string(data[84:88])
+    rest := data[headerSize:]
+    var format uint32
+    if fourCC == "DX10" {
+        if len(rest) < dx10Size {
+            return nil, errors.New("truncated DDS header")
+        }
+        dxgi, ok := dxgiFormats[le.Uint32(rest)]
+        if !ok {
+            return nil, fmt.Errorf("unsupported DXGI format %d", le.Uint32(rest))
+        }
+        if le.Uint32(rest[4:]) != 3 || le.Uint32(rest[12:]) > 1 {
+            // not TEXTURE2D, or an array
+            return nil, errors.New("only 2D DDS textures are supported")
+        }
+        format, rest = dxgi, rest[dx10Size:]
+    } else {
+        f, ok := ddsFourCCs[fourCC]
+        if !ok {
+            return nil, fmt.Errorf("unsupported format %q", fourCC)
+        }
+        format = f
+    }
+
+    pix, err := splitLevels(format, width, height, levels, rest)
+    if err != nil {
+        return nil, err
+    }
+    return &compressedImage{format: format, width: width, height: height, levels: pix}, nil
+}
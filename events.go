@@ -0,0 +1,149 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/glfw"
+    "sync/atomic"
+)
+
+// Event is an event of the main window, delivered by Events. It is one of
+// KeyEvent, CharEvent, MouseButtonEvent, MouseMove, Scroll, Resize, Focus,
+// Minimize, Drop and CloseRequested.
+type Event interface {
+    event()
+}
+
+// KeyEvent is sent when a key is pressed, repeated or released.
+type KeyEvent struct {
+    Key      glfw.Key
+    Scancode int
+    Action   glfw.Action // glfw.Press, glfw.Repeat or glfw.Release
+    Mods     glfw.ModifierKey
+}
+
+// CharEvent is sent when a character is typed, after the keyboard layout and
+// input method have been applied.
+type CharEvent struct {
+    Rune rune
+}
+
+// MouseButtonEvent is sent when a mouse button is pressed or released.
+type MouseButtonEvent struct {
+    Button glfw.MouseButton
+    Action glfw.Action
+    Mods   glfw.ModifierKey
+    X, Y   float64 // the cursor position, as reported by CursorPosition
+}
+
+// MouseMove is sent when the cursor moves, with its new position and the
+// motion since the previous position, in the units of CursorPosition.
+type MouseMove struct {
+    X, Y   float64
+    DX, DY float64
+}
+
+// Scroll is sent when the mouse wheel or the touchpad scrolls.
+type Scroll struct {
+    DX, DY float64
+}
+
+// Resize is sent when the window is resized, with its new size in screen
+// coordinates and the size of its framebuffer in pixels.
+type Resize struct {
+    Width, Height                       int
+    FramebufferWidth, FramebufferHeight int
+}
+
+// Focus is sent when the window gains or loses the keyboard focus.
+type Focus struct {
+    Focused bool
+}
+
+// Minimize is sent when the window is minimized (iconified) or restored.
+type Minimize struct {
+    Minimized bool
+}
+
+// Drop is sent when files or directories are dropped onto the window.
+type Drop struct {
+    Paths []string
+}
+
+// CloseRequested is sent when the user tries to close the window. Tick
+// returns false afterwards unless a handler registered with OnCloseRequest
+// cancels the close.
+type CloseRequested struct{}
+
+func (KeyEvent) event()         {}
+func (CharEvent) event()        {}
+func (MouseButtonEvent) event() {}
+func (MouseMove) event()        {}
+func (Scroll) event()           {}
+func (Resize) event()           {}
+func (Focus) event()            {}
+func (Minimize) event()         {}
+func (Drop) event()             {}
+func (CloseRequested) event()   {}
+
+// eventBuffer is the number of events Events buffers.
+const eventBuffer = 256
+
+// events is created up front, so Events may be called from any goroutine;
+// collecting is set by the first call.
+var (
+    events     = make(chan Event, eventBuffer)
+    collecting atomic.Bool
+)
+
+// Events returns a channel delivering the events of the main window in the
+// order they happened. The events are sent while Tick polls for them,
+// including the input of a replay (see ReplayInput), so they can be drained
+// after every Tick:
+//
+//     for app.Tick() {
+//         for e, ok := gome.PollEvent(); ok; e, ok = gome.PollEvent() {
+//             switch e := e.(type) {
+//             case gome.KeyEvent:
+//                 ...
+//             case gome.Resize:
+//                 ...
+//             }
+//         }
+//     }
+//
+// or received by another goroutine, e.g. to forward them to a UI library. The
+// channel buffers 256 events; if it is full, the oldest event is dropped.
+// Events are only collected once Events or PollEvent has been called.
+func Events() <-chan Event {
+    collecting.Store(true)
+    return events
+}
+
+// PollEvent returns the next event of Events and true, or false if there is
+// none.
+func PollEvent() (Event, bool) {
+    select {
+    case e := <-Events():
+        return e, true
+    default:
+        return nil, false
+    }
+}
+
+// sendEvent queues e for Events without blocking.
+func sendEvent(e Event) {
+    if !collecting.Load() {
+        return
+    }
+    for {
+        select {
+        case events <- e:
+            return
+        default:
+        }
+        // full, so make room
+        select {
+        case <-events:
+        default:
+        }
+    }
+}
@@ -0,0 +1,69 @@
+package gome
+
+import (
+    "io"
+    "io/fs"
+    "os"
+)
+
+// assetFS is the file system the loaders read from; nil means the operating
+// system's file system.
+var assetFS fs.FS
+
+// SetAssetFS sets the file system that the path based loaders (LoadTexture,
+// LoadProgram, text.Load and so on) read from. This makes it possible to ship
+// all assets inside the executable with go:embed:
+//
+//     //go:embed data
+//     var data embed.FS
+//
+//     gome.SetAssetFS(data)
+//
+// Passing nil restores the default of reading from the operating system's file
+// system, relative to the working directory.
+func SetAssetFS(fsys fs.FS) {
+    assetFS = fsys
+}
+
+// AssetFS returns the file system set with SetAssetFS, or nil if the loaders
+// read from the operating system's file system.
+func AssetFS() fs.FS {
+    return assetFS
+}
+
+// OpenAsset opens the named file from the asset file system.
+func OpenAsset(name string) (fs.File, error) {
+    if assetFS == nil {
+        return os.Open(name)
+    }
+    return assetFS.Open(name)
+}
+
+// ReadAsset reads the named file from the asset file system.
+func ReadAsset(name string) ([]byte, error) {
+    return readFile(assetFS, name)
+}
+
+// readFile reads name from fsys, or from the operating system if fsys is nil.
+func readFile(fsys fs.FS, name string) ([]byte, error) {
+    if fsys == nil {
+        return os.ReadFile(name)
+    }
+    return fs.ReadFile(fsys, name)
+}
+
+// openFile opens name from fsys, or from the operating system if fsys is nil.
+func openFile(fsys fs.FS, name string) (io.ReadCloser, error) {
+    if fsys == nil {
+        return os.Open(name)
+    }
+    return fsys.Open(name)
+}
+
+// statAsset returns information about the named file in the asset file system.
+func statAsset(name string) (fs.FileInfo, error) {
+    if assetFS == nil {
+        return os.Stat(name)
+    }
+    return fs.Stat(assetFS, name)
+}
@@ -0,0 +1,81 @@
+package gome
+
+import (
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+)
+
+// ContextInfo describes the OpenGL context of the main window.
+type ContextInfo struct {
+    Version        string // GL_VERSION
+    Major, Minor   int    // the version number
+    Profile        ContextProfile
+    GLSLVersion    string // GL_SHADING_LANGUAGE_VERSION
+    Vendor         string
+    Renderer       string
+    MaxTextureSize int
+    MaxArrayLayers int // the most layers of a TextureArray
+    MaxSamples     int
+    MaxAnisotropy  float32 // of anisotropic filtering; 0 if not supported
+    Extensions     []string
+}
+
+// String returns a summary of the context, suitable for logs and bug
+// reports.
+func (i ContextInfo) String() string {
+    return fmt.Sprintf("OpenGL %s (%s profile, GLSL %s), %s, %s, max texture size %d, max samples %d, %d extensions",
+        i.Version, i.Profile, i.GLSLVersion, i.Vendor, i.Renderer, i.MaxTextureSize, i.MaxSamples, len(i.Extensions))
+}
+
+var (
+    glInfo       ContextInfo
+    glExtensions map[string]bool
+)
+
+// GLInfo returns details of the OpenGL context, queried once by Init, so it
+// is cheap to call. Apps can use it to choose code paths and include it in
+// bug reports.
+func GLInfo() ContextInfo {
+    return glInfo
+}
+
+// HasExtension reports whether the OpenGL context supports the extension
+// name, e.g. "GL_ARB_texture_filter_anisotropic".
+func HasExtension(name string) bool {
+    return glExtensions[name]
+}
+
+// queryGLInfo fills in glInfo; it is called by Init.
+func queryGLInfo() {
+    getInt := func(pname uint32) int {
+        var v int32
+        gl.GetIntegerv(pname, &v)
+        return int(v)
+    }
+    getString := func(name uint32) string {
+        return gl.GoStr(gl.GetString(name))
+    }
+    glInfo = ContextInfo{
+        Version:        getString(gl.VERSION),
+        Major:          getInt(gl.MAJOR_VERSION),
+        Minor:          getInt(gl.MINOR_VERSION),
+        Profile:        obtainedContext.Profile,
+        GLSLVersion:    getString(gl.SHADING_LANGUAGE_VERSION),
+        Vendor:         getString(gl.VENDOR),
+        Renderer:       getString(gl.RENDERER),
+        MaxTextureSize: getInt(gl.MAX_TEXTURE_SIZE),
+        MaxArrayLayers: getInt(gl.MAX_ARRAY_TEXTURE_LAYERS),
+        MaxSamples:     getInt(gl.MAX_SAMPLES),
+    }
+    n := getInt(gl.NUM_EXTENSIONS)
+    glExtensions = make(map[string]bool, n)
+    for i := 0; i < n; i++ {
+        name := gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i)))
+        glInfo.Extensions = append(glInfo.Extensions, name)
+        glExtensions[name] = true
+    }
+    if glInfo.Profile != ESProfile && (glInfo.Major > 4 || glInfo.Major == 4 && glInfo.Minor >= 6) ||
+        HasExtension("GL_ARB_texture_filter_anisotropic") || HasExtension("GL_EXT_texture_filter_anisotropic") {
+        gl.GetFloatv(gl.MAX_TEXTURE_MAX_ANISOTROPY, &glInfo.MaxAnisotropy)
+    }
+}
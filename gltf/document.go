@@ -0,0 +1,376 @@
+package gltf
+
+import (
+    "encoding/base64"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "math"
+    "strings"
+)
+
+// The types in this file mirror the parts of the glTF 2.0 JSON schema that
+// are used by the importer.
+
+type document struct {
+    Asset struct {
+        Version string `json:"version"`
+    } `json:"asset"`
+    Scene       *int            `json:"scene"`
+    Scenes      []docScene      `json:"scenes"`
+    Nodes       []docNode       `json:"nodes"`
+    Meshes      []docMesh       `json:"meshes"`
+    Materials   []docMaterial   `json:"materials"`
+    Textures    []docTexture    `json:"textures"`
+    Images      []docImage      `json:"images"`
+    Samplers    []docSampler    `json:"samplers"`
+    Accessors   []docAccessor   `json:"accessors"`
+    BufferViews []docBufferView `json:"bufferViews"`
+    Buffers     []docBuffer     `json:"buffers"`
+    Skins       []docSkin       `json:"skins"`
+    Animations  []docAnimation  `json:"animations"`
+}
+
+type docScene struct {
+    Name  string `json:"name"`
+    Nodes []int  `json:"nodes"`
+}
+
+type docNode struct {
+    Name        string    `json:"name"`
+    Children    []int     `json:"children"`
+    Mesh        *int      `json:"mesh"`
+    Skin        *int      `json:"skin"`
+    Matrix      []float32 `json:"matrix"`
+    Translation []float32 `json:"translation"`
+    Rotation    []float32 `json:"rotation"`
+    Scale       []float32 `json:"scale"`
+}
+
+type docMesh struct {
+    Name       string         `json:"name"`
+    Primitives []docPrimitive `json:"primitives"`
+}
+
+type docPrimitive struct {
+    Attributes map[string]int `json:"attributes"`
+    Indices    *int           `json:"indices"`
+    Material   *int           `json:"material"`
+    Mode       *int           `json:"mode"`
+}
+
+type docTextureInfo struct {
+    Index int `json:"index"`
+}
+
+type docMaterial struct {
+    Name string `json:"name"`
+    PBR  struct {
+        BaseColorFactor  []float32       `json:"baseColorFactor"`
+        BaseColorTexture *docTextureInfo `json:"baseColorTexture"`
+    } `json:"pbrMetallicRoughness"`
+}
+
+type docTexture struct {
+    Sampler *int `json:"sampler"`
+    Source  *int `json:"source"`
+}
+
+type docImage struct {
+    URI        string `json:"uri"`
+    BufferView *int   `json:"bufferView"`
+    MimeType   string `json:"mimeType"`
+}
+
+type docSampler struct {
+    MagFilter int `json:"magFilter"`
+    MinFilter int `json:"minFilter"`
+    WrapS     int `json:"wrapS"`
+    WrapT     int `json:"wrapT"`
+}
+
+type docAccessor struct {
+    BufferView    *int      `json:"bufferView"`
+    ByteOffset    int       `json:"byteOffset"`
+    ComponentType int       `json:"componentType"`
+    Normalized    bool      `json:"normalized"`
+    Count         int       `json:"count"`
+    Type          string    `json:"type"`
+    Sparse        *struct{} `json:"sparse"`
+}
+
+type docBufferView struct {
+    Buffer     int `json:"buffer"`
+    ByteOffset int `json:"byteOffset"`
+    ByteLength int `json:"byteLength"`
+    ByteStride int `json:"byteStride"`
+}
+
+type docBuffer struct {
+    URI        string `json:"uri"`
+    ByteLength int    `json:"byteLength"`
+}
+
+type docSkin struct {
+    Name                string `json:"name"`
+    InverseBindMatrices *int   `json:"inverseBindMatrices"`
+    Joints              []int  `json:"joints"`
+    Skeleton            *int   `json:"skeleton"`
+}
+
+type docAnimation struct {
+    Name     string `json:"name"`
+    Channels []struct {
+        Sampler int `json:"sampler"`
+        Target  struct {
+            Node *int   `json:"node"`
+            Path string `json:"path"`
+        } `json:"target"`
+    } `json:"channels"`
+    Samplers []struct {
+        Input         int    `json:"input"`
+        Output        int    `json:"output"`
+        Interpolation string `json:"interpolation"`
+    } `json:"samplers"`
+}
+
+// component types of accessors
+const (
+    typeByte          = 5120
+    typeUnsignedByte  = 5121
+    typeShort         = 5122
+    typeUnsignedShort = 5123
+    typeUnsignedInt   = 5125
+    typeFloat         = 5126
+)
+
+var componentSizes = map[int]int{
+    typeByte:          1,
+    typeUnsignedByte:  1,
+    typeShort:         2,
+    typeUnsignedShort: 2,
+    typeUnsignedInt:   4,
+    typeFloat:         4,
+}
+
+var componentCounts = map[string]int{
+    "SCALAR": 1,
+    "VEC2":   2,
+    "VEC3":   3,
+    "VEC4":   4,
+    "MAT2":   4,
+    "MAT3":   9,
+    "MAT4":   16,
+}
+
+// glb container constants
+const (
+    glbMagic     = 0x46546C67 // "glTF"
+    glbChunkJSON = 0x4E4F534A
+    glbChunkBIN  = 0x004E4942
+)
+
+var errGLB = errors.New("gltf: malformed binary container")
+
+// splitGLB returns the JSON and binary chunks of a .glb file.
+func splitGLB(data []byte) (json, bin []byte, err error) {
+    if len(data) < 12 || binary.LittleEndian.Uint32(data) != glbMagic {
+        return nil, nil, errGLB
+    }
+    if version := binary.LittleEndian.Uint32(data[4:]); version != 2 {
+        return nil, nil, fmt.Errorf("gltf: unsupported binary container version %d", version)
+    }
+    length := int(binary.LittleEndian.Uint32(data[8:]))
+    if length < 12 || length > len(data) {
+        return nil, nil, errGLB
+    }
+    data = data[12:length]
+    for len(data) >= 8 {
+        size := int(binary.LittleEndian.Uint32(data))
+        typ := binary.LittleEndian.Uint32(data[4:])
+        if size > len(data)-8 {
+            return nil, nil, errGLB
+        }
+        chunk := data[8 : 8+size]
+        switch typ {
+        case glbChunkJSON:
+            json = chunk
+        case glbChunkBIN:
+            bin = chunk
+        }
+        data = data[8+size:]
+    }
+    if json == nil {
+        return nil, nil, errGLB
+    }
+    return json, bin, nil
+}
+
+// isGLB reports whether data starts with the binary glTF magic.
+func isGLB(data []byte) bool {
+    return len(data) >= 4 && binary.LittleEndian.Uint32(data) == glbMagic
+}
+
+// decodeDataURI decodes a base64 data URI, returning ok == false if uri is not
+// a data URI.
+func decodeDataURI(uri string) (data []byte, ok bool, err error) {
+    if !strings.HasPrefix(uri, "data:") {
+        return nil, false, nil
+    }
+    i := strings.Index(uri, ",")
+    if i < 0 || !strings.HasSuffix(uri[:i], ";base64") {
+        return nil, true, fmt.Errorf("gltf: unsupported data URI")
+    }
+    data, err = base64.StdEncoding.DecodeString(uri[i+1:])
+    return data, true, err
+}
+
+// decoder resolves accessors into Go slices.
+type decoder struct {
+    doc     *document
+    buffers [][]byte
+}
+
+func (d *decoder) bufferView(i int) ([]byte, int, error) {
+    if i < 0 || i >= len(d.doc.BufferViews) {
+        return nil, 0, fmt.Errorf("gltf: buffer view %d out of range", i)
+    }
+    v := d.doc.BufferViews[i]
+    if v.Buffer < 0 || v.Buffer >= len(d.buffers) {
+        return nil, 0, fmt.Errorf("gltf: buffer %d out of range", v.Buffer)
+    }
+    b := d.buffers[v.Buffer]
+    if v.ByteOffset < 0 || v.ByteLength < 0 || v.ByteStride < 0 {
+        return nil, 0, fmt.Errorf("gltf: buffer view %d has a negative size", i)
+    }
+    if v.ByteOffset > len(b) || v.ByteLength > len(b)-v.ByteOffset {
+        return nil, 0, fmt.Errorf("gltf: buffer view %d exceeds its buffer", i)
+    }
+    return b[v.ByteOffset : v.ByteOffset+v.ByteLength], v.ByteStride, nil
+}
+
+// floats reads accessor i as float32 values, converting (and, if the accessor
+// is normalized, normalizing) integer components. It returns the values and
+// the number of components per element.
+func (d *decoder) floats(i int) ([]float32, int, error) {
+    if i < 0 || i >= len(d.doc.Accessors) {
+        return nil, 0, fmt.Errorf("gltf: accessor %d out of range", i)
+    }
+    a := d.doc.Accessors[i]
+    n, ok := componentCounts[a.Type]
+    size, ok2 := componentSizes[a.ComponentType]
+    if !ok || !ok2 {
+        return nil, 0, fmt.Errorf("gltf: accessor %d has an invalid type", i)
+    }
+    if a.Sparse != nil {
+        return nil, 0, fmt.Errorf("gltf: sparse accessors are not supported")
+    }
+    if a.Count < 0 || a.ByteOffset < 0 {
+        return nil, 0, fmt.Errorf("gltf: accessor %d has a negative size", i)
+    }
+    if a.BufferView == nil {
+        return make([]float32, a.Count*n), n, nil // all zeros
+    }
+    view, stride, err := d.bufferView(*a.BufferView)
+    if err != nil {
+        return nil, 0, err
+    }
+    if stride == 0 {
+        stride = n * size
+    }
+    if !fits(len(view), a.ByteOffset, a.Count, stride, n*size) {
+        return nil, 0, fmt.Errorf("gltf: accessor %d exceeds its buffer view", i)
+    }
+    values := make([]float32, a.Count*n)
+    for e := 0; e < a.Count; e++ {
+        base := a.ByteOffset + e*stride
+        for c := 0; c < n; c++ {
+            values[e*n+c] = component(view[base+c*size:], a.ComponentType, a.Normalized)
+        }
+    }
+    return values, n, nil
+}
+
+// fits reports whether count elements of size bytes, stride bytes apart from
+// offset, fit into a buffer view of n bytes. The sizes are not negative, and
+// are compared without overflowing on huge values.
+func fits(n, offset, count, stride, size int) bool {
+    if count == 0 {
+        return true
+    }
+    if offset > n || size > n-offset {
+        return false
+    }
+    return count-1 <= (n-offset-size)/stride
+}
+
+func component(b []byte, typ int, normalized bool) float32 {
+    switch typ {
+    case typeFloat:
+        return math.Float32frombits(binary.LittleEndian.Uint32(b))
+    case typeByte:
+        v := float32(int8(b[0]))
+        if normalized {
+            return float32(math.Max(float64(v)/127, -1))
+        }
+        return v
+    case typeUnsignedByte:
+        if normalized {
+            return float32(b[0]) / 255
+        }
+        return float32(b[0])
+    case typeShort:
+        v := float32(int16(binary.LittleEndian.Uint16(b)))
+        if normalized {
+            return float32(math.Max(float64(v)/32767, -1))
+        }
+        return v
+    case typeUnsignedShort:
+        v := float32(binary.LittleEndian.Uint16(b))
+        if normalized {
+            return v / 65535
+        }
+        return v
+    case typeUnsignedInt:
+        return float32(binary.LittleEndian.Uint32(b))
+    }
+    return 0
+}
+
+// indices reads the scalar integer accessor i.
+func (d *decoder) indices(i int) ([]uint32, error) {
+    if i < 0 || i >= len(d.doc.Accessors) {
+        return nil, fmt.Errorf("gltf: accessor %d out of range", i)
+    }
+    a := d.doc.Accessors[i]
+    size := componentSizes[a.ComponentType]
+    if a.Type != "SCALAR" || a.ComponentType == typeFloat || size == 0 || a.BufferView == nil {
+        return nil, fmt.Errorf("gltf: accessor %d is not a valid index accessor", i)
+    }
+    if a.Count < 0 || a.ByteOffset < 0 {
+        return nil, fmt.Errorf("gltf: accessor %d has a negative size", i)
+    }
+    view, stride, err := d.bufferView(*a.BufferView)
+    if err != nil {
+        return nil, err
+    }
+    if stride == 0 {
+        stride = size
+    }
+    if !fits(len(view), a.ByteOffset, a.Count, stride, size) {
+        return nil, fmt.Errorf("gltf: accessor %d exceeds its buffer view", i)
+    }
+    out := make([]uint32, a.Count)
+    for e := range out {
+        b := view[a.ByteOffset+e*stride:]
+        switch size {
+        case 1:
+            out[e] = uint32(b[0])
+        case 2:
+            out[e] = uint32(binary.LittleEndian.Uint16(b))
+        default:
+            out[e] = binary.LittleEndian.Uint32(b)
+        }
+    }
+    return out, nil
+}
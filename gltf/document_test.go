@@ -0,0 +1,77 @@
+package gltf
+
+import (
+    "encoding/binary"
+    "encoding/json"
+    "testing"
+)
+
+// glb returns a binary container with the given length in its header and a
+// JSON chunk of the given size, which may exceed the data.
+func glb(length, chunk uint32, json string) []byte {
+    data := make([]byte, 20, 20+len(json))
+    binary.LittleEndian.PutUint32(data, glbMagic)
+    binary.LittleEndian.PutUint32(data[4:], 2)
+    binary.LittleEndian.PutUint32(data[8:], length)
+    binary.LittleEndian.PutUint32(data[12:], chunk)
+    binary.LittleEndian.PutUint32(data[16:], glbChunkJSON)
+    return append(data, json...)
+}
+
+func TestSplitGLB(t *testing.T) {
+    json, _, err := splitGLB(glb(22, 2, "{}"))
+    if err != nil || string(json) != "{}" {
+        t.Fatalf("JSON chunk %q, %v, want {}", json, err)
+    }
+    for _, data := range [][]byte{
+        glb(0, 2, "{}"),
+        glb(11, 2, "{}"),
+        glb(12, 2, "{}"),
+        glb(23, 2, "{}"),
+        glb(22, 3, "{}"),
+        glb(22, 1<<31, "{}"),
+        glb(22, 2, "{}")[:11],
+    } {
+        if _, _, err := splitGLB(data); err == nil {
+            t.Errorf("no error for % x", data)
+        }
+    }
+}
+
+func TestAccessorBounds(t *testing.T) {
+    tests := []struct {
+        name     string
+        view     string
+        accessor string
+        ok       bool
+    }{
+        {"valid", `{"buffer": 0, "byteOffset": 4, "byteLength": 12}`, `"byteOffset": 4, "count": 2`, true},
+        {"strided", `{"buffer": 0, "byteLength": 16, "byteStride": 12}`, `"count": 2`, true},
+        {"past the view", `{"buffer": 0, "byteOffset": 4, "byteLength": 12}`, `"byteOffset": 8, "count": 2`, false},
+        {"view past the buffer", `{"buffer": 0, "byteOffset": 4, "byteLength": 16}`, `"count": 1`, false},
+        {"negative view offset", `{"buffer": 0, "byteOffset": -4, "byteLength": 8}`, `"count": 1`, false},
+        {"negative view length", `{"buffer": 0, "byteOffset": 8, "byteLength": -4}`, `"count": 0`, false},
+        {"negative stride", `{"buffer": 0, "byteOffset": 12, "byteLength": 4, "byteStride": -4}`, `"count": 2`, false},
+        {"negative offset", `{"buffer": 0, "byteLength": 16}`, `"byteOffset": -4, "count": 1`, false},
+        {"negative count", `{"buffer": 0, "byteLength": 16}`, `"count": -1`, false},
+        {"huge count", `{"buffer": 0, "byteLength": 16}`, `"count": 4611686018427387904`, false},
+        {"huge stride", `{"buffer": 0, "byteLength": 16, "byteStride": 4611686018427387904}`, `"count": 3`, false},
+    }
+    for _, tt := range tests {
+        for _, typ := range []string{`"componentType": 5126`, `"componentType": 5125`} {
+            doc := &document{}
+            src := `{"bufferViews": [` + tt.view + `], "accessors": [{"bufferView": 0, "type": "SCALAR", ` + typ + `, ` + tt.accessor + `}]}`
+            if err := json.Unmarshal([]byte(src), doc); err != nil {
+                t.Fatal(err)
+            }
+            d := &decoder{doc: doc, buffers: [][]byte{make([]byte, 16)}}
+            _, _, err := d.floats(0)
+            if _, ierr := d.indices(0); ierr != nil && typ == `"componentType": 5125` {
+                err = ierr
+            }
+            if (err == nil) != tt.ok {
+                t.Errorf("%s, %s: error %v", tt.name, typ, err)
+            }
+        }
+    }
+}
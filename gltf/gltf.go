@@ -0,0 +1,608 @@
+/*
+Package gltf imports glTF 2.0 scenes (.gltf with external or embedded buffers,
+and binary .glb files) into gome meshes, materials and textures, together with
+their node hierarchy, skins and animation clips:
+
+    scene, err := gltf.Load("models/robot.glb")
+    if err != nil {
+        // handle error
+    }
+    defer scene.Delete()
+
+    for _, n := range scene.Nodes {
+        if n.Mesh != nil {
+            setModelMatrix(n.World())
+            n.Mesh.Draw(bindMaterial)
+        }
+    }
+
+Skinned meshes are posed by an AnimationPlayer, which plays the animation
+clips and provides the joint matrices to SkinningVertexShader.
+
+Only triangle primitives are imported; meshes of nothing but lines or points
+are left out, as nil in Scene.Meshes and on their nodes. Materials are
+reduced to their base color factor and texture, which end up as the diffuse
+color and map of gome.Material. Sparse accessors are not supported.
+*/
+package gltf
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "github.com/snorredc/gome/internal/gl"
+    "io/fs"
+    "os"
+    "path"
+    "strings"
+)
+
+// Scene is an imported glTF file.
+type Scene struct {
+    Nodes      []*Node      // all nodes, in the order of the file
+    Roots      []*Node      // root nodes of the default scene
+    Meshes     []*gome.Mesh // nil for meshes without triangles
+    Materials  []*gome.Material
+    Textures   []*gome.Texture
+    Skins      []*Skin
+    Animations []*Animation
+}
+
+// Node is a node of the scene hierarchy. Its local transform is given by
+// Translation, Rotation and Scale.
+type Node struct {
+    Name     string
+    Parent   *Node
+    Children []*Node
+
+    Translation mgl32.Vec3
+    Rotation    mgl32.Quat
+    Scale       mgl32.Vec3
+
+    Mesh *gome.Mesh // may be nil
+    Skin *Skin      // may be nil; set for nodes with skinned meshes
+}
+
+// Local returns the local transformation matrix of the node.
+func (n *Node) Local() mgl32.Mat4 {
+    t := mgl32.Translate3D(n.Translation[0], n.Translation[1], n.Translation[2])
+    s := mgl32.Scale3D(n.Scale[0], n.Scale[1], n.Scale[2])
+    return t.Mul4(n.Rotation.Mat4()).Mul4(s)
+}
+
+// World returns the transformation matrix of the node relative to the root
+// of the hierarchy.
+func (n *Node) World() mgl32.Mat4 {
+    m := n.Local()
+    for p := n.Parent; p != nil; p = p.Parent {
+        m = p.Local().Mul4(m)
+    }
+    return m
+}
+
+// Skin binds the vertices of a mesh to a skeleton of joint nodes.
+type Skin struct {
+    Name                string
+    Joints              []*Node
+    InverseBindMatrices []mgl32.Mat4 // one per joint
+    Skeleton            *Node        // common root of the joints, may be nil
+}
+
+// Animation is an animation clip made of channels that animate one property
+// of a node each.
+type Animation struct {
+    Name     string
+    Channels []*Channel
+    Duration float32 // in seconds, the end of the longest channel
+}
+
+// Interpolation modes of channels.
+const (
+    Linear      = "LINEAR"
+    Step        = "STEP"
+    CubicSpline = "CUBICSPLINE"
+)
+
+// Channel animates the translation, rotation or scale of a node.
+type Channel struct {
+    Node          *Node
+    Path          string    // "translation", "rotation" or "scale"
+    Interpolation string    // Linear, Step or CubicSpline
+    Times         []float32 // keyframe times in seconds
+
+    // Values holds the keyframe values, with 3 components per value for
+    // translations and scales and 4 for rotations (quaternions as x, y, z,
+    // w). For cubic spline interpolation each keyframe has an in-tangent,
+    // a value and an out-tangent, in that order.
+    Values []float32
+}
+
+// Load imports the .gltf or .glb file at path in gome's asset file system (see
+// gome.SetAssetFS). External buffers and images are resolved relative to it.
+func Load(path string) (*Scene, error) {
+    return LoadFS(gome.AssetFS(), path)
+}
+
+// LoadFS is like Load but reads the file and the resources it references from
+// fsys.
+func LoadFS(fsys fs.FS, name string) (*Scene, error) {
+    read := func(name string) ([]byte, error) {
+        if fsys == nil {
+            return os.ReadFile(name)
+        }
+        return fs.ReadFile(fsys, name)
+    }
+    data, err := read(name)
+    if err != nil {
+        return nil, err
+    }
+    dir := path.Dir(name)
+    s, err := decode(data, func(uri string) ([]byte, error) {
+        if strings.Contains(uri, "://") {
+            return nil, fmt.Errorf("gltf: cannot load remote resource %s", uri)
+        }
+        return read(path.Join(dir, uri))
+    })
+    if err != nil {
+        return nil, fmt.Errorf("%s: %v", name, err)
+    }
+    return s, nil
+}
+
+// Decode imports a glTF scene from the contents of a .gltf or .glb file. Any
+// external resources the file references are looked up with resolve, which
+// may be nil if the file is self-contained.
+func Decode(data []byte, resolve func(uri string) ([]byte, error)) (*Scene, error) {
+    return decode(data, resolve)
+}
+
+func decode(data []byte, resolve func(uri string) ([]byte, error)) (*Scene, error) {
+    var bin []byte
+    if isGLB(data) {
+        var err error
+        if data, bin, err = splitGLB(data); err != nil {
+            return nil, err
+        }
+    }
+    doc := &document{}
+    if err := json.Unmarshal(data, doc); err != nil {
+        return nil, fmt.Errorf("gltf: %v", err)
+    }
+    if !strings.HasPrefix(doc.Asset.Version, "2.") {
+        return nil, fmt.Errorf("gltf: unsupported version %q", doc.Asset.Version)
+    }
+
+    load := func(uri string) ([]byte, error) {
+        if data, ok, err := decodeDataURI(uri); ok {
+            return data, err
+        }
+        if resolve == nil {
+            return nil, fmt.Errorf("gltf: cannot resolve %s", uri)
+        }
+        return resolve(uri)
+    }
+    d := &decoder{doc: doc}
+    for i, b := range doc.Buffers {
+        if b.URI == "" {
+            if i != 0 || bin == nil {
+                return nil, fmt.Errorf("gltf: buffer %d has no data", i)
+            }
+            d.buffers = append(d.buffers, bin)
+            continue
+        }
+        data, err := load(b.URI)
+        if err != nil {
+            return nil, err
+        }
+        d.buffers = append(d.buffers, data)
+    }
+
+    s := &Scene{}
+    ok := false
+    defer func() {
+        if !ok {
+            s.Delete()
+        }
+    }()
+    if err := d.textures(s, load); err != nil {
+        return nil, err
+    }
+    d.materials(s)
+    if err := d.nodes(s); err != nil {
+        return nil, err
+    }
+    if err := d.meshes(s); err != nil {
+        return nil, err
+    }
+    if err := d.skins(s); err != nil {
+        return nil, err
+    }
+    if err := d.animations(s); err != nil {
+        return nil, err
+    }
+    ok = true
+    return s, nil
+}
+
+// Delete deletes the meshes and textures of the scene.
+func (s *Scene) Delete() {
+    for _, m := range s.Meshes {
+        if m != nil {
+            m.Delete()
+        }
+    }
+    for _, t := range s.Textures {
+        t.Delete()
+    }
+    s.Meshes, s.Textures = nil, nil
+}
+
+// NodeByName returns the first node called name, or nil.
+func (s *Scene) NodeByName(name string) *Node {
+    for _, n := range s.Nodes {
+        if n.Name == name {
+            return n
+        }
+    }
+    return nil
+}
+
+// AnimationByName returns the first animation called name, or nil.
+func (s *Scene) AnimationByName(name string) *Animation {
+    for _, a := range s.Animations {
+        if a.Name == name {
+            return a
+        }
+    }
+    return nil
+}
+
+func (d *decoder) textures(s *Scene, load func(string) ([]byte, error)) error {
+    for i, t := range d.doc.Textures {
+        if t.Source == nil || *t.Source < 0 || *t.Source >= len(d.doc.Images) {
+            return fmt.Errorf("gltf: texture %d has no valid image", i)
+        }
+        img := d.doc.Images[*t.Source]
+        var data []byte
+        var err error
+        if img.BufferView != nil {
+            data, _, err = d.bufferView(*img.BufferView)
+        } else {
+            data, err = load(img.URI)
+        }
+        if err != nil {
+            return err
+        }
+
+        opts := &gome.TextureOptions{Mipmaps: true, Wrap: gl.REPEAT}
+        if t.Sampler != nil && *t.Sampler >= 0 && *t.Sampler < len(d.doc.Samplers) {
+            sampler := d.doc.Samplers[*t.Sampler]
+            opts.MinFilter = uint32(sampler.MinFilter)
+            opts.MagFilter = uint32(sampler.MagFilter)
+            if sampler.WrapS != 0 {
+                opts.Wrap = uint32(sampler.WrapS)
+            }
+        }
+        tex, err := gome.ReadTexture(bytes.NewReader(data), opts)
+        if err != nil {
+            return fmt.Errorf("gltf: image %d: %v", *t.Source, err)
+        }
+        s.Textures = append(s.Textures, tex)
+    }
+    return nil
+}
+
+func (d *decoder) materials(s *Scene) {
+    for _, m := range d.doc.Materials {
+        mat := &gome.Material{
+            Name:    m.Name,
+            Diffuse: [4]float32{1, 1, 1, 1},
+        }
+        copy(mat.Diffuse[:], m.PBR.BaseColorFactor)
+        if t := m.PBR.BaseColorTexture; t != nil && t.Index >= 0 && t.Index < len(s.Textures) {
+            mat.DiffuseMap = s.Textures[t.Index]
+        }
+        s.Materials = append(s.Materials, mat)
+    }
+}
+
+func (d *decoder) nodes(s *Scene) error {
+    for _, dn := range d.doc.Nodes {
+        n := &Node{
+            Name:     dn.Name,
+            Rotation: mgl32.QuatIdent(),
+            Scale:    mgl32.Vec3{1, 1, 1},
+        }
+        if len(dn.Matrix) == 16 {
+            n.Translation, n.Rotation, n.Scale = decompose(mgl32.Mat4(toArray16(dn.Matrix)))
+        }
+        copy(n.Translation[:], dn.Translation)
+        if len(dn.Rotation) == 4 {
+            n.Rotation = mgl32.Quat{W: dn.Rotation[3], V: mgl32.Vec3{dn.Rotation[0], dn.Rotation[1], dn.Rotation[2]}}
+        }
+        copy(n.Scale[:], dn.Scale)
+        s.Nodes = append(s.Nodes, n)
+    }
+    for i, dn := range d.doc.Nodes {
+        for _, c := range dn.Children {
+            if c < 0 || c >= len(s.Nodes) || s.Nodes[c].Parent != nil {
+                return fmt.Errorf("gltf: node %d has an invalid child %d", i, c)
+            }
+            s.Nodes[c].Parent = s.Nodes[i]
+            s.Nodes[i].Children = append(s.Nodes[i].Children, s.Nodes[c])
+        }
+    }
+
+    scene := 0
+    if d.doc.Scene != nil {
+        scene = *d.doc.Scene
+    }
+    if scene >= 0 && scene < len(d.doc.Scenes) {
+        for _, i := range d.doc.Scenes[scene].Nodes {
+            if i < 0 || i >= len(s.Nodes) {
+                return fmt.Errorf("gltf: scene refers to invalid node %d", i)
+            }
+            s.Roots = append(s.Roots, s.Nodes[i])
+        }
+    } else {
+        for _, n := range s.Nodes {
+            if n.Parent == nil {
+                s.Roots = append(s.Roots, n)
+            }
+        }
+    }
+    return nil
+}
+
+func (d *decoder) meshes(s *Scene) error {
+    for i, dm := range d.doc.Meshes {
+        var (
+            vertices  []gome.Vertex
+            skin      []gome.VertexSkin
+            indices   []uint32
+            submeshes []gome.Submesh
+            skinned   bool
+        )
+        for _, p := range dm.Primitives {
+            if p.Mode != nil && *p.Mode != 4 {
+                continue // not triangles
+            }
+            pos, ok := p.Attributes["POSITION"]
+            if !ok {
+                return fmt.Errorf("gltf: mesh %d has a primitive without positions", i)
+            }
+            positions, n, err := d.floats(pos)
+            if err != nil {
+                return err
+            }
+            if n != 3 {
+                return fmt.Errorf("gltf: mesh %d has invalid positions", i)
+            }
+            count := len(positions) / 3
+            base := len(vertices)
+            vertices = append(vertices, make([]gome.Vertex, count)...)
+            for v := 0; v < count; v++ {
+                copy(vertices[base+v].Position[:], positions[v*3:])
+            }
+
+            attrib := func(name string, size int, set func(v int, values []float32)) error {
+                a, ok := p.Attributes[name]
+                if !ok {
+                    return nil
+                }
+                values, n, err := d.floats(a)
+                if err != nil {
+                    return err
+                }
+                if n != size || len(values) != count*size {
+                    return fmt.Errorf("gltf: mesh %d has an invalid %s attribute", i, name)
+                }
+                for v := 0; v < count; v++ {
+                    set(base+v, values[v*size:(v+1)*size])
+                }
+                return nil
+            }
+            if err := attrib("NORMAL", 3, func(v int, x []float32) {
+                copy(vertices[v].Normal[:], x)
+            }); err != nil {
+                return err
+            }
+            if err := attrib("TEXCOORD_0", 2, func(v int, x []float32) {
+                copy(vertices[v].Texcoord[:], x)
+            }); err != nil {
+                return err
+            }
+            if _, ok := p.Attributes["JOINTS_0"]; ok {
+                if !skinned {
+                    skin = make([]gome.VertexSkin, base)
+                    skinned = true
+                }
+            }
+            if skinned {
+                skin = append(skin, make([]gome.VertexSkin, count)...)
+                if err := attrib("JOINTS_0", 4, func(v int, x []float32) {
+                    copy(skin[v].Joints[:], x)
+                }); err != nil {
+                    return err
+                }
+                if err := attrib("WEIGHTS_0", 4, func(v int, x []float32) {
+                    copy(skin[v].Weights[:], x)
+                }); err != nil {
+                    return err
+                }
+            }
+
+            first := len(indices)
+            if p.Indices != nil {
+                idx, err := d.indices(*p.Indices)
+                if err != nil {
+                    return err
+                }
+                for _, x := range idx {
+                    if int(x) >= count {
+                        return fmt.Errorf("gltf: mesh %d has an index out of range", i)
+                    }
+                    indices = append(indices, uint32(base)+x)
+                }
+            } else {
+                for v := 0; v < count; v++ {
+                    indices = append(indices, uint32(base+v))
+                }
+            }
+            var mat *gome.Material
+            if p.Material != nil && *p.Material >= 0 && *p.Material < len(s.Materials) {
+                mat = s.Materials[*p.Material]
+            }
+            submeshes = append(submeshes, gome.Submesh{
+                Material: mat,
+                First:    first,
+                Count:    len(indices) - first,
+            })
+        }
+
+        if len(submeshes) == 0 {
+            s.Meshes = append(s.Meshes, nil)
+            continue
+        }
+        var m *gome.Mesh
+        if skinned {
+            m = gome.NewSkinnedMesh(vertices, skin, indices, submeshes)
+        } else {
+            m = gome.NewMesh(vertices, indices, submeshes)
+        }
+        s.Meshes = append(s.Meshes, m)
+    }
+
+    for i, dn := range d.doc.Nodes {
+        if dn.Mesh != nil {
+            if *dn.Mesh < 0 || *dn.Mesh >= len(s.Meshes) {
+                return fmt.Errorf("gltf: node %d refers to invalid mesh %d", i, *dn.Mesh)
+            }
+            s.Nodes[i].Mesh = s.Meshes[*dn.Mesh]
+        }
+    }
+    return nil
+}
+
+func (d *decoder) skins(s *Scene) error {
+    for i, ds := range d.doc.Skins {
+        skin := &Skin{Name: ds.Name}
+        for _, j := range ds.Joints {
+            if j < 0 || j >= len(s.Nodes) {
+                return fmt.Errorf("gltf: skin %d refers to invalid node %d", i, j)
+            }
+            skin.Joints = append(skin.Joints, s.Nodes[j])
+        }
+        if ds.Skeleton != nil && *ds.Skeleton >= 0 && *ds.Skeleton < len(s.Nodes) {
+            skin.Skeleton = s.Nodes[*ds.Skeleton]
+        }
+        skin.InverseBindMatrices = make([]mgl32.Mat4, len(skin.Joints))
+        if ds.InverseBindMatrices != nil {
+            values, n, err := d.floats(*ds.InverseBindMatrices)
+            if err != nil {
+                return err
+            }
+            if n != 16 || len(values) < 16*len(skin.Joints) {
+                return fmt.Errorf("gltf: skin %d has invalid inverse bind matrices", i)
+            }
+            for j := range skin.InverseBindMatrices {
+                skin.InverseBindMatrices[j] = mgl32.Mat4(toArray16(values[j*16:]))
+            }
+        } else {
+            for j := range skin.InverseBindMatrices {
+                skin.InverseBindMatrices[j] = mgl32.Ident4()
+            }
+        }
+        s.Skins = append(s.Skins, skin)
+    }
+    for i, dn := range d.doc.Nodes {
+        if dn.Skin != nil {
+            if *dn.Skin < 0 || *dn.Skin >= len(s.Skins) {
+                return fmt.Errorf("gltf: node %d refers to invalid skin %d", i, *dn.Skin)
+            }
+            s.Nodes[i].Skin = s.Skins[*dn.Skin]
+        }
+    }
+    return nil
+}
+
+func (d *decoder) animations(s *Scene) error {
+    for i, da := range d.doc.Animations {
+        a := &Animation{Name: da.Name}
+        for _, dc := range da.Channels {
+            if dc.Target.Node == nil || dc.Target.Path == "weights" {
+                continue // morph targets are not supported
+            }
+            if *dc.Target.Node < 0 || *dc.Target.Node >= len(s.Nodes) ||
+                dc.Sampler < 0 || dc.Sampler >= len(da.Samplers) {
+                return fmt.Errorf("gltf: animation %d has an invalid channel", i)
+            }
+            sampler := da.Samplers[dc.Sampler]
+            times, _, err := d.floats(sampler.Input)
+            if err != nil {
+                return err
+            }
+            values, _, err := d.floats(sampler.Output)
+            if err != nil {
+                return err
+            }
+            c := &Channel{
+                Node:          s.Nodes[*dc.Target.Node],
+                Path:          dc.Target.Path,
+                Interpolation: sampler.Interpolation,
+                Times:         times,
+                Values:        values,
+            }
+            if c.Interpolation == "" {
+                c.Interpolation = Linear
+            }
+            size := 3
+            if c.Path == "rotation" {
+                size = 4
+            }
+            if c.Interpolation == CubicSpline {
+                size *= 3
+            }
+            if len(values) != len(times)*size {
+                return fmt.Errorf("gltf: animation %d has a channel with mismatched keyframes", i)
+            }
+            if n := len(times); n > 0 && times[n-1] > a.Duration {
+                a.Duration = times[n-1]
+            }
+            a.Channels = append(a.Channels, c)
+        }
+        s.Animations = append(s.Animations, a)
+    }
+    return nil
+}
+
+func toArray16(v []float32) (m [16]float32) {
+    copy(m[:], v)
+    return m
+}
+
+// decompose splits an affine transformation without shear into translation,
+// rotation and scale.
+func decompose(m mgl32.Mat4) (mgl32.Vec3, mgl32.Quat, mgl32.Vec3) {
+    t := m.Col(3).Vec3()
+    c0, c1, c2 := m.Col(0).Vec3(), m.Col(1).Vec3(), m.Col(2).Vec3()
+    s := mgl32.Vec3{c0.Len(), c1.Len(), c2.Len()}
+    if c0.Cross(c1).Dot(c2) < 0 {
+        s[0] = -s[0]
+        c0 = c0.Mul(-1)
+    }
+    var r mgl32.Mat3
+    for i, c := range []mgl32.Vec3{c0.Mul(1 / abs(s[0])), c1.Mul(1 / s[1]), c2.Mul(1 / s[2])} {
+        r.SetCol(i, c)
+    }
+    return t, mgl32.Mat4ToQuat(r.Mat4()), s
+}
+
+func abs(x float32) float32 {
+    if x < 0 {
+        return -x
+    }
+    return x
+}
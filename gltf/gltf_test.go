@@ -0,0 +1,70 @@
+// +build gomemock
+
+package gltf
+
+import (
+    "github.com/snorredc/gome"
+    "testing"
+)
+
+// lines has a mesh of lines, one of a triangle and one with both; the
+// accessors have no buffer views, so their values are all zero.
+const lines = `{
+    "asset": {"version": "2.0"},
+    "nodes": [{"mesh": 0}, {"mesh": 1}, {"mesh": 2}],
+    "meshes": [
+        {"primitives": [{"attributes": {"POSITION": 0}, "mode": 1}]},
+        {"primitives": [{"attributes": {"POSITION": 0}}]},
+        {"primitives": [
+            {"attributes": {"POSITION": 0}, "mode": 0},
+            {"attributes": {"POSITION": 0}, "mode": 4}
+        ]}
+    ],
+    "accessors": [{"componentType": 5126, "count": 3, "type": "VEC3"}]
+}`
+
+func TestMockLinesOnly(t *testing.T) {
+    app := gome.NewApp(gome.Config{})
+    if err := app.Init(); err != nil {
+        t.Fatal(err)
+    }
+    defer app.Terminate()
+
+    s, err := Decode([]byte(lines), nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer s.Delete()
+    if len(s.Meshes) != 3 {
+        t.Fatalf("%d meshes, want 3", len(s.Meshes))
+    }
+    if s.Meshes[0] != nil || s.Nodes[0].Mesh != nil {
+        t.Error("mesh of lines imported")
+    }
+    for i := 1; i < 3; i++ {
+        m := s.Meshes[i]
+        if m == nil || s.Nodes[i].Mesh != m {
+            t.Errorf("mesh %d with a triangle not imported", i)
+            continue
+        }
+        if len(m.Submeshes) != 1 || m.Submeshes[0].Count != 3 {
+            t.Errorf("mesh %d has submeshes %v, want one triangle", i, m.Submeshes)
+        }
+    }
+}
+
+func TestMockNegativeScene(t *testing.T) {
+    app := gome.NewApp(gome.Config{})
+    if err := app.Init(); err != nil {
+        t.Fatal(err)
+    }
+    defer app.Terminate()
+
+    s, err := Decode([]byte(`{"asset": {"version": "2.0"}, "scene": -1, "scenes": [{"nodes": []}]}`), nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(s.Roots) != 0 {
+        t.Errorf("roots %v, want none", s.Roots)
+    }
+}
@@ -0,0 +1,348 @@
+package gltf
+
+import (
+    "fmt"
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "time"
+)
+
+// MaxJoints is the number of joint matrices of the uniform block of
+// SkinningVertexShader; skins with more joints cannot be played.
+const MaxJoints = 128
+
+// JointsBlock is the name of the uniform block holding the joint matrices.
+const JointsBlock = "Joints"
+
+// SkinningVertexShader is a vertex shader for skinned meshes played by an
+// AnimationPlayer. It transforms the vertices by the joint matrices of the
+// player, weighted by the joints and weights attributes, and then by the
+// model and viewProjection uniforms. It passes fragPosition and fragNormal,
+// in world coordinates, and fragTexcoord on to the fragment shader.
+const SkinningVertexShader = `#version 150
+layout(std140) uniform Joints {
+    mat4 jointMatrices[128];
+};
+uniform mat4 model;
+uniform mat4 viewProjection;
+in vec3 position;
+in vec3 normal;
+in vec2 texcoord;
+in vec4 joints;
+in vec4 weights;
+out vec3 fragPosition;
+out vec3 fragNormal;
+out vec2 fragTexcoord;
+void main() {
+    mat4 skin = weights.x * jointMatrices[int(joints.x)] +
+        weights.y * jointMatrices[int(joints.y)] +
+        weights.z * jointMatrices[int(joints.z)] +
+        weights.w * jointMatrices[int(joints.w)];
+    vec4 world = model * skin * vec4(position, 1.0);
+    fragPosition = world.xyz;
+    fragNormal = mat3(model * skin) * normal;
+    fragTexcoord = texcoord;
+    gl_Position = viewProjection * world;
+}
+`
+
+// jointBlock is the layout of the Joints block.
+type jointBlock struct {
+    Matrices [MaxJoints]mgl32.Mat4
+}
+
+// pose is the local transform of a node.
+type pose struct {
+    t mgl32.Vec3
+    r mgl32.Quat
+    s mgl32.Vec3
+}
+
+// track is an animation being played.
+type track struct {
+    anim *Animation
+    time float32
+    loop bool
+}
+
+// AnimationPlayer plays the animation clips of a skin and computes its joint
+// matrices, which it uploads to a uniform block for SkinningVertexShader:
+//
+//     player, err := gltf.NewAnimationPlayer(node.Skin)
+//     if err != nil {
+//         // handle error
+//     }
+//     player.Bind(program)
+//     player.Play(scene.AnimationByName("walk"), true)
+//
+//     for app.Tick() {
+//         if running {
+//             player.CrossFade(scene.AnimationByName("run"), true, 200*time.Millisecond)
+//         }
+//         player.Update(gome.FrameTime())
+//         node.Mesh.Draw(bindMaterial)
+//     }
+//
+// The player writes the animated poses into the Translation, Rotation and
+// Scale of the nodes, so Node.World also follows the animation, e.g. to
+// attach a prop to a hand. The joint matrices include the transforms of the
+// parents of the skeleton, but not a transform of the skinned mesh node,
+// which glTF defines to have no effect; the model uniform places the
+// character in the world instead.
+type AnimationPlayer struct {
+    Skin  *Skin
+    Speed float32 // the playback rate; NewAnimationPlayer sets it to 1
+
+    block    *gome.UniformBlock
+    joints   *jointBlock
+    rest     map[*Node]pose // the poses of the nodes before any animation
+    current  track
+    previous track   // the clip faded out, if fade < fadeTime
+    fade     float32 // time since the cross-fade began
+    fadeTime float32
+}
+
+// NewAnimationPlayer returns a player for skin, in its rest pose, with a
+// uniform block named JointsBlock.
+func NewAnimationPlayer(skin *Skin) (*AnimationPlayer, error) {
+    if len(skin.Joints) > MaxJoints {
+        return nil, fmt.Errorf("gltf: skin %q has %d joints, more than %d", skin.Name, len(skin.Joints), MaxJoints)
+    }
+    p := &AnimationPlayer{
+        Skin:   skin,
+        Speed:  1,
+        joints: &jointBlock{},
+        rest:   map[*Node]pose{},
+    }
+    block, err := gome.NewUniformBlock(JointsBlock, p.joints)
+    if err != nil {
+        return nil, err
+    }
+    p.block = block
+    for _, j := range skin.Joints {
+        p.rest[j] = pose{j.Translation, j.Rotation, j.Scale}
+    }
+    p.upload()
+    return p, nil
+}
+
+// Bind makes program use the joint matrices of p (see
+// gome.UniformBlock.Bind).
+func (p *AnimationPlayer) Bind(program *gome.Program) error {
+    return p.block.Bind(program)
+}
+
+// Play starts playing a from the beginning, looping if loop is set, and
+// stops any other animation.
+func (p *AnimationPlayer) Play(a *Animation, loop bool) {
+    p.current = track{anim: a, loop: loop}
+    p.previous = track{}
+    p.fadeTime = 0
+}
+
+// CrossFade starts playing a from the beginning, blending from the animation
+// playing before over d. It does nothing if a is already playing.
+func (p *AnimationPlayer) CrossFade(a *Animation, loop bool, d time.Duration) {
+    if p.current.anim == a {
+        return
+    }
+    if p.current.anim == nil || d <= 0 {
+        p.Play(a, loop)
+        return
+    }
+    p.previous = p.current
+    p.current = track{anim: a, loop: loop}
+    p.fade, p.fadeTime = 0, float32(d.Seconds())
+}
+
+// Playing returns the animation playing, or nil.
+func (p *AnimationPlayer) Playing() *Animation {
+    return p.current.anim
+}
+
+// Time returns the position in the animation playing, in seconds.
+func (p *AnimationPlayer) Time() float32 {
+    return p.current.time
+}
+
+// Finished reports whether an animation that does not loop has reached its
+// end.
+func (p *AnimationPlayer) Finished() bool {
+    a := p.current.anim
+    return a != nil && !p.current.loop && p.current.time >= a.Duration
+}
+
+// Update advances the animations by dt, usually gome.FrameTime(), poses the
+// nodes and uploads the joint matrices.
+func (p *AnimationPlayer) Update(dt time.Duration) {
+    step := float32(dt.Seconds()) * p.Speed
+    p.current.advance(step)
+    blending := p.previous.anim != nil && p.fade < p.fadeTime
+    if blending {
+        p.previous.advance(step)
+        p.fade += step
+    }
+
+    poses := p.poses(p.current)
+    if blending {
+        from := p.poses(p.previous)
+        w := p.fade / p.fadeTime
+        if w > 1 {
+            w = 1
+        }
+        for n := range from {
+            if _, ok := poses[n]; !ok {
+                // animated by the previous clip only
+                poses[n] = p.rest[n]
+            }
+        }
+        for n, to := range poses {
+            f := from[n]
+            poses[n] = pose{
+                f.t.Add(to.t.Sub(f.t).Mul(w)),
+                mgl32.QuatSlerp(f.r, to.r, w),
+                f.s.Add(to.s.Sub(f.s).Mul(w)),
+            }
+        }
+    } else {
+        p.previous = track{}
+    }
+    for n, ps := range poses {
+        n.Translation, n.Rotation, n.Scale = ps.t, ps.r, ps.s
+    }
+    p.upload()
+}
+
+// advance moves t on by step seconds, wrapping around if it loops.
+func (t *track) advance(step float32) {
+    if t.anim == nil {
+        return
+    }
+    t.time += step
+    d := t.anim.Duration
+    switch {
+    case d <= 0:
+        t.time = 0
+    case t.loop:
+        for t.time >= d {
+            t.time -= d
+        }
+        for t.time < 0 {
+            t.time += d
+        }
+    case t.time > d:
+        t.time = d
+    }
+}
+
+// poses returns the poses of the nodes animated by the player at the point of
+// the track, starting from the rest pose.
+func (p *AnimationPlayer) poses(t track) map[*Node]pose {
+    poses := make(map[*Node]pose, len(p.rest))
+    for n, ps := range p.rest {
+        poses[n] = ps
+    }
+    if t.anim == nil {
+        return poses
+    }
+    for _, c := range t.anim.Channels {
+        ps, ok := poses[c.Node]
+        if !ok {
+            // a node outside the skin; keep its pose from here on
+            ps = pose{c.Node.Translation, c.Node.Rotation, c.Node.Scale}
+            p.rest[c.Node] = ps
+        }
+        v := c.sample(t.time)
+        switch c.Path {
+        case "translation":
+            ps.t = mgl32.Vec3{v[0], v[1], v[2]}
+        case "rotation":
+            ps.r = mgl32.Quat{W: v[3], V: mgl32.Vec3{v[0], v[1], v[2]}}.Normalize()
+        case "scale":
+            ps.s = mgl32.Vec3{v[0], v[1], v[2]}
+        }
+        poses[c.Node] = ps
+    }
+    return poses
+}
+
+// upload computes the joint matrices from the poses of the joints.
+func (p *AnimationPlayer) upload() {
+    for i, j := range p.Skin.Joints {
+        p.joints.Matrices[i] = j.World().Mul4(p.Skin.InverseBindMatrices[i])
+    }
+    p.block.Update()
+}
+
+// JointMatrix returns the matrix of joint i, as uploaded by the last Update.
+func (p *AnimationPlayer) JointMatrix(i int) mgl32.Mat4 {
+    return p.joints.Matrices[i]
+}
+
+// Delete deletes the uniform block of the player.
+func (p *AnimationPlayer) Delete() {
+    p.block.Delete()
+}
+
+// sample returns the value of c at time t, with 3 or 4 components.
+func (c *Channel) sample(t float32) []float32 {
+    size := 3
+    if c.Path == "rotation" {
+        size = 4
+    }
+    stride := size
+    if c.Interpolation == CubicSpline {
+        stride = 3 * size
+    }
+    value := func(k int) []float32 {
+        if c.Interpolation == CubicSpline {
+            // skip the in-tangent
+            return c.Values[k*stride+size : k*stride+2*size]
+        }
+        return c.Values[k*stride : k*stride+size]
+    }
+    n := len(c.Times)
+    switch {
+    case n == 0:
+        return make([]float32, size)
+    case t <= c.Times[0]:
+        return value(0)
+    case t >= c.Times[n-1]:
+        return value(n - 1)
+    }
+    k := 0
+    for k+1 < n && c.Times[k+1] <= t {
+        k++
+    }
+    t0, t1 := c.Times[k], c.Times[k+1]
+    f := (t - t0) / (t1 - t0)
+    out := make([]float32, size)
+    switch c.Interpolation {
+    case Step:
+        copy(out, value(k))
+    case CubicSpline:
+        // Hermite spline with the out-tangent of k and the in-tangent of k+1,
+        // scaled by the length of the interval
+        d := t1 - t0
+        f2, f3 := f*f, f*f*f
+        v0, v1 := value(k), value(k+1)
+        out0 := c.Values[k*stride+2*size : k*stride+3*size]
+        in1 := c.Values[(k+1)*stride : (k+1)*stride+size]
+        for i := range out {
+            out[i] = (2*f3-3*f2+1)*v0[i] + (f3-2*f2+f)*d*out0[i] +
+                (-2*f3+3*f2)*v1[i] + (f3-f2)*d*in1[i]
+        }
+    default:
+        v0, v1 := value(k), value(k+1)
+        if size == 4 {
+            q := mgl32.QuatSlerp(
+                mgl32.Quat{W: v0[3], V: mgl32.Vec3{v0[0], v0[1], v0[2]}},
+                mgl32.Quat{W: v1[3], V: mgl32.Vec3{v1[0], v1[1], v1[2]}}, f)
+            return []float32{q.V[0], q.V[1], q.V[2], q.W}
+        }
+        for i := range out {
+            out[i] = v0[i] + (v1[i]-v0[i])*f
+        }
+    }
+    return out
+}
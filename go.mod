@@ -0,0 +1,19 @@
+module github.com/snorredc/gome
+
+go 1.26.0
+
+require (
+	github.com/ebitengine/oto/v3 v3.2.0
+	github.com/go-gl/gl v0.0.0-20260331235117-4566fea9a276
+	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20260823155953-d41da22a9587
+	github.com/go-gl/mathgl v1.2.0
+	github.com/jfreymuth/oggvorbis v1.0.5
+	golang.org/x/image v0.46.0
+)
+
+require (
+	github.com/ebitengine/purego v0.7.0 // indirect
+	github.com/jfreymuth/vorbis v1.0.2 // indirect
+	golang.org/x/sys v0.48.0 // indirect
+	golang.org/x/text v0.42.0 // indirect
+)
@@ -1,141 +1,153 @@
 /*
 Package gome provides a minimal and simple library for setting up a
 graphical application using OpenGL. All functions should be called on the main
-OS thread. Init locks the goroutine to the main OS thread, so calling that early
-in main ensures that any subsequent calls in main are on the right thread.
+OS thread. App.Init locks the goroutine to the main OS thread, so calling that
+early in main ensures that any subsequent calls in main are on the right
+thread.
 
 The main loop of the application then looks like this:
 
-    if err := gome.Init(); err != nil {
+    app := gome.NewApp(gome.Config{})
+    if err := app.Init(); err != nil {
         // handle error
     }
-    defer gome.Terminate()
+    defer app.Terminate()
 
     initGL()
     render()
-    gome.Tick()
+    app.Tick()
 
-    gome.Window.Show()
+    app.Window().Show()
 
-    for gome.Tick() {
+    for app.Tick() {
         update()
         render()
     }
-    if err := gome.GetError(); err != nil {
+    if err := app.GetError(); err != nil {
         // handle error
     }
 
 A time.Ticker can be used to limit framerate.
+
+Optional features with heavier dependencies are in sub-packages: audio for
+sound, gltf for models and text for TrueType and OpenType fonts.
+
+Building with the gomemock tag replaces OpenGL and the window with a mock
+backend, which records the calls in a log instead (see MockCommand), so tests
+of game logic and of what gets rendered run on machines without a display or
+GPU:
+
+    go test -tags gomemock ./...
+
+The mock backend needs neither cgo nor the development headers of the window
+system or of ALSA, and audio plays nothing (see audio.MockMix). The types of
+keys and buttons are then defined by gome, with the values of GLFW, so the
+code under test must not import go-gl/glfw itself.
 */
 package gome
 
 import (
     "errors"
-    "github.com/go-gl/gl"
-    "github.com/go-gl/glfw3"
-    "github.com/go-gl/glu"
-    "runtime"
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+    "github.com/snorredc/gome/internal/glfw"
 )
 
 var (
-    ErrGLFW3Initialize = errors.New("could not initialise GLFW3")
-    ErrGLEWInitialize  = errors.New("could not initialise GLEW")
+    ErrGLFW3Initialize = errors.New("could not initialise GLFW")
+    ErrGLEWInitialize  = errors.New("could not load the OpenGL functions")
+    ErrAppRunning      = errors.New("another App is already initialised")
 )
 
-type glError gl.GLenum
+type glError uint32
+
+// glErrorNames are the messages of the codes returned by glGetError, as
+// gluErrorString would give them.
+var glErrorNames = map[glError]string{
+    gl.INVALID_ENUM:                  "invalid enumerant",
+    gl.INVALID_VALUE:                 "invalid value",
+    gl.INVALID_OPERATION:             "invalid operation",
+    gl.INVALID_FRAMEBUFFER_OPERATION: "invalid framebuffer operation",
+    gl.OUT_OF_MEMORY:                 "out of memory",
+    gl.STACK_OVERFLOW:                "stack overflow",
+    gl.STACK_UNDERFLOW:               "stack underflow",
+}
 
 func (e glError) Error() string {
-    // it seems like GLU cannot be built under Go 1.3 (had to patch it)
-    m, err := glu.ErrorString(gl.GLenum(e))
-    if err != nil {
-        return err.Error()
+    if m, ok := glErrorNames[e]; ok {
+        return m
     }
-    return m
+    return fmt.Sprintf("unknown GL error 0x%04X", uint32(e))
 }
 
-var tickError error
-
-// GetError polls OpenGL for an error and returns that.
-func GetError() error {
-    if e := tickError; e != nil {
-        tickError = nil
-        return e
+// pollGLError returns the next error reported by glGetError, if any, and
+// keeps it for crash reports.
+func pollGLError() error {
+    if app == nil {
+        // no context to ask
+        return nil
     }
-    if code := gl.GetError(); code != 0 {
-        return glError(code)
+    code := gl.GetError()
+    if code == 0 {
+        return nil
     }
-    return nil
+    stats.glErrors++
+    noteGLError(glError(code))
+    return glError(code)
 }
 
-// Window is the main window of the application. This is created automatically
-// by Init and has dimensions 800x600 by default. It is hidden by default, so
-// the application should call gome.Window.Show() after any initialisation code.
-var Window *glfw3.Window
-
-// ShouldClose reflects whether the main loop should end. Setting ShouldClose to
-// true causes gome.Tick to return false, which should end the main loop.
-var ShouldClose = false
-
-// Init initialises GLFW3 and OpenGL and creates the main window (see Window).
-// After this has returned OpenGL functions as well as gome.Tick can be used.
-// It also locks the current OS thread (see runtime.LockOSThread).
-func Init() error {
-    runtime.LockOSThread()
-
-    if !glfw3.Init() {
-        return ErrGLFW3Initialize
-    }
-
-    // request OpenGL 3.2 (forward compatible, core)
-    glfw3.WindowHint(glfw3.ContextVersionMajor, 3)
-    glfw3.WindowHint(glfw3.ContextVersionMinor, 2) // or 3
-    glfw3.WindowHint(glfw3.OpenglForwardCompatible, 1)
-    glfw3.WindowHint(glfw3.OpenglProfile, glfw3.OpenglCoreProfile)
+// The package-level functions and variables below predate App. They act on
+// the App created by Init.
 
-    // glfw3.WindowHint(glfw3.Visible, 0)
-    window, err := glfw3.CreateWindow(800, 600, "Gome", nil, nil)
-    if err != nil {
-        return err
+// GetError returns the error of the initialised App (see App.GetError), or
+// polls OpenGL for an error if there is none.
+//
+// Deprecated: Use App.GetError.
+func GetError() error {
+    if app != nil {
+        return app.GetError()
     }
-    window.MakeContextCurrent()
-    Window = window
+    return pollGLError()
+}
 
-    glfw3.SwapInterval(1)
+// Window is the main window of the App created by Init, and nil otherwise.
+// It has dimensions 800x600 by default. It is hidden by default, so the
+// application should call gome.Window.Show() after any initialisation code.
+//
+// Deprecated: Use App.Window.
+var Window *glfw.Window
+
+// ShouldClose reflects whether the main loop should end. Setting ShouldClose
+// to true causes Tick to return false, which should end the main loop.
+//
+// Deprecated: Use App.RequestClose.
+var ShouldClose = false
 
-    if err := gl.Init(); err != 0 {
-        return ErrGLEWInitialize
-    }
+// Init creates an App with the default settings and initialises it (see
+// App.Init).
+//
+// Deprecated: Use NewApp and App.Init.
+func Init() error {
+    return InitWithConfig(Config{})
+}
 
-    errcode := gl.GetError()
-    for errcode == gl.INVALID_ENUM {
-        errcode = gl.GetError()
-    }
-    if errcode != 0 {
-        return glError(errcode)
-    }
-    return nil
+// InitWithConfig is like Init, with the settings of c.
+//
+// Deprecated: Use NewApp and App.Init.
+func InitWithConfig(c Config) error {
+    return NewApp(c).Init()
 }
 
-// Tick swaps the buffers of the main window and polls GLFW3 for events. It
-// returns true if the main loop should continue and false otherwise. It only
-// returns false if ShouldClose is true, the window is being closed or if
-// OpenGL reports an error.
+// Tick runs a frame of the initialised App (see App.Tick).
+//
+// Deprecated: Use App.Tick.
 func Tick() bool {
-    if err := GetError(); err != nil {
-        tickError = err
-        return false
-    }
-    if ShouldClose || Window.ShouldClose() {
-        return false
-    }
-    Window.SwapBuffers()
-    glfw3.PollEvents()
-    return true
+    return app.Tick()
 }
 
-// Terminate cleans up and terminates GLFW3. It should be called after the main
-// loop has finished, e.g. by deferring it in the main function.
+// Terminate terminates the initialised App (see App.Terminate).
+//
+// Deprecated: Use App.Terminate.
 func Terminate() {
-    Window.Destroy()
-    glfw3.Terminate()
+    app.Terminate()
 }
@@ -0,0 +1,155 @@
+package gometest
+
+import (
+    "flag"
+    "image"
+    "image/color"
+    "image/png"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+var update = flag.Bool("gometest.update", false, "write the frames rendered as the golden images instead of comparing them")
+
+// Threshold is how different two pixels have to look to count as different,
+// from 0 to 1, in the perceptual metric of AssertImage. The default ignores
+// differences of a few levels, e.g. from rounding on different GPUs.
+var Threshold = 0.1
+
+// AssertImage compares the last image rendered by RunFrame in the test with
+// the golden PNG image at path, and fails the test if more than the fraction
+// tolerance of the pixels differ, or if the sizes differ. Pixels are compared
+// by how different they look, weighting brightness over hue like the eye
+// (see Threshold), so tolerances can be small. On failure, the image and an
+// image highlighting the differing pixels in red are written next to the
+// golden image, with the suffixes .failed.png and .diff.png.
+//
+// With the -gometest.update flag, the image is written to path instead.
+func AssertImage(t testing.TB, path string, tolerance float64) {
+    t.Helper()
+    mu.Lock()
+    img := frames[t]
+    mu.Unlock()
+    if img == nil {
+        t.Fatal("gometest: AssertImage needs an image rendered by RunFrame")
+    }
+    if *update {
+        if err := writePNG(path, img); err != nil {
+            t.Fatalf("gometest: %v", err)
+        }
+        t.Logf("gometest: updated %s", path)
+        return
+    }
+
+    golden, err := readPNG(path)
+    if os.IsNotExist(err) {
+        t.Fatalf("gometest: %s does not exist; run the test with -gometest.update to create it", path)
+    }
+    if err != nil {
+        t.Fatalf("gometest: %v", err)
+    }
+    if golden.Bounds().Size() != img.Bounds().Size() {
+        writeFailure(t, path, img, nil)
+        t.Fatalf("gometest: image is %v, but %s is %v", img.Bounds().Size(), path, golden.Bounds().Size())
+    }
+    diff, n := compare(img, golden)
+    total := img.Bounds().Dx() * img.Bounds().Dy()
+    if float64(n) > tolerance*float64(total) {
+        writeFailure(t, path, img, diff)
+        t.Errorf("gometest: %d of %d pixels (%.3f%%) differ from %s, more than %.3f%%",
+            n, total, 100*float64(n)/float64(total), path, 100*tolerance)
+    }
+}
+
+// maxDelta is the largest value of yiqDelta, between black and white.
+const maxDelta = 35215
+
+// compare returns an image of the differences between a and b, which have
+// the same size, and the number of pixels that differ.
+func compare(a *image.NRGBA, b image.Image) (*image.NRGBA, int) {
+    r := a.Bounds()
+    bb := b.Bounds()
+    diff := image.NewNRGBA(r)
+    limit := maxDelta * Threshold * Threshold
+    n := 0
+    for y := 0; y < r.Dy(); y++ {
+        for x := 0; x < r.Dx(); x++ {
+            ca := a.NRGBAAt(r.Min.X+x, r.Min.Y+y)
+            cb := color.NRGBAModel.Convert(b.At(bb.Min.X+x, bb.Min.Y+y)).(color.NRGBA)
+            if yiqDelta(ca, cb) > limit {
+                diff.SetNRGBA(x, y, color.NRGBA{255, 0, 0, 255})
+                n++
+                continue
+            }
+            // the matching pixels faded, for context
+            l := uint8(255 - (255-luma(ca))/4)
+            diff.SetNRGBA(x, y, color.NRGBA{l, l, l, 255})
+        }
+    }
+    return diff, n
+}
+
+// blend returns the colour of c over white, as the differences in
+// transparent golden images are compared.
+func blend(c color.NRGBA) (r, g, b float64) {
+    a := float64(c.A) / 255
+    return 255 + (float64(c.R)-255)*a, 255 + (float64(c.G)-255)*a, 255 + (float64(c.B)-255)*a
+}
+
+// yiqDelta returns the perceptual difference between two colours, measured
+// in the YIQ colour space (Kotsarenko and Ramos, "Measuring perceived colour
+// difference using YIQ NTSC transmission colour space in mobile applications").
+func yiqDelta(c1, c2 color.NRGBA) float64 {
+    r1, g1, b1 := blend(c1)
+    r2, g2, b2 := blend(c2)
+    dr, dg, db := r1-r2, g1-g2, b1-b2
+    y := dr*0.29889531 + dg*0.58662247 + db*0.11448223
+    i := dr*0.59597799 - dg*0.27417610 - db*0.32180189
+    q := dr*0.21147017 - dg*0.52261711 + db*0.31114694
+    return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+}
+
+func luma(c color.NRGBA) uint8 {
+    r, g, b := blend(c)
+    return uint8(r*0.29889531 + g*0.58662247 + b*0.11448223)
+}
+
+func writeFailure(t testing.TB, path string, img, diff *image.NRGBA) {
+    base := strings.TrimSuffix(path, filepath.Ext(path))
+    if err := writePNG(base+".failed.png", img); err != nil {
+        t.Logf("gometest: %v", err)
+    }
+    if diff == nil {
+        return
+    }
+    if err := writePNG(base+".diff.png", diff); err != nil {
+        t.Logf("gometest: %v", err)
+    }
+    t.Logf("gometest: wrote %s.failed.png and %s.diff.png", base, base)
+}
+
+func readPNG(path string) (image.Image, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    return png.Decode(f)
+}
+
+func writePNG(path string, img image.Image) error {
+    if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+        return err
+    }
+    f, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    if err := png.Encode(f, img); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
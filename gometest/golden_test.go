@@ -0,0 +1,46 @@
+package gometest
+
+import (
+    "image"
+    "image/color"
+    "testing"
+)
+
+func TestCompare(t *testing.T) {
+    fill := func(c color.NRGBA) *image.NRGBA {
+        img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+        for i := 0; i < len(img.Pix); i += 4 {
+            img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+        }
+        return img
+    }
+    grey := color.NRGBA{128, 128, 128, 255}
+    tests := []struct {
+        name string
+        b    func() *image.NRGBA
+        n    int
+    }{
+        {"same", func() *image.NRGBA { return fill(grey) }, 0},
+        {"rounding", func() *image.NRGBA { return fill(color.NRGBA{130, 127, 129, 255}) }, 0},
+        {"one pixel", func() *image.NRGBA {
+            img := fill(grey)
+            img.SetNRGBA(2, 1, color.NRGBA{255, 0, 0, 255})
+            return img
+        }, 1},
+        {"all", func() *image.NRGBA { return fill(color.NRGBA{0, 0, 0, 255}) }, 16},
+        // transparent pixels are compared over white
+        {"transparent", func() *image.NRGBA { return fill(color.NRGBA{0, 0, 0, 0}) }, 16},
+    }
+    for _, tt := range tests {
+        diff, n := compare(fill(grey), tt.b())
+        if n != tt.n {
+            t.Errorf("%s: %d pixels differ, want %d", tt.name, n, tt.n)
+        }
+        if n == 1 && diff.NRGBAAt(2, 1) != (color.NRGBA{255, 0, 0, 255}) {
+            t.Errorf("%s: differing pixel not marked red", tt.name)
+        }
+    }
+    if _, n := compare(fill(color.NRGBA{255, 255, 255, 255}), fill(color.NRGBA{0, 0, 0, 0})); n != 0 {
+        t.Errorf("white and transparent differ in %d pixels, want 0", n)
+    }
+}
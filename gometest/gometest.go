@@ -0,0 +1,182 @@
+/*
+Package gometest runs rendering code in tests and compares the frames it
+renders with golden images, for visual regression tests.
+
+OpenGL has to be used from the main thread, while tests run on goroutines of
+their own, so the package takes over the main thread in TestMain:
+
+    func TestMain(m *testing.M) {
+        gometest.Main(m)
+    }
+
+    func TestTriangle(t *testing.T) {
+        gometest.RunFrame(t, func() {
+            drawTriangle()
+        })
+        gometest.AssertImage(t, "testdata/triangle.png", 0.001)
+    }
+
+The frames are rendered offscreen to a target of the size in Config, with the
+main window hidden. Running the tests with -gometest.update writes the
+frames as the golden images instead of comparing them, to create them or to
+accept a change. If OpenGL cannot be initialised, e.g. on a build machine
+without a display, the tests calling RunFrame are skipped; xvfb-run with
+Mesa's software renderer gives results that do not depend on the GPU.
+*/
+package gometest
+
+import (
+    "github.com/snorredc/gome"
+    "github.com/snorredc/gome/internal/gl"
+    "image"
+    "os"
+    "runtime"
+    "sync"
+    "testing"
+)
+
+func init() {
+    // the main goroutine runs TestMain, which has to stay on the main thread
+    runtime.LockOSThread()
+}
+
+// Config is the configuration of the App created by Main. Width and Height
+// are the size of the frames rendered. Main sets Hidden.
+var Config = gome.Config{Width: 256, Height: 256, Title: "gometest"}
+
+var (
+    app     *gome.App
+    initErr error
+    calls   chan func()
+    done    chan int // the exit code of the tests
+
+    mu     sync.Mutex
+    frames = map[testing.TB]*image.NRGBA{}
+)
+
+// Main initialises an App for the tests, runs them and exits. It has to be
+// called from TestMain, as it keeps the main thread to run the frames of
+// RunFrame.
+func Main(m *testing.M) {
+    Config.Hidden = true
+    app = gome.NewApp(Config)
+    if initErr = app.Init(); initErr == nil {
+        initErr = gome.SetVirtualResolution(Config.Width, Config.Height, gome.Stretch)
+    }
+    calls = make(chan func())
+    done = make(chan int)
+    go func() {
+        done <- m.Run()
+    }()
+    serve()
+}
+
+// serve runs the functions queued by RunFrame and Do until the tests are
+// done, and exits.
+func serve() {
+    for {
+        select {
+        case f := <-calls:
+            f()
+        case code := <-done:
+            app.Terminate()
+            os.Exit(code)
+        }
+    }
+}
+
+// RunFrame runs a frame of the App of Main, calling render to draw it, and
+// returns the image rendered, which AssertImage compares too. The frame
+// starts cleared with the clear colour (see SetClearColor), whatever the
+// frame before left and whatever SetAutoClear is set to. The tests share the
+// App, so state such as the clear colour set by one test carries over to
+// those after it. The test is skipped if the App could not be initialised.
+//
+// render runs on the main thread, not on the goroutine of the test, so it
+// must not use t: t.Fatal and the like only stop a test from its own
+// goroutine. A panic in render fails the test.
+func RunFrame(t testing.TB, render func()) *image.NRGBA {
+    t.Helper()
+    if calls == nil {
+        t.Fatal("gometest: RunFrame needs gometest.Main to be called from TestMain")
+    }
+    if initErr != nil {
+        t.Skipf("gometest: no OpenGL: %v", initErr)
+    }
+    var img *image.NRGBA
+    var err error
+    onMain(t, func() {
+        gome.BindScreen()
+        gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
+        render()
+        if err = app.GetError(); err != nil {
+            return
+        }
+        img = gome.ReadScreen()
+        app.Tick()
+        err = app.GetError()
+    })
+    if err != nil {
+        t.Fatalf("gometest: %v", err)
+    }
+    mu.Lock()
+    frames[t] = img
+    mu.Unlock()
+    t.Cleanup(func() {
+        mu.Lock()
+        delete(frames, t)
+        mu.Unlock()
+    })
+    return img
+}
+
+// Do runs f on the main thread, e.g. to create the textures and programs a
+// test renders with, and waits for it to return. Like RunFrame, it skips the
+// test if the App could not be initialised, and f must not use t.
+func Do(t testing.TB, f func()) {
+    t.Helper()
+    if calls == nil {
+        t.Fatal("gometest: Do needs gometest.Main to be called from TestMain")
+    }
+    if initErr != nil {
+        t.Skipf("gometest: no OpenGL: %v", initErr)
+    }
+    onMain(t, f)
+}
+
+// onMain runs f on the main thread for the test t and waits for it to
+// return. If f panics, or exits its goroutine as t.FailNow does, t fails
+// and the main thread goes on running the functions queued by the tests.
+func onMain(t testing.TB, f func()) {
+    t.Helper()
+    var failure interface{}
+    exited := false
+    finished := make(chan struct{})
+    calls <- func() {
+        returned := false
+        defer func() {
+            if !returned {
+                if failure = recover(); failure == nil {
+                    exited = true
+                }
+            }
+            close(finished)
+            if exited {
+                // runtime.Goexit cannot be stopped, so the main thread
+                // serves on from its deferred calls
+                serve()
+            }
+        }()
+        f()
+        returned = true
+    }
+    <-finished
+    switch {
+    case failure != nil:
+        t.Fatalf("gometest: panic on the main thread: %v", failure)
+    case exited && t.Skipped():
+        t.SkipNow()
+    case exited:
+        t.Fatal("gometest: the test was stopped on the main thread, where t must not be used")
+    }
+}
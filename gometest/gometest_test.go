@@ -0,0 +1,105 @@
+// +build gomemock
+
+package gometest
+
+import (
+    "fmt"
+    "github.com/snorredc/gome"
+    "github.com/snorredc/gome/internal/gl"
+    "github.com/snorredc/gome/internal/glfw"
+    "image/color"
+    "runtime"
+    "strings"
+    "testing"
+)
+
+func TestMain(m *testing.M) {
+    Config.Width, Config.Height = 16, 16
+    Main(m)
+}
+
+func TestHiddenWindow(t *testing.T) {
+    var visible int
+    Do(t, func() { visible = app.Window().GetAttrib(glfw.Visible) })
+    if visible != glfw.False {
+        t.Error("the main window is visible")
+    }
+    RunFrame(t, func() {})
+    Do(t, func() { visible = app.Window().GetAttrib(glfw.Visible) })
+    if visible != glfw.False {
+        t.Error("the main window is visible after a frame")
+    }
+}
+
+func TestSnapshotHidden(t *testing.T) {
+    img := RunFrame(t, func() {})
+    if size := img.Bounds().Size(); size.X != 16 || size.Y != 16 {
+        t.Errorf("frame of %v, want 16x16", size)
+    }
+    // the mock backend draws nothing, so the frame is opaque black
+    AssertImage(t, "testdata/blank.png", 0)
+}
+
+func TestFrameCleared(t *testing.T) {
+    red := gome.Color{R: 1, A: 1}
+    var old gome.Color
+    Do(t, func() {
+        old = gome.ClearColor()
+        gome.SetAutoClear(false)
+    })
+    img := RunFrame(t, func() {
+        gome.SetClearColor(red)
+        gl.Clear(gl.COLOR_BUFFER_BIT)
+    })
+    if c := img.NRGBAAt(8, 8); c != (color.NRGBA{255, 0, 0, 255}) {
+        t.Errorf("frame cleared to %v, want red", c)
+    }
+    Do(t, func() { gome.SetClearColor(old) })
+    // nothing of the red frame is left
+    RunFrame(t, func() {})
+    AssertImage(t, "testdata/blank.png", 0)
+}
+
+// stoppingTB records the failure of a test instead of failing it.
+type stoppingTB struct {
+    testing.TB
+    failure string
+}
+
+func (t *stoppingTB) Fatal(args ...interface{}) {
+    t.failure = fmt.Sprint(args...)
+    runtime.Goexit()
+}
+
+func (t *stoppingTB) Fatalf(format string, args ...interface{}) {
+    t.Fatal(fmt.Sprintf(format, args...))
+}
+
+func (t *stoppingTB) Skipped() bool {
+    return false
+}
+
+// failure returns how RunFrame with render fails the test.
+func failure(t *testing.T, render func()) string {
+    tb := &stoppingTB{TB: t}
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        RunFrame(tb, render)
+    }()
+    <-done
+    return tb.failure
+}
+
+func TestStoppedOnMainThread(t *testing.T) {
+    // as t.FailNow does
+    if f := failure(t, runtime.Goexit); !strings.Contains(f, "stopped on the main thread") {
+        t.Errorf("failure %q, want the test stopped on the main thread", f)
+    }
+    if f := failure(t, func() { panic("oops") }); f != "gometest: panic on the main thread: oops" {
+        t.Errorf("failure %q, want the panic", f)
+    }
+    // the main thread still runs the frames
+    RunFrame(t, func() {})
+    AssertImage(t, "testdata/blank.png", 0)
+}
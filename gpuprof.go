@@ -0,0 +1,102 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/gl"
+)
+
+// ZoneTiming is the time spent in a named profiling zone during one frame.
+// Zones that were entered several times in a frame are summed up.
+type ZoneTiming struct {
+    Name         string
+    Milliseconds float64
+    Calls        int // number of times the zone was entered
+    Depth        int // nesting depth, always 0 for GPU zones
+}
+
+// Timer query results only become available a few frames after they were
+// issued. Reading them earlier would stall the pipeline, so queries are kept
+// around for gpuLatency frames before their results are collected.
+const gpuLatency = 3
+
+type gpuQuery struct {
+    name  string
+    query uint32
+}
+
+type gpuProfiler struct {
+    frames  [gpuLatency][]gpuQuery
+    current int
+    active  bool
+    free    []uint32
+    zones   []ZoneTiming
+}
+
+var gpuProf gpuProfiler
+
+// BeginGPUZone starts timing the GPU work of all GL commands issued until the
+// matching EndGPUZone. GPU zones cannot be nested: beginning a zone while
+// another one is active ends the active one first. OpenGL ES has no timer
+// queries, so there GPU zones do nothing and GPUZones returns none.
+func BeginGPUZone(name string) {
+    p := &gpuProf
+    if obtainedContext.Profile == ESProfile {
+        return
+    }
+    if p.active {
+        EndGPUZone()
+    }
+    var q uint32
+    if n := len(p.free); n > 0 {
+        q, p.free = p.free[n-1], p.free[:n-1]
+    } else {
+        gl.GenQueries(1, &q)
+    }
+    gl.BeginQuery(gl.TIME_ELAPSED, q)
+    p.frames[p.current] = append(p.frames[p.current], gpuQuery{name, q})
+    p.active = true
+}
+
+// EndGPUZone ends the active GPU zone.
+func EndGPUZone() {
+    if !gpuProf.active {
+        return
+    }
+    gl.EndQuery(gl.TIME_ELAPSED)
+    gpuProf.active = false
+}
+
+// GPUZones returns the GPU timings of the most recent frame for which results
+// are available, which is usually a few frames behind the current one. The
+// zones are in the order they were first begun.
+func GPUZones() []ZoneTiming {
+    return append([]ZoneTiming(nil), gpuProf.zones...)
+}
+
+// endFrame is called at every Tick. It collects the results of the oldest
+// frame and makes its slot available for the next frame.
+func (p *gpuProfiler) endFrame() {
+    EndGPUZone()
+    p.current = (p.current + 1) % gpuLatency
+    queries := p.frames[p.current]
+    if len(queries) == 0 {
+        return
+    }
+
+    p.zones = p.zones[:0]
+    index := map[string]int{}
+    var result [1]uint32
+    for _, q := range queries {
+        // blocks if the result is still not available
+        gl.GetQueryObjectuiv(q.query, gl.QUERY_RESULT, &result[0])
+        ms := float64(result[0]) / 1e6
+        if i, ok := index[q.name]; ok {
+            p.zones[i].Milliseconds += ms
+            p.zones[i].Calls++
+        } else {
+            index[q.name] = len(p.zones)
+            p.zones = append(p.zones, ZoneTiming{q.name, ms, 1, 0})
+        }
+        p.free = append(p.free, q.query)
+    }
+    p.frames[p.current] = queries[:0]
+}
@@ -0,0 +1,29 @@
+// +build gomemock
+
+package gome
+
+import (
+    "testing"
+)
+
+func TestMockGPUZones(t *testing.T) {
+    tests := []struct {
+        name     string
+        contexts []ContextVersion
+        queries  int
+    }{
+        {"desktop", nil, 1},
+        // OpenGL ES has no timer queries
+        {"ES", ESContexts, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            initMock(t, Config{Contexts: tt.contexts})
+            BeginGPUZone("draw")
+            EndGPUZone()
+            if n := len(MockCommands("gl.BeginQuery")); n != tt.queries {
+                t.Errorf("%d queries begun, want %d", n, tt.queries)
+            }
+        })
+    }
+}
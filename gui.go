@@ -0,0 +1,436 @@
+package gome
+
+import (
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+    "github.com/snorredc/gome/internal/glfw"
+    "log/slog"
+    "strings"
+)
+
+// DebugGui is an immediate-mode GUI for tweaking parameters while developing.
+// Widgets are declared anew every frame, between Tick calls, and report
+// their interaction right away:
+//
+//     for app.Tick() {
+//         if gome.Gui.Begin("Settings") {
+//             gome.Gui.SliderFloat("speed", &speed, 0, 10)
+//             gome.Gui.Checkbox("wireframe", &wireframe)
+//             if gome.Gui.Button("reset") {
+//                 reset()
+//             }
+//         }
+//         gome.Gui.End()
+//         ...
+//     }
+//
+// The windows are drawn by Tick like the debug overlay, below it. Their
+// positions and whether they are collapsed are kept per title; dragging the
+// title bar moves a window and clicking it collapses it. Widgets are
+// identified by their window and label, so labels within a window have to
+// be unique; a suffix after ## is not shown, e.g. "x##pos" and "x##size" are
+// two sliders called x. Widgets used outside Begin and End go into a window
+// called Debug.
+type DebugGui struct {
+    windows map[string]*guiWindow
+    order   []*guiWindow // in drawing order, the topmost last
+    current *guiWindow
+
+    frameBegun bool
+    mouseX     float32 // the cursor in framebuffer pixels
+    mouseY     float32
+    hot        *guiWindow // the window under the cursor
+    active     string     // the id of the widget being pressed
+    dragged    bool       // the active title bar was moved
+
+    batch *SpriteBatch
+    font  *Font
+}
+
+type guiWindow struct {
+    title     string
+    x, y      float32
+    w, h      float32 // the size at the last draw
+    collapsed bool
+    used      bool    // begun in this frame
+    rowY      float32 // where the next widget goes, relative to y
+    width     float32 // the content width so far
+    cmds      []guiCmd
+}
+
+// guiCmd is a rectangle or a text recorded by a widget.
+type guiCmd struct {
+    rect  Rect
+    color Color
+    text  string
+}
+
+// Gui is the debug GUI of the main window.
+var Gui = &DebugGui{}
+
+var (
+    guiWidget = MustHex("#3a3a3a")
+    guiHover  = MustHex("#4e4e4e")
+    guiActive = MustHex("#2a2a2a")
+    guiAccent = MustHex("#4c8ed9")
+    guiTitle  = MustHex("#284a70")
+)
+
+// gui layout, in pixels
+const (
+    guiPadding     = 6
+    guiSpacing     = 4
+    guiSliderWidth = 160
+)
+
+// Begin starts the window called title, creating it the first time, and
+// makes the following widgets part of it until End. It reports whether the
+// window is expanded; the widgets of a collapsed window do nothing, so the
+// result can be ignored.
+func (g *DebugGui) Begin(title string) bool {
+    g.beginFrame()
+    if g.current != nil {
+        g.End()
+    }
+    win := g.windows[title]
+    if win == nil {
+        // cascade new windows from the top left
+        n := float32(len(g.windows))
+        win = &guiWindow{title: title, x: 20 + 30*n, y: 20 + 30*n}
+        g.windows[title] = win
+        g.order = append(g.order, win)
+    }
+    g.current = win
+    if win.used {
+        // begun twice in a frame; the widgets are appended
+        return !win.collapsed
+    }
+    win.used = true
+    win.cmds = win.cmds[:0]
+    win.rowY = g.rowHeight() + guiPadding
+    win.width, _ = g.font.Measure(title)
+    win.width += 2 * guiPadding
+
+    id := title + "##title"
+    bar := Rect{win.x, win.y, win.w, g.rowHeight()}
+    if g.press(win, id, bar) {
+        g.dragged = false
+        g.raise(win)
+    }
+    if g.active == id {
+        if dx, dy := g.cursorDelta(); dx != 0 || dy != 0 {
+            win.x += dx
+            win.y += dy
+            g.dragged = true
+        }
+        if MouseButtonReleased(glfw.MouseButtonLeft) && !g.dragged {
+            win.collapsed = !win.collapsed
+        }
+    } else if g.hot == win && MouseButtonPressed(glfw.MouseButtonLeft) {
+        g.raise(win)
+    }
+    return !win.collapsed
+}
+
+// End ends the current window.
+func (g *DebugGui) End() {
+    g.current = nil
+}
+
+// Text shows a line of text, formatted with fmt.Sprint.
+func (g *DebugGui) Text(a ...interface{}) {
+    win := g.window()
+    if win == nil {
+        return
+    }
+    text := fmt.Sprint(a...)
+    w, h := g.font.Measure(text)
+    g.text(win, text, win.x+guiPadding, win.y+win.rowY+(g.rowHeight()-g.font.Height)/2, White)
+    g.row(win, w, h)
+}
+
+// Button shows a button and reports whether it was clicked in this frame.
+func (g *DebugGui) Button(label string) bool {
+    win := g.window()
+    if win == nil {
+        return false
+    }
+    id, text := g.id(win, label)
+    w, _ := g.font.Measure(text)
+    r := Rect{win.x + guiPadding, win.y + win.rowY, w + 2*guiPadding, g.rowHeight()}
+    g.press(win, id, r)
+    clicked := g.active == id && g.hovered(win, r) && MouseButtonReleased(glfw.MouseButtonLeft)
+    g.rect(win, r, g.background(win, id, r))
+    g.text(win, text, r.X+guiPadding, r.Y+(r.H-g.font.Height)/2, White)
+    g.row(win, r.W, r.H)
+    return clicked
+}
+
+// Checkbox shows a checkbox for *value, toggling it when clicked, and reports
+// whether it changed in this frame.
+func (g *DebugGui) Checkbox(label string, value *bool) bool {
+    win := g.window()
+    if win == nil {
+        return false
+    }
+    id, text := g.id(win, label)
+    box := g.rowHeight()
+    w, _ := g.font.Measure(text)
+    r := Rect{win.x + guiPadding, win.y + win.rowY, box + guiSpacing + w, box}
+    g.press(win, id, r)
+    changed := g.active == id && g.hovered(win, r) && MouseButtonReleased(glfw.MouseButtonLeft)
+    if changed {
+        *value = !*value
+    }
+    g.rect(win, Rect{r.X, r.Y, box, box}, g.background(win, id, r))
+    if *value {
+        g.rect(win, Rect{r.X + box/4, r.Y + box/4, box / 2, box / 2}, guiAccent)
+    }
+    g.text(win, text, r.X+box+guiSpacing, r.Y+(box-g.font.Height)/2, White)
+    g.row(win, r.W, r.H)
+    return changed
+}
+
+// SliderFloat shows a slider for *value between min and max, setting it while
+// the slider is dragged, and reports whether it changed in this frame.
+func (g *DebugGui) SliderFloat(label string, value *float32, min, max float32) bool {
+    return g.slider(label, fmt.Sprintf("%.3f", *value), func(f float32) bool {
+        v := min + f*(max-min)
+        changed := v != *value
+        *value = v
+        return changed
+    }, (*value-min)/(max-min))
+}
+
+// SliderInt is like SliderFloat for an integer value.
+func (g *DebugGui) SliderInt(label string, value *int, min, max int) bool {
+    return g.slider(label, fmt.Sprint(*value), func(f float32) bool {
+        v := min + int(f*float32(max-min)+0.5)
+        changed := v != *value
+        *value = v
+        return changed
+    }, float32(*value-min)/float32(max-min))
+}
+
+// slider shows a slider at the fraction f, showing valueText, and calls set
+// with the fraction of the cursor while it is dragged.
+func (g *DebugGui) slider(label, valueText string, set func(f float32) bool, f float32) bool {
+    win := g.window()
+    if win == nil {
+        return false
+    }
+    id, text := g.id(win, label)
+    r := Rect{win.x + guiPadding, win.y + win.rowY, guiSliderWidth, g.rowHeight()}
+    g.press(win, id, r)
+    changed := false
+    if g.active == id && r.W > 0 {
+        f = clamp01((g.mouseX - r.X) / r.W)
+        changed = set(f)
+    }
+    if f != f {
+        // min == max
+        f = 0
+    }
+    f = clamp01(f)
+    g.rect(win, r, g.background(win, id, r))
+    g.rect(win, Rect{r.X, r.Y, r.W * f, r.H}, guiAccent.WithAlpha(0.6))
+    vw, _ := g.font.Measure(valueText)
+    g.text(win, valueText, r.X+(r.W-vw)/2, r.Y+(r.H-g.font.Height)/2, White)
+    w, _ := g.font.Measure(text)
+    g.text(win, text, r.X+r.W+guiSpacing, r.Y+(r.H-g.font.Height)/2, White)
+    g.row(win, r.W+guiSpacing+w, r.H)
+    return changed
+}
+
+// WantsMouse reports whether the cursor is over a window of the GUI or one of
+// its widgets is being pressed, so the application should ignore the mouse.
+func (g *DebugGui) WantsMouse() bool {
+    return g.hot != nil || g.active != ""
+}
+
+// window returns the current window, the Debug window outside Begin and End,
+// or nil if the window is collapsed.
+func (g *DebugGui) window() *guiWindow {
+    if g.current == nil {
+        g.Begin("Debug")
+    }
+    if g.current.collapsed {
+        return nil
+    }
+    return g.current
+}
+
+// id returns the id of the widget called label and the text it shows.
+func (g *DebugGui) id(win *guiWindow, label string) (id, text string) {
+    text = label
+    if i := strings.Index(label, "##"); i >= 0 {
+        text = label[:i]
+    }
+    return win.title + "/" + label, text
+}
+
+func (g *DebugGui) rowHeight() float32 {
+    return g.font.Height + guiSpacing
+}
+
+// row advances past a widget of size w by h.
+func (g *DebugGui) row(win *guiWindow, w, h float32) {
+    win.rowY += h + guiSpacing
+    if w+2*guiPadding > win.width {
+        win.width = w + 2*guiPadding
+    }
+}
+
+func (g *DebugGui) hovered(win *guiWindow, r Rect) bool {
+    return g.hot == win && r.Contains(g.mouseX, g.mouseY)
+}
+
+// press makes the widget id active if the mouse was pressed on r in this
+// frame, and reports whether it was.
+func (g *DebugGui) press(win *guiWindow, id string, r Rect) bool {
+    if g.active == "" && g.hovered(win, r) && MouseButtonPressed(glfw.MouseButtonLeft) {
+        g.active = id
+        return true
+    }
+    return false
+}
+
+func (g *DebugGui) background(win *guiWindow, id string, r Rect) Color {
+    switch {
+    case g.active == id:
+        return guiActive
+    case g.active == "" && g.hovered(win, r):
+        return guiHover
+    }
+    return guiWidget
+}
+
+func (g *DebugGui) rect(win *guiWindow, r Rect, c Color) {
+    win.cmds = append(win.cmds, guiCmd{rect: r, color: c})
+}
+
+func (g *DebugGui) text(win *guiWindow, s string, x, y float32, c Color) {
+    win.cmds = append(win.cmds, guiCmd{rect: Rect{X: x, Y: y}, color: c, text: s})
+}
+
+// raise moves win to the top.
+func (g *DebugGui) raise(win *guiWindow) {
+    for i, w := range g.order {
+        if w == win {
+            copy(g.order[i:], g.order[i+1:])
+            g.order[len(g.order)-1] = win
+            return
+        }
+    }
+}
+
+// cursorDelta returns the motion of the cursor in the last Tick in
+// framebuffer pixels.
+func (g *DebugGui) cursorDelta() (dx, dy float32) {
+    sx, sy := framebufferScale()
+    return float32(cursor.dx) * sx, float32(cursor.dy) * sy
+}
+
+// framebufferScale returns the framebuffer pixels per screen coordinate of
+// the main window.
+func framebufferScale() (sx, sy float32) {
+    fbw, fbh := app.window.GetFramebufferSize()
+    ww, wh := app.window.GetSize()
+    if ww <= 0 || wh <= 0 {
+        return 1, 1
+    }
+    return float32(fbw) / float32(ww), float32(fbh) / float32(wh)
+}
+
+// beginFrame prepares the first Begin of a frame: it creates the font if
+// needed and finds the window under the cursor, by the sizes the windows
+// were drawn at.
+func (g *DebugGui) beginFrame() {
+    if g.frameBegun {
+        return
+    }
+    g.frameBegun = true
+    if g.windows == nil {
+        g.windows = map[string]*guiWindow{}
+    }
+    if g.font == nil {
+        font, err := DefaultFont()
+        if err != nil {
+            // the widgets work without text
+            logAt(slog.LevelWarn, "debug GUI without text", "err", err)
+            font = &Font{glyphs: map[rune]fontGlyph{}}
+        }
+        g.font = font
+    }
+    sx, sy := framebufferScale()
+    g.mouseX, g.mouseY = float32(cursor.x)*sx, float32(cursor.y)*sy
+    g.hot = nil
+    for i := len(g.order) - 1; i >= 0 && g.hot == nil; i-- {
+        // windows not drawn in the last frame have no size
+        win := g.order[i]
+        if (Rect{win.x, win.y, win.w, win.h}).Contains(g.mouseX, g.mouseY) {
+            g.hot = win
+        }
+    }
+    if !MouseButtonDown(glfw.MouseButtonLeft) && !MouseButtonReleased(glfw.MouseButtonLeft) {
+        g.active = ""
+    }
+}
+
+// draw draws the windows begun since the last Tick, which calls it before
+// drawing the debug overlay.
+func (g *DebugGui) draw() error {
+    if !g.frameBegun {
+        return nil
+    }
+    g.frameBegun = false
+    g.current = nil
+    if MouseButtonReleased(glfw.MouseButtonLeft) {
+        g.active = ""
+    }
+    if g.batch == nil {
+        batch, err := NewSpriteBatch()
+        if err != nil {
+            return err
+        }
+        g.batch = batch
+    }
+
+    var viewport [4]int32
+    gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+    fbWidth, fbHeight := app.window.GetFramebufferSize()
+    BindFramebuffer(nil)
+    gl.Viewport(0, 0, int32(fbWidth), int32(fbHeight))
+
+    b := g.batch
+    b.Begin(float32(fbWidth), float32(fbHeight))
+    for _, win := range g.order {
+        if !win.used {
+            // not declared in this frame, so hidden and not hit
+            win.w, win.h = 0, 0
+            continue
+        }
+        win.used = false
+        win.w, win.h = win.width, g.rowHeight()
+        if !win.collapsed {
+            win.h = win.rowY + guiPadding - guiSpacing
+        }
+        b.DrawRect(Rect{win.x, win.y, win.w, win.h}, overlayBackground)
+        b.DrawRect(Rect{win.x, win.y, win.w, g.rowHeight()}, guiTitle)
+        b.DrawText(g.font, win.title, win.x+guiPadding, win.y+guiSpacing/2, White)
+        if win.collapsed {
+            continue
+        }
+        for _, c := range win.cmds {
+            if c.text != "" {
+                b.DrawText(g.font, c.text, c.rect.X, c.rect.Y, c.color)
+            } else {
+                b.DrawRect(c.rect, c.color)
+            }
+        }
+    }
+    b.End()
+
+    gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+    return nil
+}
@@ -0,0 +1,190 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/glfw"
+)
+
+// MaxGamepads is the number of gamepads (joysticks) gome keeps track of.
+const MaxGamepads = 4
+
+type gamepadState struct {
+    present bool
+    axes    []float32
+    buttons []bool
+    prev    []bool
+}
+
+// The input state is fed by the callbacks of the main window and by polling
+// the gamepads once per Tick. Edges (pressed and released) are those of the
+// last Tick; an input pressed and released within one frame reports both.
+type inputState struct {
+    keys        [glfw.KeyLast + 1]bool
+    keyPressed  [glfw.KeyLast + 1]bool
+    keyReleased [glfw.KeyLast + 1]bool
+
+    buttons        [glfw.MouseButtonLast + 1]bool
+    buttonPressed  [glfw.MouseButtonLast + 1]bool
+    buttonReleased [glfw.MouseButtonLast + 1]bool
+
+    scrollX, scrollY float64
+
+    pads [MaxGamepads]gamepadState
+}
+
+var input inputState
+
+// beginFrame is called by Tick before polling events.
+func (s *inputState) beginFrame() {
+    s.keyPressed = [glfw.KeyLast + 1]bool{}
+    s.keyReleased = [glfw.KeyLast + 1]bool{}
+    s.buttonPressed = [glfw.MouseButtonLast + 1]bool{}
+    s.buttonReleased = [glfw.MouseButtonLast + 1]bool{}
+    s.scrollX, s.scrollY = 0, 0
+}
+
+// reset releases all keys and mouse buttons, without edges.
+func (s *inputState) reset() {
+    s.keys = [glfw.KeyLast + 1]bool{}
+    s.buttons = [glfw.MouseButtonLast + 1]bool{}
+    s.beginFrame()
+}
+
+func (s *inputState) key(key glfw.Key, action glfw.Action) {
+    if key < 0 || key > glfw.KeyLast {
+        return
+    }
+    switch action {
+    case glfw.Press:
+        s.keys[key] = true
+        s.keyPressed[key] = true
+    case glfw.Release:
+        s.keys[key] = false
+        s.keyReleased[key] = true
+    }
+}
+
+func (s *inputState) mouseButton(button glfw.MouseButton, action glfw.Action) {
+    if button < 0 || button > glfw.MouseButtonLast {
+        return
+    }
+    switch action {
+    case glfw.Press:
+        s.buttons[button] = true
+        s.buttonPressed[button] = true
+    case glfw.Release:
+        s.buttons[button] = false
+        s.buttonReleased[button] = true
+    }
+}
+
+func (s *inputState) scroll(x, y float64) {
+    s.scrollX += x
+    s.scrollY += y
+}
+
+// pollGamepads reads the state of the gamepads; it is called by Tick after
+// polling events.
+func (s *inputState) pollGamepads() {
+    for i := range s.pads {
+        p := &s.pads[i]
+        joy := glfw.Joystick1 + glfw.Joystick(i)
+        p.prev = append(p.prev[:0], p.buttons...)
+        if replay.active {
+            // the state comes from the replay (see replayFrame)
+            continue
+        }
+        p.present = joy.Present()
+        if !p.present {
+            p.axes, p.buttons = p.axes[:0], p.buttons[:0]
+            continue
+        }
+        p.axes = append(p.axes[:0], joy.GetAxes()...)
+        p.buttons = p.buttons[:0]
+        for _, b := range joy.GetButtons() {
+            p.buttons = append(p.buttons, b == glfw.Press)
+        }
+    }
+}
+
+// KeyDown reports whether key is held down.
+func KeyDown(key glfw.Key) bool {
+    return key >= 0 && key <= glfw.KeyLast && input.keys[key]
+}
+
+// KeyPressed reports whether key was pressed during the last Tick. Key
+// repeats do not count.
+func KeyPressed(key glfw.Key) bool {
+    return key >= 0 && key <= glfw.KeyLast && input.keyPressed[key]
+}
+
+// KeyReleased reports whether key was released during the last Tick.
+func KeyReleased(key glfw.Key) bool {
+    return key >= 0 && key <= glfw.KeyLast && input.keyReleased[key]
+}
+
+// MouseButtonDown reports whether button is held down.
+func MouseButtonDown(button glfw.MouseButton) bool {
+    return button >= 0 && button <= glfw.MouseButtonLast && input.buttons[button]
+}
+
+// MouseButtonPressed reports whether button was pressed during the last
+// Tick.
+func MouseButtonPressed(button glfw.MouseButton) bool {
+    return button >= 0 && button <= glfw.MouseButtonLast && input.buttonPressed[button]
+}
+
+// MouseButtonReleased reports whether button was released during the last
+// Tick.
+func MouseButtonReleased(button glfw.MouseButton) bool {
+    return button >= 0 && button <= glfw.MouseButtonLast && input.buttonReleased[button]
+}
+
+// ScrollDelta returns how far the mouse wheel or touchpad scrolled during the
+// last Tick.
+func ScrollDelta() (dx, dy float64) {
+    return input.scrollX, input.scrollY
+}
+
+// GamepadPresent reports whether gamepad pad, between 0 and MaxGamepads-1, is
+// connected.
+func GamepadPresent(pad int) bool {
+    return pad >= 0 && pad < MaxGamepads && input.pads[pad].present
+}
+
+// GamepadAxis returns the position of an axis of a gamepad, between -1 and 1,
+// or 0 if the gamepad or the axis does not exist.
+func GamepadAxis(pad, axis int) float32 {
+    if !GamepadPresent(pad) || axis < 0 || axis >= len(input.pads[pad].axes) {
+        return 0
+    }
+    return input.pads[pad].axes[axis]
+}
+
+// GamepadButtonDown reports whether a button of a gamepad is held down.
+func GamepadButtonDown(pad, button int) bool {
+    if !GamepadPresent(pad) || button < 0 || button >= len(input.pads[pad].buttons) {
+        return false
+    }
+    return input.pads[pad].buttons[button]
+}
+
+// GamepadButtonPressed reports whether a button of a gamepad was pressed
+// since the previous Tick. Gamepads are polled once per frame, so very short
+// presses may be missed.
+func GamepadButtonPressed(pad, button int) bool {
+    if !GamepadButtonDown(pad, button) {
+        return false
+    }
+    p := &input.pads[pad]
+    return button >= len(p.prev) || !p.prev[button]
+}
+
+// GamepadButtonReleased reports whether a button of a gamepad was released
+// since the previous Tick.
+func GamepadButtonReleased(pad, button int) bool {
+    if !GamepadPresent(pad) || GamepadButtonDown(pad, button) {
+        return false
+    }
+    p := &input.pads[pad]
+    return button < len(p.prev) && p.prev[button]
+}
@@ -0,0 +1,11 @@
+/*
+Package gl is the part of the OpenGL API that gome and its sub-packages use.
+Normally it forwards to the go-gl bindings; built with the gomemock tag, it is
+the mock backend instead, which records the calls in the command log of
+package mock and answers queries with plausible values, so no GPU is needed.
+
+Only what gome uses is here, under the names of go-gl, so code can move to
+it by its import path alone. Anything new has to be added to both gl.go and
+gl_mock.go.
+*/
+package gl
@@ -0,0 +1,544 @@
+// +build !gomemock
+
+package gl
+
+import (
+    "github.com/go-gl/gl/v3.2-core/gl"
+    "unsafe"
+)
+
+const (
+    ALL_BARRIER_BITS                          = gl.ALL_BARRIER_BITS
+    ARRAY_BUFFER                              = gl.ARRAY_BUFFER
+    BACK                                      = gl.BACK
+    BLEND                                     = gl.BLEND
+    BUFFER_UPDATE_BARRIER_BIT                 = gl.BUFFER_UPDATE_BARRIER_BIT
+    CLAMP_TO_EDGE                             = gl.CLAMP_TO_EDGE
+    COLOR_ATTACHMENT0                         = gl.COLOR_ATTACHMENT0
+    COLOR_BUFFER_BIT                          = gl.COLOR_BUFFER_BIT
+    COMPARE_REF_TO_TEXTURE                    = gl.COMPARE_REF_TO_TEXTURE
+    COMPILE_STATUS                            = gl.COMPILE_STATUS
+    COMPRESSED_R11_EAC                        = gl.COMPRESSED_R11_EAC
+    COMPRESSED_RED_RGTC1                      = gl.COMPRESSED_RED_RGTC1
+    COMPRESSED_RG11_EAC                       = gl.COMPRESSED_RG11_EAC
+    COMPRESSED_RGB8_ETC2                      = gl.COMPRESSED_RGB8_ETC2
+    COMPRESSED_RGB8_PUNCHTHROUGH_ALPHA1_ETC2  = gl.COMPRESSED_RGB8_PUNCHTHROUGH_ALPHA1_ETC2
+    COMPRESSED_RGBA8_ETC2_EAC                 = gl.COMPRESSED_RGBA8_ETC2_EAC
+    COMPRESSED_RGBA_BPTC_UNORM_ARB            = gl.COMPRESSED_RGBA_BPTC_UNORM_ARB
+    COMPRESSED_RGBA_S3TC_DXT1_EXT             = gl.COMPRESSED_RGBA_S3TC_DXT1_EXT
+    COMPRESSED_RGBA_S3TC_DXT3_EXT             = gl.COMPRESSED_RGBA_S3TC_DXT3_EXT
+    COMPRESSED_RGBA_S3TC_DXT5_EXT             = gl.COMPRESSED_RGBA_S3TC_DXT5_EXT
+    COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB      = gl.COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB
+    COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB    = gl.COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB
+    COMPRESSED_RGB_S3TC_DXT1_EXT              = gl.COMPRESSED_RGB_S3TC_DXT1_EXT
+    COMPRESSED_RG_RGTC2                       = gl.COMPRESSED_RG_RGTC2
+    COMPRESSED_SIGNED_R11_EAC                 = gl.COMPRESSED_SIGNED_R11_EAC
+    COMPRESSED_SIGNED_RED_RGTC1               = gl.COMPRESSED_SIGNED_RED_RGTC1
+    COMPRESSED_SIGNED_RG11_EAC                = gl.COMPRESSED_SIGNED_RG11_EAC
+    COMPRESSED_SIGNED_RG_RGTC2                = gl.COMPRESSED_SIGNED_RG_RGTC2
+    COMPRESSED_SRGB8_ALPHA8_ETC2_EAC          = gl.COMPRESSED_SRGB8_ALPHA8_ETC2_EAC
+    COMPRESSED_SRGB8_ETC2                     = gl.COMPRESSED_SRGB8_ETC2
+    COMPRESSED_SRGB8_PUNCHTHROUGH_ALPHA1_ETC2 = gl.COMPRESSED_SRGB8_PUNCHTHROUGH_ALPHA1_ETC2
+    COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB      = gl.COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB
+    COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT       = gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT
+    COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT       = gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT
+    COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT       = gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT
+    COMPRESSED_SRGB_S3TC_DXT1_EXT             = gl.COMPRESSED_SRGB_S3TC_DXT1_EXT
+    COMPUTE_SHADER                            = gl.COMPUTE_SHADER
+    COMPUTE_WORK_GROUP_SIZE                   = gl.COMPUTE_WORK_GROUP_SIZE
+    CONTEXT_FLAGS                             = gl.CONTEXT_FLAGS
+    CONTEXT_FLAG_DEBUG_BIT                    = gl.CONTEXT_FLAG_DEBUG_BIT
+    CULL_FACE                                 = gl.CULL_FACE
+    DEBUG_OUTPUT                              = gl.DEBUG_OUTPUT
+    DEBUG_OUTPUT_SYNCHRONOUS                  = gl.DEBUG_OUTPUT_SYNCHRONOUS
+    DEBUG_SEVERITY_HIGH                       = gl.DEBUG_SEVERITY_HIGH
+    DEBUG_SEVERITY_LOW                        = gl.DEBUG_SEVERITY_LOW
+    DEBUG_SEVERITY_MEDIUM                     = gl.DEBUG_SEVERITY_MEDIUM
+    DEBUG_SEVERITY_NOTIFICATION               = gl.DEBUG_SEVERITY_NOTIFICATION
+    DEBUG_SOURCE_API                          = gl.DEBUG_SOURCE_API
+    DEBUG_SOURCE_APPLICATION                  = gl.DEBUG_SOURCE_APPLICATION
+    DEBUG_SOURCE_OTHER                        = gl.DEBUG_SOURCE_OTHER
+    DEBUG_SOURCE_SHADER_COMPILER              = gl.DEBUG_SOURCE_SHADER_COMPILER
+    DEBUG_SOURCE_THIRD_PARTY                  = gl.DEBUG_SOURCE_THIRD_PARTY
+    DEBUG_SOURCE_WINDOW_SYSTEM                = gl.DEBUG_SOURCE_WINDOW_SYSTEM
+    DEBUG_TYPE_DEPRECATED_BEHAVIOR            = gl.DEBUG_TYPE_DEPRECATED_BEHAVIOR
+    DEBUG_TYPE_ERROR                          = gl.DEBUG_TYPE_ERROR
+    DEBUG_TYPE_MARKER                         = gl.DEBUG_TYPE_MARKER
+    DEBUG_TYPE_OTHER                          = gl.DEBUG_TYPE_OTHER
+    DEBUG_TYPE_PERFORMANCE                    = gl.DEBUG_TYPE_PERFORMANCE
+    DEBUG_TYPE_POP_GROUP                      = gl.DEBUG_TYPE_POP_GROUP
+    DEBUG_TYPE_PORTABILITY                    = gl.DEBUG_TYPE_PORTABILITY
+    DEBUG_TYPE_PUSH_GROUP                     = gl.DEBUG_TYPE_PUSH_GROUP
+    DEBUG_TYPE_UNDEFINED_BEHAVIOR             = gl.DEBUG_TYPE_UNDEFINED_BEHAVIOR
+    DEPTH24_STENCIL8                          = gl.DEPTH24_STENCIL8
+    DEPTH_ATTACHMENT                          = gl.DEPTH_ATTACHMENT
+    DEPTH_BUFFER_BIT                          = gl.DEPTH_BUFFER_BIT
+    DEPTH_COMPONENT                           = gl.DEPTH_COMPONENT
+    DEPTH_COMPONENT24                         = gl.DEPTH_COMPONENT24
+    DEPTH_STENCIL_ATTACHMENT                  = gl.DEPTH_STENCIL_ATTACHMENT
+    DEPTH_TEST                                = gl.DEPTH_TEST
+    DRAW_FRAMEBUFFER                          = gl.DRAW_FRAMEBUFFER
+    DYNAMIC_COPY                              = gl.DYNAMIC_COPY
+    DYNAMIC_DRAW                              = gl.DYNAMIC_DRAW
+    ELEMENT_ARRAY_BARRIER_BIT                 = gl.ELEMENT_ARRAY_BARRIER_BIT
+    ELEMENT_ARRAY_BUFFER                      = gl.ELEMENT_ARRAY_BUFFER
+    EXTENSIONS                                = gl.EXTENSIONS
+    FLOAT                                     = gl.FLOAT
+    FRAGMENT_SHADER                           = gl.FRAGMENT_SHADER
+    FRAMEBUFFER                               = gl.FRAMEBUFFER
+    FRAMEBUFFER_COMPLETE                      = gl.FRAMEBUFFER_COMPLETE
+    FRAMEBUFFER_SRGB                          = gl.FRAMEBUFFER_SRGB
+    FRONT                                     = gl.FRONT
+    INFO_LOG_LENGTH                           = gl.INFO_LOG_LENGTH
+    INVALID_ENUM                              = gl.INVALID_ENUM
+    INVALID_FRAMEBUFFER_OPERATION             = gl.INVALID_FRAMEBUFFER_OPERATION
+    INVALID_INDEX                             = gl.INVALID_INDEX
+    INVALID_OPERATION                         = gl.INVALID_OPERATION
+    INVALID_VALUE                             = gl.INVALID_VALUE
+    LEQUAL                                    = gl.LEQUAL
+    LINEAR                                    = gl.LINEAR
+    LINEAR_MIPMAP_LINEAR                      = gl.LINEAR_MIPMAP_LINEAR
+    LINK_STATUS                               = gl.LINK_STATUS
+    MAJOR_VERSION                             = gl.MAJOR_VERSION
+    MAP_READ_BIT                              = gl.MAP_READ_BIT
+    MAP_WRITE_BIT                             = gl.MAP_WRITE_BIT
+    MAX_ARRAY_TEXTURE_LAYERS                  = gl.MAX_ARRAY_TEXTURE_LAYERS
+    MAX_SAMPLES                               = gl.MAX_SAMPLES
+    MAX_TEXTURE_MAX_ANISOTROPY                = gl.MAX_TEXTURE_MAX_ANISOTROPY
+    MAX_TEXTURE_SIZE                          = gl.MAX_TEXTURE_SIZE
+    MAX_UNIFORM_BUFFER_BINDINGS               = gl.MAX_UNIFORM_BUFFER_BINDINGS
+    MINOR_VERSION                             = gl.MINOR_VERSION
+    MULTISAMPLE                               = gl.MULTISAMPLE
+    NEAREST                                   = gl.NEAREST
+    NONE                                      = gl.NONE
+    NUM_EXTENSIONS                            = gl.NUM_EXTENSIONS
+    ONE                                       = gl.ONE
+    ONE_MINUS_SRC_ALPHA                       = gl.ONE_MINUS_SRC_ALPHA
+    OUT_OF_MEMORY                             = gl.OUT_OF_MEMORY
+    PACK_ALIGNMENT                            = gl.PACK_ALIGNMENT
+    POLYGON_OFFSET_FILL                       = gl.POLYGON_OFFSET_FILL
+    QUERY_RESULT                              = gl.QUERY_RESULT
+    READ_FRAMEBUFFER                          = gl.READ_FRAMEBUFFER
+    READ_ONLY                                 = gl.READ_ONLY
+    READ_WRITE                                = gl.READ_WRITE
+    RENDERBUFFER                              = gl.RENDERBUFFER
+    RENDERER                                  = gl.RENDERER
+    REPEAT                                    = gl.REPEAT
+    RGBA                                      = gl.RGBA
+    RGBA32F                                   = gl.RGBA32F
+    RGBA8                                     = gl.RGBA8
+    SAMPLES                                   = gl.SAMPLES
+    SCISSOR_TEST                              = gl.SCISSOR_TEST
+    SHADER_IMAGE_ACCESS_BARRIER_BIT           = gl.SHADER_IMAGE_ACCESS_BARRIER_BIT
+    SHADER_STORAGE_BARRIER_BIT                = gl.SHADER_STORAGE_BARRIER_BIT
+    SHADER_STORAGE_BUFFER                     = gl.SHADER_STORAGE_BUFFER
+    SHADING_LANGUAGE_VERSION                  = gl.SHADING_LANGUAGE_VERSION
+    SRC_ALPHA                                 = gl.SRC_ALPHA
+    SRGB8_ALPHA8                              = gl.SRGB8_ALPHA8
+    STACK_OVERFLOW                            = gl.STACK_OVERFLOW
+    STACK_UNDERFLOW                           = gl.STACK_UNDERFLOW
+    STATIC_DRAW                               = gl.STATIC_DRAW
+    STENCIL_BUFFER_BIT                        = gl.STENCIL_BUFFER_BIT
+    STENCIL_TEST                              = gl.STENCIL_TEST
+    STREAM_DRAW                               = gl.STREAM_DRAW
+    TEXTURE0                                  = gl.TEXTURE0
+    TEXTURE_2D                                = gl.TEXTURE_2D
+    TEXTURE_2D_ARRAY                          = gl.TEXTURE_2D_ARRAY
+    TEXTURE_3D                                = gl.TEXTURE_3D
+    TEXTURE_BORDER_COLOR                      = gl.TEXTURE_BORDER_COLOR
+    TEXTURE_COMPARE_FUNC                      = gl.TEXTURE_COMPARE_FUNC
+    TEXTURE_COMPARE_MODE                      = gl.TEXTURE_COMPARE_MODE
+    TEXTURE_FETCH_BARRIER_BIT                 = gl.TEXTURE_FETCH_BARRIER_BIT
+    TEXTURE_MAG_FILTER                        = gl.TEXTURE_MAG_FILTER
+    TEXTURE_MAX_ANISOTROPY                    = gl.TEXTURE_MAX_ANISOTROPY
+    TEXTURE_MAX_LEVEL                         = gl.TEXTURE_MAX_LEVEL
+    TEXTURE_MIN_FILTER                        = gl.TEXTURE_MIN_FILTER
+    TEXTURE_SWIZZLE_A                         = gl.TEXTURE_SWIZZLE_A
+    TEXTURE_SWIZZLE_B                         = gl.TEXTURE_SWIZZLE_B
+    TEXTURE_SWIZZLE_G                         = gl.TEXTURE_SWIZZLE_G
+    TEXTURE_SWIZZLE_R                         = gl.TEXTURE_SWIZZLE_R
+    TEXTURE_UPDATE_BARRIER_BIT                = gl.TEXTURE_UPDATE_BARRIER_BIT
+    TEXTURE_WRAP_R                            = gl.TEXTURE_WRAP_R
+    TEXTURE_WRAP_S                            = gl.TEXTURE_WRAP_S
+    TEXTURE_WRAP_T                            = gl.TEXTURE_WRAP_T
+    TIME_ELAPSED                              = gl.TIME_ELAPSED
+    TRIANGLES                                 = gl.TRIANGLES
+    TRIANGLE_STRIP                            = gl.TRIANGLE_STRIP
+    TRUE                                      = gl.TRUE
+    UNIFORM_BUFFER                            = gl.UNIFORM_BUFFER
+    UNPACK_ALIGNMENT                          = gl.UNPACK_ALIGNMENT
+    UNSIGNED_BYTE                             = gl.UNSIGNED_BYTE
+    UNSIGNED_INT                              = gl.UNSIGNED_INT
+    VENDOR                                    = gl.VENDOR
+    VERSION                                   = gl.VERSION
+    VERTEX_ATTRIB_ARRAY_BARRIER_BIT           = gl.VERTEX_ATTRIB_ARRAY_BARRIER_BIT
+    VERTEX_SHADER                             = gl.VERTEX_SHADER
+    VIEWPORT                                  = gl.VIEWPORT
+    WRITE_ONLY                                = gl.WRITE_ONLY
+)
+
+func ActiveTexture(texture uint32) {
+    gl.ActiveTexture(texture)
+}
+
+func AttachShader(program uint32, shader uint32) {
+    gl.AttachShader(program, shader)
+}
+
+func BeginQuery(target uint32, id uint32) {
+    gl.BeginQuery(target, id)
+}
+
+func BindAttribLocation(program uint32, index uint32, name *uint8) {
+    gl.BindAttribLocation(program, index, name)
+}
+
+func BindBuffer(target uint32, buffer uint32) {
+    gl.BindBuffer(target, buffer)
+}
+
+func BindBufferBase(target uint32, index uint32, buffer uint32) {
+    gl.BindBufferBase(target, index, buffer)
+}
+
+func BindFramebuffer(target uint32, framebuffer uint32) {
+    gl.BindFramebuffer(target, framebuffer)
+}
+
+func BindImageTexture(unit uint32, texture uint32, level int32, layered bool, layer int32, access uint32, format uint32) {
+    gl.BindImageTexture(unit, texture, level, layered, layer, access, format)
+}
+
+func BindRenderbuffer(target uint32, renderbuffer uint32) {
+    gl.BindRenderbuffer(target, renderbuffer)
+}
+
+func BindTexture(target uint32, texture uint32) {
+    gl.BindTexture(target, texture)
+}
+
+func BindVertexArray(array uint32) {
+    gl.BindVertexArray(array)
+}
+
+func BlendFunc(sfactor uint32, dfactor uint32) {
+    gl.BlendFunc(sfactor, dfactor)
+}
+
+func BlitFramebuffer(srcX0 int32, srcY0 int32, srcX1 int32, srcY1 int32, dstX0 int32, dstY0 int32, dstX1 int32, dstY1 int32, mask uint32, filter uint32) {
+    gl.BlitFramebuffer(srcX0, srcY0, srcX1, srcY1, dstX0, dstY0, dstX1, dstY1, mask, filter)
+}
+
+func BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
+    gl.BufferData(target, size, data, usage)
+}
+
+func BufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+    gl.BufferSubData(target, offset, size, data)
+}
+
+func CheckFramebufferStatus(target uint32) uint32 {
+    return gl.CheckFramebufferStatus(target)
+}
+
+func Clear(mask uint32) {
+    gl.Clear(mask)
+}
+
+func ClearColor(red float32, green float32, blue float32, alpha float32) {
+    gl.ClearColor(red, green, blue, alpha)
+}
+
+func CompileShader(shader uint32) {
+    gl.CompileShader(shader)
+}
+
+func CompressedTexImage2D(target uint32, level int32, internalformat uint32, width int32, height int32, border int32, imageSize int32, data unsafe.Pointer) {
+    gl.CompressedTexImage2D(target, level, internalformat, width, height, border, imageSize, data)
+}
+
+func CreateProgram() uint32 {
+    return gl.CreateProgram()
+}
+
+func CreateShader(xtype uint32) uint32 {
+    return gl.CreateShader(xtype)
+}
+
+type DebugProc = gl.DebugProc
+
+func DebugMessageCallback(callback DebugProc, userParam unsafe.Pointer) {
+    gl.DebugMessageCallback(callback, userParam)
+}
+
+func DeleteBuffers(n int32, buffers *uint32) {
+    gl.DeleteBuffers(n, buffers)
+}
+
+func DeleteFramebuffers(n int32, framebuffers *uint32) {
+    gl.DeleteFramebuffers(n, framebuffers)
+}
+
+func DeleteProgram(program uint32) {
+    gl.DeleteProgram(program)
+}
+
+func DeleteRenderbuffers(n int32, renderbuffers *uint32) {
+    gl.DeleteRenderbuffers(n, renderbuffers)
+}
+
+func DeleteShader(shader uint32) {
+    gl.DeleteShader(shader)
+}
+
+func DeleteTextures(n int32, textures *uint32) {
+    gl.DeleteTextures(n, textures)
+}
+
+func DeleteVertexArrays(n int32, arrays *uint32) {
+    gl.DeleteVertexArrays(n, arrays)
+}
+
+func Disable(cap uint32) {
+    gl.Disable(cap)
+}
+
+func DispatchCompute(num_groups_x uint32, num_groups_y uint32, num_groups_z uint32) {
+    gl.DispatchCompute(num_groups_x, num_groups_y, num_groups_z)
+}
+
+func DrawArrays(mode uint32, first int32, count int32) {
+    gl.DrawArrays(mode, first, count)
+}
+
+func DrawBuffers(n int32, bufs *uint32) {
+    gl.DrawBuffers(n, bufs)
+}
+
+func DrawElementsWithOffset(mode uint32, count int32, xtype uint32, indices uintptr) {
+    gl.DrawElementsWithOffset(mode, count, xtype, indices)
+}
+
+func Enable(cap uint32) {
+    gl.Enable(cap)
+}
+
+func EnableVertexAttribArray(index uint32) {
+    gl.EnableVertexAttribArray(index)
+}
+
+func EndQuery(target uint32) {
+    gl.EndQuery(target)
+}
+
+func FramebufferRenderbuffer(target uint32, attachment uint32, renderbuffertarget uint32, renderbuffer uint32) {
+    gl.FramebufferRenderbuffer(target, attachment, renderbuffertarget, renderbuffer)
+}
+
+func FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+    gl.FramebufferTexture2D(target, attachment, textarget, texture, level)
+}
+
+func GenBuffers(n int32, buffers *uint32) {
+    gl.GenBuffers(n, buffers)
+}
+
+func GenFramebuffers(n int32, framebuffers *uint32) {
+    gl.GenFramebuffers(n, framebuffers)
+}
+
+func GenQueries(n int32, ids *uint32) {
+    gl.GenQueries(n, ids)
+}
+
+func GenRenderbuffers(n int32, renderbuffers *uint32) {
+    gl.GenRenderbuffers(n, renderbuffers)
+}
+
+func GenTextures(n int32, textures *uint32) {
+    gl.GenTextures(n, textures)
+}
+
+func GenVertexArrays(n int32, arrays *uint32) {
+    gl.GenVertexArrays(n, arrays)
+}
+
+func GenerateMipmap(target uint32) {
+    gl.GenerateMipmap(target)
+}
+
+func GetAttribLocation(program uint32, name *uint8) int32 {
+    return gl.GetAttribLocation(program, name)
+}
+
+func GetError() uint32 {
+    return gl.GetError()
+}
+
+func GetFloatv(pname uint32, data *float32) {
+    gl.GetFloatv(pname, data)
+}
+
+func GetIntegerv(pname uint32, data *int32) {
+    gl.GetIntegerv(pname, data)
+}
+
+func GetProgramInfoLog(program uint32, bufSize int32, length *int32, infoLog *uint8) {
+    gl.GetProgramInfoLog(program, bufSize, length, infoLog)
+}
+
+func GetProgramiv(program uint32, pname uint32, params *int32) {
+    gl.GetProgramiv(program, pname, params)
+}
+
+func GetQueryObjectuiv(id uint32, pname uint32, params *uint32) {
+    gl.GetQueryObjectuiv(id, pname, params)
+}
+
+func GetShaderInfoLog(shader uint32, bufSize int32, length *int32, infoLog *uint8) {
+    gl.GetShaderInfoLog(shader, bufSize, length, infoLog)
+}
+
+func GetShaderiv(shader uint32, pname uint32, params *int32) {
+    gl.GetShaderiv(shader, pname, params)
+}
+
+func GetString(name uint32) *uint8 {
+    return gl.GetString(name)
+}
+
+func GetStringi(name uint32, index uint32) *uint8 {
+    return gl.GetStringi(name, index)
+}
+
+func GetUniformBlockIndex(program uint32, uniformBlockName *uint8) uint32 {
+    return gl.GetUniformBlockIndex(program, uniformBlockName)
+}
+
+func GetUniformLocation(program uint32, name *uint8) int32 {
+    return gl.GetUniformLocation(program, name)
+}
+
+func GoStr(cstr *uint8) string {
+    return gl.GoStr(cstr)
+}
+
+func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
+    return gl.InitWithProcAddrFunc(getProcAddr)
+}
+
+func IsEnabled(cap uint32) bool {
+    return gl.IsEnabled(cap)
+}
+
+func LinkProgram(program uint32) {
+    gl.LinkProgram(program)
+}
+
+func MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer {
+    return gl.MapBufferRange(target, offset, length, access)
+}
+
+func MemoryBarrier(barriers uint32) {
+    gl.MemoryBarrier(barriers)
+}
+
+func PixelStorei(pname uint32, param int32) {
+    gl.PixelStorei(pname, param)
+}
+
+func PolygonOffset(factor float32, units float32) {
+    gl.PolygonOffset(factor, units)
+}
+
+func Ptr(data interface{}) unsafe.Pointer {
+    return gl.Ptr(data)
+}
+
+func ReadBuffer(src uint32) {
+    gl.ReadBuffer(src)
+}
+
+func ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+    gl.ReadPixels(x, y, width, height, format, xtype, pixels)
+}
+
+func RenderbufferStorage(target uint32, internalformat uint32, width int32, height int32) {
+    gl.RenderbufferStorage(target, internalformat, width, height)
+}
+
+func RenderbufferStorageMultisample(target uint32, samples int32, internalformat uint32, width int32, height int32) {
+    gl.RenderbufferStorageMultisample(target, samples, internalformat, width, height)
+}
+
+func ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {
+    gl.ShaderSource(shader, count, xstring, length)
+}
+
+func Str(str string) *uint8 {
+    return gl.Str(str)
+}
+
+func Strs(strs ...string) (cstrs **uint8, free func()) {
+    return gl.Strs(strs...)
+}
+
+func TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+    gl.TexImage2D(target, level, internalformat, width, height, border, format, xtype, pixels)
+}
+
+func TexImage3D(target uint32, level int32, internalformat int32, width int32, height int32, depth int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+    gl.TexImage3D(target, level, internalformat, width, height, depth, border, format, xtype, pixels)
+}
+
+func TexParameterf(target uint32, pname uint32, param float32) {
+    gl.TexParameterf(target, pname, param)
+}
+
+func TexParameterfv(target uint32, pname uint32, params *float32) {
+    gl.TexParameterfv(target, pname, params)
+}
+
+func TexParameteri(target uint32, pname uint32, param int32) {
+    gl.TexParameteri(target, pname, param)
+}
+
+func TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+    gl.TexSubImage2D(target, level, xoffset, yoffset, width, height, format, xtype, pixels)
+}
+
+func TexSubImage3D(target uint32, level int32, xoffset int32, yoffset int32, zoffset int32, width int32, height int32, depth int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+    gl.TexSubImage3D(target, level, xoffset, yoffset, zoffset, width, height, depth, format, xtype, pixels)
+}
+
+func Uniform1f(location int32, v0 float32) {
+    gl.Uniform1f(location, v0)
+}
+
+func Uniform4f(location int32, v0 float32, v1 float32, v2 float32, v3 float32) {
+    gl.Uniform4f(location, v0, v1, v2, v3)
+}
+
+func UniformBlockBinding(program uint32, uniformBlockIndex uint32, uniformBlockBinding uint32) {
+    gl.UniformBlockBinding(program, uniformBlockIndex, uniformBlockBinding)
+}
+
+func UniformMatrix4fv(location int32, count int32, transpose bool, value *float32) {
+    gl.UniformMatrix4fv(location, count, transpose, value)
+}
+
+func UnmapBuffer(target uint32) bool {
+    return gl.UnmapBuffer(target)
+}
+
+func UseProgram(program uint32) {
+    gl.UseProgram(program)
+}
+
+func VertexAttribPointerWithOffset(index uint32, size int32, xtype uint32, normalized bool, stride int32, offset uintptr) {
+    gl.VertexAttribPointerWithOffset(index, size, xtype, normalized, stride, offset)
+}
+
+func Viewport(x int32, y int32, width int32, height int32) {
+    gl.Viewport(x, y, width, height)
+}
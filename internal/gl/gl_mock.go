@@ -0,0 +1,802 @@
+// +build gomemock
+
+package gl
+
+import (
+    "fmt"
+    "github.com/snorredc/gome/internal/mock"
+    "math"
+    "reflect"
+    "strings"
+    "unsafe"
+)
+
+const (
+    ALL_BARRIER_BITS                          = 0xFFFFFFFF
+    ARRAY_BUFFER                              = 0x8892
+    BACK                                      = 0x0405
+    BLEND                                     = 0x0BE2
+    BUFFER_UPDATE_BARRIER_BIT                 = 0x00000200
+    CLAMP_TO_EDGE                             = 0x812F
+    COLOR_ATTACHMENT0                         = 0x8CE0
+    COLOR_BUFFER_BIT                          = 0x00004000
+    COMPARE_REF_TO_TEXTURE                    = 0x884E
+    COMPILE_STATUS                            = 0x8B81
+    COMPRESSED_R11_EAC                        = 0x9270
+    COMPRESSED_RED_RGTC1                      = 0x8DBB
+    COMPRESSED_RG11_EAC                       = 0x9272
+    COMPRESSED_RGB8_ETC2                      = 0x9274
+    COMPRESSED_RGB8_PUNCHTHROUGH_ALPHA1_ETC2  = 0x9276
+    COMPRESSED_RGBA8_ETC2_EAC                 = 0x9278
+    COMPRESSED_RGBA_BPTC_UNORM_ARB            = 0x8E8C
+    COMPRESSED_RGBA_S3TC_DXT1_EXT             = 0x83F1
+    COMPRESSED_RGBA_S3TC_DXT3_EXT             = 0x83F2
+    COMPRESSED_RGBA_S3TC_DXT5_EXT             = 0x83F3
+    COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB      = 0x8E8E
+    COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB    = 0x8E8F
+    COMPRESSED_RGB_S3TC_DXT1_EXT              = 0x83F0
+    COMPRESSED_RG_RGTC2                       = 0x8DBD
+    COMPRESSED_SIGNED_R11_EAC                 = 0x9271
+    COMPRESSED_SIGNED_RED_RGTC1               = 0x8DBC
+    COMPRESSED_SIGNED_RG11_EAC                = 0x9273
+    COMPRESSED_SIGNED_RG_RGTC2                = 0x8DBE
+    COMPRESSED_SRGB8_ALPHA8_ETC2_EAC          = 0x9279
+    COMPRESSED_SRGB8_ETC2                     = 0x9275
+    COMPRESSED_SRGB8_PUNCHTHROUGH_ALPHA1_ETC2 = 0x9277
+    COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB      = 0x8E8D
+    COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT       = 0x8C4D
+    COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT       = 0x8C4E
+    COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT       = 0x8C4F
+    COMPRESSED_SRGB_S3TC_DXT1_EXT             = 0x8C4C
+    COMPUTE_SHADER                            = 0x91B9
+    COMPUTE_WORK_GROUP_SIZE                   = 0x8267
+    CONTEXT_FLAGS                             = 0x821E
+    CONTEXT_FLAG_DEBUG_BIT                    = 0x00000002
+    CULL_FACE                                 = 0x0B44
+    DEBUG_OUTPUT                              = 0x92E0
+    DEBUG_OUTPUT_SYNCHRONOUS                  = 0x8242
+    DEBUG_SEVERITY_HIGH                       = 0x9146
+    DEBUG_SEVERITY_LOW                        = 0x9148
+    DEBUG_SEVERITY_MEDIUM                     = 0x9147
+    DEBUG_SEVERITY_NOTIFICATION               = 0x826B
+    DEBUG_SOURCE_API                          = 0x8246
+    DEBUG_SOURCE_APPLICATION                  = 0x824A
+    DEBUG_SOURCE_OTHER                        = 0x824B
+    DEBUG_SOURCE_SHADER_COMPILER              = 0x8248
+    DEBUG_SOURCE_THIRD_PARTY                  = 0x8249
+    DEBUG_SOURCE_WINDOW_SYSTEM                = 0x8247
+    DEBUG_TYPE_DEPRECATED_BEHAVIOR            = 0x824D
+    DEBUG_TYPE_ERROR                          = 0x824C
+    DEBUG_TYPE_MARKER                         = 0x8268
+    DEBUG_TYPE_OTHER                          = 0x8251
+    DEBUG_TYPE_PERFORMANCE                    = 0x8250
+    DEBUG_TYPE_POP_GROUP                      = 0x826A
+    DEBUG_TYPE_PORTABILITY                    = 0x824F
+    DEBUG_TYPE_PUSH_GROUP                     = 0x8269
+    DEBUG_TYPE_UNDEFINED_BEHAVIOR             = 0x824E
+    DEPTH24_STENCIL8                          = 0x88F0
+    DEPTH_ATTACHMENT                          = 0x8D00
+    DEPTH_BUFFER_BIT                          = 0x00000100
+    DEPTH_COMPONENT                           = 0x1902
+    DEPTH_COMPONENT24                         = 0x81A6
+    DEPTH_STENCIL_ATTACHMENT                  = 0x821A
+    DEPTH_TEST                                = 0x0B71
+    DRAW_FRAMEBUFFER                          = 0x8CA9
+    DYNAMIC_COPY                              = 0x88EA
+    DYNAMIC_DRAW                              = 0x88E8
+    ELEMENT_ARRAY_BARRIER_BIT                 = 0x00000002
+    ELEMENT_ARRAY_BUFFER                      = 0x8893
+    EXTENSIONS                                = 0x1F03
+    FLOAT                                     = 0x1406
+    FRAGMENT_SHADER                           = 0x8B30
+    FRAMEBUFFER                               = 0x8D40
+    FRAMEBUFFER_COMPLETE                      = 0x8CD5
+    FRAMEBUFFER_SRGB                          = 0x8DB9
+    FRONT                                     = 0x0404
+    INFO_LOG_LENGTH                           = 0x8B84
+    INVALID_ENUM                              = 0x0500
+    INVALID_FRAMEBUFFER_OPERATION             = 0x0506
+    INVALID_INDEX                             = 0xFFFFFFFF
+    INVALID_OPERATION                         = 0x0502
+    INVALID_VALUE                             = 0x0501
+    LEQUAL                                    = 0x0203
+    LINEAR                                    = 0x2601
+    LINEAR_MIPMAP_LINEAR                      = 0x2703
+    LINK_STATUS                               = 0x8B82
+    MAJOR_VERSION                             = 0x821B
+    MAP_READ_BIT                              = 0x0001
+    MAP_WRITE_BIT                             = 0x0002
+    MAX_ARRAY_TEXTURE_LAYERS                  = 0x88FF
+    MAX_SAMPLES                               = 0x8D57
+    MAX_TEXTURE_MAX_ANISOTROPY                = 0x84FF
+    MAX_TEXTURE_SIZE                          = 0x0D33
+    MAX_UNIFORM_BUFFER_BINDINGS               = 0x8A2F
+    MINOR_VERSION                             = 0x821C
+    MULTISAMPLE                               = 0x809D
+    NEAREST                                   = 0x2600
+    NONE                                      = 0
+    NUM_EXTENSIONS                            = 0x821D
+    ONE                                       = 1
+    ONE_MINUS_SRC_ALPHA                       = 0x0303
+    OUT_OF_MEMORY                             = 0x0505
+    PACK_ALIGNMENT                            = 0x0D05
+    POLYGON_OFFSET_FILL                       = 0x8037
+    QUERY_RESULT                              = 0x8866
+    READ_FRAMEBUFFER                          = 0x8CA8
+    READ_ONLY                                 = 0x88B8
+    READ_WRITE                                = 0x88BA
+    RENDERBUFFER                              = 0x8D41
+    RENDERER                                  = 0x1F01
+    REPEAT                                    = 0x2901
+    RGBA                                      = 0x1908
+    RGBA32F                                   = 0x8814
+    RGBA8                                     = 0x8058
+    SAMPLES                                   = 0x80A9
+    SCISSOR_TEST                              = 0x0C11
+    SHADER_IMAGE_ACCESS_BARRIER_BIT           = 0x00000020
+    SHADER_STORAGE_BARRIER_BIT                = 0x00002000
+    SHADER_STORAGE_BUFFER                     = 0x90D2
+    SHADING_LANGUAGE_VERSION                  = 0x8B8C
+    SRC_ALPHA                                 = 0x0302
+    SRGB8_ALPHA8                              = 0x8C43
+    STACK_OVERFLOW                            = 0x0503
+    STACK_UNDERFLOW                           = 0x0504
+    STATIC_DRAW                               = 0x88E4
+    STENCIL_BUFFER_BIT                        = 0x00000400
+    STENCIL_TEST                              = 0x0B90
+    STREAM_DRAW                               = 0x88E0
+    TEXTURE0                                  = 0x84C0
+    TEXTURE_2D                                = 0x0DE1
+    TEXTURE_2D_ARRAY                          = 0x8C1A
+    TEXTURE_3D                                = 0x806F
+    TEXTURE_BORDER_COLOR                      = 0x1004
+    TEXTURE_COMPARE_FUNC                      = 0x884D
+    TEXTURE_COMPARE_MODE                      = 0x884C
+    TEXTURE_FETCH_BARRIER_BIT                 = 0x00000008
+    TEXTURE_MAG_FILTER                        = 0x2800
+    TEXTURE_MAX_ANISOTROPY                    = 0x84FE
+    TEXTURE_MAX_LEVEL                         = 0x813D
+    TEXTURE_MIN_FILTER                        = 0x2801
+    TEXTURE_SWIZZLE_A                         = 0x8E45
+    TEXTURE_SWIZZLE_B                         = 0x8E44
+    TEXTURE_SWIZZLE_G                         = 0x8E43
+    TEXTURE_SWIZZLE_R                         = 0x8E42
+    TEXTURE_UPDATE_BARRIER_BIT                = 0x00000100
+    TEXTURE_WRAP_R                            = 0x8072
+    TEXTURE_WRAP_S                            = 0x2802
+    TEXTURE_WRAP_T                            = 0x2803
+    TIME_ELAPSED                              = 0x88BF
+    TRIANGLES                                 = 0x0004
+    TRIANGLE_STRIP                            = 0x0005
+    TRUE                                      = 1
+    UNIFORM_BUFFER                            = 0x8A11
+    UNPACK_ALIGNMENT                          = 0x0CF5
+    UNSIGNED_BYTE                             = 0x1401
+    UNSIGNED_INT                              = 0x1405
+    VENDOR                                    = 0x1F00
+    VERSION                                   = 0x1F02
+    VERTEX_ATTRIB_ARRAY_BARRIER_BIT           = 0x00000001
+    VERTEX_SHADER                             = 0x8B31
+    VIEWPORT                                  = 0x0BA2
+    WRITE_ONLY                                = 0x88B9
+)
+
+// state is what the mock keeps of the context to answer queries.
+var state = struct {
+    next     uint32 // the last name handed out, shared by all kinds of objects
+    enabled  map[uint32]bool
+    viewport [4]int32
+    buffers  map[uint32][]byte // the store of each buffer, for mapping
+    bound    map[uint32]uint32 // the buffer bound to each target
+    uniforms map[uint32]map[string]int32
+    attribs  map[uint32]map[string]int32
+    blocks   map[uint32]map[string]int32
+    strings  map[string][]byte // returned by GetString, kept alive
+
+    // nothing is drawn, but each framebuffer has the colour it was last
+    // cleared to, which blits copy and ReadPixels returns
+    draw, read uint32 // the bound framebuffers
+    clear      [4]uint8
+    colors     map[uint32][4]uint8
+}{
+    enabled:  map[uint32]bool{},
+    buffers:  map[uint32][]byte{},
+    bound:    map[uint32]uint32{},
+    uniforms: map[uint32]map[string]int32{},
+    attribs:  map[uint32]map[string]int32{},
+    blocks:   map[uint32]map[string]int32{},
+    strings:  map[string][]byte{},
+    colors:   map[uint32][4]uint8{},
+}
+
+func record(name string, args ...interface{}) {
+    mock.Record("gl."+name, args...)
+}
+
+// gen hands out n new names into names and returns them.
+func gen(n int32, names *uint32) []uint32 {
+    s := unsafe.Slice(names, n)
+    for i := range s {
+        state.next++
+        s[i] = state.next
+    }
+    return append([]uint32(nil), s...)
+}
+
+// names returns a copy of the n names at p.
+func names(n int32, p *uint32) []uint32 {
+    return append([]uint32(nil), unsafe.Slice(p, n)...)
+}
+
+// location returns the location of name in the table of program m, adding
+// it if it is new.
+func location(m map[uint32]map[string]int32, program uint32, name string) int32 {
+    t := m[program]
+    if t == nil {
+        t = map[string]int32{}
+        m[program] = t
+    }
+    l, ok := t[name]
+    if !ok {
+        l = int32(len(t))
+        t[name] = l
+    }
+    return l
+}
+
+func ActiveTexture(texture uint32) {
+    record("ActiveTexture", texture)
+}
+
+func AttachShader(program uint32, shader uint32) {
+    record("AttachShader", program, shader)
+}
+
+func BeginQuery(target uint32, id uint32) {
+    record("BeginQuery", target, id)
+}
+
+func BindAttribLocation(program uint32, index uint32, name *uint8) {
+    record("BindAttribLocation", program, index, GoStr(name))
+    if state.attribs[program] == nil {
+        state.attribs[program] = map[string]int32{}
+    }
+    state.attribs[program][GoStr(name)] = int32(index)
+}
+
+func BindBuffer(target uint32, buffer uint32) {
+    record("BindBuffer", target, buffer)
+    state.bound[target] = buffer
+}
+
+func BindBufferBase(target uint32, index uint32, buffer uint32) {
+    record("BindBufferBase", target, index, buffer)
+    state.bound[target] = buffer
+}
+
+func BindFramebuffer(target uint32, framebuffer uint32) {
+    record("BindFramebuffer", target, framebuffer)
+    if target != READ_FRAMEBUFFER {
+        state.draw = framebuffer
+    }
+    if target != DRAW_FRAMEBUFFER {
+        state.read = framebuffer
+    }
+}
+
+func BindImageTexture(unit uint32, texture uint32, level int32, layered bool, layer int32, access uint32, format uint32) {
+    record("BindImageTexture", unit, texture, level, layered, layer, access, format)
+}
+
+func BindRenderbuffer(target uint32, renderbuffer uint32) {
+    record("BindRenderbuffer", target, renderbuffer)
+}
+
+func BindTexture(target uint32, texture uint32) {
+    record("BindTexture", target, texture)
+}
+
+func BindVertexArray(array uint32) {
+    record("BindVertexArray", array)
+}
+
+func BlendFunc(sfactor uint32, dfactor uint32) {
+    record("BlendFunc", sfactor, dfactor)
+}
+
+func BlitFramebuffer(srcX0 int32, srcY0 int32, srcX1 int32, srcY1 int32, dstX0 int32, dstY0 int32, dstX1 int32, dstY1 int32, mask uint32, filter uint32) {
+    record("BlitFramebuffer", srcX0, srcY0, srcX1, srcY1, dstX0, dstY0, dstX1, dstY1, mask, filter)
+    if mask&COLOR_BUFFER_BIT != 0 {
+        state.colors[state.draw] = state.colors[state.read]
+    }
+}
+
+func BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
+    record("BufferData", target, size, data, usage)
+    store := make([]byte, size)
+    if data != nil {
+        copy(store, unsafe.Slice((*byte)(data), size))
+    }
+    state.buffers[state.bound[target]] = store
+}
+
+func BufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+    record("BufferSubData", target, offset, size, data)
+    store := state.buffers[state.bound[target]]
+    if data != nil && offset >= 0 && offset+size <= len(store) {
+        copy(store[offset:], unsafe.Slice((*byte)(data), size))
+    }
+}
+
+func CheckFramebufferStatus(target uint32) uint32 {
+    record("CheckFramebufferStatus", target)
+    return FRAMEBUFFER_COMPLETE
+}
+
+func Clear(mask uint32) {
+    record("Clear", mask)
+    if mask&COLOR_BUFFER_BIT != 0 {
+        state.colors[state.draw] = state.clear
+    }
+}
+
+func ClearColor(red float32, green float32, blue float32, alpha float32) {
+    record("ClearColor", red, green, blue, alpha)
+    for i, c := range [4]float32{red, green, blue, alpha} {
+        state.clear[i] = uint8(math.Max(0, math.Min(float64(c), 1))*255 + 0.5)
+    }
+}
+
+func CompileShader(shader uint32) {
+    record("CompileShader", shader)
+}
+
+func CompressedTexImage2D(target uint32, level int32, internalformat uint32, width int32, height int32, border int32, imageSize int32, data unsafe.Pointer) {
+    record("CompressedTexImage2D", target, level, internalformat, width, height, border, imageSize, data)
+}
+
+func CreateProgram() uint32 {
+    state.next++
+    record("CreateProgram", state.next)
+    return state.next
+}
+
+func CreateShader(xtype uint32) uint32 {
+    state.next++
+    record("CreateShader", xtype, state.next)
+    return state.next
+}
+
+type DebugProc func(source uint32, gltype uint32, id uint32, severity uint32, length int32, message string, userParam unsafe.Pointer)
+
+// DebugMessageCallback records the call; the mock sends no messages.
+func DebugMessageCallback(callback DebugProc, userParam unsafe.Pointer) {
+    record("DebugMessageCallback")
+}
+
+func DeleteBuffers(n int32, buffers *uint32) {
+    s := names(n, buffers)
+    record("DeleteBuffers", n, s)
+    for _, b := range s {
+        delete(state.buffers, b)
+    }
+}
+
+func DeleteFramebuffers(n int32, framebuffers *uint32) {
+    record("DeleteFramebuffers", n, names(n, framebuffers))
+}
+
+func DeleteProgram(program uint32) {
+    record("DeleteProgram", program)
+    delete(state.uniforms, program)
+    delete(state.attribs, program)
+    delete(state.blocks, program)
+}
+
+func DeleteRenderbuffers(n int32, renderbuffers *uint32) {
+    record("DeleteRenderbuffers", n, names(n, renderbuffers))
+}
+
+func DeleteShader(shader uint32) {
+    record("DeleteShader", shader)
+}
+
+func DeleteTextures(n int32, textures *uint32) {
+    record("DeleteTextures", n, names(n, textures))
+}
+
+func DeleteVertexArrays(n int32, arrays *uint32) {
+    record("DeleteVertexArrays", n, names(n, arrays))
+}
+
+func Disable(cap uint32) {
+    record("Disable", cap)
+    state.enabled[cap] = false
+}
+
+func DispatchCompute(num_groups_x uint32, num_groups_y uint32, num_groups_z uint32) {
+    record("DispatchCompute", num_groups_x, num_groups_y, num_groups_z)
+}
+
+func DrawArrays(mode uint32, first int32, count int32) {
+    record("DrawArrays", mode, first, count)
+}
+
+func DrawBuffers(n int32, bufs *uint32) {
+    record("DrawBuffers", n, names(n, bufs))
+}
+
+func DrawElementsWithOffset(mode uint32, count int32, xtype uint32, indices uintptr) {
+    record("DrawElementsWithOffset", mode, count, xtype, indices)
+}
+
+func Enable(cap uint32) {
+    record("Enable", cap)
+    state.enabled[cap] = true
+}
+
+func EnableVertexAttribArray(index uint32) {
+    record("EnableVertexAttribArray", index)
+}
+
+func EndQuery(target uint32) {
+    record("EndQuery", target)
+}
+
+func FramebufferRenderbuffer(target uint32, attachment uint32, renderbuffertarget uint32, renderbuffer uint32) {
+    record("FramebufferRenderbuffer", target, attachment, renderbuffertarget, renderbuffer)
+}
+
+func FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+    record("FramebufferTexture2D", target, attachment, textarget, texture, level)
+}
+
+func GenBuffers(n int32, buffers *uint32) {
+    record("GenBuffers", n, gen(n, buffers))
+}
+
+func GenFramebuffers(n int32, framebuffers *uint32) {
+    record("GenFramebuffers", n, gen(n, framebuffers))
+}
+
+func GenQueries(n int32, ids *uint32) {
+    record("GenQueries", n, gen(n, ids))
+}
+
+func GenRenderbuffers(n int32, renderbuffers *uint32) {
+    record("GenRenderbuffers", n, gen(n, renderbuffers))
+}
+
+func GenTextures(n int32, textures *uint32) {
+    record("GenTextures", n, gen(n, textures))
+}
+
+func GenVertexArrays(n int32, arrays *uint32) {
+    record("GenVertexArrays", n, gen(n, arrays))
+}
+
+func GenerateMipmap(target uint32) {
+    record("GenerateMipmap", target)
+}
+
+func GetAttribLocation(program uint32, name *uint8) int32 {
+    record("GetAttribLocation", program, GoStr(name))
+    return location(state.attribs, program, GoStr(name))
+}
+
+// GetError never reports an error.
+func GetError() uint32 {
+    record("GetError")
+    return 0
+}
+
+func GetFloatv(pname uint32, data *float32) {
+    record("GetFloatv", pname)
+    switch pname {
+    case MAX_TEXTURE_MAX_ANISOTROPY:
+        *data = 16
+    default:
+        *data = 0
+    }
+}
+
+// GetIntegerv answers with the limits of a modest desktop GPU, the context
+// the mock window was created with and the viewport last set.
+func GetIntegerv(pname uint32, data *int32) {
+    record("GetIntegerv", pname)
+    switch pname {
+    case MAJOR_VERSION:
+        *data = int32(mock.ContextMajor)
+    case MINOR_VERSION:
+        *data = int32(mock.ContextMinor)
+    case MAX_TEXTURE_SIZE:
+        *data = 16384
+    case MAX_ARRAY_TEXTURE_LAYERS:
+        *data = 2048
+    case MAX_SAMPLES:
+        *data = 8
+    case SAMPLES:
+        *data = int32(mock.Samples)
+    case MAX_UNIFORM_BUFFER_BINDINGS:
+        *data = 36
+    case CONTEXT_FLAGS:
+        *data = 0
+        if mock.DebugContext {
+            *data = CONTEXT_FLAG_DEBUG_BIT
+        }
+    case VIEWPORT:
+        copy(unsafe.Slice(data, 4), state.viewport[:])
+    default:
+        *data = 0
+    }
+}
+
+func GetProgramInfoLog(program uint32, bufSize int32, length *int32, infoLog *uint8) {
+    record("GetProgramInfoLog", program, bufSize)
+    emptyLog(bufSize, length, infoLog)
+}
+
+// GetProgramiv reports every program as linked, with a work group size of
+// 1x1x1.
+func GetProgramiv(program uint32, pname uint32, params *int32) {
+    record("GetProgramiv", program, pname)
+    switch pname {
+    case LINK_STATUS:
+        *params = TRUE
+    case COMPUTE_WORK_GROUP_SIZE:
+        copy(unsafe.Slice(params, 3), []int32{1, 1, 1})
+    default:
+        *params = 0
+    }
+}
+
+func GetQueryObjectuiv(id uint32, pname uint32, params *uint32) {
+    record("GetQueryObjectuiv", id, pname)
+    *params = 0
+}
+
+func GetShaderInfoLog(shader uint32, bufSize int32, length *int32, infoLog *uint8) {
+    record("GetShaderInfoLog", shader, bufSize)
+    emptyLog(bufSize, length, infoLog)
+}
+
+// GetShaderiv reports every shader as compiled.
+func GetShaderiv(shader uint32, pname uint32, params *int32) {
+    record("GetShaderiv", shader, pname)
+    switch pname {
+    case COMPILE_STATUS:
+        *params = TRUE
+    default:
+        *params = 0
+    }
+}
+
+func emptyLog(bufSize int32, length *int32, infoLog *uint8) {
+    if length != nil {
+        *length = 0
+    }
+    if bufSize > 0 && infoLog != nil {
+        *infoLog = 0
+    }
+}
+
+func GetString(name uint32) *uint8 {
+    record("GetString", name)
+    var v string
+    switch name {
+    case VERSION:
+        v = fmt.Sprintf("%d.%d gome mock", mock.ContextMajor, mock.ContextMinor)
+    case SHADING_LANGUAGE_VERSION:
+        v = fmt.Sprintf("%d.%d0", mock.ContextMajor, mock.ContextMinor)
+    case VENDOR:
+        v = "gome"
+    case RENDERER:
+        v = "mock"
+    }
+    s, ok := state.strings[v]
+    if !ok {
+        s = append([]byte(v), 0)
+        state.strings[v] = s
+    }
+    return &s[0]
+}
+
+// GetStringi returns an empty string; the mock has no extensions.
+func GetStringi(name uint32, index uint32) *uint8 {
+    record("GetStringi", name, index)
+    return &[]byte{0}[0]
+}
+
+func GetUniformBlockIndex(program uint32, uniformBlockName *uint8) uint32 {
+    record("GetUniformBlockIndex", program, GoStr(uniformBlockName))
+    return uint32(location(state.blocks, program, GoStr(uniformBlockName)))
+}
+
+// GetUniformLocation hands out locations in the order the names are asked
+// for, so every uniform exists.
+func GetUniformLocation(program uint32, name *uint8) int32 {
+    record("GetUniformLocation", program, GoStr(name))
+    return location(state.uniforms, program, GoStr(name))
+}
+
+func GoStr(cstr *uint8) string {
+    if cstr == nil {
+        return ""
+    }
+    n := 0
+    for *(*uint8)(unsafe.Add(unsafe.Pointer(cstr), n)) != 0 {
+        n++
+    }
+    return string(unsafe.Slice(cstr, n))
+}
+
+func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
+    record("InitWithProcAddrFunc")
+    return nil
+}
+
+func IsEnabled(cap uint32) bool {
+    record("IsEnabled", cap)
+    return state.enabled[cap]
+}
+
+func LinkProgram(program uint32) {
+    record("LinkProgram", program)
+}
+
+// MapBufferRange maps the store of the buffer bound to target, which holds
+// what was written to it, so reading back works.
+func MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer {
+    record("MapBufferRange", target, offset, length, access)
+    store := state.buffers[state.bound[target]]
+    if offset < 0 || length <= 0 || offset+length > len(store) {
+        return nil
+    }
+    return unsafe.Pointer(&store[offset])
+}
+
+func MemoryBarrier(barriers uint32) {
+    record("MemoryBarrier", barriers)
+}
+
+func PixelStorei(pname uint32, param int32) {
+    record("PixelStorei", pname, param)
+}
+
+func PolygonOffset(factor float32, units float32) {
+    record("PolygonOffset", factor, units)
+}
+
+func Ptr(data interface{}) unsafe.Pointer {
+    if data == nil {
+        return nil
+    }
+    v := reflect.ValueOf(data)
+    switch v.Kind() {
+    case reflect.Ptr:
+        return v.UnsafePointer()
+    case reflect.Slice:
+        return v.Index(0).Addr().UnsafePointer()
+    }
+    panic(fmt.Errorf("unsupported type %s; must be a slice or pointer to a singular scalar value or the first element of an array or slice", v.Type()))
+}
+
+func ReadBuffer(src uint32) {
+    record("ReadBuffer", src)
+}
+
+// ReadPixels fills RGBA bytes with the colour of the read framebuffer, and
+// leaves other formats as they are.
+func ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+    record("ReadPixels", x, y, width, height, format, xtype)
+    if format != RGBA || xtype != UNSIGNED_BYTE || pixels == nil {
+        return
+    }
+    c := state.colors[state.read]
+    pix := unsafe.Slice((*byte)(pixels), int(width)*int(height)*4)
+    for i := 0; i < len(pix); i += 4 {
+        copy(pix[i:i+4], c[:])
+    }
+}
+
+func RenderbufferStorage(target uint32, internalformat uint32, width int32, height int32) {
+    record("RenderbufferStorage", target, internalformat, width, height)
+}
+
+func RenderbufferStorageMultisample(target uint32, samples int32, internalformat uint32, width int32, height int32) {
+    record("RenderbufferStorageMultisample", target, samples, internalformat, width, height)
+}
+
+// ShaderSource records the source as one string.
+func ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {
+    var src strings.Builder
+    for i, s := range unsafe.Slice(xstring, count) {
+        if length != nil && unsafe.Slice(length, count)[i] >= 0 {
+            src.Write(unsafe.Slice(s, unsafe.Slice(length, count)[i]))
+            continue
+        }
+        src.WriteString(GoStr(s))
+    }
+    record("ShaderSource", shader, src.String())
+}
+
+func Str(str string) *uint8 {
+    if !strings.HasSuffix(str, "\x00") {
+        panic("str argument missing null terminator: " + str)
+    }
+    return unsafe.StringData(str)
+}
+
+func Strs(strs ...string) (cstrs **uint8, free func()) {
+    if len(strs) == 0 {
+        panic("Strs: expected at least 1 string")
+    }
+    css := make([]*uint8, len(strs))
+    for i, s := range strs {
+        css[i] = &append([]byte(s), 0)[0]
+    }
+    return &css[0], func() {}
+}
+
+func TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+    record("TexImage2D", target, level, internalformat, width, height, border, format, xtype, pixels)
+}
+
+func TexImage3D(target uint32, level int32, internalformat int32, width int32, height int32, depth int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+    record("TexImage3D", target, level, internalformat, width, height, depth, border, format, xtype, pixels)
+}
+
+func TexParameterf(target uint32, pname uint32, param float32) {
+    record("TexParameterf", target, pname, param)
+}
+
+func TexParameterfv(target uint32, pname uint32, params *float32) {
+    record("TexParameterfv", target, pname, append([]float32(nil), unsafe.Slice(params, 4)...))
+}
+
+func TexParameteri(target uint32, pname uint32, param int32) {
+    record("TexParameteri", target, pname, param)
+}
+
+func TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+    record("TexSubImage2D", target, level, xoffset, yoffset, width, height, format, xtype, pixels)
+}
+
+func TexSubImage3D(target uint32, level int32, xoffset int32, yoffset int32, zoffset int32, width int32, height int32, depth int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+    record("TexSubImage3D", target, level, xoffset, yoffset, zoffset, width, height, depth, format, xtype, pixels)
+}
+
+func Uniform1f(location int32, v0 float32) {
+    record("Uniform1f", location, v0)
+}
+
+func Uniform4f(location int32, v0 float32, v1 float32, v2 float32, v3 float32) {
+    record("Uniform4f", location, v0, v1, v2, v3)
+}
+
+func UniformBlockBinding(program uint32, uniformBlockIndex uint32, uniformBlockBinding uint32) {
+    record("UniformBlockBinding", program, uniformBlockIndex, uniformBlockBinding)
+}
+
+// UniformMatrix4fv records copies of the matrices.
+func UniformMatrix4fv(location int32, count int32, transpose bool, value *float32) {
+    record("UniformMatrix4fv", location, count, transpose, append([]float32(nil), unsafe.Slice(value, 16*count)...))
+}
+
+func UnmapBuffer(target uint32) bool {
+    record("UnmapBuffer", target)
+    return true
+}
+
+func UseProgram(program uint32) {
+    record("UseProgram", program)
+}
+
+func VertexAttribPointerWithOffset(index uint32, size int32, xtype uint32, normalized bool, stride int32, offset uintptr) {
+    record("VertexAttribPointerWithOffset", index, size, xtype, normalized, stride, offset)
+}
+
+func Viewport(x int32, y int32, width int32, height int32) {
+    record("Viewport", x, y, width, height)
+    state.viewport = [4]int32{x, y, width, height}
+}
@@ -0,0 +1,12 @@
+/*
+Package glfw is the part of the GLFW API that gome uses. Normally it forwards
+to the go-gl bindings; built with the gomemock tag, it is the mock backend
+instead, with a Window that is never shown, one monitor and no joysticks,
+which records the calls in the command log of package mock. The types of
+input events are aliases of those of go-gl, or in the mock backend types of
+its own with the same values, so that it builds without cgo.
+
+Only what gome uses is here, under the names of go-gl. Anything new has to be
+added to both glfw.go and glfw_mock.go, or types.go and types_mock.go.
+*/
+package glfw
@@ -0,0 +1,74 @@
+// +build !gomemock
+
+package glfw
+
+import (
+    "github.com/go-gl/glfw/v3.3/glfw"
+    "image"
+    "unsafe"
+)
+
+type (
+    Window   = glfw.Window
+    Monitor  = glfw.Monitor
+    Cursor   = glfw.Cursor
+    Joystick = glfw.Joystick
+)
+
+const Joystick1 = glfw.Joystick1
+
+func Init() error {
+    return glfw.Init()
+}
+
+func Terminate() {
+    glfw.Terminate()
+}
+
+func DefaultWindowHints() {
+    glfw.DefaultWindowHints()
+}
+
+func WindowHint(target Hint, hint int) {
+    glfw.WindowHint(target, hint)
+}
+
+func CreateWindow(width, height int, title string, monitor *Monitor, share *Window) (*Window, error) {
+    return glfw.CreateWindow(width, height, title, monitor, share)
+}
+
+func CreateCursor(img image.Image, xhot, yhot int) *Cursor {
+    return glfw.CreateCursor(img, xhot, yhot)
+}
+
+func GetMonitors() []*Monitor {
+    return glfw.GetMonitors()
+}
+
+func GetPrimaryMonitor() *Monitor {
+    return glfw.GetPrimaryMonitor()
+}
+
+func GetProcAddress(procname string) unsafe.Pointer {
+    return glfw.GetProcAddress(procname)
+}
+
+func SwapInterval(interval int) {
+    glfw.SwapInterval(interval)
+}
+
+func PollEvents() {
+    glfw.PollEvents()
+}
+
+func WaitEventsTimeout(timeout float64) {
+    glfw.WaitEventsTimeout(timeout)
+}
+
+func PostEmptyEvent() {
+    glfw.PostEmptyEvent()
+}
+
+func RawMouseMotionSupported() bool {
+    return glfw.RawMouseMotionSupported()
+}
@@ -0,0 +1,516 @@
+// +build gomemock
+
+package glfw
+
+import (
+    "github.com/snorredc/gome/internal/mock"
+    "image"
+    "time"
+    "unsafe"
+)
+
+// Joystick is a joystick slot; the mock has no joysticks connected.
+type Joystick int
+
+const Joystick1 Joystick = 0
+
+func (j Joystick) Present() bool {
+    return false
+}
+
+func (j Joystick) GetAxes() []float32 {
+    return nil
+}
+
+func (j Joystick) GetButtons() []Action {
+    return nil
+}
+
+// Monitor is a monitor; the mock has one, of 1920x1080 pixels.
+type Monitor struct {
+    name string
+    mode VidMode
+}
+
+var monitor = &Monitor{"Mock", VidMode{1920, 1080, 8, 8, 8, 60}}
+
+func (m *Monitor) GetName() string {
+    return m.name
+}
+
+func (m *Monitor) GetPos() (x, y int) {
+    return 0, 0
+}
+
+func (m *Monitor) GetVideoMode() *VidMode {
+    mode := m.mode
+    return &mode
+}
+
+// Cursor is a cursor image.
+type Cursor struct {
+    image      image.Image
+    xhot, yhot int
+}
+
+func (c *Cursor) Destroy() {
+    record("Cursor.Destroy")
+}
+
+type (
+    CharCallback        func(w *Window, char rune)
+    KeyCallback         func(w *Window, key Key, scancode int, action Action, mods ModifierKey)
+    MouseButtonCallback func(w *Window, button MouseButton, action Action, mods ModifierKey)
+    CursorPosCallback   func(w *Window, xpos, ypos float64)
+    ScrollCallback      func(w *Window, xoff, yoff float64)
+    DropCallback        func(w *Window, names []string)
+    SizeCallback        func(w *Window, width, height int)
+    PosCallback         func(w *Window, xpos, ypos int)
+    FocusCallback       func(w *Window, focused bool)
+    IconifyCallback     func(w *Window, iconified bool)
+    CloseCallback       func(w *Window)
+)
+
+// Window is a window that is never shown. Its framebuffer is as large as the
+// window. The Send methods simulate events, which the callbacks get at the
+// next PollEvents or WaitEventsTimeout, as with GLFW.
+type Window struct {
+    width, height int
+    x, y          int
+    title         string
+    opacity       float32
+    shouldClose   bool
+    cursorX       float64
+    cursorY       float64
+    attribs       map[Hint]int
+    inputModes    map[InputMode]int
+    keys          map[Key]Action
+    cursor        *Cursor
+    icons         []image.Image
+
+    char        CharCallback
+    key         KeyCallback
+    mouseButton MouseButtonCallback
+    cursorPos   CursorPosCallback
+    scroll      ScrollCallback
+    drop        DropCallback
+    size        SizeCallback
+    pos         PosCallback
+    focus       FocusCallback
+    iconify     IconifyCallback
+    close       CloseCallback
+}
+
+var (
+    hints   = map[Hint]int{}
+    pending []func() // the events sent since the last poll
+)
+
+func record(name string, args ...interface{}) {
+    mock.Record("glfw."+name, args...)
+}
+
+func Init() error {
+    record("Init")
+    DefaultWindowHints()
+    return nil
+}
+
+func Terminate() {
+    record("Terminate")
+    pending = nil
+}
+
+func DefaultWindowHints() {
+    record("DefaultWindowHints")
+    hints = map[Hint]int{
+        ContextVersionMajor: 1,
+        Visible:             True,
+        Focused:             True,
+    }
+}
+
+func WindowHint(target Hint, hint int) {
+    record("WindowHint", target, hint)
+    hints[target] = hint
+}
+
+// CreateWindow creates a window with the context version, debug flag and
+// samples of the hints, so the context reports them.
+func CreateWindow(width, height int, title string, monitor *Monitor, share *Window) (*Window, error) {
+    record("CreateWindow", width, height, title)
+    mock.ContextMajor, mock.ContextMinor = hints[ContextVersionMajor], hints[ContextVersionMinor]
+    mock.DebugContext = hints[OpenGLDebugContext] == True
+    mock.Samples = hints[Samples]
+    w := &Window{
+        width:      width,
+        height:     height,
+        title:      title,
+        opacity:    1,
+        attribs:    map[Hint]int{Focused: hints[Focused], Visible: hints[Visible]},
+        inputModes: map[InputMode]int{CursorMode: CursorNormal},
+        keys:       map[Key]Action{},
+    }
+    return w, nil
+}
+
+func CreateCursor(img image.Image, xhot, yhot int) *Cursor {
+    record("CreateCursor", xhot, yhot)
+    return &Cursor{img, xhot, yhot}
+}
+
+func GetMonitors() []*Monitor {
+    return []*Monitor{monitor}
+}
+
+func GetPrimaryMonitor() *Monitor {
+    return monitor
+}
+
+func GetProcAddress(procname string) unsafe.Pointer {
+    return nil
+}
+
+func SwapInterval(interval int) {
+    record("SwapInterval", interval)
+}
+
+// PollEvents delivers the events sent since the last poll.
+func PollEvents() {
+    record("PollEvents")
+    events := pending
+    pending = nil
+    for _, e := range events {
+        e()
+    }
+}
+
+// empty wakes up WaitEventsTimeout, like the empty events of GLFW.
+var empty = make(chan struct{}, 1)
+
+// WaitEventsTimeout delivers the events sent since the last poll like
+// PollEvents. If there are none, it first waits up to timeout for
+// PostEmptyEvent, as nothing else can send events while it waits.
+func WaitEventsTimeout(timeout float64) {
+    if len(pending) == 0 {
+        select {
+        case <-empty:
+        case <-time.After(time.Duration(timeout * float64(time.Second))):
+        }
+    }
+    PollEvents()
+}
+
+// PostEmptyEvent wakes up WaitEventsTimeout. It may be called from any
+// goroutine.
+func PostEmptyEvent() {
+    select {
+    case empty <- struct{}{}:
+    default:
+    }
+}
+
+func RawMouseMotionSupported() bool {
+    return true
+}
+
+func (w *Window) MakeContextCurrent() {
+    record("Window.MakeContextCurrent")
+}
+
+func (w *Window) SwapBuffers() {
+    record("Window.SwapBuffers")
+}
+
+func (w *Window) Destroy() {
+    record("Window.Destroy")
+}
+
+func (w *Window) Show() {
+    record("Window.Show")
+    w.attribs[Visible] = True
+}
+
+func (w *Window) Hide() {
+    record("Window.Hide")
+    w.attribs[Visible] = False
+}
+
+// Maximize maximizes the window onto the monitor, which is reported to the
+// callbacks at the next poll.
+func (w *Window) Maximize() {
+    record("Window.Maximize")
+    w.attribs[Maximized] = True
+    x, y := monitor.GetPos()
+    w.SendPos(x, y)
+    w.SendSize(monitor.mode.Width, monitor.mode.Height)
+}
+
+func (w *Window) ShouldClose() bool {
+    return w.shouldClose
+}
+
+func (w *Window) SetShouldClose(value bool) {
+    w.shouldClose = value
+}
+
+func (w *Window) SetTitle(title string) {
+    record("Window.SetTitle", title)
+    w.title = title
+}
+
+func (w *Window) SetIcon(images []image.Image) {
+    record("Window.SetIcon", len(images))
+    w.icons = images
+}
+
+func (w *Window) GetSize() (width, height int) {
+    return w.width, w.height
+}
+
+func (w *Window) SetSize(width, height int) {
+    record("Window.SetSize", width, height)
+    w.width, w.height = width, height
+}
+
+func (w *Window) GetFramebufferSize() (width, height int) {
+    return w.width, w.height
+}
+
+func (w *Window) GetPos() (x, y int) {
+    return w.x, w.y
+}
+
+func (w *Window) SetPos(xpos, ypos int) {
+    record("Window.SetPos", xpos, ypos)
+    w.x, w.y = xpos, ypos
+}
+
+func (w *Window) GetOpacity() float32 {
+    return w.opacity
+}
+
+func (w *Window) SetOpacity(opacity float32) {
+    record("Window.SetOpacity", opacity)
+    w.opacity = opacity
+}
+
+func (w *Window) GetAttrib(attrib Hint) int {
+    return w.attribs[attrib]
+}
+
+func (w *Window) SetAttrib(attrib Hint, value int) {
+    record("Window.SetAttrib", attrib, value)
+    w.attribs[attrib] = value
+}
+
+func (w *Window) GetInputMode(mode InputMode) int {
+    return w.inputModes[mode]
+}
+
+func (w *Window) SetInputMode(mode InputMode, value int) {
+    record("Window.SetInputMode", mode, value)
+    w.inputModes[mode] = value
+}
+
+func (w *Window) SetCursor(c *Cursor) {
+    record("Window.SetCursor")
+    w.cursor = c
+}
+
+func (w *Window) GetCursorPos() (x, y float64) {
+    return w.cursorX, w.cursorY
+}
+
+func (w *Window) GetKey(key Key) Action {
+    return w.keys[key]
+}
+
+// The mock has a clipboard of its own.
+var clipboard string
+
+func (w *Window) GetClipboardString() string {
+    return clipboard
+}
+
+func (w *Window) SetClipboardString(str string) {
+    record("Window.SetClipboardString", str)
+    clipboard = str
+}
+
+func (w *Window) SetCharCallback(cbfun CharCallback) (previous CharCallback) {
+    previous, w.char = w.char, cbfun
+    return previous
+}
+
+func (w *Window) SetKeyCallback(cbfun KeyCallback) (previous KeyCallback) {
+    previous, w.key = w.key, cbfun
+    return previous
+}
+
+func (w *Window) SetMouseButtonCallback(cbfun MouseButtonCallback) (previous MouseButtonCallback) {
+    previous, w.mouseButton = w.mouseButton, cbfun
+    return previous
+}
+
+func (w *Window) SetCursorPosCallback(cbfun CursorPosCallback) (previous CursorPosCallback) {
+    previous, w.cursorPos = w.cursorPos, cbfun
+    return previous
+}
+
+func (w *Window) SetScrollCallback(cbfun ScrollCallback) (previous ScrollCallback) {
+    previous, w.scroll = w.scroll, cbfun
+    return previous
+}
+
+func (w *Window) SetDropCallback(cbfun DropCallback) (previous DropCallback) {
+    previous, w.drop = w.drop, cbfun
+    return previous
+}
+
+func (w *Window) SetSizeCallback(cbfun SizeCallback) (previous SizeCallback) {
+    previous, w.size = w.size, cbfun
+    return previous
+}
+
+func (w *Window) SetPosCallback(cbfun PosCallback) (previous PosCallback) {
+    previous, w.pos = w.pos, cbfun
+    return previous
+}
+
+func (w *Window) SetFocusCallback(cbfun FocusCallback) (previous FocusCallback) {
+    previous, w.focus = w.focus, cbfun
+    return previous
+}
+
+func (w *Window) SetIconifyCallback(cbfun IconifyCallback) (previous IconifyCallback) {
+    previous, w.iconify = w.iconify, cbfun
+    return previous
+}
+
+func (w *Window) SetCloseCallback(cbfun CloseCallback) (previous CloseCallback) {
+    previous, w.close = w.close, cbfun
+    return previous
+}
+
+// send queues an event for the next poll.
+func send(e func()) {
+    pending = append(pending, e)
+}
+
+// SendKey simulates a key event.
+func (w *Window) SendKey(key Key, scancode int, action Action, mods ModifierKey) {
+    send(func() {
+        if action == Release {
+            delete(w.keys, key)
+        } else {
+            w.keys[key] = Press
+        }
+        if w.key != nil {
+            w.key(w, key, scancode, action, mods)
+        }
+    })
+}
+
+// SendChar simulates text input.
+func (w *Window) SendChar(char rune) {
+    send(func() {
+        if w.char != nil {
+            w.char(w, char)
+        }
+    })
+}
+
+// SendMouseButton simulates a mouse button event.
+func (w *Window) SendMouseButton(button MouseButton, action Action, mods ModifierKey) {
+    send(func() {
+        if w.mouseButton != nil {
+            w.mouseButton(w, button, action, mods)
+        }
+    })
+}
+
+// SendCursorPos simulates a cursor motion to x, y.
+func (w *Window) SendCursorPos(x, y float64) {
+    send(func() {
+        w.cursorX, w.cursorY = x, y
+        if w.cursorPos != nil {
+            w.cursorPos(w, x, y)
+        }
+    })
+}
+
+// SendScroll simulates scrolling.
+func (w *Window) SendScroll(xoff, yoff float64) {
+    send(func() {
+        if w.scroll != nil {
+            w.scroll(w, xoff, yoff)
+        }
+    })
+}
+
+// SendDrop simulates files dropped onto the window.
+func (w *Window) SendDrop(names []string) {
+    send(func() {
+        if w.drop != nil {
+            w.drop(w, names)
+        }
+    })
+}
+
+// SendSize simulates the user resizing the window.
+func (w *Window) SendSize(width, height int) {
+    send(func() {
+        w.width, w.height = width, height
+        if w.size != nil {
+            w.size(w, width, height)
+        }
+    })
+}
+
+// SendPos simulates the user moving the window to x, y.
+func (w *Window) SendPos(x, y int) {
+    send(func() {
+        w.x, w.y = x, y
+        if w.pos != nil {
+            w.pos(w, x, y)
+        }
+    })
+}
+
+// SendFocus simulates the window gaining or losing the focus.
+func (w *Window) SendFocus(focused bool) {
+    send(func() {
+        w.attribs[Focused] = boolInt(focused)
+        if w.focus != nil {
+            w.focus(w, focused)
+        }
+    })
+}
+
+// SendIconify simulates the window being minimized or restored.
+func (w *Window) SendIconify(iconified bool) {
+    send(func() {
+        w.attribs[Iconified] = boolInt(iconified)
+        if w.iconify != nil {
+            w.iconify(w, iconified)
+        }
+    })
+}
+
+// SendClose simulates the user closing the window.
+func (w *Window) SendClose() {
+    send(func() {
+        w.shouldClose = true
+        if w.close != nil {
+            w.close(w)
+        }
+    })
+}
+
+func boolInt(b bool) int {
+    if b {
+        return True
+    }
+    return False
+}
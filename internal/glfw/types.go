@@ -0,0 +1,115 @@
+// +build !gomemock
+
+package glfw
+
+import (
+    "github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// The types of input and hints are those of go-gl, so the API of gome is the
+// same as with the mock backend, where they are defined in types_mock.go.
+type (
+    Action      = glfw.Action
+    Hint        = glfw.Hint
+    InputMode   = glfw.InputMode
+    Key         = glfw.Key
+    ModifierKey = glfw.ModifierKey
+    MouseButton = glfw.MouseButton
+    VidMode     = glfw.VidMode
+)
+
+const (
+    ClientAPI               = glfw.ClientAPI
+    ContextVersionMajor     = glfw.ContextVersionMajor
+    ContextVersionMinor     = glfw.ContextVersionMinor
+    CursorDisabled          = glfw.CursorDisabled
+    CursorHidden            = glfw.CursorHidden
+    CursorMode              = glfw.CursorMode
+    CursorNormal            = glfw.CursorNormal
+    False                   = glfw.False
+    Floating                = glfw.Floating
+    Focused                 = glfw.Focused
+    Iconified               = glfw.Iconified
+    Key0                    = glfw.Key0
+    KeyA                    = glfw.KeyA
+    KeyApostrophe           = glfw.KeyApostrophe
+    KeyBackslash            = glfw.KeyBackslash
+    KeyBackspace            = glfw.KeyBackspace
+    KeyC                    = glfw.KeyC
+    KeyCapsLock             = glfw.KeyCapsLock
+    KeyComma                = glfw.KeyComma
+    KeyD                    = glfw.KeyD
+    KeyDelete               = glfw.KeyDelete
+    KeyDown                 = glfw.KeyDown
+    KeyEnd                  = glfw.KeyEnd
+    KeyEnter                = glfw.KeyEnter
+    KeyEqual                = glfw.KeyEqual
+    KeyEscape               = glfw.KeyEscape
+    KeyF1                   = glfw.KeyF1
+    KeyF10                  = glfw.KeyF10
+    KeyF3                   = glfw.KeyF3
+    KeyGraveAccent          = glfw.KeyGraveAccent
+    KeyHome                 = glfw.KeyHome
+    KeyInsert               = glfw.KeyInsert
+    KeyKP0                  = glfw.KeyKP0
+    KeyKPAdd                = glfw.KeyKPAdd
+    KeyKPDecimal            = glfw.KeyKPDecimal
+    KeyKPDivide             = glfw.KeyKPDivide
+    KeyKPEnter              = glfw.KeyKPEnter
+    KeyKPEqual              = glfw.KeyKPEqual
+    KeyKPMultiply           = glfw.KeyKPMultiply
+    KeyKPSubtract           = glfw.KeyKPSubtract
+    KeyLast                 = glfw.KeyLast
+    KeyLeft                 = glfw.KeyLeft
+    KeyLeftAlt              = glfw.KeyLeftAlt
+    KeyLeftBracket          = glfw.KeyLeftBracket
+    KeyLeftControl          = glfw.KeyLeftControl
+    KeyLeftShift            = glfw.KeyLeftShift
+    KeyLeftSuper            = glfw.KeyLeftSuper
+    KeyMenu                 = glfw.KeyMenu
+    KeyMinus                = glfw.KeyMinus
+    KeyNumLock              = glfw.KeyNumLock
+    KeyPageDown             = glfw.KeyPageDown
+    KeyPageUp               = glfw.KeyPageUp
+    KeyPause                = glfw.KeyPause
+    KeyPeriod               = glfw.KeyPeriod
+    KeyPrintScreen          = glfw.KeyPrintScreen
+    KeyRight                = glfw.KeyRight
+    KeyRightAlt             = glfw.KeyRightAlt
+    KeyRightBracket         = glfw.KeyRightBracket
+    KeyRightControl         = glfw.KeyRightControl
+    KeyRightShift           = glfw.KeyRightShift
+    KeyRightSuper           = glfw.KeyRightSuper
+    KeyScrollLock           = glfw.KeyScrollLock
+    KeySemicolon            = glfw.KeySemicolon
+    KeySlash                = glfw.KeySlash
+    KeySpace                = glfw.KeySpace
+    KeyTab                  = glfw.KeyTab
+    KeyUnknown              = glfw.KeyUnknown
+    KeyUp                   = glfw.KeyUp
+    KeyV                    = glfw.KeyV
+    KeyX                    = glfw.KeyX
+    Maximized               = glfw.Maximized
+    ModControl              = glfw.ModControl
+    ModShift                = glfw.ModShift
+    ModSuper                = glfw.ModSuper
+    MouseButtonLast         = glfw.MouseButtonLast
+    MouseButtonLeft         = glfw.MouseButtonLeft
+    MouseButtonMiddle       = glfw.MouseButtonMiddle
+    MouseButtonRight        = glfw.MouseButtonRight
+    OpenGLAPI               = glfw.OpenGLAPI
+    OpenGLCompatProfile     = glfw.OpenGLCompatProfile
+    OpenGLCoreProfile       = glfw.OpenGLCoreProfile
+    OpenGLDebugContext      = glfw.OpenGLDebugContext
+    OpenGLESAPI             = glfw.OpenGLESAPI
+    OpenGLForwardCompatible = glfw.OpenGLForwardCompatible
+    OpenGLProfile           = glfw.OpenGLProfile
+    Press                   = glfw.Press
+    RawMouseMotion          = glfw.RawMouseMotion
+    Release                 = glfw.Release
+    Repeat                  = glfw.Repeat
+    SRGBCapable             = glfw.SRGBCapable
+    Samples                 = glfw.Samples
+    True                    = glfw.True
+    Visible                 = glfw.Visible
+)
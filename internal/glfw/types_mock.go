@@ -0,0 +1,120 @@
+// +build gomemock
+
+package glfw
+
+// The types of input and hints of the mock backend, with the values of GLFW,
+// so that it does not import go-gl and with it cgo and the headers of the
+// window system.
+type (
+    Action      int
+    Hint        int
+    InputMode   int
+    Key         int
+    ModifierKey int
+    MouseButton int
+)
+
+type VidMode struct {
+    Width       int
+    Height      int
+    RedBits     int
+    GreenBits   int
+    BlueBits    int
+    RefreshRate int
+}
+
+const (
+    ClientAPI               Hint        = 0x00022001
+    ContextVersionMajor     Hint        = 0x00022002
+    ContextVersionMinor     Hint        = 0x00022003
+    CursorDisabled          int         = 0x00034003
+    CursorHidden            int         = 0x00034002
+    CursorMode              InputMode   = 0x00033001
+    CursorNormal            int         = 0x00034001
+    False                   int         = 0
+    Floating                Hint        = 0x00020007
+    Focused                 Hint        = 0x00020001
+    Iconified               Hint        = 0x00020002
+    Key0                    Key         = 48
+    KeyA                    Key         = 65
+    KeyApostrophe           Key         = 39
+    KeyBackslash            Key         = 92
+    KeyBackspace            Key         = 259
+    KeyC                    Key         = 67
+    KeyCapsLock             Key         = 280
+    KeyComma                Key         = 44
+    KeyD                    Key         = 68
+    KeyDelete               Key         = 261
+    KeyDown                 Key         = 264
+    KeyEnd                  Key         = 269
+    KeyEnter                Key         = 257
+    KeyEqual                Key         = 61
+    KeyEscape               Key         = 256
+    KeyF1                   Key         = 290
+    KeyF10                  Key         = 299
+    KeyF3                   Key         = 292
+    KeyGraveAccent          Key         = 96
+    KeyHome                 Key         = 268
+    KeyInsert               Key         = 260
+    KeyKP0                  Key         = 320
+    KeyKPAdd                Key         = 334
+    KeyKPDecimal            Key         = 330
+    KeyKPDivide             Key         = 331
+    KeyKPEnter              Key         = 335
+    KeyKPEqual              Key         = 336
+    KeyKPMultiply           Key         = 332
+    KeyKPSubtract           Key         = 333
+    KeyLast                 Key         = 348
+    KeyLeft                 Key         = 263
+    KeyLeftAlt              Key         = 342
+    KeyLeftBracket          Key         = 91
+    KeyLeftControl          Key         = 341
+    KeyLeftShift            Key         = 340
+    KeyLeftSuper            Key         = 343
+    KeyMenu                 Key         = 348
+    KeyMinus                Key         = 45
+    KeyNumLock              Key         = 282
+    KeyPageDown             Key         = 267
+    KeyPageUp               Key         = 266
+    KeyPause                Key         = 284
+    KeyPeriod               Key         = 46
+    KeyPrintScreen          Key         = 283
+    KeyRight                Key         = 262
+    KeyRightAlt             Key         = 346
+    KeyRightBracket         Key         = 93
+    KeyRightControl         Key         = 345
+    KeyRightShift           Key         = 344
+    KeyRightSuper           Key         = 347
+    KeyScrollLock           Key         = 281
+    KeySemicolon            Key         = 59
+    KeySlash                Key         = 47
+    KeySpace                Key         = 32
+    KeyTab                  Key         = 258
+    KeyUnknown              Key         = -1
+    KeyUp                   Key         = 265
+    KeyV                    Key         = 86
+    KeyX                    Key         = 88
+    Maximized               Hint        = 0x00020008
+    ModControl              ModifierKey = 2
+    ModShift                ModifierKey = 1
+    ModSuper                ModifierKey = 8
+    MouseButtonLast         MouseButton = 7
+    MouseButtonLeft         MouseButton = 0
+    MouseButtonMiddle       MouseButton = 2
+    MouseButtonRight        MouseButton = 1
+    OpenGLAPI               int         = 0x00030001
+    OpenGLCompatProfile     int         = 0x00032002
+    OpenGLCoreProfile       int         = 0x00032001
+    OpenGLDebugContext      Hint        = 0x00022007
+    OpenGLESAPI             int         = 0x00030002
+    OpenGLForwardCompatible Hint        = 0x00022006
+    OpenGLProfile           Hint        = 0x00022008
+    Press                   Action      = 1
+    RawMouseMotion          InputMode   = 0x00033005
+    Release                 Action      = 0
+    Repeat                  Action      = 2
+    SRGBCapable             Hint        = 0x0002100E
+    Samples                 Hint        = 0x0002100D
+    True                    int         = 1
+    Visible                 Hint        = 0x00020004
+)
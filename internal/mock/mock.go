@@ -0,0 +1,65 @@
+/*
+Package mock keeps the command log of the mock backend, which packages gl and
+glfw record their calls in when gome is built with the gomemock tag, and the
+state the two share. Without the tag, nothing records anything.
+*/
+package mock
+
+import (
+    "fmt"
+    "strings"
+    "sync"
+)
+
+// Command is a call recorded by the mock backend.
+type Command struct {
+    Name string        // the qualified name of the function, e.g. "gl.DrawArrays"
+    Args []interface{} // the arguments; strings and IDs rather than pointers to them
+}
+
+// String returns the command the way it would be written in Go, e.g.
+// gl.DrawArrays(4, 0, 6).
+func (c Command) String() string {
+    args := make([]string, len(c.Args))
+    for i, a := range c.Args {
+        if s, ok := a.(string); ok {
+            args[i] = fmt.Sprintf("%q", s)
+            continue
+        }
+        args[i] = fmt.Sprint(a)
+    }
+    return c.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+var (
+    mu       sync.Mutex
+    commands []Command
+)
+
+// The context the mock window was created with, which gl reports.
+var (
+    ContextMajor, ContextMinor = 3, 2
+    DebugContext               bool
+    Samples                    int // of the default framebuffer
+)
+
+// Record appends a command to the log.
+func Record(name string, args ...interface{}) {
+    mu.Lock()
+    commands = append(commands, Command{name, args})
+    mu.Unlock()
+}
+
+// Commands returns a copy of the log.
+func Commands() []Command {
+    mu.Lock()
+    defer mu.Unlock()
+    return append([]Command(nil), commands...)
+}
+
+// Reset clears the log.
+func Reset() {
+    mu.Lock()
+    commands = nil
+    mu.Unlock()
+}
@@ -0,0 +1,143 @@
+package gome
+
+import (
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+)
+
+var (
+    ktx1Identifier = []byte("\xabKTX 11\xbb\r\n\x1a\n")
+    ktx2Identifier = []byte("\xabKTX 20\xbb\r\n\x1a\n")
+)
+
+// parseKTX parses a KTX 1 file holding a single compressed 2D image.
+func parseKTX(data []byte) (*compressedImage, error) {
+    if len(data) < 64 {
+        return nil, errors.New("truncated KTX header")
+    }
+    var order binary.ByteOrder = binary.LittleEndian
+    switch binary.LittleEndian.Uint32(data[12:]) {
+    case 0x04030201:
+    case 0x01020304:
+        order = binary.BigEndian
+    default:
+        return nil, errors.New("invalid KTX endianness")
+    }
+    field := func(i int) uint32 {
+        return order.Uint32(data[16+4*i:])
+    }
+    glType, format := field(0), field(3)
+    width, height, depth := int(field(5)), int(field(6)), field(7)
+    arrays, faces, levels, kvLength := field(8), field(9), int(field(10)), int(field(11))
+    if glType != 0 {
+        return nil, errors.New("uncompressed KTX files are not supported")
+    }
+    if depth > 1 || arrays > 0 || faces > 1 {
+        return nil, errors.New("only 2D KTX textures are supported")
+    }
+    if levels == 0 {
+        levels = 1
+    }
+    if _, ok := blockFormats[format]; !ok {
+        return nil, fmt.Errorf("unsupported format 0x%04X", format)
+    }
+
+    img := &compressedImage{format: format, width: width, height: height}
+    rest := data[64:]
+    if kvLength > len(rest) {
+        return nil, errors.New("truncated KTX key/value data")
+    }
+    rest = rest[kvLength:]
+    for i := 0; i < levels; i++ {
+        if len(rest) < 4 {
+            return nil, errors.New("truncated image data")
+        }
+        n := int(order.Uint32(rest))
+        rest = rest[4:]
+        if n > len(rest) {
+            return nil, errors.New("truncated image data")
+        }
+        img.levels = append(img.levels, rest[:n:n])
+        // levels are padded to 4 bytes
+        rest = rest[minInt((n+3)&^3, len(rest)):]
+    }
+    return img, nil
+}
+
+// vkFormats are the GL formats of the Vulkan formats used by KTX2 files.
+var vkFormats = map[uint32]uint32{
+    131: gl.COMPRESSED_RGB_S3TC_DXT1_EXT,              // VK_FORMAT_BC1_RGB_UNORM_BLOCK
+    132: gl.COMPRESSED_SRGB_S3TC_DXT1_EXT,             // VK_FORMAT_BC1_RGB_SRGB_BLOCK
+    133: gl.COMPRESSED_RGBA_S3TC_DXT1_EXT,             // VK_FORMAT_BC1_RGBA_UNORM_BLOCK
+    134: gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT1_EXT,       // VK_FORMAT_BC1_RGBA_SRGB_BLOCK
+    135: gl.COMPRESSED_RGBA_S3TC_DXT3_EXT,             // VK_FORMAT_BC2_UNORM_BLOCK
+    136: gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT3_EXT,       // VK_FORMAT_BC2_SRGB_BLOCK
+    137: gl.COMPRESSED_RGBA_S3TC_DXT5_EXT,             // VK_FORMAT_BC3_UNORM_BLOCK
+    138: gl.COMPRESSED_SRGB_ALPHA_S3TC_DXT5_EXT,       // VK_FORMAT_BC3_SRGB_BLOCK
+    139: gl.COMPRESSED_RED_RGTC1,                      // VK_FORMAT_BC4_UNORM_BLOCK
+    140: gl.COMPRESSED_SIGNED_RED_RGTC1,               // VK_FORMAT_BC4_SNORM_BLOCK
+    141: gl.COMPRESSED_RG_RGTC2,                       // VK_FORMAT_BC5_UNORM_BLOCK
+    142: gl.COMPRESSED_SIGNED_RG_RGTC2,                // VK_FORMAT_BC5_SNORM_BLOCK
+    143: gl.COMPRESSED_RGB_BPTC_UNSIGNED_FLOAT_ARB,    // VK_FORMAT_BC6H_UFLOAT_BLOCK
+    144: gl.COMPRESSED_RGB_BPTC_SIGNED_FLOAT_ARB,      // VK_FORMAT_BC6H_SFLOAT_BLOCK
+    145: gl.COMPRESSED_RGBA_BPTC_UNORM_ARB,            // VK_FORMAT_BC7_UNORM_BLOCK
+    146: gl.COMPRESSED_SRGB_ALPHA_BPTC_UNORM_ARB,      // VK_FORMAT_BC7_SRGB_BLOCK
+    147: gl.COMPRESSED_RGB8_ETC2,                      // VK_FORMAT_ETC2_R8G8B8_UNORM_BLOCK
+    148: gl.COMPRESSED_SRGB8_ETC2,                     // VK_FORMAT_ETC2_R8G8B8_SRGB_BLOCK
+    149: gl.COMPRESSED_RGB8_PUNCHTHROUGH_ALPHA1_ETC2,  // VK_FORMAT_ETC2_R8G8B8A1_UNORM_BLOCK
+    150: gl.COMPRESSED_SRGB8_PUNCHTHROUGH_ALPHA1_ETC2, // VK_FORMAT_ETC2_R8G8B8A1_SRGB_BLOCK
+    151: gl.COMPRESSED_RGBA8_ETC2_EAC,                 // VK_FORMAT_ETC2_R8G8B8A8_UNORM_BLOCK
+    152: gl.COMPRESSED_SRGB8_ALPHA8_ETC2_EAC,          // VK_FORMAT_ETC2_R8G8B8A8_SRGB_BLOCK
+    153: gl.COMPRESSED_R11_EAC,                        // VK_FORMAT_EAC_R11_UNORM_BLOCK
+    154: gl.COMPRESSED_SIGNED_R11_EAC,                 // VK_FORMAT_EAC_R11_SNORM_BLOCK
+    155: gl.COMPRESSED_RG11_EAC,                       // VK_FORMAT_EAC_R11G11_UNORM_BLOCK
+    156: gl.COMPRESSED_SIGNED_RG11_EAC,                // VK_FORMAT_EAC_R11G11_SNORM_BLOCK
+}
+
+// parseKTX2 parses a KTX2 file holding a single compressed 2D image without
+// supercompression.
+func parseKTX2(data []byte) (*compressedImage, error) {
+    const headerSize = 80
+    if len(data) < headerSize {
+        return nil, errors.New("truncated KTX2 header")
+    }
+    le := binary.LittleEndian
+    field := func(i int) uint32 {
+        return le.Uint32(data[12+4*i:])
+    }
+    vkFormat := field(0)
+    width, height, depth := int(field(2)), int(field(3)), field(4)
+    layers, faces, levels, scheme := field(5), field(6), int(field(7)), field(8)
+    if vkFormat == 0 {
+        return nil, errors.New("KTX2 files in Basis Universal are not supported")
+    }
+    if scheme != 0 {
+        return nil, errors.New("supercompressed KTX2 files are not supported")
+    }
+    if depth > 1 || layers > 0 || faces > 1 {
+        return nil, errors.New("only 2D KTX2 textures are supported")
+    }
+    format, ok := vkFormats[vkFormat]
+    if !ok {
+        return nil, fmt.Errorf("unsupported Vulkan format %d", vkFormat)
+    }
+    if levels == 0 {
+        levels = 1
+    }
+    if len(data) < headerSize+24*levels {
+        return nil, errors.New("truncated KTX2 level index")
+    }
+
+    img := &compressedImage{format: format, width: width, height: height}
+    for i := 0; i < levels; i++ {
+        entry := data[headerSize+24*i:]
+        offset, length := le.Uint64(entry), le.Uint64(entry[8:])
+        if offset > uint64(len(data)) || length > uint64(len(data))-offset {
+            return nil, errors.New("truncated image data")
+        }
+        img.levels = append(img.levels, data[offset:offset+length:offset+length])
+    }
+    return img, nil
+}
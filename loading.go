@@ -0,0 +1,102 @@
+package gome
+
+import (
+    "errors"
+    "math"
+    "sync/atomic"
+    "time"
+)
+
+// ErrLoadingAborted is returned by RunLoading if the main loop ends, e.g.
+// because the window is closed, before the loader has finished.
+var ErrLoadingAborted = errors.New("the main loop ended before loading finished")
+
+// loadingSlice is how long RunLoading runs the functions of the loader queued
+// by Do each frame, between drawing the progress screen.
+const loadingSlice = 10 * time.Millisecond
+
+// RunLoading runs loader on a new goroutine and keeps the main loop going
+// until it returns, calling draw every frame to draw a loading screen with
+// the progress the loader last reported. It returns the error of loader.
+// It has to be called from the main goroutine with the App initialised,
+// e.g. before the main loop:
+//
+//     err := gome.RunLoading(func(progress func(float32)) error {
+//         for i, path := range paths {
+//             img, err := decodeImage(path) // on the loader goroutine
+//             if err != nil {
+//                 return err
+//             }
+//             gome.Do(func() {
+//                 textures[i] = gome.NewTextureFromImage(img, nil)
+//             })
+//             progress(float32(i+1) / float32(len(paths)))
+//         }
+//         return nil
+//     }, func(progress float32) {
+//         batch.Begin(800, 600)
+//         batch.DrawRect(gome.Rect{100, 290, 600 * progress, 20}, color.White)
+//         batch.End()
+//     })
+//
+// The loader does the slow work, such as reading and decoding files, on its
+// goroutine, but must use Do for everything that touches OpenGL or GLFW. The
+// functions it queues run between frames, as many as fit into a few
+// milliseconds, so many small uploads do not take a frame each, while frames
+// without any are not held up. progress may be called from any goroutine,
+// with values from 0 to 1.
+//
+// If the main loop ends before the loader returns, because the window is
+// closed or OpenGL reports an error, RunLoading returns ErrLoadingAborted or
+// the error without waiting for it; a loader blocked in Do then stays
+// blocked, so the application should exit.
+func RunLoading(loader func(progress func(float32)) error, draw func(progress float32)) error {
+    var progress atomic.Uint32
+    report := func(p float32) {
+        progress.Store(math.Float32bits(p))
+    }
+    done := make(chan error, 1)
+    go func() {
+        done <- loader(report)
+    }()
+
+    for {
+        draw(math.Float32frombits(progress.Load()))
+        if !app.Tick() {
+            if err := app.GetError(); err != nil {
+                return err
+            }
+            return ErrLoadingAborted
+        }
+        // the loader queues one function at a time, so once it has queued
+        // one, the slice waits for the next; otherwise it ends right away
+        timeout := time.NewTimer(loadingSlice)
+        busy := false
+    slice:
+        for {
+            select {
+            case err := <-done:
+                timeout.Stop()
+                return err
+            case f := <-mainQueue:
+                f()
+                busy = true
+                continue
+            default:
+                if !busy {
+                    break slice
+                }
+            }
+            select {
+            case err := <-done:
+                timeout.Stop()
+                return err
+            case f := <-mainQueue:
+                f()
+            case <-timeout.C:
+                break slice
+            }
+        }
+        timeout.Stop()
+    }
+}
@@ -0,0 +1,38 @@
+// +build gomemock
+
+package gome
+
+import (
+    "testing"
+    "time"
+)
+
+func TestMockLoadingFrames(t *testing.T) {
+    initMock(t, Config{})
+    frames := 0
+    enough := make(chan struct{})
+    uploads := 0
+    start := time.Now()
+    err := RunLoading(func(progress func(float32)) error {
+        for i := 0; i < 5; i++ {
+            Do(func() { uploads++ })
+        }
+        // the frames go on while the loader works without queueing anything
+        <-enough
+        return nil
+    }, func(float32) {
+        if frames++; frames == 50 {
+            close(enough)
+        }
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if uploads != 5 {
+        t.Errorf("%d uploads, want 5", uploads)
+    }
+    // each frame would wait for the whole slice
+    if d := time.Since(start); d >= 50*loadingSlice/2 {
+        t.Errorf("50 frames took %v", d)
+    }
+}
@@ -0,0 +1,124 @@
+package gome
+
+import (
+    "context"
+    "github.com/snorredc/gome/internal/gl"
+    "log/slog"
+    "strings"
+    "unsafe"
+)
+
+// Logger receives the log messages of gome: at the debug level the creation
+// and deletion of GL objects and the contexts tried, at the info level the
+// window and context created and what the context supports, as warnings
+// shader compiler warnings, software renderers and failures to save
+// settings, and as errors crash reports. Messages come with key-value pairs
+// in the style of log/slog; a *slog.Logger is a Logger:
+//
+//     gome.SetLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
+//
+// With Config.DebugContext, the debug output of the driver is logged too,
+// at a level matching the severity of each message.
+type Logger interface {
+    Log(ctx context.Context, level slog.Level, msg string, args ...interface{})
+}
+
+var logger Logger
+
+// SetLogger sets the Logger gome logs to. By default, and after
+// SetLogger(nil), warnings and errors go to the default logger of log/slog,
+// prefixed with "gome: ", and the rest is dropped. It should be called before
+// App.Init to see the messages of Init.
+func SetLogger(l Logger) {
+    logger = l
+}
+
+// logAt logs msg with the key-value pairs args at level.
+func logAt(level slog.Level, msg string, args ...interface{}) {
+    if logger != nil {
+        logger.Log(context.Background(), level, msg, args...)
+        return
+    }
+    if level >= slog.LevelWarn {
+        slog.Default().Log(context.Background(), level, "gome: "+msg, args...)
+    }
+}
+
+// softwareRenderers are substrings of the GL_RENDERER of drivers that render
+// on the CPU, usually because the GPU driver is missing.
+var softwareRenderers = []string{"llvmpipe", "softpipe", "SwiftShader", "GDI Generic", "Microsoft Basic Render Driver"}
+
+// logContext logs what queryGLInfo found. It is called by Init.
+func logContext() {
+    i := glInfo
+    logAt(slog.LevelInfo, "OpenGL context",
+        "version", i.Version, "profile", i.Profile, "glsl", i.GLSLVersion,
+        "vendor", i.Vendor, "renderer", i.Renderer,
+        "max_texture_size", i.MaxTextureSize, "max_samples", i.MaxSamples,
+        "samples", obtainedSamples, "extensions", len(i.Extensions))
+    for _, s := range softwareRenderers {
+        if strings.Contains(i.Renderer, s) {
+            logAt(slog.LevelWarn, "rendering in software; the GPU driver may be missing", "renderer", i.Renderer)
+            break
+        }
+    }
+}
+
+var debugSources = map[uint32]string{
+    gl.DEBUG_SOURCE_API:             "api",
+    gl.DEBUG_SOURCE_WINDOW_SYSTEM:   "window system",
+    gl.DEBUG_SOURCE_SHADER_COMPILER: "shader compiler",
+    gl.DEBUG_SOURCE_THIRD_PARTY:     "third party",
+    gl.DEBUG_SOURCE_APPLICATION:     "application",
+    gl.DEBUG_SOURCE_OTHER:           "other",
+}
+
+var debugTypes = map[uint32]string{
+    gl.DEBUG_TYPE_ERROR:               "error",
+    gl.DEBUG_TYPE_DEPRECATED_BEHAVIOR: "deprecated behaviour",
+    gl.DEBUG_TYPE_UNDEFINED_BEHAVIOR:  "undefined behaviour",
+    gl.DEBUG_TYPE_PORTABILITY:         "portability",
+    gl.DEBUG_TYPE_PERFORMANCE:         "performance",
+    gl.DEBUG_TYPE_MARKER:              "marker",
+    gl.DEBUG_TYPE_PUSH_GROUP:          "push group",
+    gl.DEBUG_TYPE_POP_GROUP:           "pop group",
+    gl.DEBUG_TYPE_OTHER:               "other",
+}
+
+var debugLevels = map[uint32]slog.Level{
+    gl.DEBUG_SEVERITY_HIGH:         slog.LevelError,
+    gl.DEBUG_SEVERITY_MEDIUM:       slog.LevelWarn,
+    gl.DEBUG_SEVERITY_LOW:          slog.LevelInfo,
+    gl.DEBUG_SEVERITY_NOTIFICATION: slog.LevelDebug,
+}
+
+// enableDebugOutput sends the debug output of a debug context to the
+// Logger. It is called by Init if Config.DebugContext is set.
+func enableDebugOutput() {
+    i := glInfo
+    supported := i.Major > 4 || i.Major == 4 && i.Minor >= 3 || HasExtension("GL_KHR_debug")
+    if i.Profile == ESProfile {
+        // the extension has suffixed functions there, which are not loaded
+        supported = i.Major > 3 || i.Major == 3 && i.Minor >= 2
+    }
+    if !supported {
+        logAt(slog.LevelWarn, "no debug output; it needs OpenGL 4.3, GL_KHR_debug or OpenGL ES 3.2", "version", i.Version)
+        return
+    }
+    var flags int32
+    gl.GetIntegerv(gl.CONTEXT_FLAGS, &flags)
+    if flags&gl.CONTEXT_FLAG_DEBUG_BIT == 0 {
+        logAt(slog.LevelWarn, "the driver did not create a debug context; debug output may be limited")
+    }
+    Enable(gl.DEBUG_OUTPUT)
+    // synchronous, so messages come on the main thread right after the call
+    // that caused them
+    Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS)
+    gl.DebugMessageCallback(func(source, typ, id, severity uint32, length int32, message string, userParam unsafe.Pointer) {
+        level, ok := debugLevels[severity]
+        if !ok {
+            level = slog.LevelInfo
+        }
+        logAt(level, "OpenGL: "+message, "source", debugSources[source], "type", debugTypes[typ], "id", id)
+    }, nil)
+}
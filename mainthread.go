@@ -0,0 +1,84 @@
+package gome
+
+import (
+    "bytes"
+    "github.com/snorredc/gome/internal/glfw"
+    "runtime"
+    "strconv"
+)
+
+// Functions queued by other goroutines to run on the main thread.
+var mainQueue = make(chan func(), 64)
+
+// ID of the goroutine that called Init, which is locked to the main thread.
+var mainGoroutine uint64
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// header of its stack trace ("goroutine 1 [running]:").
+func goroutineID() uint64 {
+    var buf [64]byte
+    b := buf[:runtime.Stack(buf[:], false)]
+    b = bytes.TrimPrefix(b, []byte("goroutine "))
+    if i := bytes.IndexByte(b, ' '); i >= 0 {
+        b = b[:i]
+    }
+    id, _ := strconv.ParseUint(string(b), 10, 64)
+    return id
+}
+
+// onMainThread reports whether the caller runs on the goroutine that called
+// Init.
+func onMainThread() bool {
+    return mainGoroutine != 0 && goroutineID() == mainGoroutine
+}
+
+// Do runs f on the main thread and waits for it to return. This lets other
+// goroutines use GLFW and OpenGL, which may only be used from the main
+// thread. Called from the main goroutine, it runs f right away; called from
+// any other goroutine, f runs during the next Tick, so Do blocks until then
+// and must not be used once the main loop has ended.
+func Do(f func()) {
+    if onMainThread() {
+        f()
+        return
+    }
+    done := make(chan struct{})
+    mainQueue <- func() {
+        defer close(done)
+        f()
+    }
+    // wake Tick up if it is waiting for events (see SetIdleBehavior)
+    glfw.PostEmptyEvent()
+    <-done
+}
+
+// runQueued runs the functions queued by Do. It is called by Tick.
+func runQueued() {
+    for {
+        select {
+        case f := <-mainQueue:
+            f()
+        default:
+            return
+        }
+    }
+}
+
+// ClipboardString returns the contents of the system clipboard if it holds
+// text, or an empty string otherwise. It may be called from any goroutine
+// (see Do).
+func ClipboardString() string {
+    var s string
+    Do(func() {
+        s = app.window.GetClipboardString()
+    })
+    return s
+}
+
+// SetClipboardString puts s on the system clipboard. It may be called from
+// any goroutine (see Do).
+func SetClipboardString(s string) {
+    Do(func() {
+        app.window.SetClipboardString(s)
+    })
+}
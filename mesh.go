@@ -0,0 +1,163 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/gl"
+    "unsafe"
+)
+
+// Attribute locations of the vertex attributes of meshes and sprite batches.
+// NewProgram binds vertex shader inputs with the names "position", "normal",
+// "texcoord", "color", "joints" and "weights" to these locations before
+// linking.
+const (
+    PositionAttrib = 0
+    NormalAttrib   = 1
+    TexcoordAttrib = 2
+    ColorAttrib    = 3
+    JointsAttrib   = 4
+    WeightsAttrib  = 5
+)
+
+var standardAttribs = []struct {
+    location uint32
+    name     string
+}{
+    {PositionAttrib, "position"},
+    {NormalAttrib, "normal"},
+    {TexcoordAttrib, "texcoord"},
+    {ColorAttrib, "color"},
+    {JointsAttrib, "joints"},
+    {WeightsAttrib, "weights"},
+}
+
+// Vertex is a vertex of a Mesh.
+type Vertex struct {
+    Position [3]float32
+    Normal   [3]float32
+    Texcoord [2]float32
+}
+
+const vertexSize = 8 * 4
+
+// VertexSkin holds the skinning data of a vertex of a skinned mesh: the
+// indices of the (up to) four joints influencing the vertex and their weights.
+type VertexSkin struct {
+    Joints  [4]float32
+    Weights [4]float32
+}
+
+const vertexSkinSize = 8 * 4
+
+// Material describes the surface of a part of a mesh.
+type Material struct {
+    Name       string
+    Ambient    [3]float32
+    Diffuse    [4]float32 // the alpha is the opacity of the material
+    Specular   [3]float32
+    Shininess  float32
+    DiffuseMap *Texture // may be nil
+}
+
+// Submesh is a range of triangles of a mesh sharing a material.
+type Submesh struct {
+    Material *Material
+    First    int // index of the first index in the index buffer
+    Count    int // number of indices
+}
+
+// Mesh is an indexed triangle mesh stored in GL buffers. Its vertex array
+// provides the attributes at PositionAttrib, NormalAttrib and TexcoordAttrib,
+// and for skinned meshes also at JointsAttrib and WeightsAttrib.
+type Mesh struct {
+    VertexArray *VertexArray
+    Vertices    *Buffer
+    Skin        *Buffer // nil unless the mesh is skinned
+    Indices     *Buffer
+    Submeshes   []Submesh
+    Textures    []*Texture // textures owned by the mesh, deleted with it
+}
+
+// NewMesh uploads vertices and indices into a new mesh. If submeshes is
+// empty, the mesh consists of a single submesh without a material. Without
+// vertices or indices, as for a model of only lines or points, the mesh is
+// empty and draws nothing.
+func NewMesh(vertices []Vertex, indices []uint32, submeshes []Submesh) *Mesh {
+    if len(submeshes) == 0 {
+        submeshes = []Submesh{{nil, 0, len(indices)}}
+    }
+    m := &Mesh{
+        VertexArray: NewVertexArray(),
+        Vertices:    NewBuffer(),
+        Indices:     NewBuffer(),
+        Submeshes:   submeshes,
+    }
+    BindVertexArray(m.VertexArray)
+    BindBuffer(gl.ARRAY_BUFFER, m.Vertices)
+    bufferSlice(gl.ARRAY_BUFFER, len(vertices)*vertexSize, vertices)
+    BindBuffer(gl.ELEMENT_ARRAY_BUFFER, m.Indices)
+    bufferSlice(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, indices)
+
+    attribs := []struct {
+        location uint32
+        size     int32
+        offset   uintptr
+    }{{PositionAttrib, 3, 0}, {NormalAttrib, 3, 12}, {TexcoordAttrib, 2, 24}}
+    for _, a := range attribs {
+        gl.EnableVertexAttribArray(a.location)
+        gl.VertexAttribPointerWithOffset(a.location, a.size, gl.FLOAT, false, vertexSize, a.offset)
+    }
+    BindVertexArray(nil)
+    return m
+}
+
+// NewSkinnedMesh is like NewMesh but also uploads the skinning data of each
+// vertex; skin must have the same length as vertices.
+func NewSkinnedMesh(vertices []Vertex, skin []VertexSkin, indices []uint32, submeshes []Submesh) *Mesh {
+    m := NewMesh(vertices, indices, submeshes)
+    m.Skin = NewBuffer()
+    BindVertexArray(m.VertexArray)
+    BindBuffer(gl.ARRAY_BUFFER, m.Skin)
+    bufferSlice(gl.ARRAY_BUFFER, len(skin)*vertexSkinSize, skin)
+    gl.EnableVertexAttribArray(JointsAttrib)
+    gl.VertexAttribPointerWithOffset(JointsAttrib, 4, gl.FLOAT, false, vertexSkinSize, 0)
+    gl.EnableVertexAttribArray(WeightsAttrib)
+    gl.VertexAttribPointerWithOffset(WeightsAttrib, 4, gl.FLOAT, false, vertexSkinSize, 16)
+    BindVertexArray(nil)
+    return m
+}
+
+// bufferSlice uploads data, a slice of size bytes, into the buffer bound to
+// target. gl.Ptr panics on empty slices, so those leave the buffer empty.
+func bufferSlice(target uint32, size int, data interface{}) {
+    var ptr unsafe.Pointer
+    if size > 0 {
+        ptr = gl.Ptr(data)
+    }
+    gl.BufferData(target, size, ptr, gl.STATIC_DRAW)
+}
+
+// Draw draws all submeshes with the current program. If material is not nil,
+// it is called before each submesh is drawn, so it can set up the uniforms
+// and textures of the submesh's material.
+func (m *Mesh) Draw(material func(*Material)) {
+    BindVertexArray(m.VertexArray)
+    for _, s := range m.Submeshes {
+        if material != nil {
+            material(s.Material)
+        }
+        gl.DrawElementsWithOffset(gl.TRIANGLES, int32(s.Count), gl.UNSIGNED_INT, uintptr(s.First*4))
+    }
+}
+
+// Delete deletes the buffers of the mesh and the textures it owns.
+func (m *Mesh) Delete() {
+    m.VertexArray.Delete()
+    m.Vertices.Delete()
+    if m.Skin != nil {
+        m.Skin.Delete()
+    }
+    m.Indices.Delete()
+    for _, t := range m.Textures {
+        t.Delete()
+    }
+}
@@ -0,0 +1,38 @@
+// +build gomemock
+
+package gome
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestMockEmptyMesh(t *testing.T) {
+    initMock(t, Config{})
+    m := NewSkinnedMesh(nil, nil, nil, nil)
+    defer m.Delete()
+    m.Draw(nil)
+    for _, c := range MockCommands("gl.BufferData") {
+        if c.Args[1] != 0 {
+            t.Errorf("%s for an empty mesh", c)
+        }
+    }
+}
+
+func TestMockOBJWithoutFaces(t *testing.T) {
+    initMock(t, Config{})
+    const lines = `v 0 0 0
+v 1 0 0
+v 1 1 0
+l 1 2 3
+p 1
+`
+    m, err := ReadOBJ(strings.NewReader(lines))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer m.Delete()
+    if len(m.Submeshes) != 1 || m.Submeshes[0].Count != 0 {
+        t.Errorf("submeshes %v, want one of 0 indices", m.Submeshes)
+    }
+}
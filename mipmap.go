@@ -0,0 +1,168 @@
+package gome
+
+import (
+    "image"
+    "math"
+)
+
+// The mipmaps of glGenerateMipmap are usually a box filter over the stored
+// values, which darkens sRGB images and lets transparent pixels bleed their
+// colour into the edges of sprites. The functions below average in linear
+// light and weight the colours by alpha instead.
+
+// MipmapChain returns the mipmap levels below img, each half the size of the
+// one before, rounded down, ending at 1x1. If srgb is set, the pixels are
+// averaged in linear light, as they should be for sRGB images.
+func MipmapChain(img image.Image, srgb bool) []*image.NRGBA {
+    src := nrgbaPixels(img)
+    w, h := src.Rect.Dx(), src.Rect.Dy()
+    f := toLinear(src, srgb)
+    var levels []*image.NRGBA
+    for w > 1 || h > 1 {
+        nw, nh := maxInt(w/2, 1), maxInt(h/2, 1)
+        f = boxDownsample(f, w, h, nw, nh)
+        w, h = nw, nh
+        levels = append(levels, fromLinear(f, w, h, srgb))
+    }
+    return levels
+}
+
+// ResizeImage returns img scaled to width by height pixels. Shrinking
+// averages all the pixels covered, like sampling a mipmap, so thin lines and
+// text stay visible instead of aliasing, and enlarging interpolates
+// bilinearly. If srgb is set, the pixels are averaged in linear light.
+func ResizeImage(img image.Image, width, height int, srgb bool) *image.NRGBA {
+    src := nrgbaPixels(img)
+    w, h := src.Rect.Dx(), src.Rect.Dy()
+    if w == width && h == height {
+        out := image.NewNRGBA(image.Rect(0, 0, w, h))
+        copy(out.Pix, src.Pix)
+        return out
+    }
+    f := toLinear(src, srgb)
+    if width <= w && height <= h {
+        f = boxDownsample(f, w, h, width, height)
+    } else {
+        f = bilinear(f, w, h, width, height)
+    }
+    return fromLinear(f, width, height, srgb)
+}
+
+var srgbToLinear = func() (t [256]float32) {
+    for i := range t {
+        c := float64(i) / 255
+        if c <= 0.04045 {
+            t[i] = float32(c / 12.92)
+        } else {
+            t[i] = float32(math.Pow((c+0.055)/1.055, 2.4))
+        }
+    }
+    return t
+}()
+
+func linearToSRGB(c float32) float32 {
+    if c <= 0.0031308 {
+        return c * 12.92
+    }
+    return float32(1.055*math.Pow(float64(c), 1/2.4) - 0.055)
+}
+
+// toLinear returns the pixels of img as premultiplied linear RGBA floats.
+func toLinear(img *image.NRGBA, srgb bool) []float32 {
+    f := make([]float32, len(img.Pix))
+    for i := 0; i < len(img.Pix); i += 4 {
+        a := float32(img.Pix[i+3]) / 255
+        for c := 0; c < 3; c++ {
+            v := float32(img.Pix[i+c]) / 255
+            if srgb {
+                v = srgbToLinear[img.Pix[i+c]]
+            }
+            f[i+c] = v * a
+        }
+        f[i+3] = a
+    }
+    return f
+}
+
+// fromLinear converts premultiplied linear RGBA floats back to an image.
+func fromLinear(f []float32, w, h int, srgb bool) *image.NRGBA {
+    img := image.NewNRGBA(image.Rect(0, 0, w, h))
+    for i := 0; i < len(f); i += 4 {
+        a := f[i+3]
+        if a <= 0 {
+            continue
+        }
+        for c := 0; c < 3; c++ {
+            v := clamp01(f[i+c] / a)
+            if srgb {
+                v = linearToSRGB(v)
+            }
+            img.Pix[i+c] = uint8(v*255 + 0.5)
+        }
+        img.Pix[i+3] = uint8(clamp01(a)*255 + 0.5)
+    }
+    return img
+}
+
+// boxDownsample averages the pixels of f, w by h, into nw by nh pixels, each
+// covering a whole number of source pixels.
+func boxDownsample(f []float32, w, h, nw, nh int) []float32 {
+    out := make([]float32, 4*nw*nh)
+    for y := 0; y < nh; y++ {
+        y0, y1 := y*h/nh, maxInt((y+1)*h/nh, y*h/nh+1)
+        for x := 0; x < nw; x++ {
+            x0, x1 := x*w/nw, maxInt((x+1)*w/nw, x*w/nw+1)
+            var sum [4]float32
+            for sy := y0; sy < y1; sy++ {
+                for sx := x0; sx < x1; sx++ {
+                    p := f[4*(sy*w+sx):]
+                    sum[0] += p[0]
+                    sum[1] += p[1]
+                    sum[2] += p[2]
+                    sum[3] += p[3]
+                }
+            }
+            n := float32((x1 - x0) * (y1 - y0))
+            o := out[4*(y*nw+x):]
+            for c := range sum {
+                o[c] = sum[c] / n
+            }
+        }
+    }
+    return out
+}
+
+// bilinear samples f, w by h, at nw by nh pixel centres.
+func bilinear(f []float32, w, h, nw, nh int) []float32 {
+    out := make([]float32, 4*nw*nh)
+    for y := 0; y < nh; y++ {
+        sy := (float32(y)+0.5)*float32(h)/float32(nh) - 0.5
+        y0, ty := splitCoord(sy, h)
+        y1 := minInt(y0+1, h-1)
+        for x := 0; x < nw; x++ {
+            sx := (float32(x)+0.5)*float32(w)/float32(nw) - 0.5
+            x0, tx := splitCoord(sx, w)
+            x1 := minInt(x0+1, w-1)
+            o := out[4*(y*nw+x):]
+            for c := 0; c < 4; c++ {
+                top := f[4*(y0*w+x0)+c]*(1-tx) + f[4*(y0*w+x1)+c]*tx
+                bottom := f[4*(y1*w+x0)+c]*(1-tx) + f[4*(y1*w+x1)+c]*tx
+                o[c] = top*(1-ty) + bottom*ty
+            }
+        }
+    }
+    return out
+}
+
+// splitCoord splits a sample coordinate into the pixel before it, clamped
+// to 0..n-1, and the fraction of the way to the next pixel.
+func splitCoord(s float32, n int) (int, float32) {
+    if s <= 0 {
+        return 0, 0
+    }
+    i := int(s)
+    if i >= n-1 {
+        return n - 1, 0
+    }
+    return i, s - float32(i)
+}
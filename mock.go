@@ -0,0 +1,123 @@
+// +build gomemock
+
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/glfw"
+    "github.com/snorredc/gome/internal/mock"
+)
+
+// MockCommand is an OpenGL or GLFW call recorded by the mock backend, which
+// replaces the GPU and the window system when gome is built with the
+// gomemock tag. Name is the function qualified by its package, e.g.
+// "gl.DrawArrays" or "glfw.Window.SwapBuffers", and Args are its arguments,
+// with the strings and object names that pointers point to in place of the
+// pointers: shader sources, uniform names, the names handed out by
+// glGenTextures and the like. String formats it as a Go call.
+//
+// The mock accepts everything: shaders compile and link, framebuffers are
+// complete, uniforms all have locations and glGetError reports nothing.
+// Nothing is drawn, so ReadScreen returns the colour the screen was last
+// cleared to, or black. Buffers keep what is written to them, so they can be
+// mapped and read back.
+type MockCommand = mock.Command
+
+// MockCommands returns the commands recorded since the start or the last
+// ResetMockCommands, in order, or only those with one of names if given:
+//
+//     gome.ResetMockCommands()
+//     drawLevel()
+//     if n := len(gome.MockCommands("gl.DrawArrays", "gl.DrawElementsWithOffset")); n > 10 {
+//         t.Errorf("%d draw calls, want at most 10", n)
+//     }
+func MockCommands(names ...string) []MockCommand {
+    all := mock.Commands()
+    if len(names) == 0 {
+        return all
+    }
+    var matching []MockCommand
+    for _, c := range all {
+        for _, name := range names {
+            if c.Name == name {
+                matching = append(matching, c)
+                break
+            }
+        }
+    }
+    return matching
+}
+
+// ResetMockCommands clears the log of recorded commands.
+func ResetMockCommands() {
+    mock.Reset()
+}
+
+// The functions below simulate input to the main window of the mock
+// backend. As with real input, the events are delivered by the next Tick, so
+// that KeyPressed and the like report them in the frame after it.
+
+// MockKey simulates a key being pressed, repeated or released.
+func MockKey(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) {
+    app.window.SendKey(key, 0, action, mods)
+}
+
+// MockChar simulates a typed character.
+func MockChar(char rune) {
+    app.window.SendChar(char)
+}
+
+// MockMouseButton simulates a mouse button being pressed or released.
+func MockMouseButton(button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+    app.window.SendMouseButton(button, action, mods)
+}
+
+// MockCursor simulates the cursor moving to x, y in screen coordinates of the
+// window, before any virtual resolution is applied.
+func MockCursor(x, y float64) {
+    app.window.SendCursorPos(x, y)
+}
+
+// MockScroll simulates scrolling.
+func MockScroll(dx, dy float64) {
+    app.window.SendScroll(dx, dy)
+}
+
+// MockDrop simulates files being dropped onto the window.
+func MockDrop(paths ...string) {
+    app.window.SendDrop(paths)
+}
+
+// MockResize simulates the user resizing the window.
+func MockResize(width, height int) {
+    app.window.SendSize(width, height)
+}
+
+// MockMove simulates the user moving the window to x, y.
+func MockMove(x, y int) {
+    app.window.SendPos(x, y)
+}
+
+// MockFocus simulates the window gaining or losing the keyboard focus.
+func MockFocus(focused bool) {
+    app.window.SendFocus(focused)
+}
+
+// MockMinimize simulates the window being minimized or restored. While the
+// window is minimized with PauseWhenMinimized set, Tick waits for it to be
+// restored as with a real window, so another goroutine has to restore it,
+// or close it, through Do:
+//
+//     go func() {
+//         time.Sleep(time.Second)
+//         gome.Do(func() { gome.MockMinimize(false) })
+//     }()
+func MockMinimize(minimized bool) {
+    app.window.SendIconify(minimized)
+}
+
+// MockClose simulates the user closing the window, which OnCloseRequest
+// handlers can cancel as usual. Like a real close, it is seen by the Tick
+// after the one that delivers it, which returns false.
+func MockClose() {
+    app.window.SendClose()
+}
@@ -0,0 +1,258 @@
+// +build gomemock
+
+package gome
+
+import (
+    "encoding/json"
+    "github.com/snorredc/gome/internal/glfw"
+    "image"
+    "image/color"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+// initMock initialises an App on the mock backend for the duration of the
+// test, with the command log cleared.
+func initMock(t *testing.T, c Config) *App {
+    t.Helper()
+    a := NewApp(c)
+    if err := a.Init(); err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(a.Terminate)
+    ResetMockCommands()
+    return a
+}
+
+func TestMockInit(t *testing.T) {
+    ResetMockCommands()
+    a := NewApp(Config{Width: 320, Height: 240, Title: "test"})
+    if err := a.Init(); err != nil {
+        t.Fatal(err)
+    }
+    defer a.Terminate()
+    created := MockCommands("glfw.CreateWindow")
+    if len(created) != 1 {
+        t.Fatalf("%d windows created, want 1", len(created))
+    }
+    if got, want := created[0].String(), `glfw.CreateWindow(320, 240, "test")`; got != want {
+        t.Errorf("created %s, want %s", got, want)
+    }
+    if w, h := a.window.GetSize(); w != 320 || h != 240 {
+        t.Errorf("window size %dx%d, want 320x240", w, h)
+    }
+    if i := GLInfo(); i.Major < 3 {
+        t.Errorf("context version %s", i.Version)
+    }
+}
+
+func TestMockTick(t *testing.T) {
+    a := initMock(t, Config{})
+    SetAutoClear(true)
+    defer SetAutoClear(false)
+    for i := 0; i < 3; i++ {
+        if !a.Tick() {
+            t.Fatal("Tick returned false")
+        }
+    }
+    if n := len(MockCommands("glfw.Window.SwapBuffers")); n != 3 {
+        t.Errorf("%d buffer swaps, want 3", n)
+    }
+    if n := len(MockCommands("glfw.PollEvents")); n != 3 {
+        t.Errorf("%d polls, want 3", n)
+    }
+    if n := len(MockCommands("gl.Clear")); n != 3 {
+        t.Errorf("%d clears, want 3", n)
+    }
+    if FrameCount() == 0 {
+        t.Error("no frames counted")
+    }
+    if err := a.GetError(); err != nil {
+        t.Error(err)
+    }
+}
+
+func TestMockSpriteBatch(t *testing.T) {
+    initMock(t, Config{Width: 320, Height: 240})
+    tex := NewTextureFromImage(image.NewNRGBA(image.Rect(0, 0, 4, 4)), nil)
+    defer tex.Delete()
+    b, err := NewSpriteBatch()
+    if err != nil {
+        t.Fatal(err)
+    }
+    ResetMockCommands()
+    b.Begin(320, 240)
+    b.Draw(tex, Rect{0, 0, 4, 4}, Rect{10, 10, 40, 40}, color.White)
+    b.Draw(tex, Rect{0, 0, 4, 4}, Rect{60, 10, 40, 40}, color.White)
+    b.End()
+    draws := MockCommands("gl.DrawArrays", "gl.DrawElementsWithOffset")
+    if len(draws) != 1 {
+        t.Errorf("%d draw calls for two sprites of one texture, want 1: %v", len(draws), draws)
+    }
+    binds := MockCommands("gl.BindTexture")
+    if len(binds) == 0 || binds[len(binds)-1].Args[1] != tex.ID {
+        t.Errorf("texture %d not bound: %v", tex.ID, binds)
+    }
+}
+
+func TestMockInput(t *testing.T) {
+    a := initMock(t, Config{})
+    BeginTextInput()
+    defer EndTextInput()
+    MockKey(glfw.KeyA, glfw.Press, 0)
+    MockCursor(100, 50)
+    MockChar('é')
+    if KeyDown(glfw.KeyA) {
+        t.Error("key down before Tick")
+    }
+    a.Tick()
+    if !KeyPressed(glfw.KeyA) || !KeyDown(glfw.KeyA) {
+        t.Error("key not pressed after Tick")
+    }
+    if x, y := CursorPosition(); x != 100 || y != 50 {
+        t.Errorf("cursor at %v, %v, want 100, 50", x, y)
+    }
+    if ev := TextEvents(); len(ev) != 1 || ev[0].Kind != TextRune || ev[0].Rune != 'é' {
+        t.Errorf("text events %v, want the rune é", ev)
+    }
+    MockKey(glfw.KeyA, glfw.Release, 0)
+    a.Tick()
+    if KeyDown(glfw.KeyA) {
+        t.Error("key still down after release")
+    }
+}
+
+func TestMockTextBuffer(t *testing.T) {
+    a := initMock(t, Config{})
+    BeginTextInput()
+    defer EndTextInput()
+    b := &TextBuffer{}
+    for _, r := range "naïve café" {
+        MockChar(r)
+    }
+    MockKey(glfw.KeyLeft, glfw.Press, glfw.ModShift)
+    MockKey(glfw.KeyLeft, glfw.Repeat, glfw.ModShift)
+    MockKey(glfw.KeyLeft, glfw.Repeat, glfw.ModShift)
+    MockKey(glfw.KeyLeft, glfw.Repeat, glfw.ModShift)
+    MockKey(glfw.KeyX, glfw.Press, shortcutMod)
+    a.Tick()
+    b.Update()
+    if b.String() != "naïve " || ClipboardString() != "café" {
+        t.Errorf("cut %q from %q, want café from naïve", ClipboardString(), b.String())
+    }
+
+    SetClipboardString("déjà\tvu\n")
+    MockKey(glfw.KeyHome, glfw.Press, 0)
+    MockKey(glfw.KeyV, glfw.Press, shortcutMod)
+    a.Tick()
+    b.Update()
+    if b.String() != "déjà vu naïve " || b.Cursor() != 8 {
+        t.Errorf("pasted into %q with the cursor at %d, want déjà vu naïve at 8", b.String(), b.Cursor())
+    }
+}
+
+func TestMockMinimized(t *testing.T) {
+    a := initMock(t, Config{})
+    SetIdleBehavior(PauseWhenMinimized)
+    defer SetIdleBehavior(0)
+    MockMinimize(true)
+    a.Tick()
+    if !Minimized() {
+        t.Fatal("not minimized after Tick")
+    }
+
+    ResetMockCommands()
+    go func() {
+        time.Sleep(50 * time.Millisecond)
+        Do(func() { MockMinimize(false) })
+    }()
+    // waits for the restore
+    a.Tick()
+    if Minimized() {
+        t.Error("still minimized after Tick")
+    }
+    if n := len(MockCommands("glfw.PollEvents")); n > 3 {
+        t.Errorf("polled %d times while waiting, want up to 3", n)
+    }
+}
+
+func TestMockClose(t *testing.T) {
+    a := initMock(t, Config{})
+    MockClose()
+    if !a.Tick() {
+        t.Fatal("closed by the Tick delivering the close")
+    }
+    if a.Tick() {
+        t.Error("still running after close")
+    }
+}
+
+func TestMockEventsFromGoroutine(t *testing.T) {
+    a := initMock(t, Config{})
+    // Events is first called by another goroutine while Tick sends
+    got := make(chan Event, 1)
+    go func() {
+        e := <-Events()
+        got <- e
+    }()
+    for i := 0; i < 100; i++ {
+        select {
+        case e := <-got:
+            if _, ok := e.(KeyEvent); !ok {
+                t.Errorf("event %#v, want a KeyEvent", e)
+            }
+            return
+        default:
+        }
+        MockKey(glfw.KeyA, glfw.Press, 0)
+        a.Tick()
+        time.Sleep(time.Millisecond)
+    }
+    t.Fatal("no event received")
+}
+
+func TestMockSaveMaximized(t *testing.T) {
+    a := initMock(t, Config{Width: 320, Height: 240})
+    MockMove(100, 50)
+    MockResize(400, 300)
+    a.Tick()
+    a.window.Maximize()
+    a.Tick()
+    if w, h := a.window.GetSize(); w != 1920 || h != 1080 {
+        t.Fatalf("maximized to %dx%d", w, h)
+    }
+    path := filepath.Join(t.TempDir(), "window.json")
+    if err := SaveWindowState(path); err != nil {
+        t.Fatal(err)
+    }
+    data, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var s savedWindow
+    if err := json.Unmarshal(data, &s); err != nil {
+        t.Fatal(err)
+    }
+    want := savedWindow{100, 50, 400, 300, "Mock", true}
+    if s != want {
+        t.Errorf("saved %+v, want %+v", s, want)
+    }
+}
+
+func TestMockStorageBufferRead(t *testing.T) {
+    initMock(t, Config{})
+    b, err := NewStorageBufferData([]float32{1, 2, 3, 4})
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer b.Delete()
+    dst := make([]float32, 2)
+    if err := b.Read(8, dst); err != nil || dst[0] != 3 || dst[1] != 4 {
+        t.Errorf("read %v, %v", dst, err)
+    }
+    if err := b.Read(12, dst); err != ErrStorageMap {
+        t.Errorf("read past the end: error %v, want ErrStorageMap", err)
+    }
+}
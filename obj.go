@@ -0,0 +1,297 @@
+package gome
+
+import (
+    "bufio"
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+    "io"
+    "io/fs"
+    "path"
+    "strconv"
+    "strings"
+)
+
+// LoadOBJ loads the Wavefront OBJ model at path in the asset file system (see
+// SetAssetFS) into a mesh. Polygons are triangulated and materials from the
+// referenced MTL files, including their diffuse textures, are loaded into the
+// submeshes. Objects and groups are merged into a single mesh.
+func LoadOBJ(path string) (*Mesh, error) {
+    return LoadOBJFS(assetFS, path)
+}
+
+// LoadOBJFS is like LoadOBJ but reads the model and its materials from fsys.
+func LoadOBJFS(fsys fs.FS, name string) (*Mesh, error) {
+    f, err := openFile(fsys, name)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    dir := path.Dir(name)
+    textures := map[string]*Texture{}
+    loadMTL := func(mtl string) (map[string]*Material, error) {
+        r, err := openFile(fsys, path.Join(dir, mtl))
+        if err != nil {
+            return nil, err
+        }
+        defer r.Close()
+        return parseMTL(r, func(file string) (*Texture, error) {
+            file = path.Join(dir, file)
+            if t, ok := textures[file]; ok {
+                return t, nil
+            }
+            t, err := LoadTextureFS(fsys, file, &TextureOptions{
+                Wrap:    gl.REPEAT,
+                Mipmaps: true,
+            })
+            if err != nil {
+                return nil, err
+            }
+            textures[file] = t
+            return t, nil
+        })
+    }
+    m, err := readOBJ(f, loadMTL)
+    if err != nil {
+        for _, t := range textures {
+            t.Delete()
+        }
+        return nil, fmt.Errorf("%s: %v", name, err)
+    }
+    for _, t := range textures {
+        m.Textures = append(m.Textures, t)
+    }
+    return m, nil
+}
+
+// ReadOBJ reads a Wavefront OBJ model from r into a mesh. Since there is no
+// way to locate referenced MTL files, the materials of the submeshes only have
+// their names set.
+func ReadOBJ(r io.Reader) (*Mesh, error) {
+    return readOBJ(r, nil)
+}
+
+func readOBJ(r io.Reader, loadMTL func(string) (map[string]*Material, error)) (*Mesh, error) {
+    data, err := parseOBJ(r, loadMTL)
+    if err != nil {
+        return nil, err
+    }
+    return NewMesh(data.vertices, data.indices, data.submeshes), nil
+}
+
+type objData struct {
+    vertices  []Vertex
+    indices   []uint32
+    submeshes []Submesh
+}
+
+// parseOBJ parses an OBJ file. loadMTL is called for every material library
+// and may be nil to skip them.
+func parseOBJ(r io.Reader, loadMTL func(string) (map[string]*Material, error)) (*objData, error) {
+    var (
+        positions [][3]float32
+        normals   [][3]float32
+        texcoords [][2]float32
+        materials = map[string]*Material{}
+        data      = &objData{}
+        cache     = map[[3]int]uint32{}
+        current   *Material
+        start     int
+    )
+    endSubmesh := func() {
+        if len(data.indices) > start {
+            data.submeshes = append(data.submeshes,
+                Submesh{current, start, len(data.indices) - start})
+        }
+        start = len(data.indices)
+    }
+
+    // resolves a 1-based or negative relative index
+    index := func(s string, n int) (int, error) {
+        i, err := strconv.Atoi(s)
+        if err != nil {
+            return 0, err
+        }
+        if i < 0 {
+            i += n
+        } else {
+            i--
+        }
+        if i < 0 || i >= n {
+            return 0, fmt.Errorf("index %s out of range", s)
+        }
+        return i, nil
+    }
+    vertex := func(s string) (uint32, error) {
+        key := [3]int{-1, -1, -1}
+        parts := strings.Split(s, "/")
+        counts := []int{len(positions), len(texcoords), len(normals)}
+        for i, p := range parts {
+            if i > 2 || p == "" {
+                continue
+            }
+            j, err := index(p, counts[i])
+            if err != nil {
+                return 0, err
+            }
+            key[i] = j
+        }
+        if key[0] < 0 {
+            return 0, fmt.Errorf("vertex %q has no position", s)
+        }
+        if i, ok := cache[key]; ok {
+            return i, nil
+        }
+        v := Vertex{Position: positions[key[0]]}
+        if key[1] >= 0 {
+            // OBJ texture coordinates have their origin at the bottom
+            v.Texcoord = [2]float32{texcoords[key[1]][0], 1 - texcoords[key[1]][1]}
+        }
+        if key[2] >= 0 {
+            v.Normal = normals[key[2]]
+        }
+        i := uint32(len(data.vertices))
+        data.vertices = append(data.vertices, v)
+        cache[key] = i
+        return i, nil
+    }
+
+    scanner := bufio.NewScanner(r)
+    line := 0
+    for scanner.Scan() {
+        line++
+        fields := strings.Fields(scanner.Text())
+        if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+            continue
+        }
+        var err error
+        switch fields[0] {
+        case "v":
+            var p [3]float32
+            err = parseFloats(fields[1:], p[:])
+            positions = append(positions, p)
+        case "vn":
+            var n [3]float32
+            err = parseFloats(fields[1:], n[:])
+            normals = append(normals, n)
+        case "vt":
+            var t [2]float32
+            err = parseFloats(fields[1:], t[:])
+            texcoords = append(texcoords, t)
+        case "f":
+            if len(fields) < 4 {
+                err = fmt.Errorf("face with less than 3 vertices")
+                break
+            }
+            face := make([]uint32, len(fields)-1)
+            for i, f := range fields[1:] {
+                if face[i], err = vertex(f); err != nil {
+                    break
+                }
+            }
+            // triangulate as a fan
+            for i := 2; i < len(face) && err == nil; i++ {
+                data.indices = append(data.indices, face[0], face[i-1], face[i])
+            }
+        case "usemtl":
+            endSubmesh()
+            name := strings.Join(fields[1:], " ")
+            m, ok := materials[name]
+            if !ok {
+                m = &Material{Name: name, Diffuse: [4]float32{1, 1, 1, 1}}
+                materials[name] = m
+            }
+            current = m
+        case "mtllib":
+            if loadMTL == nil {
+                break
+            }
+            for _, lib := range fields[1:] {
+                var loaded map[string]*Material
+                if loaded, err = loadMTL(lib); err != nil {
+                    break
+                }
+                for name, m := range loaded {
+                    materials[name] = m
+                }
+            }
+        }
+        if err != nil {
+            return nil, fmt.Errorf("line %d: %v", line, err)
+        }
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+    endSubmesh()
+    return data, nil
+}
+
+// parseMTL parses an MTL file. loadTexture is called for texture maps.
+func parseMTL(r io.Reader, loadTexture func(string) (*Texture, error)) (map[string]*Material, error) {
+    materials := map[string]*Material{}
+    var m *Material
+    scanner := bufio.NewScanner(r)
+    line := 0
+    for scanner.Scan() {
+        line++
+        fields := strings.Fields(scanner.Text())
+        if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+            continue
+        }
+        if fields[0] == "newmtl" {
+            name := strings.Join(fields[1:], " ")
+            m = &Material{Name: name, Diffuse: [4]float32{1, 1, 1, 1}}
+            materials[name] = m
+            continue
+        }
+        if m == nil {
+            continue
+        }
+        var err error
+        switch fields[0] {
+        case "Ka":
+            err = parseFloats(fields[1:], m.Ambient[:])
+        case "Kd":
+            err = parseFloats(fields[1:], m.Diffuse[:3])
+        case "Ks":
+            err = parseFloats(fields[1:], m.Specular[:])
+        case "Ns":
+            ns := [1]float32{m.Shininess}
+            err = parseFloats(fields[1:], ns[:])
+            m.Shininess = ns[0]
+        case "d":
+            err = parseFloats(fields[1:], m.Diffuse[3:])
+        case "Tr":
+            var tr [1]float32
+            err = parseFloats(fields[1:], tr[:])
+            m.Diffuse[3] = 1 - tr[0]
+        case "map_Kd":
+            if len(fields) < 2 || loadTexture == nil {
+                break
+            }
+            // options such as -s precede the file name, which comes last
+            m.DiffuseMap, err = loadTexture(fields[len(fields)-1])
+        }
+        if err != nil {
+            return nil, fmt.Errorf("line %d: %v", line, err)
+        }
+    }
+    return materials, scanner.Err()
+}
+
+// parseFloats parses fields into dst. Missing trailing fields leave the
+// corresponding values in dst untouched.
+func parseFloats(fields []string, dst []float32) error {
+    for i := range dst {
+        if i >= len(fields) {
+            break
+        }
+        f, err := strconv.ParseFloat(fields[i], 32)
+        if err != nil {
+            return err
+        }
+        dst[i] = float32(f)
+    }
+    return nil
+}
@@ -0,0 +1,167 @@
+package gome
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+const objQuad = `# a unit quad
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vt 0 0
+vt 1 0
+vt 1 1
+vt 0 1
+vn 0 0 1
+`
+
+func TestParseOBJFaces(t *testing.T) {
+    tests := []struct {
+        name     string
+        faces    string
+        indices  []uint32
+        vertices int
+    }{
+        {"triangle", "f 1 2 3", []uint32{0, 1, 2}, 3},
+        {"negative", "f -4 -3 -2", []uint32{0, 1, 2}, 3},
+        {"quad fan", "f 1 2 3 4", []uint32{0, 1, 2, 0, 2, 3}, 4},
+        {"pentagon fan", "f 1 2 3 4 1/1", []uint32{0, 1, 2, 0, 2, 3, 0, 3, 4}, 5},
+        {"shared vertices", "f 1 2 3\nf 1 3 4", []uint32{0, 1, 2, 0, 2, 3}, 4},
+        {"normals only", "f 1//1 2//1 3//1", []uint32{0, 1, 2}, 3},
+        {"all attributes", "f 1/1/1 2/2/1 3/3/1 4/4/1", []uint32{0, 1, 2, 0, 2, 3}, 4},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            data, err := parseOBJ(strings.NewReader(objQuad+tt.faces), nil)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(data.indices, tt.indices) {
+                t.Errorf("indices %v, want %v", data.indices, tt.indices)
+            }
+            if len(data.vertices) != tt.vertices {
+                t.Errorf("%d vertices, want %d", len(data.vertices), tt.vertices)
+            }
+        })
+    }
+}
+
+func TestParseOBJAttributes(t *testing.T) {
+    data, err := parseOBJ(strings.NewReader(objQuad+"f 1//1 2/2/1 -1/-2\n"), nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := []Vertex{
+        {Position: [3]float32{0, 0, 0}, Normal: [3]float32{0, 0, 1}},
+        // the texture coordinates are flipped vertically
+        {Position: [3]float32{1, 0, 0}, Normal: [3]float32{0, 0, 1}, Texcoord: [2]float32{1, 1}},
+        {Position: [3]float32{0, 1, 0}, Texcoord: [2]float32{1, 0}},
+    }
+    if !reflect.DeepEqual(data.vertices, want) {
+        t.Errorf("vertices\n%v, want\n%v", data.vertices, want)
+    }
+}
+
+func TestParseOBJMaterials(t *testing.T) {
+    obj := objQuad + `mtllib quad.mtl
+usemtl red
+f 1 2 3
+usemtl unknown
+f 1 3 4
+usemtl red
+f 4 3 2
+`
+    var libs []string
+    red := &Material{Name: "red"}
+    data, err := parseOBJ(strings.NewReader(obj), func(lib string) (map[string]*Material, error) {
+        libs = append(libs, lib)
+        return map[string]*Material{"red": red}, nil
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !reflect.DeepEqual(libs, []string{"quad.mtl"}) {
+        t.Errorf("loaded %v, want quad.mtl", libs)
+    }
+    if len(data.submeshes) != 3 {
+        t.Fatalf("%d submeshes, want 3", len(data.submeshes))
+    }
+    for i, want := range []Submesh{{red, 0, 3}, {nil, 3, 3}, {red, 6, 3}} {
+        s := data.submeshes[i]
+        if s.First != want.First || s.Count != want.Count {
+            t.Errorf("submesh %d covers %d+%d, want %d+%d", i, s.First, s.Count, want.First, want.Count)
+        }
+        if want.Material != nil && s.Material != want.Material {
+            t.Errorf("submesh %d has material %v, want red", i, s.Material)
+        }
+    }
+    if m := data.submeshes[1].Material; m == nil || m.Name != "unknown" || m.Diffuse != [4]float32{1, 1, 1, 1} {
+        t.Errorf("unknown material %+v, want a white one of that name", m)
+    }
+}
+
+func TestParseOBJErrors(t *testing.T) {
+    tests := []struct {
+        obj string
+        err string
+    }{
+        {"f 1 2 5", "line 11: index 5 out of range"},
+        {"f -5 1 2", "line 11: index -5 out of range"},
+        {"f 1 2", "line 11: face with less than 3 vertices"},
+        {"f /1 2 3", `line 11: vertex "/1" has no position`},
+        {"v 1 x 0", "line 11: strconv.ParseFloat: parsing \"x\": invalid syntax"},
+    }
+    for _, tt := range tests {
+        _, err := parseOBJ(strings.NewReader(objQuad+tt.obj), nil)
+        if err == nil || err.Error() != tt.err {
+            t.Errorf("%q: error %v, want %s", tt.obj, err, tt.err)
+        }
+    }
+}
+
+func TestParseMTL(t *testing.T) {
+    const mtl = `# two materials
+Kd 0 0 0
+newmtl shiny red
+Ka 0.1 0.1 0.1
+Kd 1 0 0
+Ks 0.5 0.5 0.5
+Ns 32
+d 0.5
+newmtl glass
+Tr 0.75
+map_Kd -s 2 2 1 textures/glass.png
+`
+    var textures []string
+    tex := &Texture{ID: 7}
+    materials, err := parseMTL(strings.NewReader(mtl), func(file string) (*Texture, error) {
+        textures = append(textures, file)
+        return tex, nil
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := map[string]*Material{
+        "shiny red": {
+            Name:      "shiny red",
+            Ambient:   [3]float32{0.1, 0.1, 0.1},
+            Diffuse:   [4]float32{1, 0, 0, 0.5},
+            Specular:  [3]float32{0.5, 0.5, 0.5},
+            Shininess: 32,
+        },
+        "glass": {
+            Name:       "glass",
+            Diffuse:    [4]float32{1, 1, 1, 0.25},
+            DiffuseMap: tex,
+        },
+    }
+    if !reflect.DeepEqual(materials, want) {
+        t.Errorf("materials\n%+v, want\n%+v", materials, want)
+    }
+    if !reflect.DeepEqual(textures, []string{"textures/glass.png"}) {
+        t.Errorf("loaded textures %v, want textures/glass.png", textures)
+    }
+}
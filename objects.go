@@ -0,0 +1,309 @@
+package gome
+
+import (
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+    "log/slog"
+    "runtime/debug"
+    "sort"
+    "strings"
+)
+
+// DebugObjects enables recording of the stack trace at which each GL object
+// wrapper is created. When it is true, Terminate logs every object that was
+// never deleted together with the place it was created, which makes tracking
+// down GPU memory leaks a lot easier. It should be set before creating any
+// objects.
+var DebugObjects = false
+
+// Object describes a live GL object created through one of the wrappers.
+type Object struct {
+    Kind  string // "texture", "buffer", "program", "vertex array", "framebuffer", ...
+    ID    uint
+    Stack string // stack trace of the creation, empty unless DebugObjects is set
+}
+
+type objectKey struct {
+    kind string
+    id   uint
+}
+
+var liveObjects = map[objectKey]string{}
+
+func trackObject(kind string, id uint) {
+    var stack string
+    if DebugObjects {
+        stack = string(debug.Stack())
+    }
+    liveObjects[objectKey{kind, id}] = stack
+    logAt(slog.LevelDebug, "GL object created", "kind", kind, "id", id)
+}
+
+func untrackObject(kind string, id uint) {
+    delete(liveObjects, objectKey{kind, id})
+    logAt(slog.LevelDebug, "GL object deleted", "kind", kind, "id", id)
+}
+
+// LiveObjects returns all GL objects created through the wrappers that have
+// not been deleted yet, sorted by kind and ID.
+func LiveObjects() []Object {
+    objects := make([]Object, 0, len(liveObjects))
+    for k, stack := range liveObjects {
+        objects = append(objects, Object{k.kind, k.id, stack})
+    }
+    sort.Sort(objectsByKind(objects))
+    return objects
+}
+
+type objectsByKind []Object
+
+func (s objectsByKind) Len() int      { return len(s) }
+func (s objectsByKind) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s objectsByKind) Less(i, j int) bool {
+    if s[i].Kind != s[j].Kind {
+        return s[i].Kind < s[j].Kind
+    }
+    return s[i].ID < s[j].ID
+}
+
+// reportLeaks logs all live objects if DebugObjects is set.
+func reportLeaks() {
+    if !DebugObjects {
+        return
+    }
+    for _, o := range LiveObjects() {
+        logAt(slog.LevelWarn, "GL object never deleted", "kind", o.Kind, "id", o.ID, "stack", o.Stack)
+    }
+}
+
+// Texture is a tracked GL texture object. Width and Height are set by the
+// texture loaders but are left at zero by NewTexture.
+type Texture struct {
+    ID            uint32
+    Width, Height int
+}
+
+// NewTexture generates a new texture object.
+func NewTexture() *Texture {
+    t := &Texture{}
+    gl.GenTextures(1, &t.ID)
+    trackObject("texture", uint(t.ID))
+    return t
+}
+
+// Delete deletes the texture.
+func (t *Texture) Delete() {
+    untrackObject("texture", uint(t.ID))
+    state.deleteTexture(t.ID)
+    gl.DeleteTextures(1, &t.ID)
+}
+
+// Buffer is a tracked GL buffer object.
+type Buffer struct {
+    ID uint32
+}
+
+// NewBuffer generates a new buffer object.
+func NewBuffer() *Buffer {
+    b := &Buffer{}
+    gl.GenBuffers(1, &b.ID)
+    trackObject("buffer", uint(b.ID))
+    return b
+}
+
+// Delete deletes the buffer.
+func (b *Buffer) Delete() {
+    untrackObject("buffer", uint(b.ID))
+    state.deleteBuffer(b.ID)
+    gl.DeleteBuffers(1, &b.ID)
+}
+
+// VertexArray is a tracked GL vertex array object.
+type VertexArray struct {
+    ID uint32
+}
+
+// NewVertexArray generates a new vertex array object.
+func NewVertexArray() *VertexArray {
+    a := &VertexArray{}
+    gl.GenVertexArrays(1, &a.ID)
+    trackObject("vertex array", uint(a.ID))
+    return a
+}
+
+// Delete deletes the vertex array.
+func (a *VertexArray) Delete() {
+    untrackObject("vertex array", uint(a.ID))
+    state.deleteVertexArray(a.ID)
+    gl.DeleteVertexArrays(1, &a.ID)
+}
+
+// Framebuffer is a tracked GL framebuffer object.
+type Framebuffer struct {
+    ID uint32
+}
+
+// NewFramebuffer generates a new framebuffer object.
+func NewFramebuffer() *Framebuffer {
+    f := &Framebuffer{}
+    gl.GenFramebuffers(1, &f.ID)
+    trackObject("framebuffer", uint(f.ID))
+    return f
+}
+
+// Delete deletes the framebuffer.
+func (f *Framebuffer) Delete() {
+    untrackObject("framebuffer", uint(f.ID))
+    state.deleteFramebuffer(f.ID)
+    gl.DeleteFramebuffers(1, &f.ID)
+}
+
+// Renderbuffer is a tracked GL renderbuffer object.
+type Renderbuffer struct {
+    ID uint32
+}
+
+// NewRenderbuffer generates a new renderbuffer object.
+func NewRenderbuffer() *Renderbuffer {
+    r := &Renderbuffer{}
+    gl.GenRenderbuffers(1, &r.ID)
+    trackObject("renderbuffer", uint(r.ID))
+    return r
+}
+
+// Bind binds the renderbuffer to GL_RENDERBUFFER.
+func (r *Renderbuffer) Bind() {
+    gl.BindRenderbuffer(gl.RENDERBUFFER, r.ID)
+}
+
+// Delete deletes the renderbuffer.
+func (r *Renderbuffer) Delete() {
+    untrackObject("renderbuffer", uint(r.ID))
+    gl.DeleteRenderbuffers(1, &r.ID)
+}
+
+// Program is a tracked GL program object.
+type Program struct {
+    ID uint32
+}
+
+// GetUniformLocation returns the location of the uniform name in p, or -1 if
+// p has no such active uniform.
+func (p *Program) GetUniformLocation(name string) int32 {
+    return gl.GetUniformLocation(p.ID, gl.Str(name+"\x00"))
+}
+
+// GetAttribLocation returns the location of the attribute name in p, or -1 if
+// p has no such active attribute.
+func (p *Program) GetAttribLocation(name string) int32 {
+    return gl.GetAttribLocation(p.ID, gl.Str(name+"\x00"))
+}
+
+// ShaderError is returned when a shader fails to compile or a program fails
+// to link. Log contains the info log reported by the driver.
+type ShaderError struct {
+    Stage string // "vertex", "fragment", "compute" or "link"
+    Log   string
+}
+
+func (e *ShaderError) Error() string {
+    return fmt.Sprintf("%s shader error: %s", e.Stage, e.Log)
+}
+
+// NewProgram compiles the given vertex and fragment shader sources and links
+// them into a new program. On error a *ShaderError is returned. The #version
+// line of the sources is adapted to the context, so shaders written for
+// desktop GLSL 1.50 also compile on OpenGL ES (see ESContexts).
+func NewProgram(vertex, fragment string) (*Program, error) {
+    p, err := linkProgram(vertex, fragment)
+    if err != nil {
+        return nil, err
+    }
+    prog := &Program{p}
+    trackObject("program", uint(prog.ID))
+    return prog, nil
+}
+
+// Delete deletes the program.
+func (p *Program) Delete() {
+    untrackObject("program", uint(p.ID))
+    state.deleteProgram(p.ID)
+    gl.DeleteProgram(p.ID)
+}
+
+func compileShader(typ uint32, stage, source string) (uint32, error) {
+    s := gl.CreateShader(typ)
+    src, free := gl.Strs(adaptShader(typ, source) + "\x00")
+    gl.ShaderSource(s, 1, src, nil)
+    free()
+    gl.CompileShader(s)
+    var status int32
+    gl.GetShaderiv(s, gl.COMPILE_STATUS, &status)
+    if status != gl.TRUE {
+        err := &ShaderError{stage, shaderInfoLog(s)}
+        gl.DeleteShader(s)
+        return 0, err
+    }
+    logShaderWarnings(stage, shaderInfoLog(s))
+    return s, nil
+}
+
+func linkProgram(vertex, fragment string) (uint32, error) {
+    vs, err := compileShader(gl.VERTEX_SHADER, "vertex", vertex)
+    if err != nil {
+        return 0, err
+    }
+    defer gl.DeleteShader(vs)
+    fs, err := compileShader(gl.FRAGMENT_SHADER, "fragment", fragment)
+    if err != nil {
+        return 0, err
+    }
+    defer gl.DeleteShader(fs)
+
+    p := gl.CreateProgram()
+    gl.AttachShader(p, vs)
+    gl.AttachShader(p, fs)
+    for _, a := range standardAttribs {
+        gl.BindAttribLocation(p, a.location, gl.Str(a.name+"\x00"))
+    }
+    gl.LinkProgram(p)
+    var status int32
+    gl.GetProgramiv(p, gl.LINK_STATUS, &status)
+    if status != gl.TRUE {
+        err := &ShaderError{"link", programInfoLog(p)}
+        gl.DeleteProgram(p)
+        return 0, err
+    }
+    logShaderWarnings("link", programInfoLog(p))
+    return p, nil
+}
+
+// logShaderWarnings logs the info log of a shader that compiled or a program
+// that linked, which holds the warnings of the driver, if any.
+func logShaderWarnings(stage, infoLog string) {
+    if infoLog = strings.TrimSpace(infoLog); infoLog != "" {
+        logAt(slog.LevelWarn, "shader warnings", "stage", stage, "log", infoLog)
+    }
+}
+
+func shaderInfoLog(s uint32) string {
+    var n int32
+    gl.GetShaderiv(s, gl.INFO_LOG_LENGTH, &n)
+    if n == 0 {
+        return ""
+    }
+    buf := make([]byte, n)
+    gl.GetShaderInfoLog(s, n, nil, &buf[0])
+    return gl.GoStr(&buf[0])
+}
+
+func programInfoLog(p uint32) string {
+    var n int32
+    gl.GetProgramiv(p, gl.INFO_LOG_LENGTH, &n)
+    if n == 0 {
+        return ""
+    }
+    buf := make([]byte, n)
+    gl.GetProgramInfoLog(p, n, nil, &buf[0])
+    return gl.GoStr(&buf[0])
+}
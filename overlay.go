@@ -0,0 +1,168 @@
+package gome
+
+import (
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+    "github.com/snorredc/gome/internal/glfw"
+    "sort"
+    "strings"
+)
+
+// DebugOverlay draws frame statistics, profiling zones and custom watches on
+// top of each frame. It is drawn by Tick after the application has rendered
+// the frame, using the default framebuffer and its own state; the viewport is
+// restored afterwards, but the state cache functions (see UseProgram) are used
+// to change other state.
+type DebugOverlay struct {
+    Enabled bool
+
+    // ToggleKey toggles Enabled when pressed. Set it to glfw.KeyUnknown to
+    // disable toggling.
+    ToggleKey glfw.Key
+
+    watches map[string]interface{}
+    batch   *SpriteBatch
+    font    *Font
+    keyDown bool
+}
+
+// Overlay is the debug overlay of the main window. It is disabled by default
+// and toggled with F3.
+var Overlay = &DebugOverlay{ToggleKey: glfw.KeyF3}
+
+// Watch shows value under name in the overlay, formatted with fmt.Sprint.
+// Watching a name again replaces its value, so Watch can be called every
+// frame with the current value.
+func (o *DebugOverlay) Watch(name string, value interface{}) {
+    if o.watches == nil {
+        o.watches = map[string]interface{}{}
+    }
+    o.watches[name] = value
+}
+
+// Unwatch removes the watch called name.
+func (o *DebugOverlay) Unwatch(name string) {
+    delete(o.watches, name)
+}
+
+var (
+    overlayBackground = Black.WithAlpha(0.63)
+    overlayText       = White
+    overlayDim        = MustHex("#a0a0a0")
+    overlayBar        = MustHex("#50c850")
+    overlaySlowBar    = MustHex("#dc4632")
+)
+
+// overlay layout, in pixels
+const (
+    overlayPadding     = 6
+    overlayGraphHeight = 40
+    overlayBarWidth    = 2
+)
+
+// update checks the toggle key; it is called at every Tick.
+func (o *DebugOverlay) update() {
+    if o.ToggleKey == glfw.KeyUnknown {
+        return
+    }
+    down := app.window.GetKey(o.ToggleKey) == glfw.Press
+    if down && !o.keyDown {
+        o.Enabled = !o.Enabled
+    }
+    o.keyDown = down
+}
+
+func (o *DebugOverlay) draw() error {
+    if !o.Enabled {
+        return nil
+    }
+    if o.batch == nil {
+        batch, err := NewSpriteBatch()
+        if err != nil {
+            return err
+        }
+        font, err := DefaultFont()
+        if err != nil {
+            batch.Delete()
+            return err
+        }
+        o.batch, o.font = batch, font
+    }
+
+    lines := o.lines()
+    text := strings.Join(lines, "\n")
+    textWidth, textHeight := o.font.Measure(text)
+    graphWidth := float32(frameHistory * overlayBarWidth)
+    width := graphWidth
+    if textWidth > width {
+        width = textWidth
+    }
+    width += 2 * overlayPadding
+    height := textHeight + overlayGraphHeight + 3*overlayPadding
+
+    var viewport [4]int32
+    gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+    fbWidth, fbHeight := app.window.GetFramebufferSize()
+    BindFramebuffer(nil)
+    gl.Viewport(0, 0, int32(fbWidth), int32(fbHeight))
+
+    b := o.batch
+    b.Begin(float32(fbWidth), float32(fbHeight))
+    b.DrawRect(Rect{0, 0, width, height}, overlayBackground)
+    b.DrawText(o.font, text, overlayPadding, overlayPadding, overlayText)
+
+    // frame time graph; a full bar is 1/30 s, one frame at 60 fps is marked
+    graphY := textHeight + 2*overlayPadding
+    b.DrawRect(Rect{overlayPadding, graphY + overlayGraphHeight/2, graphWidth, 1}, overlayDim)
+    for i, d := range FrameTimes() {
+        ms := float32(d.Seconds() * 1000)
+        h := ms / (1000.0 / 30) * overlayGraphHeight
+        c := overlayBar
+        if h > overlayGraphHeight {
+            h = overlayGraphHeight
+            c = overlaySlowBar
+        }
+        x := overlayPadding + float32(i*overlayBarWidth)
+        b.DrawRect(Rect{x, graphY + overlayGraphHeight - h, overlayBarWidth, h}, c)
+    }
+    b.End()
+
+    gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+    return nil
+}
+
+// lines returns the text lines of the overlay.
+func (o *DebugOverlay) lines() []string {
+    lines := []string{
+        fmt.Sprintf("FPS %.1f (%.2f ms)", FPS(), UnscaledDelta().Seconds()*1000),
+        fmt.Sprintf("GL errors %d", GLErrorCount()),
+    }
+    if Paused() {
+        lines = append(lines, "Paused")
+    }
+    if timeScale != 1 {
+        lines = append(lines, fmt.Sprintf("Time scale %gx", timeScale))
+    }
+    zones := func(title string, zones []ZoneTiming) {
+        if len(zones) == 0 {
+            return
+        }
+        lines = append(lines, title)
+        for _, z := range zones {
+            indent := strings.Repeat("  ", z.Depth+1)
+            lines = append(lines, fmt.Sprintf("%s%s %.2f ms", indent, z.Name, z.Milliseconds))
+        }
+    }
+    zones("CPU", CPUZones())
+    zones("GPU", GPUZones())
+
+    names := make([]string, 0, len(o.watches))
+    for name := range o.watches {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        lines = append(lines, fmt.Sprintf("%s: %v", name, o.watches[name]))
+    }
+    return lines
+}
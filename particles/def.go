@@ -0,0 +1,159 @@
+package particles
+
+import (
+    "encoding/json"
+    "github.com/snorredc/gome"
+    "io"
+    "io/fs"
+    "math/rand"
+    "os"
+)
+
+// Range is a range of values that particles pick a random value from.
+type Range struct {
+    Min float32 `json:"min"`
+    Max float32 `json:"max"`
+}
+
+func (r Range) random(rnd *rand.Rand) float32 {
+    return r.Min + (r.Max-r.Min)*rnd.Float32()
+}
+
+// Key is a key of a Curve: the value V at the time T, with T between 0 at
+// the birth of a particle and 1 at the end of its life.
+type Key struct {
+    T float32 `json:"t"`
+    V float32 `json:"v"`
+}
+
+// Curve is a value that changes over the life of a particle, interpolated
+// linearly between keys ordered by time. An empty curve is 1 throughout.
+type Curve []Key
+
+// At returns the value of the curve at time t.
+func (c Curve) At(t float32) float32 {
+    if len(c) == 0 {
+        return 1
+    }
+    if t <= c[0].T {
+        return c[0].V
+    }
+    for i := 1; i < len(c); i++ {
+        if t < c[i].T {
+            a, b := c[i-1], c[i]
+            return a.V + (b.V-a.V)*(t-a.T)/(b.T-a.T)
+        }
+    }
+    return c[len(c)-1].V
+}
+
+// ColorKey is a key of a ColorCurve.
+type ColorKey struct {
+    T     float32    `json:"t"`
+    Color [4]float32 `json:"color"` // non-premultiplied RGBA between 0 and 1
+}
+
+// ColorCurve is a color that changes over the life of a particle, like
+// Curve. An empty curve is opaque white throughout.
+type ColorCurve []ColorKey
+
+// At returns the color of the curve at time t.
+func (c ColorCurve) At(t float32) [4]float32 {
+    if len(c) == 0 {
+        return [4]float32{1, 1, 1, 1}
+    }
+    if t <= c[0].T {
+        return c[0].Color
+    }
+    for i := 1; i < len(c); i++ {
+        if t < c[i].T {
+            a, b := c[i-1], c[i]
+            f := (t - a.T) / (b.T - a.T)
+            var col [4]float32
+            for j := range col {
+                col[j] = a.Color[j] + (b.Color[j]-a.Color[j])*f
+            }
+            return col
+        }
+    }
+    return c[len(c)-1].Color
+}
+
+// EmitterDef defines the behaviour of an emitter. Its fields map directly to
+// JSON, so artists can tweak definitions in files loaded with Load. Angles
+// are in radians, distances in world units and times in seconds.
+type EmitterDef struct {
+    Rate         float32 `json:"rate"`         // particles spawned per second
+    Burst        int     `json:"burst"`        // particles spawned at once by Start
+    MaxParticles int     `json:"maxParticles"` // zero means no limit
+
+    Lifetime  Range      `json:"lifetime"`
+    Speed     Range      `json:"speed"`     // initial speed
+    Direction float32    `json:"direction"` // direction of emission; 0 is to the right
+    Spread    float32    `json:"spread"`    // maximum deviation from Direction
+    Radius    float32    `json:"radius"`    // particles spawn within this distance of the emitter
+    Gravity   [2]float32 `json:"gravity"`   // acceleration
+    Drag      float32    `json:"drag"`      // fraction of the velocity lost per second
+
+    Size     Range      `json:"size"`               // initial size
+    SizeOver Curve      `json:"sizeOver,omitempty"` // size multiplier over the life of a particle
+    Velocity Curve      `json:"velocity,omitempty"` // velocity multiplier over the life of a particle
+    Color    ColorCurve `json:"color,omitempty"`
+    Additive bool       `json:"additive"` // use additive instead of alpha blending
+}
+
+// Load loads an emitter definition from the JSON file at path in gome's
+// asset file system (see gome.SetAssetFS).
+func Load(path string) (*EmitterDef, error) {
+    return LoadFS(gome.AssetFS(), path)
+}
+
+// LoadFS is like Load but reads the file from fsys.
+func LoadFS(fsys fs.FS, name string) (*EmitterDef, error) {
+    var (
+        f   io.ReadCloser
+        err error
+    )
+    if fsys == nil {
+        f, err = os.Open(name)
+    } else {
+        f, err = fsys.Open(name)
+    }
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    return Decode(f)
+}
+
+// Decode reads an emitter definition in JSON from r.
+func Decode(r io.Reader) (*EmitterDef, error) {
+    d := &EmitterDef{}
+    if err := json.NewDecoder(r).Decode(d); err != nil {
+        return nil, err
+    }
+    return d, nil
+}
+
+// Encode writes d to w as indented JSON.
+func (d *EmitterDef) Encode(w io.Writer) error {
+    data, err := json.MarshalIndent(d, "", "    ")
+    if err != nil {
+        return err
+    }
+    _, err = w.Write(append(data, '\n'))
+    return err
+}
+
+// Save writes d as JSON to the file at path.
+func (d *EmitterDef) Save(path string) error {
+    f, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    if err := d.Encode(f); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
@@ -0,0 +1,180 @@
+/*
+Package particles simulates particle effects such as smoke, sparks and fire
+on the CPU and draws them with a gome.SpriteBatch:
+
+    def, err := particles.Load("effects/fire.json")
+    if err != nil {
+        // handle error
+    }
+    fire := particles.NewEmitter(def, flameRegion)
+    fire.Start()
+
+    for app.Tick() {
+        fire.Position = torch
+        fire.Update(gome.FrameTime())
+
+        batch.Begin(width, height)
+        fire.Draw(batch)
+        batch.End()
+    }
+
+The behaviour of an emitter is described by an EmitterDef, which can be
+shared by any number of emitters and stored as JSON.
+*/
+package particles
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "math"
+    "math/rand"
+    "time"
+)
+
+type particle struct {
+    pos, vel  mgl32.Vec2
+    size      float32
+    age, life float32
+}
+
+// Emitter spawns and simulates particles.
+type Emitter struct {
+    Def      *EmitterDef
+    Position mgl32.Vec2
+    Region   gome.Region // drawn for every particle; particles are plain squares if it has no texture
+
+    particles []particle
+    emitting  bool
+    acc       float32
+    rnd       *rand.Rand
+}
+
+// NewEmitter creates an emitter that is not emitting yet.
+func NewEmitter(def *EmitterDef, region gome.Region) *Emitter {
+    return &Emitter{
+        Def:    def,
+        Region: region,
+        rnd:    rand.New(rand.NewSource(time.Now().UnixNano())),
+    }
+}
+
+// Seed seeds the random numbers of the emitter, to make it deterministic.
+func (e *Emitter) Seed(seed int64) {
+    e.rnd.Seed(seed)
+}
+
+// Start spawns a burst of particles (see EmitterDef.Burst) and starts
+// emitting at the rate of the definition.
+func (e *Emitter) Start() {
+    for i := 0; i < e.Def.Burst; i++ {
+        e.spawn()
+    }
+    e.emitting = true
+}
+
+// Stop stops emitting new particles. Live particles keep being simulated
+// until they die.
+func (e *Emitter) Stop() {
+    e.emitting = false
+    e.acc = 0
+}
+
+// Emitting reports whether the emitter is spawning particles.
+func (e *Emitter) Emitting() bool {
+    return e.emitting
+}
+
+// Count returns the number of live particles.
+func (e *Emitter) Count() int {
+    return len(e.particles)
+}
+
+// Done reports whether the emitter has stopped and all its particles are
+// dead, so it can be discarded.
+func (e *Emitter) Done() bool {
+    return !e.emitting && len(e.particles) == 0
+}
+
+// Clear removes all live particles.
+func (e *Emitter) Clear() {
+    e.particles = e.particles[:0]
+}
+
+func (e *Emitter) spawn() {
+    d := e.Def
+    if d.MaxParticles > 0 && len(e.particles) >= d.MaxParticles {
+        return
+    }
+    angle := float64(d.Direction + d.Spread*(2*e.rnd.Float32()-1))
+    speed := d.Speed.random(e.rnd)
+    p := particle{
+        pos:  e.Position,
+        vel:  mgl32.Vec2{float32(math.Cos(angle)) * speed, float32(math.Sin(angle)) * speed},
+        size: d.Size.random(e.rnd),
+        life: d.Lifetime.random(e.rnd),
+    }
+    if d.Radius > 0 {
+        // uniformly distributed within the disk
+        a := 2 * math.Pi * e.rnd.Float64()
+        r := d.Radius * float32(math.Sqrt(e.rnd.Float64()))
+        p.pos = p.pos.Add(mgl32.Vec2{float32(math.Cos(a)) * r, float32(math.Sin(a)) * r})
+    }
+    if p.life <= 0 {
+        return
+    }
+    e.particles = append(e.particles, p)
+}
+
+// Update spawns new particles and advances the simulation by dt.
+func (e *Emitter) Update(dt time.Duration) {
+    d := e.Def
+    t := float32(dt.Seconds())
+    gravity := mgl32.Vec2(d.Gravity).Mul(t)
+    drag := float32(1)
+    if d.Drag > 0 {
+        drag = float32(math.Pow(float64(1-d.Drag), float64(t)))
+    }
+    for i := 0; i < len(e.particles); {
+        p := &e.particles[i]
+        p.age += t
+        if p.age >= p.life {
+            // swap in the last particle
+            last := len(e.particles) - 1
+            e.particles[i] = e.particles[last]
+            e.particles = e.particles[:last]
+            continue
+        }
+        p.vel = p.vel.Add(gravity).Mul(drag)
+        p.pos = p.pos.Add(p.vel.Mul(t * d.Velocity.At(p.age/p.life)))
+        i++
+    }
+
+    if e.emitting && d.Rate > 0 {
+        e.acc += t * d.Rate
+        for ; e.acc >= 1; e.acc-- {
+            e.spawn()
+        }
+    }
+}
+
+// Draw draws the live particles centered at their positions. With an
+// additive definition it switches b to additive blending and back.
+func (e *Emitter) Draw(b *gome.SpriteBatch) {
+    if e.Def.Additive {
+        b.SetBlendMode(gome.BlendAdditive)
+        defer b.SetBlendMode(gome.BlendAlpha)
+    }
+    for i := range e.particles {
+        p := &e.particles[i]
+        t := p.age / p.life
+        size := p.size * e.Def.SizeOver.At(t)
+        c := e.Def.Color.At(t)
+        col := gome.Color{R: c[0], G: c[1], B: c[2], A: c[3]}.Clamp()
+        dst := gome.Rect{X: p.pos[0] - size/2, Y: p.pos[1] - size/2, W: size, H: size}
+        if e.Region.Texture == nil {
+            b.DrawRect(dst, col)
+        } else {
+            b.DrawRegion(e.Region, dst, col)
+        }
+    }
+}
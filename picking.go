@@ -0,0 +1,220 @@
+package gome
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome/internal/gl"
+    "math"
+)
+
+// Ray is a half-line from Origin in the unit-length Direction.
+type Ray struct {
+    Origin    mgl32.Vec3
+    Direction mgl32.Vec3
+}
+
+// At returns the point at distance t along r.
+func (r Ray) At(t float32) mgl32.Vec3 {
+    return r.Origin.Add(r.Direction.Mul(t))
+}
+
+// IntersectPlane returns the distance along r to the plane through point
+// with the given normal. ok is false if r is parallel to the plane or points
+// away from it.
+func (r Ray) IntersectPlane(point, normal mgl32.Vec3) (t float32, ok bool) {
+    d := r.Direction.Dot(normal)
+    if d == 0 {
+        return 0, false
+    }
+    t = point.Sub(r.Origin).Dot(normal) / d
+    return t, t >= 0
+}
+
+// IntersectSphere returns the distance along r to the first intersection
+// with s, which is 0 if the origin of r is inside.
+func (r Ray) IntersectSphere(s Sphere) (t float32, ok bool) {
+    oc := r.Origin.Sub(s.Center)
+    b := oc.Dot(r.Direction)
+    c := oc.Dot(oc) - s.Radius*s.Radius
+    disc := b*b - c
+    if disc < 0 {
+        return 0, false
+    }
+    sq := float32(math.Sqrt(float64(disc)))
+    t = -b - sq
+    if t < 0 {
+        t = -b + sq
+        if t < 0 {
+            return 0, false
+        }
+        // inside the sphere
+        return 0, true
+    }
+    return t, true
+}
+
+// IntersectAABB returns the distance along r to the first intersection with
+// b, which is 0 if the origin of r is inside.
+func (r Ray) IntersectAABB(b AABB) (t float32, ok bool) {
+    min, max := b.Min, b.Max
+    near, far := float32(0), float32(math.Inf(1))
+    for i := 0; i < 3; i++ {
+        if r.Direction[i] == 0 {
+            if r.Origin[i] < min[i] || r.Origin[i] > max[i] {
+                return 0, false
+            }
+            continue
+        }
+        t0 := (min[i] - r.Origin[i]) / r.Direction[i]
+        t1 := (max[i] - r.Origin[i]) / r.Direction[i]
+        if t0 > t1 {
+            t0, t1 = t1, t0
+        }
+        if t0 > near {
+            near = t0
+        }
+        if t1 < far {
+            far = t1
+        }
+        if near > far {
+            return 0, false
+        }
+    }
+    return near, true
+}
+
+// PickRay returns the ray of cam through the cursor position (mouseX,
+// mouseY), as reported by CursorPosition, for a camera covering the screen.
+// Intersecting it with the objects of the scene finds the one under the
+// cursor; for meshes without simple bounds, Picker does this on the GPU.
+func PickRay(mouseX, mouseY float32, cam *Camera3D) Ray {
+    w, h := cursorArea()
+    return cam.Ray(mouseX, mouseY, w, h)
+}
+
+// cursorArea returns the size of the screen in the units of CursorPosition.
+func cursorArea() (width, height float32) {
+    if virtual.target != nil {
+        return float32(virtual.width), float32(virtual.height)
+    }
+    w, h := app.window.GetSize()
+    return float32(w), float32(h)
+}
+
+const pickVertexShader = `#version 150
+uniform mat4 matrix;
+in vec3 position;
+void main() {
+    gl_Position = matrix * vec4(position, 1.0);
+}
+`
+
+const pickFragmentShader = `#version 150
+uniform vec4 id;
+out vec4 outColor;
+void main() {
+    outColor = id;
+}
+`
+
+// Picker finds the object under the cursor by drawing the registered meshes
+// with their IDs as colours and reading back the pixel under the cursor. It
+// draws the pixel only, into a target of its own, so picking is cheap enough
+// to do on every click. Meshes are drawn by their positions alone, so
+// skinned meshes are picked in their bind pose.
+type Picker struct {
+    target  *RenderTarget
+    program *Program
+    matrix  int32
+    id      int32
+    objects map[uint32]pickObject
+    nextID  uint32
+}
+
+type pickObject struct {
+    mesh  *Mesh
+    model *mgl32.Mat4
+}
+
+// NewPicker creates a picker without objects.
+func NewPicker() (*Picker, error) {
+    program, err := NewProgram(pickVertexShader, pickFragmentShader)
+    if err != nil {
+        return nil, err
+    }
+    target, err := NewRenderTarget(1, 1, &RenderTargetOptions{Depth: true, Filter: gl.NEAREST})
+    if err != nil {
+        program.Delete()
+        return nil, err
+    }
+    return &Picker{
+        target:  target,
+        program: program,
+        matrix:  program.GetUniformLocation("matrix"),
+        id:      program.GetUniformLocation("id"),
+        objects: map[uint32]pickObject{},
+    }, nil
+}
+
+// Add registers mesh, transformed into world coordinates by *model, and
+// returns its ID, which is never 0. model is read at every Pick, so moving
+// the object only needs a change of the matrix.
+func (p *Picker) Add(mesh *Mesh, model *mgl32.Mat4) uint32 {
+    p.nextID++
+    p.objects[p.nextID] = pickObject{mesh, model}
+    return p.nextID
+}
+
+// Remove unregisters the object with the given ID.
+func (p *Picker) Remove(id uint32) {
+    delete(p.objects, id)
+}
+
+// Clear unregisters all objects.
+func (p *Picker) Clear() {
+    p.objects = map[uint32]pickObject{}
+}
+
+// Pick returns the ID of the nearest object at the cursor position (mouseX,
+// mouseY), as reported by CursorPosition, seen by cam covering the screen,
+// or false if there is none. It enables depth testing, disables blending and
+// binds the screen again (see BindScreen).
+func (p *Picker) Pick(mouseX, mouseY float32, cam *Camera3D) (id uint32, ok bool) {
+    w, h := cursorArea()
+    // scale the pixel under the cursor up to the whole 1x1 target
+    nx, ny := 2*mouseX/w-1, 1-2*mouseY/h
+    pick := mgl32.Scale3D(w, h, 1).Mul4(mgl32.Translate3D(-nx, -ny, 0))
+    viewProj := pick.Mul4(cam.Matrix(w, h))
+
+    p.target.Bind()
+    gl.ClearColor(0, 0, 0, 0)
+    gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+    applyClearColor()
+    Enable(gl.DEPTH_TEST)
+    Disable(gl.BLEND)
+    UseProgram(p.program)
+    for oid, o := range p.objects {
+        m := viewProj.Mul4(*o.model)
+        gl.UniformMatrix4fv(p.matrix, 1, false, &m[0])
+        gl.Uniform4f(p.id,
+            float32(oid&0xff)/255,
+            float32(oid>>8&0xff)/255,
+            float32(oid>>16&0xff)/255,
+            float32(oid>>24&0xff)/255)
+        o.mesh.Draw(nil)
+    }
+    var pixel [4]uint8
+    gl.ReadPixels(0, 0, 1, 1, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(&pixel[0]))
+    BindScreen()
+
+    id = uint32(pixel[0]) | uint32(pixel[1])<<8 | uint32(pixel[2])<<16 | uint32(pixel[3])<<24
+    if _, found := p.objects[id]; !found {
+        return 0, false
+    }
+    return id, true
+}
+
+// Delete deletes the GL objects of the picker.
+func (p *Picker) Delete() {
+    p.target.Delete()
+    p.program.Delete()
+}
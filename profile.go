@@ -0,0 +1,165 @@
+package gome
+
+import (
+    "encoding/json"
+    "io"
+    "time"
+)
+
+type openZone struct {
+    record int // index into cpuProfiler.records
+    start  time.Time
+}
+
+type zoneRecord struct {
+    ZoneTiming
+    path   string
+    parent string
+}
+
+type traceEvent struct {
+    Name  string  `json:"name"`
+    Phase string  `json:"ph"`
+    Time  float64 `json:"ts"`  // microseconds since the start of the trace
+    Dur   float64 `json:"dur"` // microseconds
+    Pid   int     `json:"pid"`
+    Tid   int     `json:"tid"`
+}
+
+type cpuProfiler struct {
+    stack      []openZone
+    index      map[string]int
+    records    []zoneRecord
+    last       []ZoneTiming
+    frameStart time.Time
+
+    tracing    bool
+    traceStart time.Time
+    events     []traceEvent
+}
+
+var cpuProf cpuProfiler
+
+// Zone starts timing a CPU profiling zone and returns a function that ends it,
+// so it is easiest used as
+//
+//     defer gome.Zone("update")()
+//
+// Zones may be nested; the timings of a frame are aggregated per nesting path
+// and can be retrieved with CPUZones after the frame has ended.
+func Zone(name string) func() {
+    p := &cpuProf
+    var parent string
+    if n := len(p.stack); n > 0 {
+        parent = p.records[p.stack[n-1].record].path
+    }
+    path := parent + "/" + name
+    if p.index == nil {
+        p.index = map[string]int{}
+    }
+    i, ok := p.index[path]
+    if !ok {
+        i = len(p.records)
+        p.index[path] = i
+        p.records = append(p.records, zoneRecord{
+            ZoneTiming: ZoneTiming{Name: name, Depth: len(p.stack)},
+            path:       path,
+            parent:     parent,
+        })
+    }
+    p.stack = append(p.stack, openZone{i, time.Now()})
+    depth := len(p.stack)
+    return func() {
+        // ending a zone also ends any zones nested in it that were left open
+        for len(p.stack) >= depth {
+            p.end(time.Now())
+        }
+    }
+}
+
+func (p *cpuProfiler) end(now time.Time) {
+    n := len(p.stack)
+    z := p.stack[n-1]
+    p.stack = p.stack[:n-1]
+
+    d := now.Sub(z.start)
+    r := &p.records[z.record]
+    r.Milliseconds += d.Seconds() * 1000
+    r.Calls++
+    if p.tracing {
+        p.event(r.Name, z.start, d)
+    }
+}
+
+func (p *cpuProfiler) event(name string, start time.Time, d time.Duration) {
+    p.events = append(p.events, traceEvent{
+        Name:  name,
+        Phase: "X",
+        Time:  float64(start.Sub(p.traceStart)) / float64(time.Microsecond),
+        Dur:   float64(d) / float64(time.Microsecond),
+        Pid:   1,
+        Tid:   1,
+    })
+}
+
+// endFrame is called at every Tick. It ends any zones left open and makes the
+// timings of the frame available through CPUZones.
+func (p *cpuProfiler) endFrame() {
+    now := time.Now()
+    for len(p.stack) > 0 {
+        p.end(now)
+    }
+    if p.tracing && !p.frameStart.IsZero() {
+        p.event("frame", p.frameStart, now.Sub(p.frameStart))
+    }
+    p.frameStart = now
+
+    // order the zones depth first, so each zone is followed by its children
+    children := map[string][]int{}
+    for i, r := range p.records {
+        children[r.parent] = append(children[r.parent], i)
+    }
+    p.last = p.last[:0]
+    var visit func(path string)
+    visit = func(path string) {
+        for _, i := range children[path] {
+            p.last = append(p.last, p.records[i].ZoneTiming)
+            visit(p.records[i].path)
+        }
+    }
+    visit("")
+    p.records = p.records[:0]
+    p.index = nil
+}
+
+// CPUZones returns the CPU zone timings of the last frame. Each zone is
+// followed by the zones nested in it, which have a greater Depth.
+func CPUZones() []ZoneTiming {
+    return append([]ZoneTiming(nil), cpuProf.last...)
+}
+
+// StartTrace starts recording every CPU zone and frame as a trace event,
+// discarding any previously recorded events.
+func StartTrace() {
+    cpuProf.tracing = true
+    cpuProf.traceStart = time.Now()
+    cpuProf.events = nil
+}
+
+// StopTrace stops recording trace events. The events recorded so far are kept
+// until the next StartTrace, so they can still be written with WriteTrace.
+func StopTrace() {
+    cpuProf.tracing = false
+}
+
+// WriteTrace writes the recorded trace events to w in the Trace Event Format,
+// which can be viewed with chrome://tracing.
+func WriteTrace(w io.Writer) error {
+    events := cpuProf.events
+    if events == nil {
+        events = []traceEvent{}
+    }
+    return json.NewEncoder(w).Encode(struct {
+        TraceEvents []traceEvent `json:"traceEvents"`
+    }{events})
+}
@@ -0,0 +1,235 @@
+package gome
+
+import (
+    "encoding/gob"
+    "errors"
+    "github.com/snorredc/gome/internal/glfw"
+    "io"
+    "time"
+)
+
+var (
+    ErrRecording = errors.New("input is already being recorded")
+    ErrReplay    = errors.New("invalid input recording")
+)
+
+// recordingHeader starts every recording, followed by one frameRecord per
+// frame.
+const recordingHeader = "gome input recording 1"
+
+type gamepadRecord struct {
+    Present bool
+    Axes    []float32
+    Buttons []bool
+}
+
+// frameRecord is the input of a frame. The fields are exported for
+// encoding/gob.
+type frameRecord struct {
+    Frame  uint64        // counted from the start of the recording
+    Time   time.Duration // the frame time
+    Events []inputEvent
+    Pads   []gamepadRecord // nil if the gamepads did not change
+}
+
+type inputRecorder struct {
+    enc    *gob.Encoder
+    err    error
+    start  uint64
+    events []inputEvent
+    pads   []gamepadRecord
+}
+
+var recorder inputRecorder
+
+// RecordInput starts writing the input of every following frame to w: the
+// keys, mouse buttons, cursor motion, scrolling, typed text, file drops and
+// the gamepad state, together with the frame numbers and frame times. The
+// keys and buttons held when recording starts are recorded as pressed in the
+// first frame.
+//
+// ReplayInput plays a recording back. As the recorded frame times are
+// replayed too, a FixedTimestep runs the same steps, so a game that only
+// depends on its input behaves identically, which makes bugs reproducible and
+// gameplay testable.
+func RecordInput(w io.Writer) error {
+    if recorder.enc != nil {
+        return ErrRecording
+    }
+    enc := gob.NewEncoder(w)
+    if err := enc.Encode(recordingHeader); err != nil {
+        return err
+    }
+    recorder = inputRecorder{enc: enc, start: FrameCount()}
+    for k, down := range input.keys {
+        if down {
+            recorder.event(inputEvent{Kind: keyEvent, Code: k, Action: glfw.Press})
+        }
+    }
+    for b, down := range input.buttons {
+        if down {
+            recorder.event(inputEvent{Kind: mouseButtonEvent, Code: b, Action: glfw.Press})
+        }
+    }
+    recorder.event(inputEvent{Kind: cursorEvent, X: cursor.x, Y: cursor.y})
+    return nil
+}
+
+// StopRecording stops recording input and returns the first error that
+// occurred while writing the recording, if any.
+func StopRecording() error {
+    err := recorder.err
+    recorder = inputRecorder{}
+    return err
+}
+
+// Recording reports whether input is being recorded.
+func Recording() bool {
+    return recorder.enc != nil
+}
+
+func (r *inputRecorder) event(e inputEvent) {
+    if r.enc != nil {
+        r.events = append(r.events, e)
+    }
+}
+
+// endFrame writes the input of the frame. It is called by Tick after the
+// events have been polled.
+func (r *inputRecorder) endFrame() {
+    if r.enc == nil || r.err != nil {
+        return
+    }
+    rec := frameRecord{
+        Frame:  FrameCount() - r.start,
+        Time:   UnscaledDelta(),
+        Events: r.events,
+    }
+    if pads := recordPads(); !samePads(pads, r.pads) {
+        rec.Pads = pads
+        r.pads = pads
+    }
+    r.err = r.enc.Encode(rec)
+    r.events = r.events[:0]
+}
+
+func recordPads() []gamepadRecord {
+    pads := make([]gamepadRecord, len(input.pads))
+    for i, p := range input.pads {
+        pads[i] = gamepadRecord{
+            Present: p.present,
+            Axes:    append([]float32(nil), p.axes...),
+            Buttons: append([]bool(nil), p.buttons...),
+        }
+    }
+    return pads
+}
+
+func samePads(a, b []gamepadRecord) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for i := range a {
+        if a[i].Present != b[i].Present || len(a[i].Axes) != len(b[i].Axes) || len(a[i].Buttons) != len(b[i].Buttons) {
+            return false
+        }
+        for j := range a[i].Axes {
+            if a[i].Axes[j] != b[i].Axes[j] {
+                return false
+            }
+        }
+        for j := range a[i].Buttons {
+            if a[i].Buttons[j] != b[i].Buttons[j] {
+                return false
+            }
+        }
+    }
+    return true
+}
+
+var replay struct {
+    active bool
+    frames []frameRecord
+    next   int
+    start  uint64
+}
+
+// ReplayInput reads a recording made by RecordInput from r and plays it back
+// from the next frame on, as if the input was live: keys, buttons, cursor,
+// gamepads and FrameTime report the recorded state, and the live input of
+// the window is ignored until the replay has finished (see Replaying). All
+// keys and buttons are released when the replay starts and ends.
+func ReplayInput(r io.Reader) error {
+    dec := gob.NewDecoder(r)
+    var header string
+    if err := dec.Decode(&header); err != nil || header != recordingHeader {
+        return ErrReplay
+    }
+    var frames []frameRecord
+    for {
+        var rec frameRecord
+        err := dec.Decode(&rec)
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return err
+        }
+        frames = append(frames, rec)
+    }
+    replay.active = true
+    replay.frames = frames
+    replay.next = 0
+    replay.start = FrameCount()
+    input.reset()
+    cursor.havePosition = false
+    return nil
+}
+
+// StopReplay ends a running replay early and returns to live input.
+func StopReplay() {
+    if replay.active {
+        replay.active = false
+        replay.frames = nil
+        input.reset()
+        cursor.havePosition = false
+    }
+}
+
+// Replaying reports whether a recording is being replayed.
+func Replaying() bool {
+    return replay.active
+}
+
+// replayFrame feeds the recorded input of the frame to the subsystems. It is
+// called by Tick after the events have been polled.
+func replayFrame() {
+    if !replay.active {
+        return
+    }
+    if replay.next == len(replay.frames) {
+        // the last recorded frame has been seen by the game
+        StopReplay()
+        return
+    }
+    frame := FrameCount() - replay.start
+    for replay.next < len(replay.frames) && replay.frames[replay.next].Frame <= frame {
+        rec := replay.frames[replay.next]
+        replay.next++
+        if rec.Frame < frame {
+            continue
+        }
+        stats.frameTime = rec.Time
+        for i, p := range rec.Pads {
+            if i < len(input.pads) {
+                input.pads[i].present = p.Present
+                input.pads[i].axes = append(input.pads[i].axes[:0], p.Axes...)
+                input.pads[i].buttons = append(input.pads[i].buttons[:0], p.Buttons...)
+            }
+        }
+        for _, e := range rec.Events {
+            recorder.event(e)
+            dispatchInput(e)
+        }
+    }
+}
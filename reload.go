@@ -0,0 +1,152 @@
+package gome
+
+import (
+    "io"
+    "io/fs"
+    "log/slog"
+    "time"
+)
+
+// ReloadInterval is how often watched shader files are checked for changes.
+var ReloadInterval = 500 * time.Millisecond
+
+// ProgramWatch recompiles a program whenever its shader files change. It is
+// returned by WatchProgram.
+type ProgramWatch struct {
+    // OnReload is called after the program has been recompiled successfully.
+    // Since the program object is replaced, uniform locations and uniform
+    // block bindings have to be set up again, which is best done here.
+    OnReload func(p *Program)
+
+    // Err is the error of the last reload, or nil if it succeeded.
+    Err error
+
+    program            *Program
+    vertPath, fragPath string
+    vertTime, fragTime time.Time
+}
+
+var (
+    programWatches []*ProgramWatch
+    lastReload     time.Time
+)
+
+// LoadProgram reads the vertex and fragment shaders from the given files in
+// the asset file system (see SetAssetFS) and links them into a program (see
+// NewProgram).
+func LoadProgram(vertPath, fragPath string) (*Program, error) {
+    return LoadProgramFS(assetFS, vertPath, fragPath)
+}
+
+// LoadProgramFS is like LoadProgram but reads the shaders from fsys.
+func LoadProgramFS(fsys fs.FS, vertPath, fragPath string) (*Program, error) {
+    vert, err := readFile(fsys, vertPath)
+    if err != nil {
+        return nil, err
+    }
+    frag, err := readFile(fsys, fragPath)
+    if err != nil {
+        return nil, err
+    }
+    return NewProgram(string(vert), string(frag))
+}
+
+// ReadProgram reads the vertex and fragment shaders from the given readers and
+// links them into a program (see NewProgram).
+func ReadProgram(vert, frag io.Reader) (*Program, error) {
+    vs, err := io.ReadAll(vert)
+    if err != nil {
+        return nil, err
+    }
+    fs, err := io.ReadAll(frag)
+    if err != nil {
+        return nil, err
+    }
+    return NewProgram(string(vs), string(fs))
+}
+
+// WatchProgram watches the shader files a program was built from and
+// recompiles prog in place on the next Tick after one of them changes. If
+// compilation fails, the old program is kept and the error is logged and shown
+// in the debug overlay until a later reload succeeds. The files are looked up
+// in the asset file system; note that files embedded with go:embed never
+// change.
+func WatchProgram(prog *Program, vertPath, fragPath string) (*ProgramWatch, error) {
+    w := &ProgramWatch{program: prog, vertPath: vertPath, fragPath: fragPath}
+    var err error
+    if w.vertTime, err = modTime(vertPath); err != nil {
+        return nil, err
+    }
+    if w.fragTime, err = modTime(fragPath); err != nil {
+        return nil, err
+    }
+    programWatches = append(programWatches, w)
+    return w, nil
+}
+
+// Stop stops watching the files.
+func (w *ProgramWatch) Stop() {
+    for i, other := range programWatches {
+        if other == w {
+            programWatches = append(programWatches[:i], programWatches[i+1:]...)
+            break
+        }
+    }
+    Overlay.Unwatch(w.overlayName())
+}
+
+func (w *ProgramWatch) overlayName() string {
+    return "shader " + w.vertPath + " + " + w.fragPath
+}
+
+func modTime(path string) (time.Time, error) {
+    info, err := statAsset(path)
+    if err != nil {
+        return time.Time{}, err
+    }
+    return info.ModTime(), nil
+}
+
+// reloadPrograms is called at every Tick and checks the watched files.
+func reloadPrograms() {
+    if len(programWatches) == 0 || time.Since(lastReload) < ReloadInterval {
+        return
+    }
+    lastReload = time.Now()
+    for _, w := range programWatches {
+        w.check()
+    }
+}
+
+func (w *ProgramWatch) check() {
+    vertTime, err1 := modTime(w.vertPath)
+    fragTime, err2 := modTime(w.fragPath)
+    if err1 != nil || err2 != nil {
+        // editors often replace files by deleting and recreating them, so
+        // missing files are simply checked again later
+        return
+    }
+    if vertTime.Equal(w.vertTime) && fragTime.Equal(w.fragTime) {
+        return
+    }
+    w.vertTime, w.fragTime = vertTime, fragTime
+
+    p, err := LoadProgram(w.vertPath, w.fragPath)
+    w.Err = err
+    if err != nil {
+        logAt(slog.LevelWarn, "reloading shaders", "program", w.overlayName(), "err", err)
+        Overlay.Watch(w.overlayName(), err)
+        return
+    }
+    Overlay.Unwatch(w.overlayName())
+
+    // move the new program object into the existing wrapper
+    old := *w.program
+    untrackObject("program", uint(p.ID))
+    w.program.ID = p.ID
+    old.Delete()
+    trackObject("program", uint(w.program.ID))
+    if w.OnReload != nil {
+        w.OnReload(w.program)
+    }
+}
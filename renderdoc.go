@@ -0,0 +1,57 @@
+package gome
+
+import (
+    "log/slog"
+)
+
+// RenderDoc is a graphics debugger that records every OpenGL call of a frame
+// for inspection. It injects itself into the application when launched from
+// its UI, or is loaded with Config.RenderDoc, and usually captures a frame at
+// the press of F12; the functions below trigger captures from the
+// application instead, e.g. as soon as a glitch is detected. A frame ends
+// at the buffer swap of Tick. Without RenderDoc, or on macOS, which it does
+// not support, they do nothing.
+
+var renderDoc struct {
+    checked  bool
+    attached bool
+}
+
+// RenderDocAttached reports whether RenderDoc is loaded into the process.
+func RenderDocAttached() bool {
+    if !renderDoc.checked {
+        renderDoc.attached = renderDocInit(false)
+        renderDoc.checked = true
+    }
+    return renderDoc.attached
+}
+
+// TriggerCapture makes RenderDoc capture the next frame.
+func TriggerCapture() {
+    if RenderDocAttached() {
+        renderDocTrigger()
+    }
+}
+
+// CaptureNextFrames makes RenderDoc capture the next n frames.
+func CaptureNextFrames(n int) {
+    if n > 0 && RenderDocAttached() {
+        renderDocTriggerFrames(uint32(n))
+    }
+}
+
+// RenderDocCapturing reports whether RenderDoc is capturing the current
+// frame.
+func RenderDocCapturing() bool {
+    return RenderDocAttached() && renderDocCapturing()
+}
+
+// loadRenderDoc loads the RenderDoc library for Config.RenderDoc. It has to
+// run before the OpenGL context is created, so that RenderDoc can hook it.
+func loadRenderDoc() {
+    renderDoc.attached = renderDocInit(true)
+    renderDoc.checked = true
+    if !renderDoc.attached {
+        logAt(slog.LevelWarn, "RenderDoc could not be loaded")
+    }
+}
@@ -0,0 +1,87 @@
+// +build linux freebsd windows
+// +build cgo,!gomemock
+
+package gome
+
+/*
+#cgo linux LDFLAGS: -ldl
+
+#include <stdint.h>
+#include <stddef.h>
+#ifdef _WIN32
+#include <windows.h>
+#else
+#include <dlfcn.h>
+#endif
+
+// The entries of the function table of version 1.1.2 of the RenderDoc
+// in-application API (renderdoc_app.h) used here.
+enum {
+    gomeTriggerCapture = 15,
+    gomeIsFrameCapturing = 20,
+    gomeTriggerMultiFrameCapture = 22,
+};
+
+typedef int (*gomeGetAPI)(int version, void **api);
+
+static void **gomeRenderDoc;
+
+static int gomeRenderDocInit(int load) {
+    void *getAPI = NULL;
+#ifdef _WIN32
+    HMODULE lib = GetModuleHandleA("renderdoc.dll");
+    if (!lib && load) {
+        lib = LoadLibraryA("renderdoc.dll");
+    }
+    if (lib) {
+        getAPI = (void *)GetProcAddress(lib, "RENDERDOC_GetAPI");
+    }
+#else
+    void *lib = dlopen("librenderdoc.so", RTLD_NOW | RTLD_NOLOAD);
+    if (!lib && load) {
+        lib = dlopen("librenderdoc.so", RTLD_NOW);
+    }
+    if (lib) {
+        getAPI = dlsym(lib, "RENDERDOC_GetAPI");
+    }
+#endif
+    if (!getAPI) {
+        return 0;
+    }
+    // eRENDERDOC_API_Version_1_1_2
+    return ((gomeGetAPI)getAPI)(10102, (void **)&gomeRenderDoc) == 1;
+}
+
+static void gomeRenderDocTrigger(void) {
+    ((void (*)(void))gomeRenderDoc[gomeTriggerCapture])();
+}
+
+static void gomeRenderDocTriggerFrames(uint32_t n) {
+    ((void (*)(uint32_t))gomeRenderDoc[gomeTriggerMultiFrameCapture])(n);
+}
+
+static uint32_t gomeRenderDocCapturing(void) {
+    return ((uint32_t (*)(void))gomeRenderDoc[gomeIsFrameCapturing])();
+}
+*/
+import "C"
+
+func renderDocInit(load bool) bool {
+    l := C.int(0)
+    if load {
+        l = 1
+    }
+    return C.gomeRenderDocInit(l) != 0
+}
+
+func renderDocTrigger() {
+    C.gomeRenderDocTrigger()
+}
+
+func renderDocTriggerFrames(n uint32) {
+    C.gomeRenderDocTriggerFrames(C.uint32_t(n))
+}
+
+func renderDocCapturing() bool {
+    return C.gomeRenderDocCapturing() != 0
+}
@@ -0,0 +1,18 @@
+// +build !linux,!freebsd,!windows !cgo gomemock
+
+package gome
+
+// RenderDoc does not run on the other systems, nor without cgo or with the
+// mock backend.
+
+func renderDocInit(load bool) bool {
+    return false
+}
+
+func renderDocTrigger() {}
+
+func renderDocTriggerFrames(n uint32) {}
+
+func renderDocCapturing() bool {
+    return false
+}
@@ -0,0 +1,160 @@
+package gome
+
+import (
+    "errors"
+    "github.com/snorredc/gome/internal/gl"
+)
+
+var ErrFramebufferIncomplete = errors.New("framebuffer is incomplete")
+
+// RenderTargetOptions controls how NewRenderTarget creates a target. The zero
+// value gives a single-sampled RGBA8 colour texture with linear filtering and
+// no depth buffer.
+type RenderTargetOptions struct {
+    Samples int    // number of samples for antialiasing; 0 for none
+    Depth   bool   // add a depth/stencil buffer (DEPTH24_STENCIL8)
+    SRGB    bool   // store the colour as SRGB8_ALPHA8
+    Filter  uint32 // filter of Texture; defaults to LINEAR
+}
+
+// RenderTarget is an offscreen framebuffer with a colour texture. If it is
+// multisampled, rendering goes to multisampled renderbuffers instead, which
+// Resolve blits to the texture.
+type RenderTarget struct {
+    Width, Height int
+    Samples       int      // the number of samples obtained; 0 if single-sampled
+    Texture       *Texture // the colour, after Resolve if multisampled
+
+    fbo     *Framebuffer
+    color   *Renderbuffer // multisampled colour
+    depth   *Renderbuffer
+    resolve *Framebuffer // with Texture attached, if multisampled
+}
+
+// NewRenderTarget creates a render target of width by height pixels. If opts
+// is nil, the default options are used. More samples than the driver
+// supports (GL_MAX_SAMPLES) are reduced to the maximum.
+func NewRenderTarget(width, height int, opts *RenderTargetOptions) (*RenderTarget, error) {
+    if opts == nil {
+        opts = &RenderTargetOptions{}
+    }
+    t := &RenderTarget{Width: width, Height: height, Samples: opts.Samples}
+    if t.Samples > 0 {
+        var max [1]int32
+        gl.GetIntegerv(gl.MAX_SAMPLES, &max[0])
+        if t.Samples > int(max[0]) {
+            t.Samples = int(max[0])
+        }
+    }
+    if t.Samples < 0 {
+        t.Samples = 0
+    }
+    format := uint32(gl.RGBA8)
+    if opts.SRGB {
+        format = gl.SRGB8_ALPHA8
+    }
+    filter := opts.Filter
+    if filter == 0 {
+        filter = gl.LINEAR
+    }
+
+    t.Texture = NewTexture()
+    t.Texture.Width, t.Texture.Height = width, height
+    BindTexture(0, gl.TEXTURE_2D, t.Texture)
+    gl.TexImage2D(gl.TEXTURE_2D, 0, int32(format), int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, int32(filter))
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int32(filter))
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+
+    t.fbo = NewFramebuffer()
+    BindFramebuffer(t.fbo)
+    if t.Samples > 0 {
+        t.color = NewRenderbuffer()
+        t.color.Bind()
+        gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, int32(t.Samples), format, int32(width), int32(height))
+        gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, t.color.ID)
+    } else {
+        gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.Texture.ID, 0)
+    }
+    if opts.Depth {
+        t.depth = NewRenderbuffer()
+        t.depth.Bind()
+        if t.Samples > 0 {
+            gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, int32(t.Samples), gl.DEPTH24_STENCIL8, int32(width), int32(height))
+        } else {
+            gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, int32(width), int32(height))
+        }
+        gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, t.depth.ID)
+    }
+    if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+        t.Delete()
+        return nil, ErrFramebufferIncomplete
+    }
+
+    if t.Samples > 0 {
+        t.resolve = NewFramebuffer()
+        BindFramebuffer(t.resolve)
+        gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.Texture.ID, 0)
+        if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+            t.Delete()
+            return nil, ErrFramebufferIncomplete
+        }
+    }
+    return t, nil
+}
+
+// Bind binds the target for rendering and sets the viewport to cover it.
+func (t *RenderTarget) Bind() {
+    BindFramebuffer(t.fbo)
+    gl.Viewport(0, 0, int32(t.Width), int32(t.Height))
+}
+
+// Resolve blits the multisampled colour to Texture, averaging the samples. It
+// has to be called after rendering and before using Texture; for a
+// single-sampled target it does nothing.
+func (t *RenderTarget) Resolve() {
+    if t.resolve == nil {
+        return
+    }
+    blitFramebuffer(t.fbo, t.resolve, 0, 0, t.Width, t.Height, 0, 0, t.Width, t.Height, gl.NEAREST)
+}
+
+// readFramebuffer returns the framebuffer Texture is attached to.
+func (t *RenderTarget) readFramebuffer() *Framebuffer {
+    if t.resolve != nil {
+        return t.resolve
+    }
+    return t.fbo
+}
+
+// Delete deletes the target with its texture and renderbuffers.
+func (t *RenderTarget) Delete() {
+    for _, r := range []*Renderbuffer{t.color, t.depth} {
+        if r != nil {
+            r.Delete()
+        }
+    }
+    for _, f := range []*Framebuffer{t.fbo, t.resolve} {
+        if f != nil {
+            f.Delete()
+        }
+    }
+    t.Texture.Delete()
+    t.color, t.depth, t.fbo, t.resolve, t.Texture = nil, nil, nil, nil, nil
+}
+
+// blitFramebuffer copies the colour of a rectangle of src to one of dst,
+// where nil is the default framebuffer. It leaves src bound.
+func blitFramebuffer(src, dst *Framebuffer, sx0, sy0, sx1, sy1, dx0, dy0, dx1, dy1 int, filter uint32) {
+    BindFramebuffer(src)
+    var id uint32
+    if dst != nil {
+        id = dst.ID
+    }
+    gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, id)
+    // the draw binding changed behind the back of the cache
+    state.fboSet = false
+    gl.BlitFramebuffer(int32(sx0), int32(sy0), int32(sx1), int32(sy1),
+        int32(dx0), int32(dy0), int32(dx1), int32(dy1), gl.COLOR_BUFFER_BIT, filter)
+}
@@ -0,0 +1,86 @@
+package scene
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "github.com/snorredc/gome/internal/gl"
+)
+
+// MeshDrawable draws a mesh with a program that has the mat4 uniforms model
+// and viewProjection, which are set to the world matrix of the node and the
+// matrix of the camera. Other uniforms are up to the application, e.g. in
+// Material.
+type MeshDrawable struct {
+    Mesh     *gome.Mesh
+    Program  *gome.Program
+    Material func(*gome.Material) // called for each submesh (see gome.Mesh.Draw); may be nil
+
+    // Box is the box around the mesh in its own coordinates, for culling.
+    // The zero box means that the mesh is never culled.
+    Box gome.AABB
+
+    program        *gome.Program // the program of the cached locations
+    model          int32
+    viewProjection int32
+}
+
+// Bounds returns Box.
+func (m *MeshDrawable) Bounds() (box gome.AABB, ok bool) {
+    return m.Box, m.Box != (gome.AABB{})
+}
+
+// Draw draws the mesh.
+func (m *MeshDrawable) Draw(c *DrawContext) {
+    if m.program != m.Program {
+        m.program = m.Program
+        m.model = m.Program.GetUniformLocation("model")
+        m.viewProjection = m.Program.GetUniformLocation("viewProjection")
+    }
+    gome.UseProgram(m.Program)
+    gl.UniformMatrix4fv(m.model, 1, false, &c.World[0])
+    gl.UniformMatrix4fv(m.viewProjection, 1, false, &c.ViewProjection[0])
+    m.Mesh.Draw(m.Material)
+}
+
+// Sprite draws a region of a texture centred on its node, in the x-y plane
+// of the node with y growing downwards, as in the world of a gome.Camera2D.
+// In a 3D scene with y growing upwards, a node scale of -1 in y turns it
+// upright. Every sprite is a draw call of its own; for many sprites, a
+// gome.SpriteBatch used directly is faster.
+type Sprite struct {
+    Region        gome.Region
+    Width, Height float32    // the size in world units; zero uses the size of Region
+    Color         gome.Color // the tint; the zero value is treated as white
+}
+
+func (s *Sprite) size() (w, h float32) {
+    w, h = s.Width, s.Height
+    if w == 0 || h == 0 {
+        w, h = s.Region.Width, s.Region.Height
+    }
+    return w, h
+}
+
+// Bounds returns the rectangle of the sprite.
+func (s *Sprite) Bounds() (box gome.AABB, ok bool) {
+    w, h := s.size()
+    return gome.AABB{Min: mgl32.Vec3{-w / 2, -h / 2, 0}, Max: mgl32.Vec3{w / 2, h / 2, 0}}, true
+}
+
+// Draw draws the sprite with the batch of the scene.
+func (s *Sprite) Draw(c *DrawContext) {
+    col := s.Color
+    if col == (gome.Color{}) {
+        col = gome.White
+    }
+    w, h := s.size()
+    // the batch disables depth testing, which the meshes of a 3D scene drawn
+    // after the sprite still need
+    depth := gl.IsEnabled(gl.DEPTH_TEST)
+    c.Batch.BeginMatrix(c.ViewProjection.Mul4(c.World))
+    c.Batch.DrawRegion(s.Region, gome.Rect{X: -w / 2, Y: -h / 2, W: w, H: h}, col)
+    c.Batch.End()
+    if depth {
+        gome.Enable(gl.DEPTH_TEST)
+    }
+}
@@ -0,0 +1,186 @@
+package scene
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+)
+
+// Node is a node of a scene graph. Its local transform, relative to its
+// parent, is made of a position, a rotation and a scale, which are changed
+// through the setters so the node knows when to recompute its matrices.
+type Node struct {
+    Name     string
+    Drawable Drawable // drawn at the world transform of the node; may be nil
+    Hidden   bool     // the node and its descendants are not drawn
+
+    parent   *Node
+    children []*Node
+
+    position mgl32.Vec3
+    rotation mgl32.Quat
+    scale    mgl32.Vec3
+
+    local      mgl32.Mat4
+    world      mgl32.Mat4
+    localDirty bool
+    worldDirty bool // set for all descendants of a node whenever it is set for the node
+}
+
+// NewNode returns a node called name with the identity transform, drawing d.
+func NewNode(name string, d Drawable) *Node {
+    return &Node{
+        Name:       name,
+        Drawable:   d,
+        rotation:   mgl32.QuatIdent(),
+        scale:      mgl32.Vec3{1, 1, 1},
+        localDirty: true,
+        worldDirty: true,
+    }
+}
+
+// Parent returns the parent of n, or nil if n is a root.
+func (n *Node) Parent() *Node {
+    return n.parent
+}
+
+// Children returns the children of n. The slice must not be modified.
+func (n *Node) Children() []*Node {
+    return n.children
+}
+
+// Add makes c a child of n, removing it from its previous parent first. It
+// returns c, for building a hierarchy.
+func (n *Node) Add(c *Node) *Node {
+    if c.parent != nil {
+        c.parent.Remove(c)
+    }
+    c.parent = n
+    n.children = append(n.children, c)
+    c.invalidateWorld()
+    return c
+}
+
+// Remove removes the child c from n.
+func (n *Node) Remove(c *Node) {
+    for i, child := range n.children {
+        if child == c {
+            n.children = append(n.children[:i], n.children[i+1:]...)
+            c.parent = nil
+            c.invalidateWorld()
+            return
+        }
+    }
+}
+
+// Detach removes n from its parent, if it has one.
+func (n *Node) Detach() {
+    if n.parent != nil {
+        n.parent.Remove(n)
+    }
+}
+
+// Position returns the position of n relative to its parent.
+func (n *Node) Position() mgl32.Vec3 {
+    return n.position
+}
+
+// SetPosition sets the position of n relative to its parent.
+func (n *Node) SetPosition(p mgl32.Vec3) {
+    n.position = p
+    n.invalidateLocal()
+}
+
+// Rotation returns the rotation of n relative to its parent.
+func (n *Node) Rotation() mgl32.Quat {
+    return n.rotation
+}
+
+// SetRotation sets the rotation of n relative to its parent.
+func (n *Node) SetRotation(q mgl32.Quat) {
+    n.rotation = q
+    n.invalidateLocal()
+}
+
+// Scale returns the scale of n.
+func (n *Node) Scale() mgl32.Vec3 {
+    return n.scale
+}
+
+// SetScale sets the scale of n along its local axes.
+func (n *Node) SetScale(s mgl32.Vec3) {
+    n.scale = s
+    n.invalidateLocal()
+}
+
+// Local returns the transformation matrix of n relative to its parent.
+func (n *Node) Local() mgl32.Mat4 {
+    if n.localDirty {
+        t := mgl32.Translate3D(n.position[0], n.position[1], n.position[2])
+        s := mgl32.Scale3D(n.scale[0], n.scale[1], n.scale[2])
+        n.local = t.Mul4(n.rotation.Mat4()).Mul4(s)
+        n.localDirty = false
+    }
+    return n.local
+}
+
+// World returns the transformation matrix of n into world coordinates. It is
+// only recomputed after the transform of n or of one of its ancestors has
+// changed.
+func (n *Node) World() mgl32.Mat4 {
+    if n.worldDirty {
+        n.world = n.Local()
+        if n.parent != nil {
+            n.world = n.parent.World().Mul4(n.world)
+        }
+        n.worldDirty = false
+    }
+    return n.world
+}
+
+// WorldPosition returns the position of n in world coordinates.
+func (n *Node) WorldPosition() mgl32.Vec3 {
+    return n.World().Col(3).Vec3()
+}
+
+// Find returns the first node called name in the subtree of n, in depth-first
+// order, or nil.
+func (n *Node) Find(name string) *Node {
+    var found *Node
+    n.Walk(func(c *Node) bool {
+        if c.Name == name {
+            found = c
+        }
+        return found == nil
+    })
+    return found
+}
+
+// Walk calls f for n and its descendants in depth-first order, parents
+// before children, until f returns false. It reports whether the whole
+// subtree was walked.
+func (n *Node) Walk(f func(*Node) bool) bool {
+    if !f(n) {
+        return false
+    }
+    for _, c := range n.children {
+        if !c.Walk(f) {
+            return false
+        }
+    }
+    return true
+}
+
+func (n *Node) invalidateLocal() {
+    n.localDirty = true
+    n.invalidateWorld()
+}
+
+func (n *Node) invalidateWorld() {
+    if n.worldDirty {
+        // so are the descendants
+        return
+    }
+    n.worldDirty = true
+    for _, c := range n.children {
+        c.invalidateWorld()
+    }
+}
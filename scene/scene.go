@@ -0,0 +1,113 @@
+/*
+Package scene provides a scene graph: a hierarchy of nodes with transforms
+relative to their parents, drawing meshes and sprites attached to them, with
+frustum culling by gome.Frustum:
+
+    s, err := scene.New()
+    if err != nil {
+        // handle error
+    }
+    car := s.Root.Add(scene.NewNode("car", &scene.MeshDrawable{
+        Mesh:    body,
+        Program: program,
+        Box:     gome.AABB{Min: mgl32.Vec3{-2, 0, -1}, Max: mgl32.Vec3{2, 1.5, 1}},
+    }))
+    wheel := car.Add(scene.NewNode("wheel", &scene.MeshDrawable{Mesh: wheelMesh, Program: program}))
+    wheel.SetPosition(mgl32.Vec3{1.5, 0.4, 1})
+
+    for app.Tick() {
+        car.SetPosition(carPosition)
+        w, h := gome.ScreenSize()
+        s.Draw(camera, float32(w), float32(h))
+    }
+
+World matrices are cached and only recomputed for the nodes whose transform,
+or that of an ancestor, changed. A Camera is a gome.Camera3D or, for 2D
+scenes, a gome.Camera2D.
+*/
+package scene
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+)
+
+// Camera provides the view-projection matrix of a viewport, as
+// gome.Camera2D and gome.Camera3D do.
+type Camera interface {
+    Matrix(width, height float32) mgl32.Mat4
+}
+
+// Drawable is something attached to a node to be drawn.
+type Drawable interface {
+    // Bounds returns the box around the drawable in the coordinates of its
+    // node. If ok is false, it is never culled.
+    Bounds() (box gome.AABB, ok bool)
+    // Draw draws the drawable.
+    Draw(c *DrawContext)
+}
+
+// DrawContext is passed to Drawable.Draw.
+type DrawContext struct {
+    ViewProjection mgl32.Mat4        // the matrix of the camera
+    World          mgl32.Mat4        // the world matrix of the node drawn
+    Node           *Node             // the node drawn
+    Batch          *gome.SpriteBatch // shared by the drawables of the scene
+}
+
+// Scene is a scene graph with a root node.
+type Scene struct {
+    Root *Node
+
+    Drawn  int // the number of drawables drawn by the last Draw
+    Culled int // the number of drawables culled by the last Draw
+
+    batch *gome.SpriteBatch
+}
+
+// New returns a scene with an empty root node, creating the sprite batch
+// used for Sprites.
+func New() (*Scene, error) {
+    batch, err := gome.NewSpriteBatch()
+    if err != nil {
+        return nil, err
+    }
+    return &Scene{Root: NewNode("root", nil), batch: batch}, nil
+}
+
+// Draw draws the visible nodes of the scene with cam for a viewport of the
+// given size, parents before children. Drawables entirely outside the view
+// of the camera are skipped.
+func (s *Scene) Draw(cam Camera, width, height float32) {
+    vp := cam.Matrix(width, height)
+    f := gome.NewFrustum(vp)
+    c := &DrawContext{ViewProjection: vp, Batch: s.batch}
+    s.Drawn, s.Culled = 0, 0
+    s.draw(s.Root, c, &f)
+}
+
+func (s *Scene) draw(n *Node, c *DrawContext, f *gome.Frustum) {
+    if n.Hidden {
+        return
+    }
+    if d := n.Drawable; d != nil {
+        world := n.World()
+        box, ok := d.Bounds()
+        if ok && !f.IntersectsAABB(box.Transform(world)) {
+            s.Culled++
+        } else {
+            c.World, c.Node = world, n
+            d.Draw(c)
+            s.Drawn++
+        }
+    }
+    for _, child := range n.children {
+        s.draw(child, c, f)
+    }
+}
+
+// Delete deletes the sprite batch of the scene. The drawables are not
+// deleted.
+func (s *Scene) Delete() {
+    s.batch.Delete()
+}
@@ -0,0 +1,114 @@
+package gome
+
+import (
+    "sort"
+    "time"
+)
+
+// Timer is a function scheduled with After or Every.
+type Timer struct {
+    at       time.Duration // due time on the scheduler clock
+    interval time.Duration
+    repeat   bool // scheduled with Every
+    f        func()
+    stopped  bool
+}
+
+// Stop cancels the timer. It does nothing if the timer has run already or has
+// been stopped.
+func (t *Timer) Stop() {
+    t.stopped = true
+}
+
+var scheduler struct {
+    now    time.Duration // the frame times summed up, without idle pauses
+    timers []*Timer
+    next   []func()
+
+    // Tick pauses after measuring the frame time, so a pause is part of the
+    // frame time of the Tick after it
+    paused     time.Duration // time this Tick spent in an idle pause
+    lastPaused time.Duration // that of the last Tick, within the frame time
+}
+
+// After schedules f to run once, d after the current frame, on the main
+// thread during Tick. The time is that of the main loop, the sum of the
+// frame times (see FrameTime), so timers do not advance while the loop is
+// paused in the background (see SetIdleBehavior), they follow the frame
+// times of a replay (see ReplayInput), they speed up and slow down with
+// the time scale (see SetTimeScale) and they stop while the fixed timesteps
+// are paused (see Pause), advancing by a single frame time in each frame
+// stepped with StepFrame.
+func After(d time.Duration, f func()) *Timer {
+    t := &Timer{at: scheduler.now + d, f: f}
+    scheduler.timers = append(scheduler.timers, t)
+    return t
+}
+
+// Every schedules f to run every d, starting d after the current frame, like
+// After. If a frame takes longer than d, the missed runs are skipped rather
+// than made up in a burst. A d of 0 or less runs f at every Tick.
+func Every(d time.Duration, f func()) *Timer {
+    if d < 0 {
+        d = 0
+    }
+    t := &Timer{at: scheduler.now + d, interval: d, repeat: true, f: f}
+    scheduler.timers = append(scheduler.timers, t)
+    return t
+}
+
+// NextFrame schedules f to run once during the next Tick.
+func NextFrame(f func()) {
+    scheduler.next = append(scheduler.next, f)
+}
+
+// runTimers advances the scheduler clock by the last frame and runs the
+// functions that are due, timers in the order of their due times. It is
+// called by Tick after the events have been polled and the pause updated.
+// Functions scheduled while it runs are due at the next Tick at the
+// earliest.
+func runTimers() {
+    s := &scheduler
+    step := UnscaledDelta() - s.lastPaused
+    if step < 0 || pause.paused && !pause.stepping {
+        step = 0
+    }
+    s.now += time.Duration(float64(step) * timeScale)
+    s.lastPaused, s.paused = s.paused, 0
+
+    next := s.next
+    s.next = nil
+    for _, f := range next {
+        f()
+    }
+
+    var due []*Timer
+    pending := s.timers[:0]
+    for _, t := range s.timers {
+        switch {
+        case t.stopped:
+        case t.at <= s.now:
+            due = append(due, t)
+        default:
+            pending = append(pending, t)
+        }
+    }
+    for i := len(pending); i < len(s.timers); i++ {
+        s.timers[i] = nil
+    }
+    s.timers = pending
+    sort.SliceStable(due, func(i, j int) bool { return due[i].at < due[j].at })
+    for _, t := range due {
+        if t.stopped {
+            continue
+        }
+        t.f()
+        if t.repeat && !t.stopped {
+            t.at += t.interval
+            if t.at <= s.now {
+                t.at = s.now + t.interval
+            }
+            s.timers = append(s.timers, t)
+        }
+    }
+}
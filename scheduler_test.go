@@ -0,0 +1,82 @@
+// +build gomemock
+
+package gome
+
+import (
+    "testing"
+    "time"
+)
+
+// tickFor runs a Tick taking at least d.
+func tickFor(t *testing.T, a *App, d time.Duration) {
+    t.Helper()
+    time.Sleep(d)
+    if !a.Tick() {
+        t.Fatal(a.GetError())
+    }
+}
+
+func TestMockTimersPaused(t *testing.T) {
+    a := initMock(t, Config{})
+    defer Resume()
+    tickFor(t, a, 0)
+
+    once, every, next := 0, 0, 0
+    After(time.Millisecond, func() { once++ })
+    ticker := Every(time.Millisecond, func() { every++ })
+    defer ticker.Stop()
+    Pause()
+    NextFrame(func() { next++ })
+    for i := 0; i < 3; i++ {
+        tickFor(t, a, 2*time.Millisecond)
+    }
+    if once != 0 || every != 0 {
+        t.Errorf("timers ran %d and %d times while paused", once, every)
+    }
+    if next != 1 {
+        t.Errorf("NextFrame function ran %d times while paused, want 1", next)
+    }
+
+    StepFrame()
+    tickFor(t, a, 2*time.Millisecond)
+    if once != 1 || every != 1 {
+        t.Errorf("timers ran %d and %d times in a stepped frame, want 1 and 1", once, every)
+    }
+    tickFor(t, a, 2*time.Millisecond)
+    if every != 1 {
+        t.Errorf("repeating timer ran %d times after the step, want 1", every)
+    }
+
+    Resume()
+    tickFor(t, a, 2*time.Millisecond)
+    if every != 2 {
+        t.Errorf("repeating timer ran %d times after Resume, want 2", every)
+    }
+}
+
+func TestMockTimersMinimized(t *testing.T) {
+    a := initMock(t, Config{})
+    SetIdleBehavior(PauseWhenMinimized)
+    defer SetIdleBehavior(0)
+    tickFor(t, a, 0)
+
+    fired := false
+    timer := After(100*time.Millisecond, func() { fired = true })
+    defer timer.Stop()
+    MockMinimize(true)
+    tickFor(t, a, 0)
+    go func() {
+        time.Sleep(300 * time.Millisecond)
+        Do(func() { MockMinimize(false) })
+    }()
+    // waits while minimized, then runs a few quick frames
+    for i := 0; i < 4; i++ {
+        tickFor(t, a, 0)
+    }
+    if Minimized() {
+        t.Fatal("still minimized")
+    }
+    if fired {
+        t.Error("timer fired after a pause while minimized")
+    }
+}
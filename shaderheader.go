@@ -0,0 +1,52 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/gl"
+    "strings"
+)
+
+// GLSL ES needs a version of its own and default precisions in fragment
+// shaders, while desktop GLSL 1.50 and later is otherwise close enough that
+// the shaders of gome and most applications compile on both. The shader
+// helpers therefore write shaders for the desktop and let adaptShader fit
+// them to the context.
+
+// esFragmentPrecision is inserted after the version of fragment shaders on
+// OpenGL ES, which has no default float precision there.
+const esFragmentPrecision = "precision highp float;\nprecision highp int;\nprecision highp sampler2D;\nprecision highp sampler2DShadow;\nprecision highp sampler2DArray;\nprecision highp sampler3D;\n"
+
+// adaptShader fits the #version line of a shader of type typ to the context of
+// the main window: on OpenGL ES, a desktop version is replaced by
+// "#version 300 es", or "310 es" for compute shaders, with default precisions
+// for fragment shaders, and a shader without a version gets one for the
+// context. Shaders already written for the context are left alone.
+func adaptShader(typ uint32, source string) string {
+    version, body := "", source
+    if strings.HasPrefix(strings.TrimLeft(source, " \t\r\n"), "#version") {
+        source = strings.TrimLeft(source, " \t\r\n")
+        if i := strings.IndexByte(source, '\n'); i >= 0 {
+            version, body = source[:i], source[i+1:]
+        } else {
+            version, body = source, ""
+        }
+    }
+    es := obtainedContext.Profile == ESProfile
+    switch {
+    case es && strings.HasSuffix(strings.TrimSpace(version), " es"):
+        return source
+    case es:
+        header := "#version 300 es\n"
+        if typ == gl.COMPUTE_SHADER {
+            header = "#version 310 es\n"
+        }
+        if typ == gl.FRAGMENT_SHADER {
+            header += esFragmentPrecision
+        }
+        return header + body
+    case version == "" && typ == gl.COMPUTE_SHADER:
+        return "#version 430\n" + body
+    case version == "":
+        return "#version 150\n" + body
+    }
+    return source
+}
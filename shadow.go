@@ -0,0 +1,177 @@
+package gome
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome/internal/gl"
+)
+
+const shadowVertexShader = `#version 150
+uniform mat4 matrix;
+in vec3 position;
+void main() {
+    gl_Position = matrix * vec4(position, 1.0);
+}
+`
+
+const shadowFragmentShader = `#version 150
+void main() {
+}
+`
+
+// ShadowGLSL is a GLSL function for the fragment shader of the main pass
+// that looks up a ShadowMap. It takes the shadow map, bound as a
+// sampler2DShadow, and the position of the fragment in light space, the
+// world position multiplied by ShadowMap.LightMatrix, and returns how much
+// of the light reaches the fragment, from 0 in shadow to 1 lit, with the
+// four nearest texels filtered by the hardware. Fragments outside the map
+// are lit.
+const ShadowGLSL = `
+float shadowFactor(sampler2DShadow shadowMap, vec4 lightSpace) {
+    vec3 p = lightSpace.xyz / lightSpace.w * 0.5 + 0.5;
+    if (p.z > 1.0 || p.x < 0.0 || p.x > 1.0 || p.y < 0.0 || p.y > 1.0) {
+        return 1.0;
+    }
+    return texture(shadowMap, p);
+}
+`
+
+// ShadowMap renders the depth of a scene as seen from a directional light,
+// for shadows in the main pass:
+//
+//     shadow.SetLight(sunDirection, scene.Center, scene.Radius)
+//     shadow.Render(func(model func(mgl32.Mat4)) {
+//         for _, o := range objects {
+//             model(o.Transform)
+//             o.Mesh.Draw(nil)
+//         }
+//     })
+//     // main pass
+//     shadow.Bind(1)
+//     light := shadow.LightMatrix()
+//     gl.UniformMatrix4fv(lightMatrixLoc, 1, false, &light[0])
+//
+// where the fragment shader includes ShadowGLSL and calls shadowFactor with
+// its shadow map uniform set to unit 1.
+type ShadowMap struct {
+    Size    int      // width and height of Texture in texels
+    Texture *Texture // the depth texture, set up for comparisons
+
+    // Bias is the polygon offset of the depth pass (see glPolygonOffset),
+    // which keeps lit surfaces from shadowing themselves ("shadow acne").
+    // NewShadowMap sets it to 2, 4.
+    Bias [2]float32
+
+    fbo     *Framebuffer
+    program *Program
+    matrix  int32
+    light   mgl32.Mat4
+}
+
+// NewShadowMap creates a shadow map of size by size texels. Larger maps give
+// sharper shadows; 2048 is a common size.
+func NewShadowMap(size int) (*ShadowMap, error) {
+    program, err := NewProgram(shadowVertexShader, shadowFragmentShader)
+    if err != nil {
+        return nil, err
+    }
+    s := &ShadowMap{
+        Size:    size,
+        Bias:    [2]float32{2, 4},
+        program: program,
+        matrix:  program.GetUniformLocation("matrix"),
+        light:   mgl32.Ident4(),
+    }
+
+    s.Texture = NewTexture()
+    s.Texture.Width, s.Texture.Height = size, size
+    BindTexture(0, gl.TEXTURE_2D, s.Texture)
+    gl.TexImage2D(gl.TEXTURE_2D, 0, gl.DEPTH_COMPONENT24, int32(size), int32(size), 0,
+        gl.DEPTH_COMPONENT, gl.UNSIGNED_INT, nil)
+    // linear filtering of a comparison sampler averages the results of the
+    // four nearest texels, which softens the edges of the shadows
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_COMPARE_MODE, gl.COMPARE_REF_TO_TEXTURE)
+    gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_COMPARE_FUNC, gl.LEQUAL)
+
+    s.fbo = NewFramebuffer()
+    BindFramebuffer(s.fbo)
+    gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, s.Texture.ID, 0)
+    // without a colour attachment, OpenGL 3.2 needs the draw and read buffers
+    // set to none; glDrawBuffers is used as it also exists on OpenGL ES
+    none := uint32(gl.NONE)
+    gl.DrawBuffers(1, &none)
+    gl.ReadBuffer(gl.NONE)
+    status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+    BindScreen()
+    if status != gl.FRAMEBUFFER_COMPLETE {
+        s.Delete()
+        return nil, ErrFramebufferIncomplete
+    }
+    return s, nil
+}
+
+// SetLight sets up the light-space matrix for a directional light shining
+// in direction dir onto the sphere around center with the given radius, which
+// should enclose everything that casts or receives shadows. A tighter sphere
+// gives sharper shadows.
+func (s *ShadowMap) SetLight(dir, center mgl32.Vec3, radius float32) {
+    dir = dir.Normalize()
+    up := mgl32.Vec3{0, 1, 0}
+    if d := dir.Dot(up); d > 0.99 || d < -0.99 {
+        // looking straight up or down
+        up = mgl32.Vec3{0, 0, 1}
+    }
+    eye := center.Sub(dir.Mul(2 * radius))
+    view := mgl32.LookAtV(eye, center, up)
+    proj := mgl32.Ortho(-radius, radius, -radius, radius, radius, 3*radius)
+    s.light = proj.Mul4(view)
+}
+
+// SetLightMatrix sets the light-space matrix directly, e.g. for a spot light
+// with a perspective projection.
+func (s *ShadowMap) SetLightMatrix(m mgl32.Mat4) {
+    s.light = m
+}
+
+// LightMatrix returns the matrix transforming world coordinates into the clip
+// coordinates of the light, for the main pass to look up the shadow map with
+// (see ShadowGLSL).
+func (s *ShadowMap) LightMatrix() mgl32.Mat4 {
+    return s.light
+}
+
+// Render runs the depth pass: it clears the shadow map and calls draw, which
+// draws the shadow casters with the depth program of the shadow map bound,
+// calling model with the model matrix of each mesh before drawing it.
+// Afterwards the screen is bound again (see BindScreen), with depth testing
+// left enabled.
+func (s *ShadowMap) Render(draw func(model func(m mgl32.Mat4))) {
+    BindFramebuffer(s.fbo)
+    gl.Viewport(0, 0, int32(s.Size), int32(s.Size))
+    Enable(gl.DEPTH_TEST)
+    gl.Clear(gl.DEPTH_BUFFER_BIT)
+    Enable(gl.POLYGON_OFFSET_FILL)
+    gl.PolygonOffset(s.Bias[0], s.Bias[1])
+    UseProgram(s.program)
+    draw(func(m mgl32.Mat4) {
+        mvp := s.light.Mul4(m)
+        gl.UniformMatrix4fv(s.matrix, 1, false, &mvp[0])
+    })
+    Disable(gl.POLYGON_OFFSET_FILL)
+    BindScreen()
+}
+
+// Bind binds the shadow map to the texture unit for the main pass.
+func (s *ShadowMap) Bind(unit int) {
+    BindTexture(unit, gl.TEXTURE_2D, s.Texture)
+}
+
+// Delete deletes the texture, framebuffer and program of the shadow map.
+func (s *ShadowMap) Delete() {
+    s.Texture.Delete()
+    s.fbo.Delete()
+    s.program.Delete()
+}
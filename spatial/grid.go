@@ -0,0 +1,260 @@
+package spatial
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "math"
+)
+
+// Grid is a uniform grid of square cells, each listing the rectangles that
+// overlap it. Only cells in use take memory, so the world is unbounded. The
+// cell size should be around the size of typical objects: much smaller
+// cells make large objects span many cells, much larger ones put many
+// objects into each cell.
+type Grid struct {
+    cellSize float32
+    cells    map[cellKey][]int
+    items    map[int]*gridItem
+    stamp    uint32 // marks the items already seen by a query
+
+    // the range of cells ever used, bounding ray walks
+    min, max cellKey
+    used     bool
+}
+
+type cellKey struct {
+    x, y int32
+}
+
+type gridItem struct {
+    rect     gome.Rect
+    min, max cellKey
+    stamp    uint32
+}
+
+// NewGrid returns an empty grid with the given cell size.
+func NewGrid(cellSize float32) *Grid {
+    return &Grid{
+        cellSize: cellSize,
+        cells:    map[cellKey][]int{},
+        items:    map[int]*gridItem{},
+    }
+}
+
+func (g *Grid) cell(x, y float32) cellKey {
+    return cellKey{
+        int32(math.Floor(float64(x / g.cellSize))),
+        int32(math.Floor(float64(y / g.cellSize))),
+    }
+}
+
+// cellRange returns the cells overlapped by r.
+func (g *Grid) cellRange(r gome.Rect) (min, max cellKey) {
+    return g.cell(r.X, r.Y), g.cell(r.X+r.W, r.Y+r.H)
+}
+
+// Insert implements Index.
+func (g *Grid) Insert(id int, r gome.Rect) {
+    it := &gridItem{rect: r}
+    it.min, it.max = g.cellRange(r)
+    g.items[id] = it
+    for y := it.min.y; y <= it.max.y; y++ {
+        for x := it.min.x; x <= it.max.x; x++ {
+            k := cellKey{x, y}
+            g.cells[k] = append(g.cells[k], id)
+        }
+    }
+    if !g.used {
+        g.min, g.max, g.used = it.min, it.max, true
+        return
+    }
+    g.min = cellKey{minInt32(g.min.x, it.min.x), minInt32(g.min.y, it.min.y)}
+    g.max = cellKey{maxInt32(g.max.x, it.max.x), maxInt32(g.max.y, it.max.y)}
+}
+
+// Remove implements Index.
+func (g *Grid) Remove(id int) {
+    it, ok := g.items[id]
+    if !ok {
+        return
+    }
+    delete(g.items, id)
+    g.unlink(id, it)
+}
+
+// unlink removes id from the cells of it.
+func (g *Grid) unlink(id int, it *gridItem) {
+    for y := it.min.y; y <= it.max.y; y++ {
+        for x := it.min.x; x <= it.max.x; x++ {
+            k := cellKey{x, y}
+            ids := g.cells[k]
+            for i, other := range ids {
+                if other == id {
+                    ids[i] = ids[len(ids)-1]
+                    ids = ids[:len(ids)-1]
+                    break
+                }
+            }
+            if len(ids) == 0 {
+                delete(g.cells, k)
+            } else {
+                g.cells[k] = ids
+            }
+        }
+    }
+}
+
+// Move implements Index. Moving within the same cells only updates the
+// rectangle.
+func (g *Grid) Move(id int, r gome.Rect) {
+    it, ok := g.items[id]
+    if ok {
+        if min, max := g.cellRange(r); min == it.min && max == it.max {
+            it.rect = r
+            return
+        }
+        g.Remove(id)
+    }
+    g.Insert(id, r)
+}
+
+// Bounds implements Index.
+func (g *Grid) Bounds(id int) (gome.Rect, bool) {
+    it, ok := g.items[id]
+    if !ok {
+        return gome.Rect{}, false
+    }
+    return it.rect, true
+}
+
+// Len implements Index.
+func (g *Grid) Len() int {
+    return len(g.items)
+}
+
+// Query implements Index.
+func (g *Grid) Query(r gome.Rect, dst []int) []int {
+    if !g.used {
+        return dst
+    }
+    min, max := g.cellRange(r)
+    // cells outside the used range are empty
+    min = cellKey{maxInt32(min.x, g.min.x), maxInt32(min.y, g.min.y)}
+    max = cellKey{minInt32(max.x, g.max.x), minInt32(max.y, g.max.y)}
+    g.stamp++
+    for y := min.y; y <= max.y; y++ {
+        for x := min.x; x <= max.x; x++ {
+            for _, id := range g.cells[cellKey{x, y}] {
+                it := g.items[id]
+                if it.stamp == g.stamp {
+                    continue
+                }
+                it.stamp = g.stamp
+                if it.rect.Intersects(r) {
+                    dst = append(dst, id)
+                }
+            }
+        }
+    }
+    return dst
+}
+
+// Raycast implements Index. It walks the cells along the ray, within the
+// range of cells in use.
+func (g *Grid) Raycast(origin, dir mgl32.Vec2, maxDist float32, dst []Hit) []Hit {
+    if !g.used {
+        return dst
+    }
+    inv, maxDist := inverse(dir, maxDist)
+    s := g.cellSize
+    area := gome.Rect{
+        X: float32(g.min.x) * s,
+        Y: float32(g.min.y) * s,
+        W: float32(g.max.x-g.min.x+1) * s,
+        H: float32(g.max.y-g.min.y+1) * s,
+    }
+    enter, exit, ok := rayRect(origin, inv, maxDist, area)
+    if !ok {
+        return dst
+    }
+    p := origin.Add(dir.Mul(enter))
+    c := g.cell(p[0], p[1])
+    c = cellKey{clampInt32(c.x, g.min.x, g.max.x), clampInt32(c.y, g.min.y, g.max.y)}
+
+    // the distances at which the ray crosses the next cell border on each
+    // axis, and between borders
+    var step [2]int32
+    var next, delta [2]float32
+    cell := [2]int32{c.x, c.y}
+    for i := 0; i < 2; i++ {
+        switch {
+        case dir[i] > 0:
+            step[i] = 1
+            next[i] = (float32(cell[i]+1)*s - origin[i]) * inv[i]
+        case dir[i] < 0:
+            step[i] = -1
+            next[i] = (float32(cell[i])*s - origin[i]) * inv[i]
+        default:
+            next[i] = float32(math.Inf(1))
+        }
+        delta[i] = s * float32(math.Abs(float64(inv[i])))
+    }
+
+    start := len(dst)
+    g.stamp++
+    for {
+        for _, id := range g.cells[cellKey{cell[0], cell[1]}] {
+            it := g.items[id]
+            if it.stamp == g.stamp {
+                continue
+            }
+            it.stamp = g.stamp
+            if t, _, ok := rayRect(origin, inv, maxDist, it.rect); ok {
+                dst = append(dst, Hit{id, t})
+            }
+        }
+        i := 0
+        if next[1] < next[0] {
+            i = 1
+        }
+        if next[i] > exit {
+            break
+        }
+        cell[i] += step[i]
+        next[i] += delta[i]
+    }
+    sortHits(dst[start:])
+    return dst
+}
+
+// Pairs implements Index. Only rectangles sharing a cell are compared.
+func (g *Grid) Pairs(dst []Pair) []Pair {
+    var found []int
+    for id, it := range g.items {
+        found = g.Query(it.rect, found[:0])
+        for _, other := range found {
+            if id < other {
+                dst = append(dst, Pair{id, other})
+            }
+        }
+    }
+    return dst
+}
+
+func minInt32(a, b int32) int32 {
+    if a < b {
+        return a
+    }
+    return b
+}
+
+func maxInt32(a, b int32) int32 {
+    if a > b {
+        return a
+    }
+    return b
+}
+
+func clampInt32(v, min, max int32) int32 {
+    return maxInt32(min, minInt32(v, max))
+}
@@ -0,0 +1,202 @@
+package spatial
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+)
+
+// Quadtree is a loose quadtree: every node covers twice the size of its
+// quarter of the parent, so a rectangle is stored in the deepest node it
+// fits by its size alone, wherever it lies within that node, and moving
+// objects rarely have to change nodes. Rectangles outside the bounds of the
+// tree are kept in the root, so the world can extend beyond the bounds at
+// the cost of slower queries there.
+type Quadtree struct {
+    root     *quadNode
+    maxDepth int
+    items    map[int]*quadItem
+}
+
+type quadItem struct {
+    rect gome.Rect
+    node *quadNode
+}
+
+type quadNode struct {
+    bounds   gome.Rect // the quarter of the parent, before loosening
+    depth    int
+    parent   *quadNode
+    children *[4]quadNode // nil until needed
+    items    []int
+    count    int // the number of items in the subtree
+}
+
+// NewQuadtree returns an empty quadtree covering bounds, with nodes down to
+// maxDepth levels below the root; 0 gives 8 levels.
+func NewQuadtree(bounds gome.Rect, maxDepth int) *Quadtree {
+    if maxDepth <= 0 {
+        maxDepth = 8
+    }
+    return &Quadtree{
+        root:     &quadNode{bounds: bounds},
+        maxDepth: maxDepth,
+        items:    map[int]*quadItem{},
+    }
+}
+
+// loose returns the area covered by the rectangles stored in n.
+func (n *quadNode) loose() gome.Rect {
+    b := n.bounds
+    return gome.Rect{X: b.X - b.W/2, Y: b.Y - b.H/2, W: 2 * b.W, H: 2 * b.H}
+}
+
+// Insert implements Index.
+func (q *Quadtree) Insert(id int, r gome.Rect) {
+    n := q.root
+    cx, cy := r.X+r.W/2, r.Y+r.H/2
+    if n.bounds.Contains(cx, cy) {
+        for n.depth < q.maxDepth {
+            hw, hh := n.bounds.W/2, n.bounds.H/2
+            if r.W > hw || r.H > hh {
+                break
+            }
+            if n.children == nil {
+                n.split()
+            }
+            i := 0
+            if cx >= n.bounds.X+hw {
+                i |= 1
+            }
+            if cy >= n.bounds.Y+hh {
+                i |= 2
+            }
+            n = &n.children[i]
+        }
+    }
+    n.items = append(n.items, id)
+    for p := n; p != nil; p = p.parent {
+        p.count++
+    }
+    q.items[id] = &quadItem{r, n}
+}
+
+func (n *quadNode) split() {
+    hw, hh := n.bounds.W/2, n.bounds.H/2
+    n.children = &[4]quadNode{}
+    for i := range n.children {
+        c := &n.children[i]
+        c.bounds = gome.Rect{X: n.bounds.X + float32(i&1)*hw, Y: n.bounds.Y + float32(i>>1)*hh, W: hw, H: hh}
+        c.depth = n.depth + 1
+        c.parent = n
+    }
+}
+
+// Remove implements Index.
+func (q *Quadtree) Remove(id int) {
+    it, ok := q.items[id]
+    if !ok {
+        return
+    }
+    delete(q.items, id)
+    n := it.node
+    for i, other := range n.items {
+        if other == id {
+            last := len(n.items) - 1
+            n.items[i] = n.items[last]
+            n.items = n.items[:last]
+            break
+        }
+    }
+    for p := n; p != nil; p = p.parent {
+        p.count--
+        if p.count == 0 {
+            // an empty subtree is dropped
+            p.children = nil
+        }
+    }
+}
+
+// Move implements Index.
+func (q *Quadtree) Move(id int, r gome.Rect) {
+    q.Remove(id)
+    q.Insert(id, r)
+}
+
+// Bounds implements Index.
+func (q *Quadtree) Bounds(id int) (gome.Rect, bool) {
+    it, ok := q.items[id]
+    if !ok {
+        return gome.Rect{}, false
+    }
+    return it.rect, true
+}
+
+// Len implements Index.
+func (q *Quadtree) Len() int {
+    return len(q.items)
+}
+
+// Query implements Index.
+func (q *Quadtree) Query(r gome.Rect, dst []int) []int {
+    return q.query(q.root, r, dst)
+}
+
+func (q *Quadtree) query(n *quadNode, r gome.Rect, dst []int) []int {
+    if n.count == 0 || (n != q.root && !n.loose().Intersects(r)) {
+        return dst
+    }
+    for _, id := range n.items {
+        if q.items[id].rect.Intersects(r) {
+            dst = append(dst, id)
+        }
+    }
+    if n.children != nil {
+        for i := range n.children {
+            dst = q.query(&n.children[i], r, dst)
+        }
+    }
+    return dst
+}
+
+// Raycast implements Index.
+func (q *Quadtree) Raycast(origin, dir mgl32.Vec2, maxDist float32, dst []Hit) []Hit {
+    inv, maxDist := inverse(dir, maxDist)
+    start := len(dst)
+    dst = q.raycast(q.root, origin, inv, maxDist, dst)
+    sortHits(dst[start:])
+    return dst
+}
+
+func (q *Quadtree) raycast(n *quadNode, origin, inv mgl32.Vec2, maxDist float32, dst []Hit) []Hit {
+    if n.count == 0 {
+        return dst
+    }
+    if _, _, ok := rayRect(origin, inv, maxDist, n.loose()); !ok && n != q.root {
+        return dst
+    }
+    for _, id := range n.items {
+        if t, _, ok := rayRect(origin, inv, maxDist, q.items[id].rect); ok {
+            dst = append(dst, Hit{id, t})
+        }
+    }
+    if n.children != nil {
+        for i := range n.children {
+            dst = q.raycast(&n.children[i], origin, inv, maxDist, dst)
+        }
+    }
+    return dst
+}
+
+// Pairs implements Index.
+func (q *Quadtree) Pairs(dst []Pair) []Pair {
+    var found []int
+    for id, it := range q.items {
+        found = q.Query(it.rect, found[:0])
+        for _, other := range found {
+            if id < other {
+                dst = append(dst, Pair{id, other})
+            }
+        }
+    }
+    return dst
+}
@@ -0,0 +1,114 @@
+/*
+Package spatial indexes rectangles by user IDs for fast range and ray
+queries, as a broadphase for collision detection and for culling and picking
+in 2D. There are two indexes with the same interface: a Grid of uniform
+cells, best for objects of similar size spread evenly, and a loose Quadtree,
+which adapts to objects of very different sizes and to crowded areas.
+
+    index := spatial.NewGrid(64)
+    for id, e := range entities {
+        index.Insert(id, e.Bounds())
+    }
+
+    for app.Tick() {
+        for id, e := range entities {
+            e.Update()
+            index.Move(id, e.Bounds())
+        }
+        pairs = index.Pairs(pairs[:0])
+        for _, p := range pairs {
+            collide(entities[p.A], entities[p.B])
+        }
+    }
+
+The query functions append to a slice passed in, so a slice reused across
+frames saves the allocations.
+*/
+package spatial
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "math"
+    "sort"
+)
+
+// Index is a set of rectangles with IDs that can be queried by area.
+type Index interface {
+    // Insert adds the rectangle r with the given ID, which must not be in
+    // the index yet.
+    Insert(id int, r gome.Rect)
+    // Remove removes the rectangle with the given ID, if any.
+    Remove(id int)
+    // Move changes the rectangle with the given ID to r.
+    Move(id int, r gome.Rect)
+    // Bounds returns the rectangle with the given ID.
+    Bounds(id int) (r gome.Rect, ok bool)
+    // Len returns the number of rectangles in the index.
+    Len() int
+    // Query appends the IDs of the rectangles that overlap r to dst, in no
+    // particular order, and returns the extended slice.
+    Query(r gome.Rect, dst []int) []int
+    // Raycast appends the rectangles hit by the ray from origin in the unit
+    // direction dir, up to maxDist, to dst, nearest first. A maxDist of 0 or
+    // less means no limit.
+    Raycast(origin, dir mgl32.Vec2, maxDist float32, dst []Hit) []Hit
+    // Pairs appends the pairs of overlapping rectangles to dst, each pair
+    // once with A < B, and returns the extended slice.
+    Pairs(dst []Pair) []Pair
+}
+
+// Hit is a rectangle hit by a ray.
+type Hit struct {
+    ID int
+    T  float32 // the distance along the ray; 0 if the origin is inside
+}
+
+// Pair is a pair of IDs of overlapping rectangles.
+type Pair struct {
+    A, B int
+}
+
+// rayRect returns the distances along the ray from origin with the inverse
+// direction inv at which it enters and leaves r, if it hits r within
+// maxDist; near is 0 if origin is inside r.
+func rayRect(origin, inv mgl32.Vec2, maxDist float32, r gome.Rect) (near, far float32, ok bool) {
+    near, far = 0, maxDist
+    lo, hi := [2]float32{r.X, r.Y}, [2]float32{r.X + r.W, r.Y + r.H}
+    for i := 0; i < 2; i++ {
+        if math.IsInf(float64(inv[i]), 0) {
+            // parallel to this axis
+            if origin[i] < lo[i] || origin[i] > hi[i] {
+                return 0, 0, false
+            }
+            continue
+        }
+        t0, t1 := (lo[i]-origin[i])*inv[i], (hi[i]-origin[i])*inv[i]
+        if t0 > t1 {
+            t0, t1 = t1, t0
+        }
+        if t0 > near {
+            near = t0
+        }
+        if t1 < far {
+            far = t1
+        }
+        if near > far {
+            return 0, 0, false
+        }
+    }
+    return near, far, true
+}
+
+// inverse returns the componentwise inverse of a direction and the maximum
+// distance to use for a ray.
+func inverse(dir mgl32.Vec2, maxDist float32) (mgl32.Vec2, float32) {
+    if maxDist <= 0 {
+        maxDist = float32(math.Inf(1))
+    }
+    return mgl32.Vec2{1 / dir[0], 1 / dir[1]}, maxDist
+}
+
+func sortHits(hits []Hit) {
+    sort.Slice(hits, func(i, j int) bool { return hits[i].T < hits[j].T })
+}
@@ -0,0 +1,216 @@
+package spatial
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "math"
+    "math/rand"
+    "reflect"
+    "sort"
+    "testing"
+)
+
+// indexes returns empty indexes of each kind, with a quadtree smaller than
+// the area the tests use and a grid with cells smaller than some objects.
+func indexes() map[string]Index {
+    return map[string]Index{
+        "grid":     NewGrid(16),
+        "quadtree": NewQuadtree(gome.Rect{W: 256, H: 256}, 5),
+    }
+}
+
+// bruteForce is an index testing every rectangle.
+type bruteForce map[int]gome.Rect
+
+func (b bruteForce) query(r gome.Rect) []int {
+    ids := []int{}
+    for id, s := range b {
+        if s.Intersects(r) {
+            ids = append(ids, id)
+        }
+    }
+    sort.Ints(ids)
+    return ids
+}
+
+func (b bruteForce) raycast(origin, dir mgl32.Vec2, maxDist float32) map[int]float32 {
+    inv, maxDist := inverse(dir, maxDist)
+    hits := map[int]float32{}
+    for id, s := range b {
+        if t, _, ok := rayRect(origin, inv, maxDist, s); ok {
+            hits[id] = t
+        }
+    }
+    return hits
+}
+
+func (b bruteForce) pairs() []Pair {
+    pairs := []Pair{}
+    for id, r := range b {
+        for other, s := range b {
+            if id < other && r.Intersects(s) {
+                pairs = append(pairs, Pair{id, other})
+            }
+        }
+    }
+    sortPairs(pairs)
+    return pairs
+}
+
+func sortPairs(pairs []Pair) {
+    sort.Slice(pairs, func(i, j int) bool {
+        if pairs[i].A != pairs[j].A {
+            return pairs[i].A < pairs[j].A
+        }
+        return pairs[i].B < pairs[j].B
+    })
+}
+
+// randomRect returns a rectangle in and around the area from -64 to 320,
+// mostly small but some spanning many cells and nodes.
+func randomRect(rnd *rand.Rand) gome.Rect {
+    size := float32(1 + rnd.Intn(24))
+    if rnd.Intn(10) == 0 {
+        size = float32(32 + rnd.Intn(160))
+    }
+    return gome.Rect{
+        X: float32(rnd.Intn(384) - 64),
+        Y: float32(rnd.Intn(384) - 64),
+        W: size,
+        H: size * float32(1+rnd.Intn(3)) / 2,
+    }
+}
+
+// check compares the queries of index with a brute-force scan of want.
+func check(t *testing.T, rnd *rand.Rand, index Index, want bruteForce) {
+    t.Helper()
+    if index.Len() != len(want) {
+        t.Errorf("length %d, want %d", index.Len(), len(want))
+    }
+    for id := 0; id < 300; id++ {
+        r, ok := index.Bounds(id)
+        if w, in := want[id]; ok != in || r != w {
+            t.Errorf("bounds of %d %+v, %v, want %+v, %v", id, r, ok, w, in)
+        }
+    }
+    for i := 0; i < 100; i++ {
+        area := randomRect(rnd)
+        got := index.Query(area, []int{})
+        sort.Ints(got)
+        if w := want.query(area); !reflect.DeepEqual(got, w) {
+            t.Fatalf("query %+v\n%v, want\n%v", area, got, w)
+        }
+    }
+    for i := 0; i < 100; i++ {
+        origin := mgl32.Vec2{float32(rnd.Intn(384) - 64), float32(rnd.Intn(384) - 64)}
+        a := rnd.Float64() * 2 * math.Pi
+        dir := mgl32.Vec2{float32(math.Cos(a)), float32(math.Sin(a))}
+        if i%4 == 0 {
+            // along the cell borders
+            dir = [4]mgl32.Vec2{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}[i/4%4]
+        }
+        maxDist := float32(rnd.Intn(3) * 100)
+        hits := index.Raycast(origin, dir, maxDist, nil)
+        got := map[int]float32{}
+        for j, h := range hits {
+            got[h.ID] = h.T
+            if j > 0 && h.T < hits[j-1].T {
+                t.Errorf("ray from %v towards %v: hit %d nearer than the one before", origin, dir, j)
+            }
+        }
+        if w := want.raycast(origin, dir, maxDist); !reflect.DeepEqual(got, w) {
+            t.Fatalf("ray from %v towards %v up to %v\n%v, want\n%v", origin, dir, maxDist, got, w)
+        }
+    }
+    got := index.Pairs([]Pair{})
+    sortPairs(got)
+    if w := want.pairs(); !reflect.DeepEqual(got, w) {
+        t.Fatalf("pairs\n%v, want\n%v", got, w)
+    }
+}
+
+func TestIndexes(t *testing.T) {
+    for name, index := range indexes() {
+        t.Run(name, func(t *testing.T) {
+            rnd := rand.New(rand.NewSource(1))
+            want := bruteForce{}
+            for id := 0; id < 200; id++ {
+                r := randomRect(rnd)
+                index.Insert(id, r)
+                want[id] = r
+            }
+            check(t, rnd, index, want)
+
+            // small steps mostly stay within their cells and nodes
+            for id := 0; id < 200; id += 2 {
+                r := want[id]
+                r.X += float32(rnd.Intn(5) - 2)
+                r.Y += float32(rnd.Intn(5) - 2)
+                index.Move(id, r)
+                want[id] = r
+            }
+            for id := 1; id < 200; id += 4 {
+                r := randomRect(rnd)
+                index.Move(id, r)
+                want[id] = r
+            }
+            check(t, rnd, index, want)
+
+            for id := 0; id < 200; id += 3 {
+                index.Remove(id)
+                delete(want, id)
+            }
+            index.Remove(300)
+            // moving an ID not in the index inserts it
+            index.Move(250, gome.Rect{X: 8, Y: 8, W: 8, H: 8})
+            want[250] = gome.Rect{X: 8, Y: 8, W: 8, H: 8}
+            check(t, rnd, index, want)
+
+            for id := range want {
+                index.Remove(id)
+                delete(want, id)
+            }
+            check(t, rnd, index, want)
+        })
+    }
+}
+
+func TestIndexesCrossingBounds(t *testing.T) {
+    rects := []gome.Rect{
+        // across the cell borders and the center of the quadtree
+        {X: -4, Y: -4, W: 8, H: 8},
+        {X: 124, Y: 124, W: 8, H: 8},
+        // centered on a node border, sticking out of the node storing it
+        {X: 60, Y: 60, W: 8, H: 8},
+        // larger than the quadtree
+        {X: -64, Y: 100, W: 512, H: 4},
+        // outside the quadtree
+        {X: 300, Y: -40, W: 8, H: 8},
+    }
+    tests := []struct {
+        area gome.Rect
+        ids  []int
+    }{
+        {gome.Rect{X: -2, Y: -2, W: 1, H: 1}, []int{0}},
+        {gome.Rect{X: 2, Y: 2, W: 1, H: 1}, []int{0}},
+        {gome.Rect{X: 4, Y: 4, W: 1, H: 1}, []int{}},
+        {gome.Rect{X: 126, Y: 130, W: 1, H: 1}, []int{1}},
+        {gome.Rect{X: 130, Y: 126, W: 1, H: 1}, []int{1}},
+        {gome.Rect{X: 66, Y: 66, W: 1, H: 1}, []int{2}},
+        {gome.Rect{X: 400, Y: 101, W: 1, H: 1}, []int{3}},
+        {gome.Rect{X: 120, Y: 90, W: 8, H: 40}, []int{1, 3}},
+        {gome.Rect{X: 302, Y: -38, W: 1, H: 1}, []int{4}},
+    }
+    for name, index := range indexes() {
+        for id, r := range rects {
+            index.Insert(id, r)
+        }
+        for _, tt := range tests {
+            got := index.Query(tt.area, []int{})
+            sort.Ints(got)
+            if !reflect.DeepEqual(got, tt.ids) {
+                t.Errorf("%s: query %+v %v, want %v", name, tt.area, got, tt.ids)
+            }
+        }
+    }
+}
@@ -0,0 +1,175 @@
+package gome
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io/fs"
+    "path"
+    "time"
+)
+
+// SpriteSheet is a texture with named frames, as exported by TexturePacker or
+// Aseprite.
+type SpriteSheet struct {
+    Texture *Texture
+    Frames  []Frame    // in the order of the file
+    Tags    []FrameTag // animation tags exported by Aseprite
+
+    byName map[string]int
+}
+
+// Frame is a named region of a sprite sheet.
+type Frame struct {
+    Region
+    Name     string
+    Duration time.Duration // zero unless exported by Aseprite
+}
+
+// FrameTag is a named range of frames, typically an animation.
+type FrameTag struct {
+    Name      string
+    From, To  int    // indices of the first and last frame
+    Direction string // "forward", "reverse" or "pingpong"
+}
+
+type sheetRect struct {
+    X, Y, W, H float32
+}
+
+type sheetFrame struct {
+    Filename         string    `json:"filename"`
+    Frame            sheetRect `json:"frame"`
+    Rotated          bool      `json:"rotated"`
+    SpriteSourceSize sheetRect `json:"spriteSourceSize"`
+    SourceSize       sheetRect `json:"sourceSize"`
+    Duration         int       `json:"duration"` // milliseconds
+}
+
+type sheetFile struct {
+    Frames json.RawMessage `json:"frames"`
+    Meta   struct {
+        Image     string     `json:"image"`
+        FrameTags []FrameTag `json:"frameTags"`
+    } `json:"meta"`
+}
+
+// LoadSpriteSheet loads the sprite sheet described by the JSON file at path
+// in the asset file system (see SetAssetFS), in either the "hash" or the
+// "array" layout of TexturePacker and Aseprite. The image is loaded relative
+// to the JSON file with the given texture options. Rotated frames are not
+// supported.
+func LoadSpriteSheet(path string, opts *TextureOptions) (*SpriteSheet, error) {
+    return LoadSpriteSheetFS(assetFS, path, opts)
+}
+
+// LoadSpriteSheetFS is like LoadSpriteSheet but reads the files from fsys.
+func LoadSpriteSheetFS(fsys fs.FS, name string, opts *TextureOptions) (*SpriteSheet, error) {
+    data, err := readFile(fsys, name)
+    if err != nil {
+        return nil, err
+    }
+    s, image, err := parseSpriteSheet(data)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %v", name, err)
+    }
+    if image == "" {
+        return nil, fmt.Errorf("%s: no image given", name)
+    }
+    t, err := LoadTextureFS(fsys, path.Join(path.Dir(name), image), opts)
+    if err != nil {
+        return nil, err
+    }
+    s.Texture = t
+    for i := range s.Frames {
+        s.Frames[i].Texture = t
+    }
+    return s, nil
+}
+
+// parseSpriteSheet parses the frames and tags of a sprite sheet and returns
+// the image file name given in it.
+func parseSpriteSheet(data []byte) (*SpriteSheet, string, error) {
+    var file sheetFile
+    if err := json.Unmarshal(data, &file); err != nil {
+        return nil, "", err
+    }
+    var frames []sheetFrame
+    if len(file.Frames) > 0 && file.Frames[0] == '[' {
+        if err := json.Unmarshal(file.Frames, &frames); err != nil {
+            return nil, "", err
+        }
+    } else if len(file.Frames) > 0 {
+        // decode the hash by hand to keep the order of the frames, which
+        // the indices of the frame tags refer to
+        dec := json.NewDecoder(bytes.NewReader(file.Frames))
+        if _, err := dec.Token(); err != nil {
+            return nil, "", err
+        }
+        for dec.More() {
+            key, err := dec.Token()
+            if err != nil {
+                return nil, "", err
+            }
+            var f sheetFrame
+            if err := dec.Decode(&f); err != nil {
+                return nil, "", err
+            }
+            f.Filename = key.(string)
+            frames = append(frames, f)
+        }
+    }
+
+    s := &SpriteSheet{Tags: file.Meta.FrameTags, byName: map[string]int{}}
+    for _, f := range frames {
+        if f.Rotated {
+            return nil, "", fmt.Errorf("frame %q is rotated, which is not supported", f.Filename)
+        }
+        frame := Frame{
+            Region: Region{
+                Src:     Rect(f.Frame),
+                OffsetX: f.SpriteSourceSize.X,
+                OffsetY: f.SpriteSourceSize.Y,
+                Width:   f.SourceSize.W,
+                Height:  f.SourceSize.H,
+            },
+            Name:     f.Filename,
+            Duration: time.Duration(f.Duration) * time.Millisecond,
+        }
+        if frame.Width == 0 || frame.Height == 0 {
+            frame.Width, frame.Height = frame.Src.W, frame.Src.H
+        }
+        s.byName[frame.Name] = len(s.Frames)
+        s.Frames = append(s.Frames, frame)
+    }
+    for _, tag := range s.Tags {
+        if tag.From < 0 || tag.To >= len(s.Frames) || tag.From > tag.To {
+            return nil, "", fmt.Errorf("tag %q has an invalid frame range", tag.Name)
+        }
+    }
+    return s, file.Meta.Image, nil
+}
+
+// Frame returns the frame with the given name.
+func (s *SpriteSheet) Frame(name string) (Frame, bool) {
+    i, ok := s.byName[name]
+    if !ok {
+        return Frame{}, false
+    }
+    return s.Frames[i], true
+}
+
+// Tag returns the frame tag with the given name.
+func (s *SpriteSheet) Tag(name string) (FrameTag, bool) {
+    for _, t := range s.Tags {
+        if t.Name == name {
+            return t, true
+        }
+    }
+    return FrameTag{}, false
+}
+
+// Delete deletes the texture of the sprite sheet.
+func (s *SpriteSheet) Delete() {
+    s.Texture.Delete()
+}
@@ -0,0 +1,216 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/gl"
+)
+
+// The functions in this file form an optional caching layer on top of the GL
+// state machine. Each call is only forwarded to GL if it actually changes the
+// state, which saves a CGo call for every redundant bind. The cache assumes
+// that it sees every change of the state it tracks; after changing that state
+// with raw GL calls, InvalidateState has to be called.
+
+// StateStats counts the calls made through the state cache during a frame.
+type StateStats struct {
+    Calls   int // number of calls to the state cache functions
+    Skipped int // number of those calls that were redundant and not sent to GL
+}
+
+type textureUnit struct {
+    unit   int
+    target uint32
+}
+
+type stateCache struct {
+    program     uint32
+    programSet  bool
+    activeUnit  int
+    textures    map[textureUnit]uint32
+    buffers     map[uint32]uint32
+    vertexArray uint32
+    vaoSet      bool
+    framebuffer uint32
+    fboSet      bool
+    caps        map[uint32]bool
+
+    frame, last StateStats
+}
+
+var state = stateCache{activeUnit: -1}
+
+// InvalidateState clears the state cache, so that the next call to each of
+// the cached functions is forwarded to GL regardless of the previous state.
+func InvalidateState() {
+    state.programSet = false
+    state.activeUnit = -1
+    state.textures = nil
+    state.buffers = nil
+    state.vaoSet = false
+    state.fboSet = false
+    state.caps = nil
+}
+
+// FrameStateStats returns the state cache statistics of the last frame.
+func FrameStateStats() StateStats {
+    return state.last
+}
+
+// endFrame is called at every Tick to reset the per-frame counters.
+func (s *stateCache) endFrame() {
+    s.last = s.frame
+    s.frame = StateStats{}
+}
+
+func (s *stateCache) skip(redundant bool) bool {
+    s.frame.Calls++
+    if redundant {
+        s.frame.Skipped++
+    }
+    return redundant
+}
+
+// UseProgram makes p the current program. A nil p unbinds the current program.
+func UseProgram(p *Program) {
+    var id uint32
+    if p != nil {
+        id = p.ID
+    }
+    if state.skip(state.programSet && state.program == id) {
+        return
+    }
+    gl.UseProgram(id)
+    state.program, state.programSet = id, true
+}
+
+// BindTexture binds t to target on the given texture unit (0 for
+// GL_TEXTURE0 and so on). A nil t unbinds the texture. Note that BindTexture
+// may change the active texture unit.
+func BindTexture(unit int, target uint32, t *Texture) {
+    var id uint32
+    if t != nil {
+        id = t.ID
+    }
+    key := textureUnit{unit, target}
+    bound, ok := state.textures[key]
+    if state.skip(ok && bound == id) {
+        return
+    }
+    if state.activeUnit != unit {
+        gl.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+        state.activeUnit = unit
+    }
+    gl.BindTexture(target, id)
+    if state.textures == nil {
+        state.textures = map[textureUnit]uint32{}
+    }
+    state.textures[key] = id
+}
+
+// BindBuffer binds b to target. A nil b unbinds the buffer.
+func BindBuffer(target uint32, b *Buffer) {
+    var id uint32
+    if b != nil {
+        id = b.ID
+    }
+    bound, ok := state.buffers[target]
+    if state.skip(ok && bound == id) {
+        return
+    }
+    gl.BindBuffer(target, id)
+    if state.buffers == nil {
+        state.buffers = map[uint32]uint32{}
+    }
+    state.buffers[target] = id
+}
+
+// BindVertexArray binds a. A nil a unbinds the vertex array.
+func BindVertexArray(a *VertexArray) {
+    var id uint32
+    if a != nil {
+        id = a.ID
+    }
+    if state.skip(state.vaoSet && state.vertexArray == id) {
+        return
+    }
+    gl.BindVertexArray(id)
+    state.vertexArray, state.vaoSet = id, true
+    // the element array binding is part of the vertex array state
+    delete(state.buffers, gl.ELEMENT_ARRAY_BUFFER)
+}
+
+// BindFramebuffer binds f to GL_FRAMEBUFFER. A nil f binds the default
+// framebuffer.
+func BindFramebuffer(f *Framebuffer) {
+    var id uint32
+    if f != nil {
+        id = f.ID
+    }
+    if state.skip(state.fboSet && state.framebuffer == id) {
+        return
+    }
+    gl.BindFramebuffer(gl.FRAMEBUFFER, id)
+    state.framebuffer, state.fboSet = id, true
+}
+
+// Enable enables the server-side capability cap (see glEnable).
+func Enable(cap uint32) {
+    setCapability(cap, true)
+}
+
+// Disable disables the server-side capability cap (see glDisable).
+func Disable(cap uint32) {
+    setCapability(cap, false)
+}
+
+func setCapability(cap uint32, enabled bool) {
+    current, ok := state.caps[cap]
+    if state.skip(ok && current == enabled) {
+        return
+    }
+    if enabled {
+        gl.Enable(cap)
+    } else {
+        gl.Disable(cap)
+    }
+    if state.caps == nil {
+        state.caps = map[uint32]bool{}
+    }
+    state.caps[cap] = enabled
+}
+
+// The following are called by the object wrappers on deletion, since GL
+// resets bindings of deleted objects to zero and the name may be reused.
+
+func (s *stateCache) deleteProgram(id uint32) {
+    if s.program == id {
+        s.programSet = false
+    }
+}
+
+func (s *stateCache) deleteTexture(id uint32) {
+    for k, t := range s.textures {
+        if t == id {
+            delete(s.textures, k)
+        }
+    }
+}
+
+func (s *stateCache) deleteBuffer(id uint32) {
+    for k, b := range s.buffers {
+        if b == id {
+            delete(s.buffers, k)
+        }
+    }
+}
+
+func (s *stateCache) deleteVertexArray(id uint32) {
+    if s.vertexArray == id {
+        s.vaoSet = false
+    }
+}
+
+func (s *stateCache) deleteFramebuffer(id uint32) {
+    if s.framebuffer == id {
+        s.fboSet = false
+    }
+}
@@ -0,0 +1,104 @@
+package gome
+
+import (
+    "time"
+)
+
+// frameHistory is the number of frame times kept for FrameTimes.
+const frameHistory = 120
+
+type frameStats struct {
+    frames    uint64
+    last      time.Time
+    frameTime time.Duration
+    history   [frameHistory]time.Duration
+    fps       float64
+    fpsStart  time.Time
+    fpsFrames int
+    glErrors  int
+}
+
+var stats frameStats
+
+// endFrame is called at every Tick to measure the frame time.
+func (s *frameStats) endFrame() {
+    now := time.Now()
+    if !s.last.IsZero() {
+        s.frameTime = now.Sub(s.last)
+        s.history[s.frames%frameHistory] = s.frameTime
+        s.frames++
+    } else {
+        s.fpsStart = now
+    }
+    s.last = now
+
+    // the frame rate is averaged over (roughly) a second
+    s.fpsFrames++
+    if d := now.Sub(s.fpsStart); d >= time.Second {
+        s.fps = float64(s.fpsFrames) / d.Seconds()
+        s.fpsStart = now
+        s.fpsFrames = 0
+    }
+}
+
+// FrameCount returns the number of frames that have been completed by Tick.
+func FrameCount() uint64 {
+    return stats.frames
+}
+
+// FrameTime returns the duration of the last frame in game time: measured
+// from Tick to Tick and multiplied by the time scale (see SetTimeScale). It is
+// the time FixedTimestep and the timers of After and Every advance by, and the
+// one to pass to the Update methods of tweens, particles and animations.
+func FrameTime() time.Duration {
+    return time.Duration(float64(stats.frameTime) * timeScale)
+}
+
+// UnscaledDelta returns the duration of the last frame in real time, measured
+// from Tick to Tick and unaffected by the time scale, e.g. for menus that
+// keep animating in slow motion. FrameTimes and FPS are always in real time.
+func UnscaledDelta() time.Duration {
+    return stats.frameTime
+}
+
+var timeScale = 1.0
+
+// SetTimeScale sets how fast game time passes relative to real time: 0.25
+// for slow motion, 2 for fast forward and 0 to freeze it. Negative scales
+// count as 0. The scale applies from the next call of FrameTime, which all
+// of gome's time-based updates go through, so they stay in step with each
+// other. It defaults to 1 and is kept across App.Init.
+func SetTimeScale(s float64) {
+    if s < 0 {
+        s = 0
+    }
+    timeScale = s
+}
+
+// TimeScale returns the scale set by SetTimeScale.
+func TimeScale() float64 {
+    return timeScale
+}
+
+// FrameTimes returns the durations of up to the last 120 frames, oldest first.
+func FrameTimes() []time.Duration {
+    n := stats.frames
+    if n > frameHistory {
+        n = frameHistory
+    }
+    times := make([]time.Duration, 0, n)
+    for i := stats.frames - n; i < stats.frames; i++ {
+        times = append(times, stats.history[i%frameHistory])
+    }
+    return times
+}
+
+// FPS returns the number of frames per second, averaged over the last second.
+func FPS() float64 {
+    return stats.fps
+}
+
+// GLErrorCount returns the number of OpenGL errors reported by GetError so far.
+func GLErrorCount() int {
+    return stats.glErrors
+}
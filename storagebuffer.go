@@ -0,0 +1,154 @@
+package gome
+
+import (
+    "errors"
+    "github.com/snorredc/gome/internal/gl"
+    "reflect"
+    "unsafe"
+)
+
+var (
+    ErrStorageData = errors.New("storage buffer data must be a slice or a pointer")
+    ErrStorageMap  = errors.New("storage buffer could not be mapped")
+)
+
+// StorageBuffer is a shader storage buffer object, which shaders, usually
+// compute shaders, read and write as a buffer block. The contents are raw
+// bytes: the Go values written and read have to match the std430 layout of
+// the block, which for arrays of float32, vec2 and vec4 (mgl32.Vec4) is
+// their layout in Go, but pads vec3 to 16 bytes.
+//
+// The buffer can also be bound as any other kind of buffer through Buffer,
+// e.g. as the vertex buffer of particles simulated by a compute shader.
+type StorageBuffer struct {
+    Buffer  *Buffer
+    Binding int // the binding point of the last Bind, or -1
+    size    int
+
+    mapped bool
+}
+
+// NewStorageBuffer creates a storage buffer of size bytes, filled with zeros.
+func NewStorageBuffer(size int) *StorageBuffer {
+    b := &StorageBuffer{Buffer: NewBuffer(), Binding: -1, size: size}
+    BindBuffer(gl.SHADER_STORAGE_BUFFER, b.Buffer)
+    gl.BufferData(gl.SHADER_STORAGE_BUFFER, size, gl.Ptr(make([]byte, size)), gl.DYNAMIC_COPY)
+    return b
+}
+
+// NewStorageBufferData creates a storage buffer holding data, a slice or a
+// pointer to a value.
+func NewStorageBufferData(data interface{}) (*StorageBuffer, error) {
+    p, n, err := storageData(data)
+    if err != nil {
+        return nil, err
+    }
+    b := &StorageBuffer{Buffer: NewBuffer(), Binding: -1, size: n}
+    BindBuffer(gl.SHADER_STORAGE_BUFFER, b.Buffer)
+    gl.BufferData(gl.SHADER_STORAGE_BUFFER, n, p, gl.DYNAMIC_COPY)
+    return b, nil
+}
+
+// storageData returns the memory of the slice or pointed value data.
+func storageData(data interface{}) (unsafe.Pointer, int, error) {
+    v := reflect.ValueOf(data)
+    switch v.Kind() {
+    case reflect.Slice:
+        n := v.Len() * int(v.Type().Elem().Size())
+        if n == 0 {
+            return nil, 0, nil
+        }
+        return v.Index(0).Addr().UnsafePointer(), n, nil
+    case reflect.Ptr:
+        if v.IsNil() {
+            return nil, 0, ErrStorageData
+        }
+        return v.UnsafePointer(), int(v.Type().Elem().Size()), nil
+    }
+    return nil, 0, ErrStorageData
+}
+
+// Size returns the size of the buffer in bytes.
+func (b *StorageBuffer) Size() int {
+    return b.size
+}
+
+// Bind binds the buffer to the storage buffer binding point, which the
+// shader declares with layout(binding = ...) on the block.
+func (b *StorageBuffer) Bind(binding int) {
+    BindBuffer(gl.SHADER_STORAGE_BUFFER, b.Buffer)
+    gl.BindBufferBase(gl.SHADER_STORAGE_BUFFER, uint32(binding), b.Buffer.ID)
+    b.Binding = binding
+}
+
+// Write uploads data, a slice or a pointer to a value, at offset bytes
+// into the buffer.
+func (b *StorageBuffer) Write(offset int, data interface{}) error {
+    p, n, err := storageData(data)
+    if err != nil || n == 0 {
+        return err
+    }
+    BindBuffer(gl.SHADER_STORAGE_BUFFER, b.Buffer)
+    gl.BufferSubData(gl.SHADER_STORAGE_BUFFER, offset, n, p)
+    return nil
+}
+
+// Read reads the buffer from offset bytes into dst, a slice or a pointer to
+// a value, after the writes of the shaders run so far. It waits for the GPU,
+// so use it for results and debugging rather than every frame. It returns
+// ErrStorageMap if the range is not in the buffer or the driver fails to map
+// it.
+func (b *StorageBuffer) Read(offset int, dst interface{}) error {
+    p, n, err := storageData(dst)
+    if err != nil || n == 0 {
+        return err
+    }
+    src := b.Map(offset, n, MapRead)
+    if src == nil {
+        return ErrStorageMap
+    }
+    copy(unsafe.Slice((*byte)(p), n), src)
+    b.Unmap()
+    return nil
+}
+
+// MapAccess is how a mapped buffer is accessed.
+type MapAccess uint32
+
+const (
+    MapRead      MapAccess = gl.MAP_READ_BIT
+    MapWrite     MapAccess = gl.MAP_WRITE_BIT
+    MapReadWrite           = MapRead | MapWrite
+)
+
+// Map maps length bytes of the buffer from offset into memory, after the
+// writes of the shaders run so far, and returns them, or nil if they could
+// not be mapped, e.g. because they are not in the buffer. The slice is only
+// valid until Unmap, which has to be called before the buffer is used by
+// OpenGL again.
+func (b *StorageBuffer) Map(offset, length int, access MapAccess) []byte {
+    MemoryBarrier(BufferBarrier)
+    BindBuffer(gl.SHADER_STORAGE_BUFFER, b.Buffer)
+    p := gl.MapBufferRange(gl.SHADER_STORAGE_BUFFER, offset, length, uint32(access))
+    if p == nil {
+        return nil
+    }
+    b.mapped = true
+    return unsafe.Slice((*byte)(p), length)
+}
+
+// Unmap unmaps the buffer mapped by Map.
+func (b *StorageBuffer) Unmap() {
+    if !b.mapped {
+        return
+    }
+    BindBuffer(gl.SHADER_STORAGE_BUFFER, b.Buffer)
+    gl.UnmapBuffer(gl.SHADER_STORAGE_BUFFER)
+    b.mapped = false
+}
+
+// Delete deletes the buffer.
+func (b *StorageBuffer) Delete() {
+    b.Unmap()
+    b.Buffer.Delete()
+}
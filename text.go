@@ -0,0 +1,139 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/gl"
+    "golang.org/x/image/font"
+    "golang.org/x/image/font/basicfont"
+    "golang.org/x/image/math/fixed"
+    "image"
+    "image/draw"
+)
+
+// Font is a set of glyphs rasterised into a texture, ready to be drawn with
+// SpriteBatch.DrawText. TrueType and OpenType fonts are loaded by the text
+// package.
+type Font struct {
+    Texture *Texture
+    Height  float32 // distance between two lines in pixels
+    Ascent  float32 // distance from the top of a line to the baseline
+
+    glyphs map[rune]fontGlyph
+}
+
+type fontGlyph struct {
+    src     Rect    // region in the texture
+    x, y    float32 // offset of the glyph image from the dot
+    advance float32
+}
+
+// the width of font textures; the height depends on the number of glyphs
+const fontTextureWidth = 256
+
+// NewFont rasterises the printable characters of the Latin-1 range of face
+// into a texture and returns the resulting font.
+func NewFont(face font.Face) (*Font, error) {
+    type rasterised struct {
+        r       rune
+        bounds  image.Rectangle
+        mask    image.Image
+        maskp   image.Point
+        advance fixed.Int26_6
+    }
+    var glyphs []rasterised
+    for r := rune(32); r < 256; r++ {
+        if r >= 127 && r < 160 {
+            continue
+        }
+        dr, mask, maskp, advance, ok := face.Glyph(fixed.Point26_6{}, r)
+        if ok {
+            glyphs = append(glyphs, rasterised{r, dr, mask, maskp, advance})
+        }
+    }
+
+    // place the glyphs in rows, leaving a pixel of padding around each
+    positions := make([]image.Point, len(glyphs))
+    x, y, rowHeight := 1, 1, 0
+    for i, g := range glyphs {
+        w, h := g.bounds.Dx(), g.bounds.Dy()
+        if x+w+1 > fontTextureWidth {
+            x, y = 1, y+rowHeight+1
+            rowHeight = 0
+        }
+        positions[i] = image.Pt(x, y)
+        x += w + 1
+        if h > rowHeight {
+            rowHeight = h
+        }
+    }
+    height := 1
+    for height < y+rowHeight+1 {
+        height *= 2
+    }
+
+    atlas := image.NewNRGBA(image.Rect(0, 0, fontTextureWidth, height))
+    metrics := face.Metrics()
+    f := &Font{
+        Height: float32(metrics.Height) / 64,
+        Ascent: float32(metrics.Ascent) / 64,
+        glyphs: make(map[rune]fontGlyph, len(glyphs)),
+    }
+    for i, g := range glyphs {
+        p := positions[i]
+        dst := image.Rectangle{p, p.Add(g.bounds.Size())}
+        draw.DrawMask(atlas, dst, image.White, image.Point{}, g.mask, g.maskp, draw.Over)
+        f.glyphs[g.r] = fontGlyph{
+            src:     Rect{float32(p.X), float32(p.Y), float32(dst.Dx()), float32(dst.Dy())},
+            x:       float32(g.bounds.Min.X),
+            y:       float32(g.bounds.Min.Y),
+            advance: float32(g.advance) / 64,
+        }
+    }
+    // glyphs are drawn at their native size, so there is nothing to filter
+    f.Texture = NewTextureFromImage(atlas, &TextureOptions{
+        MinFilter: gl.NEAREST,
+        MagFilter: gl.NEAREST,
+    })
+    return f, nil
+}
+
+// Measure returns the size of s when drawn with f.
+func (f *Font) Measure(s string) (width, height float32) {
+    var lineWidth float32
+    height = f.Height
+    for _, r := range s {
+        if r == '\n' {
+            lineWidth = 0
+            height += f.Height
+            continue
+        }
+        g, ok := f.glyphs[r]
+        if !ok {
+            g = f.glyphs['?']
+        }
+        lineWidth += g.advance
+        if lineWidth > width {
+            width = lineWidth
+        }
+    }
+    return width, height
+}
+
+// Delete deletes the texture of the font.
+func (f *Font) Delete() {
+    f.Texture.Delete()
+}
+
+var defaultFont *Font
+
+// DefaultFont returns the built-in 7x13 pixel monospace font. It is created on
+// the first call and shared afterwards, so it must not be deleted.
+func DefaultFont() (*Font, error) {
+    if defaultFont == nil {
+        f, err := NewFont(basicfont.Face7x13)
+        if err != nil {
+            return nil, err
+        }
+        defaultFont = f
+    }
+    return defaultFont, nil
+}
@@ -0,0 +1,80 @@
+/*
+Package text loads TrueType and OpenType fonts as gome fonts, ready to be drawn
+with gome.SpriteBatch.DrawText. It is kept apart from gome itself, so that
+applications that only use the built-in font (see gome.DefaultFont) do not
+pull in the OpenType parser.
+
+    f, err := text.Load("fonts/DejaVuSans.ttf", 16)
+    if err != nil {
+        // handle error
+    }
+    defer f.Delete()
+
+Like the rest of gome, the functions of this package create GL objects and must
+be called on the main OS thread after gome.App.Init.
+*/
+package text
+
+import (
+    "github.com/snorredc/gome"
+    "golang.org/x/image/font"
+    "golang.org/x/image/font/opentype"
+    "io"
+    "io/fs"
+    "os"
+)
+
+// Parse parses TrueType or OpenType font data and rasterises it at the given
+// size in pixels (see gome.NewFont).
+func Parse(data []byte, size float64) (*gome.Font, error) {
+    otf, err := opentype.Parse(data)
+    if err != nil {
+        return nil, err
+    }
+    face, err := opentype.NewFace(otf, &opentype.FaceOptions{
+        Size:    size,
+        DPI:     72,
+        Hinting: font.HintingFull,
+    })
+    if err != nil {
+        return nil, err
+    }
+    defer face.Close()
+    return gome.NewFont(face)
+}
+
+// Load reads the TrueType or OpenType font at path in the asset file system
+// (see gome.SetAssetFS) and rasterises it at the given size in pixels.
+func Load(path string, size float64) (*gome.Font, error) {
+    data, err := gome.ReadAsset(path)
+    if err != nil {
+        return nil, err
+    }
+    return Parse(data, size)
+}
+
+// LoadFS is like Load but reads the font from fsys, or from the operating
+// system's file system if fsys is nil.
+func LoadFS(fsys fs.FS, path string, size float64) (*gome.Font, error) {
+    var data []byte
+    var err error
+    if fsys == nil {
+        data, err = os.ReadFile(path)
+    } else {
+        data, err = fs.ReadFile(fsys, path)
+    }
+    if err != nil {
+        return nil, err
+    }
+    return Parse(data, size)
+}
+
+// Read reads a TrueType or OpenType font from r and rasterises it at the
+// given size in pixels.
+func Read(r io.Reader, size float64) (*gome.Font, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return nil, err
+    }
+    return Parse(data, size)
+}
@@ -0,0 +1,266 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/glfw"
+    "runtime"
+    "unicode"
+)
+
+// TextEventKind is the kind of a TextEvent.
+type TextEventKind int
+
+const (
+    TextRune TextEventKind = iota // a character was typed
+    TextEdit                      // an editing key or shortcut was pressed
+)
+
+// TextEditOp is an editing operation of a TextEvent.
+type TextEditOp int
+
+const (
+    EditBackspace TextEditOp = iota
+    EditDelete
+    EditLeft
+    EditRight
+    EditHome
+    EditEnd
+    EditEnter
+    EditSelectAll
+    EditCopy
+    EditCut
+    EditPaste
+)
+
+// TextEvent is an event of the text input stream.
+type TextEvent struct {
+    Kind  TextEventKind
+    Rune  rune       // the character for TextRune
+    Op    TextEditOp // the operation for TextEdit
+    Shift bool       // whether shift was held, to extend the selection
+}
+
+type textInputState struct {
+    active bool
+    events []TextEvent
+}
+
+var textInput textInputState
+
+// BeginTextInput starts reporting typed text as TextEvents, e.g. when a text
+// field gets the focus. Characters are reported after the keyboard layout and
+// any input method of the system have been applied, so this works for
+// non-ASCII layouts where raw key events do not. GLFW does not report the
+// text being composed by an input method, so the input method shows it
+// itself and the composed text arrives as runes once it is committed.
+func BeginTextInput() {
+    textInput.active = true
+}
+
+// EndTextInput stops reporting text input.
+func EndTextInput() {
+    textInput.active = false
+    textInput.events = nil
+}
+
+// TextInputActive reports whether text input is active.
+func TextInputActive() bool {
+    return textInput.active
+}
+
+// TextEvents returns the text events received by the last Tick, in the order
+// they happened. It is empty unless text input is active.
+func TextEvents() []TextEvent {
+    return textInput.events
+}
+
+// beginFrame is called by Tick before polling events.
+func (t *textInputState) beginFrame() {
+    t.events = t.events[:0]
+}
+
+func (t *textInputState) char(r rune) {
+    if t.active && unicode.IsPrint(r) {
+        t.events = append(t.events, TextEvent{Kind: TextRune, Rune: r})
+    }
+}
+
+// shortcutMod is the modifier of the clipboard shortcuts.
+var shortcutMod = glfw.ModControl
+
+func init() {
+    if runtime.GOOS == "darwin" {
+        shortcutMod = glfw.ModSuper
+    }
+}
+
+func (t *textInputState) key(key glfw.Key, mods glfw.ModifierKey) {
+    if !t.active {
+        return
+    }
+    var op TextEditOp
+    switch {
+    case key == glfw.KeyBackspace:
+        op = EditBackspace
+    case key == glfw.KeyDelete:
+        op = EditDelete
+    case key == glfw.KeyLeft:
+        op = EditLeft
+    case key == glfw.KeyRight:
+        op = EditRight
+    case key == glfw.KeyHome:
+        op = EditHome
+    case key == glfw.KeyEnd:
+        op = EditEnd
+    case key == glfw.KeyEnter || key == glfw.KeyKPEnter:
+        op = EditEnter
+    case key == glfw.KeyA && mods&shortcutMod != 0:
+        op = EditSelectAll
+    case key == glfw.KeyC && mods&shortcutMod != 0:
+        op = EditCopy
+    case key == glfw.KeyX && mods&shortcutMod != 0:
+        op = EditCut
+    case key == glfw.KeyV && mods&shortcutMod != 0:
+        op = EditPaste
+    default:
+        return
+    }
+    t.events = append(t.events, TextEvent{Kind: TextEdit, Op: op, Shift: mods&glfw.ModShift != 0})
+}
+
+// TextBuffer is an editable line of text with a cursor and a selection. It
+// implements the usual editing keys and the clipboard shortcuts, so a text
+// field only has to call Update every frame and draw the text.
+type TextBuffer struct {
+    MaxLength int          // maximum number of runes; zero means no limit
+    OnEnter   func(string) // called with the text when enter is pressed
+
+    text      []rune
+    cursor    int
+    selection int // the other end of the selection; equals cursor if there is none
+}
+
+// String returns the text.
+func (b *TextBuffer) String() string {
+    return string(b.text)
+}
+
+// SetString replaces the text and moves the cursor to its end.
+func (b *TextBuffer) SetString(s string) {
+    b.text = []rune(s)
+    if b.MaxLength > 0 && len(b.text) > b.MaxLength {
+        b.text = b.text[:b.MaxLength]
+    }
+    b.cursor = len(b.text)
+    b.selection = b.cursor
+}
+
+// Cursor returns the position of the cursor in runes.
+func (b *TextBuffer) Cursor() int {
+    return b.cursor
+}
+
+// Selection returns the selected range of runes, which is empty if nothing is
+// selected.
+func (b *TextBuffer) Selection() (start, end int) {
+    if b.selection < b.cursor {
+        return b.selection, b.cursor
+    }
+    return b.cursor, b.selection
+}
+
+// Update applies the text events of the frame (see TextEvents).
+func (b *TextBuffer) Update() {
+    for _, e := range TextEvents() {
+        b.Apply(e)
+    }
+}
+
+// Apply applies a single text event.
+func (b *TextBuffer) Apply(e TextEvent) {
+    if e.Kind == TextRune {
+        b.insert([]rune{e.Rune})
+        return
+    }
+    start, end := b.Selection()
+    move := func(pos int) {
+        b.cursor = pos
+        if !e.Shift {
+            b.selection = pos
+        }
+    }
+    switch e.Op {
+    case EditBackspace:
+        if start == end && start > 0 {
+            start--
+        }
+        b.remove(start, end)
+    case EditDelete:
+        if start == end && end < len(b.text) {
+            end++
+        }
+        b.remove(start, end)
+    case EditLeft:
+        if start != end && !e.Shift {
+            move(start)
+        } else if b.cursor > 0 {
+            move(b.cursor - 1)
+        }
+    case EditRight:
+        if start != end && !e.Shift {
+            move(end)
+        } else if b.cursor < len(b.text) {
+            move(b.cursor + 1)
+        }
+    case EditHome:
+        move(0)
+    case EditEnd:
+        move(len(b.text))
+    case EditEnter:
+        if b.OnEnter != nil {
+            b.OnEnter(b.String())
+        }
+    case EditSelectAll:
+        b.selection, b.cursor = 0, len(b.text)
+    case EditCopy, EditCut:
+        if start != end {
+            SetClipboardString(string(b.text[start:end]))
+            if e.Op == EditCut {
+                b.remove(start, end)
+            }
+        }
+    case EditPaste:
+        var paste []rune
+        for _, r := range ClipboardString() {
+            // a single line, so newlines and tabs become spaces
+            if unicode.IsSpace(r) {
+                r = ' '
+            }
+            if unicode.IsPrint(r) {
+                paste = append(paste, r)
+            }
+        }
+        b.insert(paste)
+    }
+}
+
+// insert replaces the selection with runes, as far as MaxLength allows.
+func (b *TextBuffer) insert(runes []rune) {
+    start, end := b.Selection()
+    b.remove(start, end)
+    if b.MaxLength > 0 && len(b.text)+len(runes) > b.MaxLength {
+        // none fit if MaxLength was lowered below the text
+        runes = runes[:max(b.MaxLength-len(b.text), 0)]
+    }
+    text := make([]rune, 0, len(b.text)+len(runes))
+    text = append(text, b.text[:b.cursor]...)
+    text = append(text, runes...)
+    text = append(text, b.text[b.cursor:]...)
+    b.text = text
+    b.cursor += len(runes)
+    b.selection = b.cursor
+}
+
+func (b *TextBuffer) remove(start, end int) {
+    b.text = append(b.text[:start], b.text[end:]...)
+    b.cursor, b.selection = start, start
+}
@@ -0,0 +1,132 @@
+package gome
+
+import (
+    "testing"
+)
+
+// typeText applies the runes of s to b as typed characters.
+func typeText(b *TextBuffer, s string) {
+    for _, r := range s {
+        b.Apply(TextEvent{Kind: TextRune, Rune: r})
+    }
+}
+
+func edit(b *TextBuffer, op TextEditOp, shift bool, times int) {
+    for i := 0; i < times; i++ {
+        b.Apply(TextEvent{Kind: TextEdit, Op: op, Shift: shift})
+    }
+}
+
+func TestTextBufferEditing(t *testing.T) {
+    tests := []struct {
+        name   string
+        edit   func(b *TextBuffer)
+        text   string
+        cursor int
+        start  int
+        end    int
+    }{
+        {"typing", func(b *TextBuffer) { typeText(b, "héllo, 世界") }, "héllo, 世界", 9, 9, 9},
+        {"inserting", func(b *TextBuffer) {
+            typeText(b, "héllo, 世界")
+            edit(b, EditLeft, false, 2)
+            typeText(b, "¡")
+        }, "héllo, ¡世界", 8, 8, 8},
+        {"backspace", func(b *TextBuffer) {
+            typeText(b, "日本語")
+            edit(b, EditBackspace, false, 1)
+        }, "日本", 2, 2, 2},
+        {"delete", func(b *TextBuffer) {
+            typeText(b, "日本語")
+            edit(b, EditHome, false, 1)
+            edit(b, EditRight, false, 1)
+            edit(b, EditDelete, false, 1)
+        }, "日語", 1, 1, 1},
+        {"backspace at the start", func(b *TextBuffer) {
+            typeText(b, "ñ")
+            edit(b, EditHome, false, 1)
+            edit(b, EditBackspace, false, 1)
+        }, "ñ", 0, 0, 0},
+        {"delete at the end", func(b *TextBuffer) {
+            typeText(b, "ñ")
+            edit(b, EditDelete, false, 1)
+        }, "ñ", 1, 1, 1},
+        {"moving past the ends", func(b *TextBuffer) {
+            typeText(b, "€€")
+            edit(b, EditRight, false, 3)
+            edit(b, EditLeft, false, 5)
+        }, "€€", 0, 0, 0},
+        {"selecting", func(b *TextBuffer) {
+            typeText(b, "añb€c")
+            edit(b, EditHome, false, 1)
+            edit(b, EditRight, true, 2)
+        }, "añb€c", 2, 0, 2},
+        {"selecting backwards", func(b *TextBuffer) {
+            typeText(b, "añb€c")
+            edit(b, EditLeft, true, 2)
+        }, "añb€c", 3, 3, 5},
+        {"replacing the selection", func(b *TextBuffer) {
+            typeText(b, "añb€c")
+            edit(b, EditLeft, false, 1)
+            edit(b, EditLeft, true, 2)
+            typeText(b, "ü")
+        }, "añüc", 3, 3, 3},
+        {"deleting the selection", func(b *TextBuffer) {
+            typeText(b, "añb€c")
+            edit(b, EditHome, false, 1)
+            edit(b, EditEnd, true, 1)
+            edit(b, EditLeft, true, 1)
+            edit(b, EditBackspace, false, 1)
+        }, "c", 0, 0, 0},
+        {"left collapses the selection", func(b *TextBuffer) {
+            typeText(b, "añb€c")
+            edit(b, EditLeft, true, 2)
+            edit(b, EditLeft, false, 1)
+        }, "añb€c", 3, 3, 3},
+        {"right collapses the selection", func(b *TextBuffer) {
+            typeText(b, "añb€c")
+            edit(b, EditHome, false, 1)
+            edit(b, EditRight, true, 2)
+            edit(b, EditRight, false, 1)
+        }, "añb€c", 2, 2, 2},
+        {"select all", func(b *TextBuffer) {
+            typeText(b, "añb€c")
+            edit(b, EditHome, false, 1)
+            edit(b, EditSelectAll, false, 1)
+        }, "añb€c", 5, 0, 5},
+        {"maximum length", func(b *TextBuffer) {
+            b.MaxLength = 4
+            typeText(b, "€€€€€")
+        }, "€€€€", 4, 4, 4},
+        {"maximum length replacing", func(b *TextBuffer) {
+            b.MaxLength = 4
+            b.SetString("αβγδε")
+            edit(b, EditLeft, true, 1)
+            typeText(b, "ωψ")
+        }, "αβγω", 4, 4, 4},
+        {"lowered maximum length", func(b *TextBuffer) {
+            b.SetString("αβγδε")
+            b.MaxLength = 3
+            typeText(b, "ω")
+        }, "αβγδε", 5, 5, 5},
+    }
+    for _, tt := range tests {
+        b := &TextBuffer{}
+        tt.edit(b)
+        start, end := b.Selection()
+        if b.String() != tt.text || b.Cursor() != tt.cursor || start != tt.start || end != tt.end {
+            t.Errorf("%s: %q with the cursor at %d and %d-%d selected, want %q at %d and %d-%d", tt.name,
+                b.String(), b.Cursor(), start, end, tt.text, tt.cursor, tt.start, tt.end)
+        }
+    }
+}
+
+func TestTextBufferEnter(t *testing.T) {
+    var entered []string
+    b := &TextBuffer{OnEnter: func(s string) { entered = append(entered, s) }}
+    b.SetString("ça va")
+    edit(b, EditEnter, false, 1)
+    if len(entered) != 1 || entered[0] != "ça va" || b.String() != "ça va" {
+        t.Errorf("entered %q, want ça va once with the text kept", entered)
+    }
+}
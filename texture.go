@@ -0,0 +1,159 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/gl"
+    "image"
+    "image/draw"
+    _ "image/gif"
+    _ "image/jpeg"
+    _ "image/png"
+    "io"
+    "io/fs"
+)
+
+// TextureOptions controls how images are uploaded by NewTextureFromImage and
+// LoadTexture. The zero value gives linear filtering without mipmaps and
+// clamps texture coordinates to the edge.
+//
+// CPUMipmaps cost some time at load, but they stay sharper and keep the
+// colours of sRGB images and the edges of transparent sprites right. They
+// apply to 2D textures and texture arrays; 3D textures use glGenerateMipmap.
+// Anisotropic filtering keeps textures seen at a grazing angle, such as
+// terrain and floors, sharp; it is limited to GLInfo().MaxAnisotropy, which
+// is 0 where it is not supported.
+type TextureOptions struct {
+    MinFilter  uint32    // defaults to LINEAR, or LINEAR_MIPMAP_LINEAR with mipmaps
+    MagFilter  uint32    // defaults to LINEAR
+    Wrap       uint32    // defaults to CLAMP_TO_EDGE
+    Mipmaps    bool      // generate mipmaps after uploading
+    CPUMipmaps bool      // generate mipmaps on the CPU (see MipmapChain) rather than with glGenerateMipmap
+    SRGB       bool      // the image is sRGB encoded; upload as SRGB8_ALPHA8
+    Anisotropy float32   // the most samples of anisotropic filtering, such as 16; 0 for none
+    Border     Color     // the colour outside the texture with Wrap CLAMP_TO_BORDER
+    Swizzle    [4]uint32 // the sources of red, green, blue and alpha, such as RED or ONE; 0 keeps a component
+}
+
+// NewTextureFromImage creates a 2D RGBA texture with the contents of img. If
+// opts is nil, the default options are used.
+func NewTextureFromImage(img image.Image, opts *TextureOptions) *Texture {
+    if opts == nil {
+        opts = &TextureOptions{}
+    }
+    rgba := nrgbaPixels(img)
+    t := NewTexture()
+    t.Width, t.Height = rgba.Rect.Dx(), rgba.Rect.Dy()
+    BindTexture(0, gl.TEXTURE_2D, t)
+    gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+    gl.TexImage2D(gl.TEXTURE_2D, 0, opts.format(), int32(t.Width), int32(t.Height), 0,
+        gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+    if opts.CPUMipmaps {
+        for i, level := range MipmapChain(rgba, opts.SRGB) {
+            gl.TexImage2D(gl.TEXTURE_2D, int32(i+1), opts.format(), int32(level.Rect.Dx()), int32(level.Rect.Dy()), 0,
+                gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(level.Pix))
+        }
+    }
+    opts.apply(gl.TEXTURE_2D)
+    return t
+}
+
+// nrgbaPixels returns img as an NRGBA image with tightly packed rows,
+// converting it if needed.
+func nrgbaPixels(img image.Image) *image.NRGBA {
+    b := img.Bounds()
+    rgba, ok := img.(*image.NRGBA)
+    if !ok || rgba.Stride != 4*b.Dx() {
+        rgba = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+        draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+    }
+    return rgba
+}
+
+// format returns the internal format of textures uploaded with o.
+func (o *TextureOptions) format() int32 {
+    if o.SRGB {
+        return gl.SRGB8_ALPHA8
+    }
+    return gl.RGBA8
+}
+
+// mipmapped reports whether textures uploaded with o have mipmaps.
+func (o *TextureOptions) mipmapped() bool {
+    return o.Mipmaps || o.CPUMipmaps
+}
+
+// apply sets the filtering, wrapping and swizzling of o on the texture bound
+// to target, and generates its mipmaps if o asks for them and they were not
+// uploaded from the CPU; 3D textures always generate them.
+func (o *TextureOptions) apply(target uint32) {
+    min, mag, wrap := o.MinFilter, o.MagFilter, o.Wrap
+    if min == 0 {
+        min = gl.LINEAR
+        if o.mipmapped() {
+            min = gl.LINEAR_MIPMAP_LINEAR
+        }
+    }
+    if mag == 0 {
+        mag = gl.LINEAR
+    }
+    if wrap == 0 {
+        wrap = gl.CLAMP_TO_EDGE
+    }
+    gl.TexParameteri(target, gl.TEXTURE_MIN_FILTER, int32(min))
+    gl.TexParameteri(target, gl.TEXTURE_MAG_FILTER, int32(mag))
+    gl.TexParameteri(target, gl.TEXTURE_WRAP_S, int32(wrap))
+    gl.TexParameteri(target, gl.TEXTURE_WRAP_T, int32(wrap))
+    if target == gl.TEXTURE_3D {
+        gl.TexParameteri(target, gl.TEXTURE_WRAP_R, int32(wrap))
+    }
+    if o.Border != (Color{}) {
+        border := o.Border.Vec4()
+        gl.TexParameterfv(target, gl.TEXTURE_BORDER_COLOR, &border[0])
+    }
+    if a := minFloat32(o.Anisotropy, glInfo.MaxAnisotropy); a > 1 {
+        gl.TexParameterf(target, gl.TEXTURE_MAX_ANISOTROPY, a)
+    }
+    // one component at a time, as OpenGL ES has no TEXTURE_SWIZZLE_RGBA
+    swizzles := [4]uint32{gl.TEXTURE_SWIZZLE_R, gl.TEXTURE_SWIZZLE_G, gl.TEXTURE_SWIZZLE_B, gl.TEXTURE_SWIZZLE_A}
+    for i, s := range o.Swizzle {
+        if s != 0 {
+            gl.TexParameteri(target, swizzles[i], int32(s))
+        }
+    }
+    if o.Mipmaps && !o.CPUMipmaps || o.mipmapped() && target == gl.TEXTURE_3D {
+        gl.GenerateMipmap(target)
+    }
+}
+
+func minFloat32(a, b float32) float32 {
+    if a < b {
+        return a
+    }
+    return b
+}
+
+// LoadTexture decodes the PNG, JPEG or GIF image at path in the asset file
+// system (see SetAssetFS) and uploads it as a texture (see
+// NewTextureFromImage).
+func LoadTexture(path string, opts *TextureOptions) (*Texture, error) {
+    return LoadTextureFS(assetFS, path, opts)
+}
+
+// LoadTextureFS is like LoadTexture but reads the image from fsys.
+func LoadTextureFS(fsys fs.FS, path string, opts *TextureOptions) (*Texture, error) {
+    f, err := openFile(fsys, path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    return ReadTexture(f, opts)
+}
+
+// ReadTexture decodes a PNG, JPEG or GIF image from r and uploads it as a
+// texture (see NewTextureFromImage).
+func ReadTexture(r io.Reader, opts *TextureOptions) (*Texture, error) {
+    img, _, err := image.Decode(r)
+    if err != nil {
+        return nil, err
+    }
+    return NewTextureFromImage(img, opts), nil
+}
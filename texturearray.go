@@ -0,0 +1,209 @@
+package gome
+
+import (
+    "errors"
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+    "image"
+)
+
+var ErrTextureLayerSize = errors.New("image does not have the size of the texture layers")
+
+// TextureArray is a GL_TEXTURE_2D_ARRAY texture: a stack of layers of the
+// same size, which a shader samples as a sampler2DArray with the layer as
+// the third coordinate:
+//
+//     uniform sampler2DArray tiles;
+//     ...
+//     color = texture(tiles, vec3(uv, layer));
+//
+// Unlike the images of an atlas, layers do not bleed into each other with
+// filtering, mipmaps or wrapping, and one texture holds as many layers as
+// GLInfo().MaxArrayLayers, so a tilemap or a batch of sprites with more
+// images than fit on one page still needs a single texture.
+type TextureArray struct {
+    *Texture
+    Layers int
+
+    opts TextureOptions
+}
+
+// NewTextureArray creates a texture array of layers transparent layers of
+// width by height pixels, to be filled by SetLayer. If opts is nil, the
+// default options are used.
+func NewTextureArray(width, height, layers int, opts *TextureOptions) *TextureArray {
+    a := newTextureArray(width, height, layers, opts)
+    a.opts.apply(gl.TEXTURE_2D_ARRAY)
+    return a
+}
+
+// newTextureArray creates a transparent texture array with its mipmap levels
+// if they come from the CPU, before the options are applied.
+func newTextureArray(width, height, layers int, opts *TextureOptions) *TextureArray {
+    if opts == nil {
+        opts = &TextureOptions{}
+    }
+    a := &TextureArray{Texture: NewTexture(), Layers: layers, opts: *opts}
+    a.Width, a.Height = width, height
+    BindTexture(0, gl.TEXTURE_2D_ARRAY, a.Texture)
+    zeros := make([]byte, 4*width*height*layers)
+    for level := 0; ; level++ {
+        gl.TexImage3D(gl.TEXTURE_2D_ARRAY, int32(level), opts.format(), int32(width), int32(height), int32(layers), 0,
+            gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(zeros))
+        if !opts.CPUMipmaps || width == 1 && height == 1 {
+            break
+        }
+        width, height = maxInt(width/2, 1), maxInt(height/2, 1)
+    }
+    return a
+}
+
+// NewTextureArrayFromImages creates a texture array with a layer for each of
+// imgs, which must all have the same size.
+func NewTextureArrayFromImages(imgs []image.Image, opts *TextureOptions) (*TextureArray, error) {
+    if len(imgs) == 0 {
+        return nil, errors.New("no images for the texture array")
+    }
+    b := imgs[0].Bounds()
+    for _, img := range imgs[1:] {
+        if img.Bounds().Size() != b.Size() {
+            return nil, ErrTextureLayerSize
+        }
+    }
+    return newTextureArrayFrom(b.Dx(), b.Dy(), imgs, opts), nil
+}
+
+// NewTextureArrayFromGrid slices img into cells of width by height pixels,
+// like the tiles of a tileset or the frames of a sprite sheet, and creates a
+// texture array with a layer for each cell, row by row from the top left.
+// Partial cells at the right and bottom edges are left out.
+func NewTextureArrayFromGrid(img image.Image, width, height int, opts *TextureOptions) (*TextureArray, error) {
+    b := img.Bounds()
+    if width <= 0 || height <= 0 || b.Dx() < width || b.Dy() < height {
+        return nil, ErrTextureLayerSize
+    }
+    sub, ok := img.(interface {
+        SubImage(r image.Rectangle) image.Image
+    })
+    if !ok {
+        rgba := nrgbaPixels(img)
+        sub, b = rgba, rgba.Rect
+    }
+    var cells []image.Image
+    for y := b.Min.Y; y+height <= b.Max.Y; y += height {
+        for x := b.Min.X; x+width <= b.Max.X; x += width {
+            cells = append(cells, sub.SubImage(image.Rect(x, y, x+width, y+height)))
+        }
+    }
+    return newTextureArrayFrom(width, height, cells, opts), nil
+}
+
+func newTextureArrayFrom(width, height int, imgs []image.Image, opts *TextureOptions) *TextureArray {
+    a := newTextureArray(width, height, len(imgs), opts)
+    gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+    for i, img := range imgs {
+        a.upload(i, img)
+    }
+    a.opts.apply(gl.TEXTURE_2D_ARRAY)
+    return a
+}
+
+// upload uploads img to layer of the bound array, with its mipmaps if they
+// come from the CPU.
+func (a *TextureArray) upload(layer int, img image.Image) {
+    rgba := nrgbaPixels(img)
+    gl.TexSubImage3D(gl.TEXTURE_2D_ARRAY, 0, 0, 0, int32(layer), int32(a.Width), int32(a.Height), 1,
+        gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+    if !a.opts.CPUMipmaps {
+        return
+    }
+    for i, level := range MipmapChain(rgba, a.opts.SRGB) {
+        gl.TexSubImage3D(gl.TEXTURE_2D_ARRAY, int32(i+1), 0, 0, int32(layer), int32(level.Rect.Dx()), int32(level.Rect.Dy()), 1,
+            gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(level.Pix))
+    }
+}
+
+// SetLayer replaces the contents of layer with img, which must have the size
+// of the layers. The mipmaps are generated again if the options ask for
+// them, on the GPU for all layers, so set all layers before drawing rather
+// than one per frame.
+func (a *TextureArray) SetLayer(layer int, img image.Image) error {
+    if img.Bounds().Dx() != a.Width || img.Bounds().Dy() != a.Height {
+        return ErrTextureLayerSize
+    }
+    if layer < 0 || layer >= a.Layers {
+        return fmt.Errorf("layer %d out of range [0, %d)", layer, a.Layers)
+    }
+    BindTexture(0, gl.TEXTURE_2D_ARRAY, a.Texture)
+    gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+    a.upload(layer, img)
+    if a.opts.Mipmaps && !a.opts.CPUMipmaps {
+        gl.GenerateMipmap(gl.TEXTURE_2D_ARRAY)
+    }
+    return nil
+}
+
+// Bind binds the array to the texture unit, for a sampler2DArray uniform set
+// to unit.
+func (a *TextureArray) Bind(unit int) {
+    BindTexture(unit, gl.TEXTURE_2D_ARRAY, a.Texture)
+}
+
+// Texture3D is a GL_TEXTURE_3D texture of RGBA8 texels, which a shader
+// samples as a sampler3D with three coordinates, filtering between slices
+// too; it suits colour grading lookup tables and volumes.
+type Texture3D struct {
+    *Texture
+    Depth int
+
+    opts TextureOptions
+}
+
+// NewTexture3D creates a 3D texture of width by height by depth texels from
+// data, 4 bytes of RGBA per texel, row by row and slice by slice; nil data
+// leaves it transparent. If opts is nil, the default options are used.
+func NewTexture3D(width, height, depth int, data []byte, opts *TextureOptions) (*Texture3D, error) {
+    if data != nil && len(data) != 4*width*height*depth {
+        return nil, fmt.Errorf("%d bytes for a %dx%dx%d texture", len(data), width, height, depth)
+    }
+    if data == nil {
+        data = make([]byte, 4*width*height*depth)
+    }
+    if opts == nil {
+        opts = &TextureOptions{}
+    }
+    t := &Texture3D{Texture: NewTexture(), Depth: depth, opts: *opts}
+    t.Width, t.Height = width, height
+    BindTexture(0, gl.TEXTURE_3D, t.Texture)
+    gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+    gl.TexImage3D(gl.TEXTURE_3D, 0, opts.format(), int32(width), int32(height), int32(depth), 0,
+        gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(data))
+    t.opts.apply(gl.TEXTURE_3D)
+    return t, nil
+}
+
+// SetSlice replaces slice z of the texture with img, which must have the
+// width and height of the texture.
+func (t *Texture3D) SetSlice(z int, img image.Image) error {
+    if img.Bounds().Dx() != t.Width || img.Bounds().Dy() != t.Height {
+        return ErrTextureLayerSize
+    }
+    if z < 0 || z >= t.Depth {
+        return fmt.Errorf("slice %d out of range [0, %d)", z, t.Depth)
+    }
+    rgba := nrgbaPixels(img)
+    BindTexture(0, gl.TEXTURE_3D, t.Texture)
+    gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+    gl.TexSubImage3D(gl.TEXTURE_3D, 0, 0, 0, int32(z), int32(t.Width), int32(t.Height), 1,
+        gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+    if t.opts.mipmapped() {
+        gl.GenerateMipmap(gl.TEXTURE_3D)
+    }
+    return nil
+}
+
+// Bind binds the texture to the texture unit, for a sampler3D uniform set to
+// unit.
+func (t *Texture3D) Bind(unit int) {
+    BindTexture(unit, gl.TEXTURE_3D, t.Texture)
+}
@@ -0,0 +1,368 @@
+package tilemap
+
+import (
+    "bytes"
+    "compress/gzip"
+    "compress/zlib"
+    "encoding/base64"
+    "encoding/binary"
+    "encoding/json"
+    "encoding/xml"
+    "fmt"
+    "io"
+    "path"
+    "strconv"
+    "strings"
+)
+
+// This file decodes Tiled's XML (TMX/TSX) and JSON formats into the types of
+// tilemap.go. Only orthogonal, finite maps are supported.
+
+type tmxMap struct {
+    Orientation string       `xml:"orientation,attr"`
+    Width       int          `xml:"width,attr"`
+    Height      int          `xml:"height,attr"`
+    TileWidth   int          `xml:"tilewidth,attr"`
+    TileHeight  int          `xml:"tileheight,attr"`
+    Infinite    int          `xml:"infinite,attr"`
+    Properties  []tmxProp    `xml:"properties>property"`
+    Tilesets    []tmxTileset `xml:"tileset"`
+    Layers      []tmxLayer   `xml:"layer"`
+}
+
+type tmxProp struct {
+    Name  string `xml:"name,attr"`
+    Value string `xml:"value,attr"`
+}
+
+type tmxTileset struct {
+    FirstGID   uint32 `xml:"firstgid,attr"`
+    Source     string `xml:"source,attr"`
+    Name       string `xml:"name,attr"`
+    TileWidth  int    `xml:"tilewidth,attr"`
+    TileHeight int    `xml:"tileheight,attr"`
+    Spacing    int    `xml:"spacing,attr"`
+    Margin     int    `xml:"margin,attr"`
+    TileCount  int    `xml:"tilecount,attr"`
+    Columns    int    `xml:"columns,attr"`
+    Image      struct {
+        Source string `xml:"source,attr"`
+        Width  int    `xml:"width,attr"`
+        Height int    `xml:"height,attr"`
+    } `xml:"image"`
+}
+
+type tmxLayer struct {
+    Name       string    `xml:"name,attr"`
+    Width      int       `xml:"width,attr"`
+    Height     int       `xml:"height,attr"`
+    Visible    *int      `xml:"visible,attr"`
+    Opacity    *float32  `xml:"opacity,attr"`
+    OffsetX    float32   `xml:"offsetx,attr"`
+    OffsetY    float32   `xml:"offsety,attr"`
+    Properties []tmxProp `xml:"properties>property"`
+    Data       struct {
+        Encoding    string `xml:"encoding,attr"`
+        Compression string `xml:"compression,attr"`
+        Tiles       []struct {
+            GID uint32 `xml:"gid,attr"`
+        } `xml:"tile"`
+        Text string `xml:",chardata"`
+    } `xml:"data"`
+}
+
+// decodeTMX decodes a TMX map; read is used to load external tilesets, with
+// paths relative to the map.
+func decodeTMX(data []byte, dir string, read func(string) ([]byte, error)) (*Map, error) {
+    var t tmxMap
+    if err := xml.Unmarshal(data, &t); err != nil {
+        return nil, err
+    }
+    if t.Orientation != "" && t.Orientation != "orthogonal" {
+        return nil, fmt.Errorf("tilemap: %s maps are not supported", t.Orientation)
+    }
+    if t.Infinite != 0 {
+        return nil, fmt.Errorf("tilemap: infinite maps are not supported")
+    }
+    m := &Map{
+        Width:      t.Width,
+        Height:     t.Height,
+        TileWidth:  t.TileWidth,
+        TileHeight: t.TileHeight,
+        Properties: props(t.Properties),
+    }
+    for _, ts := range t.Tilesets {
+        firstGID, base := ts.FirstGID, dir
+        if ts.Source != "" {
+            // external tileset
+            file := path.Join(dir, ts.Source)
+            data, err := read(file)
+            if err != nil {
+                return nil, err
+            }
+            ts = tmxTileset{}
+            if err := xml.Unmarshal(data, &ts); err != nil {
+                return nil, fmt.Errorf("tilemap: %s: %v", file, err)
+            }
+            base = path.Dir(file)
+        }
+        if ts.Image.Source == "" {
+            return nil, fmt.Errorf("tilemap: tileset %q has no image; image collections are not supported", ts.Name)
+        }
+        m.Tilesets = append(m.Tilesets, &Tileset{
+            Name:       ts.Name,
+            FirstGID:   firstGID,
+            TileWidth:  ts.TileWidth,
+            TileHeight: ts.TileHeight,
+            Spacing:    ts.Spacing,
+            Margin:     ts.Margin,
+            TileCount:  ts.TileCount,
+            Columns:    ts.Columns,
+            Image:      path.Join(base, ts.Image.Source),
+        })
+    }
+    for _, l := range t.Layers {
+        layer := &Layer{
+            Name:       l.Name,
+            Width:      l.Width,
+            Height:     l.Height,
+            Visible:    l.Visible == nil || *l.Visible != 0,
+            Opacity:    1,
+            OffsetX:    l.OffsetX,
+            OffsetY:    l.OffsetY,
+            Properties: props(l.Properties),
+        }
+        if l.Opacity != nil {
+            layer.Opacity = *l.Opacity
+        }
+        var err error
+        switch l.Data.Encoding {
+        case "":
+            for _, tile := range l.Data.Tiles {
+                layer.Tiles = append(layer.Tiles, tile.GID)
+            }
+        case "csv":
+            layer.Tiles, err = decodeCSV(l.Data.Text)
+        case "base64":
+            layer.Tiles, err = decodeBase64(l.Data.Text, l.Data.Compression)
+        default:
+            err = fmt.Errorf("unsupported encoding %q", l.Data.Encoding)
+        }
+        if err == nil && len(layer.Tiles) != layer.Width*layer.Height {
+            err = fmt.Errorf("expected %d tiles, got %d", layer.Width*layer.Height, len(layer.Tiles))
+        }
+        if err != nil {
+            return nil, fmt.Errorf("tilemap: layer %q: %v", l.Name, err)
+        }
+        m.Layers = append(m.Layers, layer)
+    }
+    return m, nil
+}
+
+func props(p []tmxProp) map[string]string {
+    if len(p) == 0 {
+        return nil
+    }
+    m := make(map[string]string, len(p))
+    for _, prop := range p {
+        m[prop.Name] = prop.Value
+    }
+    return m
+}
+
+func decodeCSV(s string) ([]uint32, error) {
+    var tiles []uint32
+    for _, f := range strings.Split(s, ",") {
+        f = strings.TrimSpace(f)
+        if f == "" {
+            continue
+        }
+        gid, err := strconv.ParseUint(f, 10, 32)
+        if err != nil {
+            return nil, err
+        }
+        tiles = append(tiles, uint32(gid))
+    }
+    return tiles, nil
+}
+
+func decodeBase64(s, compression string) ([]uint32, error) {
+    data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+    if err != nil {
+        return nil, err
+    }
+    switch compression {
+    case "":
+    case "zlib", "gzip":
+        var r io.Reader
+        if compression == "zlib" {
+            r, err = zlib.NewReader(bytes.NewReader(data))
+        } else {
+            r, err = gzip.NewReader(bytes.NewReader(data))
+        }
+        if err != nil {
+            return nil, err
+        }
+        if data, err = io.ReadAll(r); err != nil {
+            return nil, err
+        }
+    default:
+        return nil, fmt.Errorf("unsupported compression %q", compression)
+    }
+    if len(data)%4 != 0 {
+        return nil, fmt.Errorf("tile data has an invalid length")
+    }
+    tiles := make([]uint32, len(data)/4)
+    for i := range tiles {
+        tiles[i] = binary.LittleEndian.Uint32(data[i*4:])
+    }
+    return tiles, nil
+}
+
+type jsonProp struct {
+    Name  string      `json:"name"`
+    Value interface{} `json:"value"`
+}
+
+type jsonTileset struct {
+    FirstGID   uint32 `json:"firstgid"`
+    Source     string `json:"source"`
+    Name       string `json:"name"`
+    TileWidth  int    `json:"tilewidth"`
+    TileHeight int    `json:"tileheight"`
+    Spacing    int    `json:"spacing"`
+    Margin     int    `json:"margin"`
+    TileCount  int    `json:"tilecount"`
+    Columns    int    `json:"columns"`
+    Image      string `json:"image"`
+}
+
+type jsonMap struct {
+    Orientation string        `json:"orientation"`
+    Width       int           `json:"width"`
+    Height      int           `json:"height"`
+    TileWidth   int           `json:"tilewidth"`
+    TileHeight  int           `json:"tileheight"`
+    Infinite    bool          `json:"infinite"`
+    Properties  []jsonProp    `json:"properties"`
+    Tilesets    []jsonTileset `json:"tilesets"`
+    Layers      []struct {
+        Type        string          `json:"type"`
+        Name        string          `json:"name"`
+        Width       int             `json:"width"`
+        Height      int             `json:"height"`
+        Visible     *bool           `json:"visible"`
+        Opacity     *float32        `json:"opacity"`
+        OffsetX     float32         `json:"offsetx"`
+        OffsetY     float32         `json:"offsety"`
+        Encoding    string          `json:"encoding"`
+        Compression string          `json:"compression"`
+        Data        json.RawMessage `json:"data"`
+        Properties  []jsonProp      `json:"properties"`
+    } `json:"layers"`
+}
+
+// decodeJSON decodes a map in Tiled's JSON format. External tilesets may be in
+// either the JSON or the TSX format.
+func decodeJSON(data []byte, dir string, read func(string) ([]byte, error)) (*Map, error) {
+    var j jsonMap
+    if err := json.Unmarshal(data, &j); err != nil {
+        return nil, err
+    }
+    if j.Orientation != "" && j.Orientation != "orthogonal" {
+        return nil, fmt.Errorf("tilemap: %s maps are not supported", j.Orientation)
+    }
+    if j.Infinite {
+        return nil, fmt.Errorf("tilemap: infinite maps are not supported")
+    }
+    m := &Map{
+        Width:      j.Width,
+        Height:     j.Height,
+        TileWidth:  j.TileWidth,
+        TileHeight: j.TileHeight,
+        Properties: jsonProps(j.Properties),
+    }
+    for _, ts := range j.Tilesets {
+        firstGID, base := ts.FirstGID, dir
+        if ts.Source != "" {
+            file := path.Join(dir, ts.Source)
+            data, err := read(file)
+            if err != nil {
+                return nil, err
+            }
+            ts = jsonTileset{}
+            if strings.HasSuffix(file, ".tsx") {
+                var x tmxTileset
+                err = xml.Unmarshal(data, &x)
+                ts = jsonTileset{0, "", x.Name, x.TileWidth, x.TileHeight,
+                    x.Spacing, x.Margin, x.TileCount, x.Columns, x.Image.Source}
+            } else {
+                err = json.Unmarshal(data, &ts)
+            }
+            if err != nil {
+                return nil, fmt.Errorf("tilemap: %s: %v", file, err)
+            }
+            base = path.Dir(file)
+        }
+        if ts.Image == "" {
+            return nil, fmt.Errorf("tilemap: tileset %q has no image; image collections are not supported", ts.Name)
+        }
+        m.Tilesets = append(m.Tilesets, &Tileset{
+            Name:       ts.Name,
+            FirstGID:   firstGID,
+            TileWidth:  ts.TileWidth,
+            TileHeight: ts.TileHeight,
+            Spacing:    ts.Spacing,
+            Margin:     ts.Margin,
+            TileCount:  ts.TileCount,
+            Columns:    ts.Columns,
+            Image:      path.Join(base, ts.Image),
+        })
+    }
+    for _, l := range j.Layers {
+        if l.Type != "tilelayer" {
+            continue
+        }
+        layer := &Layer{
+            Name:       l.Name,
+            Width:      l.Width,
+            Height:     l.Height,
+            Visible:    l.Visible == nil || *l.Visible,
+            Opacity:    1,
+            OffsetX:    l.OffsetX,
+            OffsetY:    l.OffsetY,
+            Properties: jsonProps(l.Properties),
+        }
+        if l.Opacity != nil {
+            layer.Opacity = *l.Opacity
+        }
+        var err error
+        if l.Encoding == "base64" {
+            var s string
+            if err = json.Unmarshal(l.Data, &s); err == nil {
+                layer.Tiles, err = decodeBase64(s, l.Compression)
+            }
+        } else {
+            err = json.Unmarshal(l.Data, &layer.Tiles)
+        }
+        if err == nil && len(layer.Tiles) != layer.Width*layer.Height {
+            err = fmt.Errorf("expected %d tiles, got %d", layer.Width*layer.Height, len(layer.Tiles))
+        }
+        if err != nil {
+            return nil, fmt.Errorf("tilemap: layer %q: %v", l.Name, err)
+        }
+        m.Layers = append(m.Layers, layer)
+    }
+    return m, nil
+}
+
+func jsonProps(p []jsonProp) map[string]string {
+    if len(p) == 0 {
+        return nil
+    }
+    m := make(map[string]string, len(p))
+    for _, prop := range p {
+        m[prop.Name] = fmt.Sprint(prop.Value)
+    }
+    return m
+}
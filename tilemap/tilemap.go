@@ -0,0 +1,360 @@
+/*
+Package tilemap loads orthogonal tile maps made with the Tiled map editor, in
+its TMX (XML) or JSON format, and renders them with a gome.Camera2D:
+
+    m, err := tilemap.Load("maps/level1.tmx")
+    if err != nil {
+        // handle error
+    }
+    defer m.Delete()
+
+    cam := &gome.Camera2D{Position: mgl32.Vec2{160, 120}, Zoom: 2}
+    for app.Tick() {
+        m.Draw(cam, width, height)
+    }
+
+Tile layers are split into chunks of ChunkSize × ChunkSize tiles that are
+uploaded once as meshes; only chunks intersecting the camera's bounds are
+drawn. Tilesets may be embedded in the map or external TSX or JSON files, but
+must consist of a single image. Object and image layers are ignored.
+*/
+package tilemap
+
+import (
+    "fmt"
+    "github.com/snorredc/gome"
+    "github.com/snorredc/gome/internal/gl"
+    "io/fs"
+    "os"
+    "path"
+    "strings"
+)
+
+// ChunkSize is the width and height in tiles of the chunks layers are split
+// into for drawing. It affects maps loaded after it is changed.
+var ChunkSize = 16
+
+// Flags stored in the high bits of global tile IDs.
+const (
+    FlipHorizontal uint32 = 0x80000000
+    FlipVertical   uint32 = 0x40000000
+    FlipDiagonal   uint32 = 0x20000000
+
+    flipMask = FlipHorizontal | FlipVertical | FlipDiagonal
+)
+
+// Map is a loaded tile map. Positions are in pixels with the origin in the
+// top left corner of the map, which is also how gome.Camera2D measures world
+// coordinates.
+type Map struct {
+    Width, Height         int // in tiles
+    TileWidth, TileHeight int // in pixels
+    Tilesets              []*Tileset
+    Layers                []*Layer
+    Properties            map[string]string
+
+    program    *gome.Program
+    projection int32
+    opacity    int32
+}
+
+// Tileset is an image divided into tiles.
+type Tileset struct {
+    Name                  string
+    FirstGID              uint32 // global ID of the first tile
+    TileWidth, TileHeight int
+    Spacing, Margin       int
+    TileCount, Columns    int
+    Image                 string // path of the image in the file system of the map
+    Texture               *gome.Texture
+
+    material *gome.Material
+}
+
+// Layer is a tile layer.
+type Layer struct {
+    Name             string
+    Width, Height    int
+    Visible          bool
+    Opacity          float32
+    OffsetX, OffsetY float32
+    Properties       map[string]string
+
+    // Tiles holds the global tile IDs of the layer row by row, including the
+    // flip flags. Zero means there is no tile.
+    Tiles []uint32
+
+    chunks []chunk
+}
+
+type chunk struct {
+    mesh   *gome.Mesh
+    bounds gome.Rect
+}
+
+// Tile returns the global ID of the tile at (x, y), without the flip flags,
+// or zero if there is no tile or (x, y) is outside of the layer.
+func (l *Layer) Tile(x, y int) uint32 {
+    if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+        return 0
+    }
+    return l.Tiles[y*l.Width+x] &^ flipMask
+}
+
+const vertexShader = `#version 150
+uniform mat4 projection;
+in vec3 position;
+in vec2 texcoord;
+out vec2 fragTexcoord;
+void main() {
+    fragTexcoord = texcoord;
+    gl_Position = projection * vec4(position, 1.0);
+}
+`
+
+const fragmentShader = `#version 150
+uniform sampler2D tex;
+uniform float opacity;
+in vec2 fragTexcoord;
+out vec4 outColor;
+void main() {
+    vec4 c = texture(tex, fragTexcoord);
+    outColor = vec4(c.rgb, c.a * opacity);
+}
+`
+
+// Load loads the .tmx or .json map at path in gome's asset file system (see
+// gome.SetAssetFS). Tilesets and images are resolved relative to the map.
+func Load(path string) (*Map, error) {
+    return LoadFS(gome.AssetFS(), path)
+}
+
+// LoadFS is like Load but reads the map and the files it references from
+// fsys.
+func LoadFS(fsys fs.FS, name string) (*Map, error) {
+    read := func(name string) ([]byte, error) {
+        if fsys == nil {
+            return os.ReadFile(name)
+        }
+        return fs.ReadFile(fsys, name)
+    }
+    data, err := read(name)
+    if err != nil {
+        return nil, err
+    }
+    var m *Map
+    switch strings.ToLower(path.Ext(name)) {
+    case ".tmx":
+        m, err = decodeTMX(data, path.Dir(name), read)
+    case ".json", ".tmj":
+        m, err = decodeJSON(data, path.Dir(name), read)
+    default:
+        err = fmt.Errorf("tilemap: unknown map format %q", path.Ext(name))
+    }
+    if err != nil {
+        return nil, fmt.Errorf("%s: %v", name, err)
+    }
+    if err := m.init(fsys); err != nil {
+        m.Delete()
+        return nil, fmt.Errorf("%s: %v", name, err)
+    }
+    return m, nil
+}
+
+// init loads the tileset textures and builds the chunk meshes.
+func (m *Map) init(fsys fs.FS) error {
+    program, err := gome.NewProgram(vertexShader, fragmentShader)
+    if err != nil {
+        return err
+    }
+    m.program = program
+    m.projection = program.GetUniformLocation("projection")
+    m.opacity = program.GetUniformLocation("opacity")
+
+    for _, ts := range m.Tilesets {
+        ts.Texture, err = gome.LoadTextureFS(fsys, ts.Image, &gome.TextureOptions{
+            MinFilter: gl.NEAREST,
+            MagFilter: gl.NEAREST,
+        })
+        if err != nil {
+            return err
+        }
+        if ts.Columns == 0 && ts.TileWidth > 0 {
+            ts.Columns = (ts.Texture.Width - 2*ts.Margin + ts.Spacing) / (ts.TileWidth + ts.Spacing)
+        }
+        ts.material = &gome.Material{
+            Name:       ts.Name,
+            Diffuse:    [4]float32{1, 1, 1, 1},
+            DiffuseMap: ts.Texture,
+        }
+    }
+    for _, l := range m.Layers {
+        for y := 0; y < l.Height; y += ChunkSize {
+            for x := 0; x < l.Width; x += ChunkSize {
+                if c, ok := m.buildChunk(l, x, y); ok {
+                    l.chunks = append(l.chunks, c)
+                }
+            }
+        }
+    }
+    return nil
+}
+
+// tileset returns the tileset containing the tile with the given global ID.
+func (m *Map) tileset(gid uint32) *Tileset {
+    var found *Tileset
+    for _, ts := range m.Tilesets {
+        if ts.FirstGID <= gid && (found == nil || ts.FirstGID > found.FirstGID) {
+            found = ts
+        }
+    }
+    return found
+}
+
+// buildChunk builds the mesh of the chunk of l whose top left tile is at
+// (x0, y0), with a submesh per tileset. It reports false for empty chunks.
+func (m *Map) buildChunk(l *Layer, x0, y0 int) (chunk, bool) {
+    var (
+        vertices []gome.Vertex
+        indices  = map[*Tileset][]uint32{}
+        order    []*Tileset
+        bounds   gome.Rect
+    )
+    for y := y0; y < y0+ChunkSize && y < l.Height; y++ {
+        for x := x0; x < x0+ChunkSize && x < l.Width; x++ {
+            gid := l.Tiles[y*l.Width+x]
+            ts := m.tileset(gid &^ flipMask)
+            if gid&^flipMask == 0 || ts == nil || ts.Columns == 0 {
+                continue
+            }
+            local := int(gid&^flipMask - ts.FirstGID)
+            if ts.TileCount > 0 && local >= ts.TileCount {
+                continue
+            }
+            tw, th := float32(ts.TileWidth), float32(ts.TileHeight)
+            sx := float32(ts.Margin + local%ts.Columns*(ts.TileWidth+ts.Spacing))
+            sy := float32(ts.Margin + local/ts.Columns*(ts.TileHeight+ts.Spacing))
+            texW, texH := float32(ts.Texture.Width), float32(ts.Texture.Height)
+
+            // tiles larger than the map's grid extend up and to the right
+            dst := gome.Rect{
+                X: float32(x*m.TileWidth) + l.OffsetX,
+                Y: float32((y+1)*m.TileHeight) - th + l.OffsetY,
+                W: tw,
+                H: th,
+            }
+            if len(vertices) == 0 {
+                bounds = dst
+            } else {
+                bounds = union(bounds, dst)
+            }
+
+            base := uint32(len(vertices))
+            for _, corner := range [4][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}} {
+                u, v := corner[0], corner[1]
+                if gid&FlipHorizontal != 0 {
+                    u = 1 - u
+                }
+                if gid&FlipVertical != 0 {
+                    v = 1 - v
+                }
+                if gid&FlipDiagonal != 0 {
+                    u, v = v, u
+                }
+                vertices = append(vertices, gome.Vertex{
+                    Position: [3]float32{dst.X + corner[0]*tw, dst.Y + corner[1]*th, 0},
+                    Normal:   [3]float32{0, 0, 1},
+                    Texcoord: [2]float32{(sx + u*tw) / texW, (sy + v*th) / texH},
+                })
+            }
+            if _, ok := indices[ts]; !ok {
+                order = append(order, ts)
+            }
+            indices[ts] = append(indices[ts], base, base+1, base+2, base, base+2, base+3)
+        }
+    }
+    if len(vertices) == 0 {
+        return chunk{}, false
+    }
+    var all []uint32
+    var submeshes []gome.Submesh
+    for _, ts := range order {
+        submeshes = append(submeshes, gome.Submesh{
+            Material: ts.material,
+            First:    len(all),
+            Count:    len(indices[ts]),
+        })
+        all = append(all, indices[ts]...)
+    }
+    return chunk{gome.NewMesh(vertices, all, submeshes), bounds}, true
+}
+
+func union(a, b gome.Rect) gome.Rect {
+    x0, y0 := min(a.X, b.X), min(a.Y, b.Y)
+    x1, y1 := max(a.X+a.W, b.X+b.W), max(a.Y+a.H, b.Y+b.H)
+    return gome.Rect{X: x0, Y: y0, W: x1 - x0, H: y1 - y0}
+}
+
+// Layer returns the layer with the given name, or nil.
+func (m *Map) Layer(name string) *Layer {
+    for _, l := range m.Layers {
+        if l.Name == name {
+            return l
+        }
+    }
+    return nil
+}
+
+// Draw draws all visible layers in order, as seen by cam in a viewport of the
+// given size in pixels. Like SpriteBatch.Begin it enables alpha blending and
+// disables depth testing.
+func (m *Map) Draw(cam *gome.Camera2D, width, height float32) {
+    for _, l := range m.Layers {
+        if l.Visible {
+            m.DrawLayer(l, cam, width, height)
+        }
+    }
+}
+
+// DrawLayer draws the layer l of m, whether it is visible or not. This allows
+// drawing sprites between layers.
+func (m *Map) DrawLayer(l *Layer, cam *gome.Camera2D, width, height float32) {
+    gome.UseProgram(m.program)
+    projection := cam.Matrix(width, height)
+    gl.UniformMatrix4fv(m.projection, 1, false, &projection[0])
+    gl.Uniform1f(m.opacity, l.Opacity)
+    gome.Enable(gl.BLEND)
+    gome.Disable(gl.DEPTH_TEST)
+    gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+
+    view := cam.Bounds(width, height)
+    for _, c := range l.chunks {
+        if !c.bounds.Intersects(view) {
+            continue
+        }
+        c.mesh.Draw(func(mat *gome.Material) {
+            gome.BindTexture(0, gl.TEXTURE_2D, mat.DiffuseMap)
+        })
+    }
+}
+
+// Delete deletes the GL objects of the map: the chunk meshes, the tileset
+// textures and the shader program.
+func (m *Map) Delete() {
+    for _, l := range m.Layers {
+        for _, c := range l.chunks {
+            c.mesh.Delete()
+        }
+        l.chunks = nil
+    }
+    for _, ts := range m.Tilesets {
+        if ts.Texture != nil {
+            ts.Texture.Delete()
+            ts.Texture = nil
+        }
+    }
+    if m.program != nil {
+        m.program.Delete()
+        m.program = nil
+    }
+}
@@ -0,0 +1,129 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/glfw"
+    "time"
+)
+
+// FixedTimestep runs updates at a fixed rate, independent of the frame rate.
+// Calling Update once per frame runs as many steps as needed to keep up with
+// the time that passed, carrying over the remainder to the next frame, so
+// that simulations and animations advance at the same speed on every machine:
+//
+//     loop := &gome.FixedTimestep{Step: time.Second / 120}
+//     for app.Tick() {
+//         alpha := loop.Update(update)
+//         render(alpha)
+//     }
+type FixedTimestep struct {
+    Step     time.Duration // duration of a step; defaults to 1/60 s
+    MaxSteps int           // maximum number of steps per Update; defaults to 8
+
+    acc time.Duration
+}
+
+// Update advances the timestep by the duration of the last frame (see
+// FrameTime) and calls update once for every whole step, with the step as
+// its argument. The time scale (see SetTimeScale) changes how many steps
+// run, not their length. If more than MaxSteps steps are due, as after a
+// long stall, the excess time is dropped instead of running ever more steps
+// to catch up.
+//
+// It returns how far the time is into the next step, between 0 and 1, which
+// can be used to interpolate between the last two simulated states.
+//
+// While paused (see Pause), no time passes: update is only called once in
+// each frame after StepFrame, and the value returned stays the same.
+func (t *FixedTimestep) Update(update func(dt time.Duration)) float64 {
+    step, max := t.Step, t.MaxSteps
+    if step <= 0 {
+        step = time.Second / 60
+    }
+    if max <= 0 {
+        max = 8
+    }
+    if pause.paused {
+        if pause.stepping {
+            update(step)
+        }
+        return float64(t.acc) / float64(step)
+    }
+    t.acc += FrameTime()
+    for n := 0; t.acc >= step; n++ {
+        if n == max {
+            t.acc %= step
+            break
+        }
+        update(step)
+        t.acc -= step
+    }
+    return float64(t.acc) / float64(step)
+}
+
+// Reset discards the time carried over from previous frames.
+func (t *FixedTimestep) Reset() {
+    t.acc = 0
+}
+
+// PauseKey toggles between Pause and Resume when pressed, and StepKey calls
+// StepFrame, for debugging simulations frame by frame. Set them to
+// glfw.KeyUnknown to disable them, e.g. in release builds.
+var (
+    PauseKey = glfw.KeyPause
+    StepKey  = glfw.KeyF10
+)
+
+var pause struct {
+    paused    bool
+    requested int  // steps requested by StepFrame
+    stepping  bool // whether this frame runs one of them
+}
+
+// Pause freezes every FixedTimestep: Update stops advancing time and calling
+// its update function, while the main loop goes on polling events and
+// rendering, so the frozen state can be inspected, e.g. with the debug
+// overlay. The timers of After and Every stop as well, while NextFrame
+// functions still run. StepFrame advances it one step at a time.
+func Pause() {
+    pause.paused = true
+}
+
+// Resume undoes Pause. The time spent paused is not caught up with.
+func Resume() {
+    pause.paused = false
+    pause.requested, pause.stepping = 0, false
+}
+
+// Paused reports whether the fixed timesteps are paused.
+func Paused() bool {
+    return pause.paused
+}
+
+// StepFrame makes every FixedTimestep run exactly one step in the next frame
+// while paused, and the timers of After and Every advance by the time of
+// that frame. Calls in the same frame add up, one step per frame. It does
+// nothing if not paused.
+func StepFrame() {
+    if pause.paused {
+        pause.requested++
+    }
+}
+
+// updatePause handles PauseKey and StepKey and picks the frame's step. It
+// is called at every Tick.
+func updatePause() {
+    if PauseKey != glfw.KeyUnknown && KeyPressed(PauseKey) {
+        if pause.paused {
+            Resume()
+        } else {
+            Pause()
+        }
+    }
+    if StepKey != glfw.KeyUnknown && KeyPressed(StepKey) {
+        StepFrame()
+    }
+    pause.stepping = pause.paused && pause.requested > 0
+    if pause.stepping {
+        pause.requested--
+    }
+}
@@ -0,0 +1,102 @@
+package tween
+
+import (
+    "math"
+)
+
+// Easing maps the linear progress of a tween, from 0 to 1, to the progress of
+// its value. An easing should map 0 to 0 and 1 to 1; in between it may leave
+// that range, to overshoot like EaseOutBack.
+type Easing func(t float64) float64
+
+// Linear moves at a constant speed.
+func Linear(t float64) float64 {
+    return t
+}
+
+// EaseInQuad starts slowly and accelerates.
+func EaseInQuad(t float64) float64 {
+    return t * t
+}
+
+// EaseOutQuad starts fast and decelerates.
+func EaseOutQuad(t float64) float64 {
+    return 1 - (1-t)*(1-t)
+}
+
+// EaseInOutQuad accelerates until halfway and then decelerates.
+func EaseInOutQuad(t float64) float64 {
+    if t < 0.5 {
+        return 2 * t * t
+    }
+    return 1 - 2*(1-t)*(1-t)
+}
+
+// EaseInCubic is like EaseInQuad, with a stronger acceleration.
+func EaseInCubic(t float64) float64 {
+    return t * t * t
+}
+
+// EaseOutCubic is like EaseOutQuad, with a stronger deceleration. It suits
+// most UI and camera motion.
+func EaseOutCubic(t float64) float64 {
+    u := 1 - t
+    return 1 - u*u*u
+}
+
+// EaseInOutCubic is like EaseInOutQuad, with stronger acceleration and
+// deceleration.
+func EaseInOutCubic(t float64) float64 {
+    if t < 0.5 {
+        return 4 * t * t * t
+    }
+    u := 1 - t
+    return 1 - 4*u*u*u
+}
+
+// EaseInSine starts slowly along a quarter sine wave.
+func EaseInSine(t float64) float64 {
+    return 1 - math.Cos(t*math.Pi/2)
+}
+
+// EaseOutSine decelerates along a quarter sine wave.
+func EaseOutSine(t float64) float64 {
+    return math.Sin(t * math.Pi / 2)
+}
+
+// EaseInOutSine accelerates and decelerates along half a cosine wave.
+func EaseInOutSine(t float64) float64 {
+    return (1 - math.Cos(t*math.Pi)) / 2
+}
+
+// EaseOutBack overshoots the target a little and settles back onto it.
+func EaseOutBack(t float64) float64 {
+    const c = 1.70158
+    u := t - 1
+    return 1 + (c+1)*u*u*u + c*u*u
+}
+
+// EaseOutElastic overshoots and oscillates around the target like a spring.
+func EaseOutElastic(t float64) float64 {
+    if t <= 0 || t >= 1 {
+        return t
+    }
+    return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*2*math.Pi/3) + 1
+}
+
+// EaseOutBounce bounces on the target like a dropped ball.
+func EaseOutBounce(t float64) float64 {
+    const n, d = 7.5625, 2.75
+    switch {
+    case t < 1/d:
+        return n * t * t
+    case t < 2/d:
+        t -= 1.5 / d
+        return n*t*t + 0.75
+    case t < 2.5/d:
+        t -= 2.25 / d
+        return n*t*t + 0.9375
+    }
+    t -= 2.625 / d
+    return n*t*t + 0.984375
+}
@@ -0,0 +1,63 @@
+package tween
+
+import (
+    "math"
+    "testing"
+)
+
+var easings = map[string]Easing{
+    "Linear":         Linear,
+    "EaseInQuad":     EaseInQuad,
+    "EaseOutQuad":    EaseOutQuad,
+    "EaseInOutQuad":  EaseInOutQuad,
+    "EaseInCubic":    EaseInCubic,
+    "EaseOutCubic":   EaseOutCubic,
+    "EaseInOutCubic": EaseInOutCubic,
+    "EaseInSine":     EaseInSine,
+    "EaseOutSine":    EaseOutSine,
+    "EaseInOutSine":  EaseInOutSine,
+    "EaseOutBack":    EaseOutBack,
+    "EaseOutElastic": EaseOutElastic,
+    "EaseOutBounce":  EaseOutBounce,
+}
+
+func TestEasingEndpoints(t *testing.T) {
+    for name, ease := range easings {
+        if v := ease(0); math.Abs(v) > 1e-9 {
+            t.Errorf("%s(0) = %v, want 0", name, v)
+        }
+        if v := ease(1); math.Abs(v-1) > 1e-9 {
+            t.Errorf("%s(1) = %v, want 1", name, v)
+        }
+    }
+    for _, name := range []string{"EaseInOutQuad", "EaseInOutCubic", "EaseInOutSine"} {
+        if v := easings[name](0.5); math.Abs(v-0.5) > 1e-9 {
+            t.Errorf("%s(0.5) = %v, want 0.5", name, v)
+        }
+    }
+}
+
+func TestEasingRange(t *testing.T) {
+    overshooting := map[string]bool{"EaseOutBack": true, "EaseOutElastic": true}
+    for name, ease := range easings {
+        over := false
+        prev := 0.0
+        for i := 1; i <= 100; i++ {
+            v := ease(float64(i) / 100)
+            if v > 1+1e-9 {
+                over = true
+            }
+            // only the bounces and springs go back
+            if !overshooting[name] && name != "EaseOutBounce" && v < prev {
+                t.Errorf("%s decreases at %v", name, float64(i)/100)
+            }
+            if v < -1e-9 {
+                t.Errorf("%s(%v) = %v below 0", name, float64(i)/100, v)
+            }
+            prev = v
+        }
+        if over != overshooting[name] {
+            t.Errorf("%s overshoots %v, want %v", name, over, overshooting[name])
+        }
+    }
+}
@@ -0,0 +1,259 @@
+/*
+Package tween animates values towards targets over time with easing curves,
+for UI transitions, camera moves and the like:
+
+    tween.To(&panel.X, 0, 300*time.Millisecond, tween.EaseOutCubic).
+        Then(&panel.Alpha, 1, 200*time.Millisecond, tween.Linear).
+        OnComplete(func() { panel.Ready = true })
+
+    for app.Tick() {
+        tween.Update(gome.FrameTime())
+        ...
+    }
+
+A tween takes the current value of its variable as the start when it
+starts, so a tween chained with Then continues from wherever the previous one
+left the value. Tweens are run by a Manager; the package-level functions use
+Default.
+*/
+package tween
+
+import (
+    "fmt"
+    "github.com/go-gl/mathgl/mgl32"
+    "github.com/snorredc/gome"
+    "image/color"
+    "time"
+)
+
+// Tween animates a variable from its value at the start to a target.
+type Tween struct {
+    ptr      interface{}
+    target   interface{}
+    duration time.Duration
+    ease     Easing
+
+    manager  *Manager
+    delay    time.Duration
+    elapsed  time.Duration
+    started  bool
+    done     bool
+    set      func(t float64)
+    next     []*Tween
+    complete []func()
+}
+
+// Manager runs a set of tweens.
+type Manager struct {
+    tweens []*Tween
+}
+
+// NewManager returns a manager without tweens.
+func NewManager() *Manager {
+    return &Manager{}
+}
+
+// Default is the manager of the package-level functions.
+var Default = NewManager()
+
+// To starts a tween animating the variable ptr points to towards target over
+// duration d, with the easing ease, or Linear if ease is nil. The supported
+// variables are *float32, *float64, *mgl32.Vec2, *mgl32.Vec3, *mgl32.Vec4,
+// *gome.Color and *color.NRGBA, and target must be of the type pointed to; To panics
+// otherwise.
+func (m *Manager) To(ptr, target interface{}, d time.Duration, ease Easing) *Tween {
+    t := newTween(m, ptr, target, d, ease)
+    m.tweens = append(m.tweens, t)
+    return t
+}
+
+// To starts a tween in Default (see Manager.To).
+func To(ptr, target interface{}, d time.Duration, ease Easing) *Tween {
+    return Default.To(ptr, target, d, ease)
+}
+
+func newTween(m *Manager, ptr, target interface{}, d time.Duration, ease Easing) *Tween {
+    if ease == nil {
+        ease = Linear
+    }
+    if err := checkTypes(ptr, target); err != nil {
+        panic(err)
+    }
+    return &Tween{ptr: ptr, target: target, duration: d, ease: ease, manager: m}
+}
+
+// Update advances the tweens of m by dt, usually gome.FrameTime(), setting
+// their variables, and calls the completion functions of the tweens that
+// finish. Tweens of menus and the like that should ignore gome.SetTimeScale
+// can be kept in a Manager of their own updated by gome.UnscaledDelta().
+func (m *Manager) Update(dt time.Duration) {
+    // tweens started during the update wait for the next one
+    tweens := m.tweens
+    m.tweens = nil
+    for _, t := range tweens {
+        if !t.update(dt) {
+            m.tweens = append(m.tweens, t)
+        }
+    }
+}
+
+// Update updates Default (see Manager.Update).
+func Update(dt time.Duration) {
+    Default.Update(dt)
+}
+
+// Clear stops all tweens of m, leaving their variables as they are.
+func (m *Manager) Clear() {
+    for _, t := range m.tweens {
+        t.done = true
+    }
+    m.tweens = nil
+}
+
+// Active returns the number of tweens of m that have not finished.
+func (m *Manager) Active() int {
+    return len(m.tweens)
+}
+
+// update advances t by dt and reports whether it has finished.
+func (t *Tween) update(dt time.Duration) bool {
+    if t.done {
+        return true
+    }
+    if t.delay > 0 {
+        if dt < t.delay {
+            t.delay -= dt
+            return false
+        }
+        dt -= t.delay
+        t.delay = 0
+    }
+    if !t.started {
+        t.set = interpolator(t.ptr, t.target)
+        t.started = true
+    }
+    t.elapsed += dt
+    if t.duration <= 0 || t.elapsed >= t.duration {
+        t.set(1)
+        t.finish()
+        return true
+    }
+    t.set(t.ease(float64(t.elapsed) / float64(t.duration)))
+    return false
+}
+
+func (t *Tween) finish() {
+    t.done = true
+    for _, f := range t.complete {
+        f()
+    }
+    for _, n := range t.next {
+        t.manager.tweens = append(t.manager.tweens, n)
+    }
+}
+
+// Then returns a tween that starts once t has finished, animating ptr
+// towards target like To. Several tweens chained to the same t run at the
+// same time.
+func (t *Tween) Then(ptr, target interface{}, d time.Duration, ease Easing) *Tween {
+    n := newTween(t.manager, ptr, target, d, ease)
+    t.next = append(t.next, n)
+    return n
+}
+
+// Delay postpones the start of t by d. It returns t, for chaining.
+func (t *Tween) Delay(d time.Duration) *Tween {
+    t.delay += d
+    return t
+}
+
+// OnComplete registers f to be called when t reaches its target. It is not
+// called if t is stopped. It returns t, for chaining.
+func (t *Tween) OnComplete(f func()) *Tween {
+    t.complete = append(t.complete, f)
+    return t
+}
+
+// Stop stops t where it is, without calling its completion functions or
+// starting the tweens chained to it.
+func (t *Tween) Stop() {
+    t.done = true
+}
+
+// Done reports whether t has reached its target or has been stopped.
+func (t *Tween) Done() bool {
+    return t.done
+}
+
+func checkTypes(ptr, target interface{}) error {
+    ok := false
+    switch ptr.(type) {
+    case *float32:
+        _, ok = target.(float32)
+    case *float64:
+        _, ok = target.(float64)
+    case *mgl32.Vec2:
+        _, ok = target.(mgl32.Vec2)
+    case *mgl32.Vec3:
+        _, ok = target.(mgl32.Vec3)
+    case *mgl32.Vec4:
+        _, ok = target.(mgl32.Vec4)
+    case *gome.Color:
+        _, ok = target.(gome.Color)
+    case *color.NRGBA:
+        _, ok = target.(color.NRGBA)
+    default:
+        return fmt.Errorf("tween: cannot animate %T", ptr)
+    }
+    if !ok {
+        return fmt.Errorf("tween: target %T does not match %T", target, ptr)
+    }
+    return nil
+}
+
+// interpolator returns a function setting the variable ptr points to a
+// fraction t of the way from its current value to target.
+func interpolator(ptr, target interface{}) func(t float64) {
+    switch p := ptr.(type) {
+    case *float32:
+        from, to := *p, target.(float32)
+        return func(t float64) { *p = from + (to-from)*float32(t) }
+    case *float64:
+        from, to := *p, target.(float64)
+        return func(t float64) { *p = from + (to-from)*t }
+    case *mgl32.Vec2:
+        from, to := *p, target.(mgl32.Vec2)
+        return func(t float64) { *p = from.Add(to.Sub(from).Mul(float32(t))) }
+    case *mgl32.Vec3:
+        from, to := *p, target.(mgl32.Vec3)
+        return func(t float64) { *p = from.Add(to.Sub(from).Mul(float32(t))) }
+    case *mgl32.Vec4:
+        from, to := *p, target.(mgl32.Vec4)
+        return func(t float64) { *p = from.Add(to.Sub(from).Mul(float32(t))) }
+    case *gome.Color:
+        from, to := *p, target.(gome.Color)
+        return func(t float64) { *p = from.Lerp(to, float32(t)) }
+    case *color.NRGBA:
+        from, to := *p, target.(color.NRGBA)
+        return func(t float64) {
+            *p = color.NRGBA{
+                lerpByte(from.R, to.R, t),
+                lerpByte(from.G, to.G, t),
+                lerpByte(from.B, to.B, t),
+                lerpByte(from.A, to.A, t),
+            }
+        }
+    }
+    panic(fmt.Sprintf("tween: cannot animate %T", ptr))
+}
+
+// lerpByte interpolates between a and b, clamping overshooting easings.
+func lerpByte(a, b uint8, t float64) uint8 {
+    v := float64(a) + (float64(b)-float64(a))*t + 0.5
+    if v < 0 {
+        return 0
+    } else if v > 255 {
+        return 255
+    }
+    return uint8(v)
+}
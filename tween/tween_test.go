@@ -0,0 +1,140 @@
+package tween
+
+import (
+    "github.com/go-gl/mathgl/mgl32"
+    "image/color"
+    "reflect"
+    "testing"
+    "time"
+)
+
+const ms = time.Millisecond
+
+func TestTweenCompletion(t *testing.T) {
+    m := NewManager()
+    x := float32(10)
+    completed := 0
+    tw := m.To(&x, float32(20), 100*ms, nil).OnComplete(func() { completed++ })
+
+    m.Update(25 * ms)
+    if x != 12.5 || tw.Done() {
+        t.Errorf("after a quarter %v, done %v, want 12.5 and not done", x, tw.Done())
+    }
+    x = 0
+    m.Update(25 * ms)
+    // the start was taken at the first update
+    if x != 15 {
+        t.Errorf("halfway %v, want 15", x)
+    }
+    m.Update(60 * ms)
+    if x != 20 || !tw.Done() || completed != 1 || m.Active() != 0 {
+        t.Errorf("past the end %v, done %v, completed %d times, %d active", x, tw.Done(), completed, m.Active())
+    }
+    m.Update(100 * ms)
+    if completed != 1 {
+        t.Errorf("completed %d times, want 1", completed)
+    }
+}
+
+func TestTweenEndsOnTarget(t *testing.T) {
+    for name, ease := range easings {
+        m := NewManager()
+        v := mgl32.Vec2{1, 2}
+        m.To(&v, mgl32.Vec2{-3, 7}, 90*ms, ease)
+        for i := 0; i < 10; i++ {
+            m.Update(10 * ms)
+        }
+        if v != (mgl32.Vec2{-3, 7}) {
+            t.Errorf("%s ends on %v, want the target", name, v)
+        }
+    }
+}
+
+func TestTweenZeroDuration(t *testing.T) {
+    m := NewManager()
+    x := 1.0
+    completed := false
+    m.To(&x, 2.0, 0, EaseOutBounce).OnComplete(func() { completed = true })
+    m.Update(0)
+    if x != 2 || !completed {
+        t.Errorf("%v, completed %v, want 2 and completed", x, completed)
+    }
+}
+
+func TestTweenThen(t *testing.T) {
+    m := NewManager()
+    x, y := float32(0), float32(0)
+    var order []string
+    first := m.To(&x, float32(10), 50*ms, nil).OnComplete(func() { order = append(order, "x") })
+    first.Then(&x, float32(0), 50*ms, nil).OnComplete(func() { order = append(order, "x back") })
+    first.Then(&y, float32(4), 50*ms, nil).Delay(25 * ms).OnComplete(func() { order = append(order, "y") })
+
+    m.Update(50 * ms)
+    if x != 10 || m.Active() != 2 {
+        t.Errorf("after the first %v with %d active, want 10 with 2", x, m.Active())
+    }
+    m.Update(25 * ms)
+    // the chained tweens start from where the first one left x
+    if x != 5 || y != 0 {
+        t.Errorf("x %v and y %v, want 5 and 0 during the delay", x, y)
+    }
+    m.Update(25 * ms)
+    if x != 0 || y != 2 {
+        t.Errorf("x %v and y %v, want 0 and 2", x, y)
+    }
+    m.Update(25 * ms)
+    if want := []string{"x", "x back", "y"}; !reflect.DeepEqual(order, want) {
+        t.Errorf("completed %v, want %v", order, want)
+    }
+}
+
+func TestTweenStop(t *testing.T) {
+    m := NewManager()
+    x := 0.0
+    completed := false
+    tw := m.To(&x, 1.0, 100*ms, nil).OnComplete(func() { completed = true })
+    chained := tw.Then(&x, 5.0, 100*ms, nil)
+    m.Update(50 * ms)
+    tw.Stop()
+    m.Update(100 * ms)
+    if x != 0.5 || completed || chained.Done() || m.Active() != 0 {
+        t.Errorf("stopped at %v, completed %v, %d active, want 0.5 and nothing run", x, completed, m.Active())
+    }
+
+    tw = m.To(&x, 1.0, 100*ms, nil)
+    m.Clear()
+    m.Update(50 * ms)
+    if x != 0.5 || !tw.Done() {
+        t.Errorf("cleared at %v, done %v, want 0.5 and done", x, tw.Done())
+    }
+}
+
+func TestTweenColorClamp(t *testing.T) {
+    m := NewManager()
+    c := color.NRGBA{0, 128, 255, 255}
+    m.To(&c, color.NRGBA{255, 128, 0, 255}, 100*ms, EaseOutBack)
+    // EaseOutBack is past 1 here
+    m.Update(70 * ms)
+    if c.R != 255 || c.G != 128 || c.B != 0 {
+        t.Errorf("overshooting %v, want clamped to the target", c)
+    }
+}
+
+func TestTweenTypes(t *testing.T) {
+    for _, tt := range []struct {
+        ptr, target interface{}
+    }{
+        {new(int), 1},
+        {new(float32), 1.0},
+        {float32(0), float32(1)},
+    } {
+        func() {
+            defer func() {
+                if recover() == nil {
+                    t.Errorf("To(%T, %T) did not panic", tt.ptr, tt.target)
+                }
+            }()
+            NewManager().To(tt.ptr, tt.target, ms, nil)
+        }()
+    }
+}
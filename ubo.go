@@ -0,0 +1,232 @@
+package gome
+
+import (
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "github.com/snorredc/gome/internal/gl"
+    "math"
+    "reflect"
+)
+
+var (
+    ErrUniformLayout   = errors.New("uniform block layout must be a pointer to a struct")
+    ErrUniformBindings = errors.New("no free uniform buffer binding points")
+)
+
+// UniformBlock is a uniform buffer object whose contents mirror a Go struct.
+// The struct is laid out according to the std140 rules, so the matching GLSL
+// block must be declared with layout(std140).
+//
+// Fields may be bool, int32, uint32, float32, arrays and nested structs.
+// Arrays of 2, 3 or 4 scalars are treated as vectors (vec2, ivec3, ...),
+// [9]float32 as mat3 and [16]float32 as mat4; like GLSL, matrices are stored
+// column-major. A field tagged `std140:"array"` is always treated as an array,
+// e.g. a float[4] rather than a vec4.
+type UniformBlock struct {
+    Name    string // name of the block in GLSL
+    Binding uint   // binding point the buffer is bound to
+
+    buffer *Buffer
+    value  reflect.Value
+    layout *std140Type
+    data   []byte
+}
+
+// uniformBindings keeps track of which binding points are in use.
+var uniformBindings []bool
+
+// NewUniformBlock creates a uniform buffer object for the block named name,
+// sized and laid out after layout, which must be a pointer to a struct. The
+// buffer is bound to a free binding point and filled with the current contents
+// of layout. After modifying the struct, call Update to upload the changes.
+func NewUniformBlock(name string, layout interface{}) (*UniformBlock, error) {
+    v := reflect.ValueOf(layout)
+    if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+        return nil, ErrUniformLayout
+    }
+    t, err := newStd140Type(v.Elem().Type(), "")
+    if err != nil {
+        return nil, err
+    }
+
+    binding := -1
+    for i, used := range uniformBindings {
+        if !used {
+            binding = i
+            break
+        }
+    }
+    if binding < 0 {
+        var max [1]int32
+        gl.GetIntegerv(gl.MAX_UNIFORM_BUFFER_BINDINGS, &max[0])
+        if len(uniformBindings) >= int(max[0]) {
+            return nil, ErrUniformBindings
+        }
+        binding = len(uniformBindings)
+        uniformBindings = append(uniformBindings, false)
+    }
+    uniformBindings[binding] = true
+
+    b := &UniformBlock{
+        Name:    name,
+        Binding: uint(binding),
+        buffer:  NewBuffer(),
+        value:   v.Elem(),
+        layout:  t,
+        data:    make([]byte, t.size),
+    }
+    b.layout.encode(b.data, b.value)
+    BindBuffer(gl.UNIFORM_BUFFER, b.buffer)
+    gl.BufferData(gl.UNIFORM_BUFFER, len(b.data), gl.Ptr(b.data), gl.DYNAMIC_DRAW)
+    gl.BindBufferBase(gl.UNIFORM_BUFFER, uint32(b.Binding), b.buffer.ID)
+    return b, nil
+}
+
+// Size returns the size of the block in bytes.
+func (b *UniformBlock) Size() int {
+    return len(b.data)
+}
+
+// Bind associates the block in program with the binding point of b. It has to
+// be called once for every program using the block.
+func (b *UniformBlock) Bind(program *Program) error {
+    index := gl.GetUniformBlockIndex(program.ID, gl.Str(b.Name+"\x00"))
+    if index == gl.INVALID_INDEX {
+        return fmt.Errorf("uniform block %q not found in program", b.Name)
+    }
+    gl.UniformBlockBinding(program.ID, index, uint32(b.Binding))
+    return nil
+}
+
+// Update uploads the current contents of the layout struct to the buffer.
+func (b *UniformBlock) Update() {
+    b.layout.encode(b.data, b.value)
+    BindBuffer(gl.UNIFORM_BUFFER, b.buffer)
+    gl.BufferSubData(gl.UNIFORM_BUFFER, 0, len(b.data), gl.Ptr(b.data))
+}
+
+// Delete deletes the buffer and frees its binding point.
+func (b *UniformBlock) Delete() {
+    b.buffer.Delete()
+    uniformBindings[b.Binding] = false
+}
+
+// std140Type describes how a Go type is laid out under the std140 rules.
+type std140Type struct {
+    kind   reflect.Kind // Bool, Int32, Uint32, Float32, Array or Struct
+    align  int
+    size   int
+    elem   *std140Type // element type of arrays
+    stride int         // distance between array elements
+    count  int         // number of array elements
+    fields []std140Field
+}
+
+type std140Field struct {
+    index  int
+    offset int
+    typ    *std140Type
+}
+
+func roundUp(n, align int) int {
+    return (n + align - 1) / align * align
+}
+
+func newStd140Type(t reflect.Type, tag string) (*std140Type, error) {
+    switch t.Kind() {
+    case reflect.Bool, reflect.Int32, reflect.Uint32, reflect.Float32:
+        return &std140Type{kind: t.Kind(), align: 4, size: 4}, nil
+
+    case reflect.Array:
+        elem, err := newStd140Type(t.Elem(), "")
+        if err != nil {
+            return nil, err
+        }
+        n := t.Len()
+        scalar := elem.kind != reflect.Array && elem.kind != reflect.Struct
+        if tag != "array" && scalar {
+            switch {
+            case n == 2:
+                return &std140Type{kind: reflect.Array, align: 8, size: 8,
+                    elem: elem, stride: 4, count: 2}, nil
+            case n == 3 || n == 4:
+                return &std140Type{kind: reflect.Array, align: 16, size: 4 * n,
+                    elem: elem, stride: 4, count: n}, nil
+            case (n == 9 || n == 16) && elem.kind == reflect.Float32:
+                // a matrix is an array of column vectors
+                rows := 3
+                if n == 16 {
+                    rows = 4
+                }
+                column := &std140Type{kind: reflect.Array, align: 16,
+                    size: 4 * rows, elem: elem, stride: 4, count: rows}
+                return &std140Type{kind: reflect.Array, align: 16,
+                    size: 16 * rows, elem: column, stride: 16, count: rows}, nil
+            }
+        }
+        align := roundUp(elem.align, 16)
+        stride := roundUp(elem.size, align)
+        return &std140Type{kind: reflect.Array, align: align, size: stride * n,
+            elem: elem, stride: stride, count: n}, nil
+
+    case reflect.Struct:
+        s := &std140Type{kind: reflect.Struct, align: 16}
+        offset := 0
+        for i := 0; i < t.NumField(); i++ {
+            f := t.Field(i)
+            if f.PkgPath != "" {
+                continue // unexported
+            }
+            ft, err := newStd140Type(f.Type, f.Tag.Get("std140"))
+            if err != nil {
+                return nil, fmt.Errorf("field %s: %v", f.Name, err)
+            }
+            offset = roundUp(offset, ft.align)
+            s.fields = append(s.fields, std140Field{i, offset, ft})
+            offset += ft.size
+            if ft.align > s.align {
+                s.align = ft.align
+            }
+        }
+        s.size = roundUp(offset, s.align)
+        return s, nil
+    }
+    return nil, fmt.Errorf("type %s is not supported in uniform blocks", t)
+}
+
+// encode writes v into buf according to the layout described by t.
+func (t *std140Type) encode(buf []byte, v reflect.Value) {
+    switch t.kind {
+    case reflect.Bool:
+        var u uint32
+        if v.Bool() {
+            u = 1
+        }
+        binary.LittleEndian.PutUint32(buf, u)
+    case reflect.Int32:
+        binary.LittleEndian.PutUint32(buf, uint32(v.Int()))
+    case reflect.Uint32:
+        binary.LittleEndian.PutUint32(buf, uint32(v.Uint()))
+    case reflect.Float32:
+        binary.LittleEndian.PutUint32(buf, math.Float32bits(float32(v.Float())))
+    case reflect.Array:
+        if t.elem.kind == reflect.Array && t.stride == 16 && t.elem.stride == 4 &&
+            v.Len() == t.count*t.elem.count {
+            // matrix stored as a flat array of columns
+            for c := 0; c < t.count; c++ {
+                for r := 0; r < t.elem.count; r++ {
+                    t.elem.elem.encode(buf[c*16+r*4:], v.Index(c*t.elem.count+r))
+                }
+            }
+            return
+        }
+        for i := 0; i < t.count; i++ {
+            t.elem.encode(buf[i*t.stride:], v.Index(i))
+        }
+    case reflect.Struct:
+        for _, f := range t.fields {
+            f.typ.encode(buf[f.offset:], v.Field(f.index))
+        }
+    }
+}
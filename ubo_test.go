@@ -0,0 +1,215 @@
+package gome
+
+import (
+    "encoding/binary"
+    "math"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestStd140Layouts(t *testing.T) {
+    tests := []struct {
+        name    string
+        layout  interface{}
+        offsets []int
+        size    int
+    }{
+        {"scalars", struct {
+            A bool
+            B int32
+            C uint32
+            D float32
+        }{}, []int{0, 4, 8, 12}, 16},
+        {"float after vec3", struct {
+            A [3]float32
+            B float32
+        }{}, []int{0, 12}, 16},
+        {"vec3 after float", struct {
+            A float32
+            B [3]float32
+        }{}, []int{0, 16}, 32},
+        {"vec2 after float", struct {
+            A float32
+            B [2]float32
+            C [2]float32
+        }{}, []int{0, 8, 16}, 32},
+        {"vec4 after vec2", struct {
+            A [2]int32
+            B [4]uint32
+        }{}, []int{0, 16}, 32},
+        {"float array", struct {
+            A float32
+            B [2]float32 `std140:"array"`
+            C float32
+        }{}, []int{0, 16, 48}, 64},
+        {"scalar array of 5", struct {
+            A [5]float32
+            B float32
+        }{}, []int{0, 80}, 96},
+        {"vec3 array", struct {
+            A [2][3]float32
+            B float32
+        }{}, []int{0, 32}, 48},
+        {"mat3", struct {
+            A float32
+            B [9]float32
+            C float32
+        }{}, []int{0, 16, 64}, 80},
+        {"mat4 array", struct {
+            A [2][16]float32
+            B float32
+        }{}, []int{0, 128}, 144},
+        {"nested struct", struct {
+            A float32
+            B struct{ X float32 }
+            C float32
+        }{}, []int{0, 16, 32}, 48},
+        {"struct array", struct {
+            A [2]struct {
+                X [3]float32
+                Y float32
+                Z float32
+            }
+            B float32
+        }{}, []int{0, 64}, 80},
+        {"unexported", struct {
+            A float32
+            b [4]float32
+            C float32
+        }{}, []int{0, 4}, 16},
+    }
+    for _, tt := range tests {
+        typ, err := newStd140Type(reflect.TypeOf(tt.layout), "")
+        if err != nil {
+            t.Errorf("%s: %v", tt.name, err)
+            continue
+        }
+        var offsets []int
+        for _, f := range typ.fields {
+            offsets = append(offsets, f.offset)
+        }
+        if !reflect.DeepEqual(offsets, tt.offsets) || typ.size != tt.size {
+            t.Errorf("%s: offsets %v and size %d, want %v and %d", tt.name, offsets, typ.size, tt.offsets, tt.size)
+        }
+    }
+}
+
+// std140Example is the example block of the std140 rules in the OpenGL
+// specification (4.5, section 7.6.2.2), without its mat2x3 member.
+type std140Example struct {
+    A float32
+    B [2]float32
+    C [3]float32
+    F struct {
+        D int32
+        E [2]bool
+    }
+    G float32
+    H [2]float32 `std140:"array"`
+    O [2]struct {
+        J [3]uint32
+        K [2]float32
+        L [2]float32 `std140:"array"`
+        M [2]float32
+        N [2][9]float32
+    }
+}
+
+func TestStd140Example(t *testing.T) {
+    var e std140Example
+    e.A = 1
+    e.B = [2]float32{2, 3}
+    e.C = [3]float32{4, 5, 6}
+    e.F.D = -7
+    e.F.E = [2]bool{true, false}
+    e.G = 8
+    e.H = [2]float32{9, 10}
+    for i := range e.O {
+        o := &e.O[i]
+        base := float32(100 * (i + 1))
+        o.J = [3]uint32{uint32(base) + 1, uint32(base) + 2, uint32(base) + 3}
+        o.K = [2]float32{base + 4, base + 5}
+        o.L = [2]float32{base + 6, base + 7}
+        o.M = [2]float32{base + 8, base + 9}
+        for m := range o.N {
+            for c := range o.N[m] {
+                o.N[m][c] = base + float32(10+9*m+c)
+            }
+        }
+    }
+
+    typ, err := newStd140Type(reflect.TypeOf(e), "")
+    if err != nil {
+        t.Fatal(err)
+    }
+    // the specification ends at 480, with 32 bytes for the mat2x3
+    if typ.size != 448 {
+        t.Fatalf("size %d, want 448", typ.size)
+    }
+    buf := make([]byte, typ.size)
+    typ.encode(buf, reflect.ValueOf(e))
+
+    float := func(off int) float32 { return math.Float32frombits(binary.LittleEndian.Uint32(buf[off:])) }
+    word := func(off int) uint32 { return binary.LittleEndian.Uint32(buf[off:]) }
+    floats := map[int]float32{
+        0: 1, 8: 2, 12: 3, 16: 4, 20: 5, 24: 6,
+        48: 8, 64: 9, 80: 10,
+    }
+    uints := map[int]uint32{32: math.MaxUint32 - 6, 40: 1, 44: 0}
+    for i := 0; i < 2; i++ {
+        // the members of the struct array, 96 bytes earlier than in the
+        // specification without the mat2x3 and h padded to 96
+        o := 96 + 176*i
+        base := float32(100 * (i + 1))
+        uints[o] = uint32(base) + 1
+        uints[o+8] = uint32(base) + 3
+        floats[o+16] = base + 4
+        floats[o+20] = base + 5
+        floats[o+32] = base + 6
+        floats[o+48] = base + 7
+        floats[o+64] = base + 8
+        // mat3 n[2], column-major with columns padded to vec4
+        for m := 0; m < 2; m++ {
+            for c := 0; c < 3; c++ {
+                for r := 0; r < 3; r++ {
+                    floats[o+80+48*m+16*c+4*r] = base + float32(10+9*m+3*c+r)
+                }
+            }
+        }
+    }
+    for off, want := range floats {
+        if got := float(off); got != want {
+            t.Errorf("float at %d is %v, want %v", off, got, want)
+        }
+    }
+    for off, want := range uints {
+        if got := word(off); got != want {
+            t.Errorf("uint at %d is %v, want %v", off, got, want)
+        }
+    }
+    // the padding stays zero
+    for _, off := range []int{4, 28, 36, 52, 68, 96 + 12, 96 + 36, 96 + 76, 96 + 80 + 12} {
+        if got := word(off); got != 0 {
+            t.Errorf("padding at %d is %#x", off, got)
+        }
+    }
+}
+
+func TestStd140Errors(t *testing.T) {
+    for _, layout := range []interface{}{
+        struct{ A float64 }{},
+        struct{ A string }{},
+        struct{ A struct{ B []float32 } }{},
+    } {
+        _, err := newStd140Type(reflect.TypeOf(layout), "")
+        if err == nil || !strings.HasPrefix(err.Error(), "field A: ") {
+            t.Errorf("%T: error %v, want one for field A", layout, err)
+        }
+    }
+    for _, layout := range []interface{}{std140Example{}, 1, &[]float32{}} {
+        if _, err := NewUniformBlock("Example", layout); err != ErrUniformLayout {
+            t.Errorf("%T: error %v, want ErrUniformLayout", layout, err)
+        }
+    }
+}
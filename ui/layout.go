@@ -0,0 +1,202 @@
+package ui
+
+import (
+    "github.com/snorredc/gome"
+)
+
+// Direction is the direction a Flex lays out its children in.
+type Direction int
+
+const (
+    Column Direction = iota // from top to bottom
+    Row                     // from left to right
+)
+
+// Align is how a Flex places its children across its direction.
+type Align int
+
+const (
+    Stretch Align = iota // over the whole width of a column or height of a row
+    Start                // at the left of a column or the top of a row
+    Middle
+    End
+)
+
+// Flex lays out its children in a row or a column at their minimum sizes,
+// sharing any space left over among the children added with a grow factor,
+// in proportion to it.
+type Flex struct {
+    Box
+    Direction Direction
+    Align     Align
+    Spacing   float32 // space between children; negative for none, zero for the theme's
+    Padding   float32 // space around the children
+    Panel     bool    // draw the background of the theme
+
+    children []Widget
+    grow     []float32
+    spacing  float32 // the spacing used by the last layout
+    minSizes [][2]float32
+}
+
+// NewColumn returns a column of children.
+func NewColumn(children ...Widget) *Flex {
+    f := &Flex{Direction: Column}
+    for _, c := range children {
+        f.Add(c, 0)
+    }
+    return f
+}
+
+// NewRow returns a row of children.
+func NewRow(children ...Widget) *Flex {
+    f := &Flex{Direction: Row}
+    for _, c := range children {
+        f.Add(c, 0)
+    }
+    return f
+}
+
+// Add appends w, which gets a share grow of the left-over space. It returns
+// f, for chaining.
+func (f *Flex) Add(w Widget, grow float32) *Flex {
+    f.children = append(f.children, w)
+    f.grow = append(f.grow, grow)
+    return f
+}
+
+// Children returns the children of f.
+func (f *Flex) Children() []Widget {
+    return f.children
+}
+
+// MinSize sums up the minimum sizes of the children in the direction of f.
+func (f *Flex) MinSize(t *Theme) (w, h float32) {
+    f.spacing = f.Spacing
+    if f.spacing == 0 {
+        f.spacing = t.Spacing
+    } else if f.spacing < 0 {
+        f.spacing = 0
+    }
+    f.minSizes = f.minSizes[:0]
+    var main, cross float32
+    for i, c := range f.children {
+        cw, ch := c.MinSize(t)
+        f.minSizes = append(f.minSizes, [2]float32{cw, ch})
+        if f.Direction == Row {
+            cw, ch = ch, cw
+        }
+        // cw is now the cross size and ch the main one
+        main += ch
+        if i > 0 {
+            main += f.spacing
+        }
+        if cw > cross {
+            cross = cw
+        }
+    }
+    w, h = cross+2*f.Padding, main+2*f.Padding
+    if f.Direction == Row {
+        w, h = h, w
+    }
+    return w, h
+}
+
+// SetBounds places f and lays out its children. It relies on the sizes
+// computed by the MinSize call of the same layout.
+func (f *Flex) SetBounds(r gome.Rect) {
+    f.Box.SetBounds(r)
+    if len(f.minSizes) != len(f.children) {
+        // MinSize was not called since children were added
+        return
+    }
+    // work in main and cross coordinates, swapping for a row
+    pos, crossPos := r.Y+f.Padding, r.X+f.Padding
+    length, crossLength := r.H-2*f.Padding, r.W-2*f.Padding
+    if f.Direction == Row {
+        pos, crossPos = crossPos, pos
+        length, crossLength = crossLength, length
+    }
+    var used, totalGrow float32
+    for i, s := range f.minSizes {
+        used += f.mainOf(s)
+        if i > 0 {
+            used += f.spacing
+        }
+        totalGrow += f.grow[i]
+    }
+    extra := length - used
+    if extra < 0 {
+        extra = 0
+    }
+
+    for i, c := range f.children {
+        s := f.minSizes[i]
+        size, crossSize := f.mainOf(s), f.crossOf(s)
+        if totalGrow > 0 {
+            size += extra * f.grow[i] / totalGrow
+        }
+        at := crossPos
+        switch f.Align {
+        case Stretch:
+            crossSize = crossLength
+        case Middle:
+            at += (crossLength - crossSize) / 2
+        case End:
+            at += crossLength - crossSize
+        }
+        if f.Direction == Row {
+            c.SetBounds(gome.Rect{X: pos, Y: at, W: size, H: crossSize})
+        } else {
+            c.SetBounds(gome.Rect{X: at, Y: pos, W: crossSize, H: size})
+        }
+        pos += size + f.spacing
+    }
+}
+
+func (f *Flex) mainOf(s [2]float32) float32 {
+    if f.Direction == Row {
+        return s[0]
+    }
+    return s[1]
+}
+
+func (f *Flex) crossOf(s [2]float32) float32 {
+    if f.Direction == Row {
+        return s[1]
+    }
+    return s[0]
+}
+
+// Update does nothing; the children handle the input.
+func (f *Flex) Update(u *UI) {}
+
+// Draw draws the background if Panel is set.
+func (f *Flex) Draw(u *UI, b *gome.SpriteBatch) {
+    if f.Panel {
+        b.DrawRect(f.Bounds(), u.Theme.Panel)
+    }
+}
+
+// Space is an empty widget of a fixed size, to keep widgets apart, or of no
+// size with a grow factor, to push them apart.
+type Space struct {
+    Box
+    W, H float32
+}
+
+// NewSpace returns a space of w by h.
+func NewSpace(w, h float32) *Space {
+    return &Space{W: w, H: h}
+}
+
+// MinSize returns the size of s.
+func (s *Space) MinSize(t *Theme) (w, h float32) {
+    return s.W, s.H
+}
+
+// Update does nothing.
+func (s *Space) Update(u *UI) {}
+
+// Draw draws nothing.
+func (s *Space) Draw(u *UI, b *gome.SpriteBatch) {}
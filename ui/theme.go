@@ -0,0 +1,56 @@
+package ui
+
+import (
+    "github.com/snorredc/gome"
+)
+
+// Theme holds the font, colours and spacing the widgets are drawn with.
+type Theme struct {
+    Font *gome.Font
+
+    Text   gome.Color // labels and captions
+    Dim    gome.Color // placeholders
+    Panel  gome.Color // the background of Flex containers with Panel set
+    Widget gome.Color // the background of buttons, checkboxes and fields
+    Hover  gome.Color // the background of a widget under the cursor
+    Active gome.Color // the background of a widget being pressed
+    Accent gome.Color // check marks, slider fills, carets and selections
+    Border gome.Color // the outline of a focused text field
+
+    Padding float32 // space between the edge of a widget and its content
+    Spacing float32 // default space between the children of a Flex
+}
+
+// DefaultTheme returns a dark theme with the default font of gome (see
+// gome.DefaultFont), which needs an initialised App.
+func DefaultTheme() (*Theme, error) {
+    f, err := gome.DefaultFont()
+    if err != nil {
+        return nil, err
+    }
+    return &Theme{
+        Font:    f,
+        Text:    gome.MustHex("#e8e8e8"),
+        Dim:     gome.MustHex("#8c8c8c"),
+        Panel:   gome.MustHex("#202020e0"),
+        Widget:  gome.MustHex("#3a3a3a"),
+        Hover:   gome.MustHex("#4a4a4a"),
+        Active:  gome.MustHex("#2c2c2c"),
+        Accent:  gome.MustHex("#4c8ed9"),
+        Border:  gome.MustHex("#6fa8e8"),
+        Padding: 6,
+        Spacing: 4,
+    }, nil
+}
+
+// background returns the background colour of a widget that reacts to the
+// mouse. Other widgets are not highlighted while one is being pressed.
+func (t *Theme) background(u *UI, w Widget) gome.Color {
+    switch {
+    case u.Active(w) && u.Hovered(w):
+        return t.Active
+    case u.Hovered(w) && u.active == nil:
+        return t.Hover
+    }
+    return t.Widget
+}
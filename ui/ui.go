@@ -0,0 +1,314 @@
+/*
+Package ui provides a few retained widgets for tool-style applications and
+game menus: labels, buttons, checkboxes, sliders and text fields, laid out in
+rows and columns and anchored to the edges of the screen. They are drawn with
+a gome.SpriteBatch and read the input state of gome:
+
+    theme, err := ui.DefaultTheme()
+    if err != nil {
+        // handle error
+    }
+    gui := ui.New(theme)
+    volume := &ui.Slider{Max: 1, Value: 0.8}
+    menu := ui.NewColumn(
+        ui.NewLabel("Settings"),
+        volume,
+        ui.NewButton("Quit", app.RequestClose),
+    )
+    gui.Add(menu, ui.Center, 0, 0)
+
+    for app.Tick() {
+        w, h := app.Window().GetSize()
+        gui.Update(float32(w), float32(h))
+        ...
+        batch.Begin(float32(w), float32(h))
+        gui.Draw(batch)
+        batch.End()
+    }
+
+Coordinates are those of gome.CursorPosition: screen coordinates of the main
+window, or virtual pixels if a virtual resolution is set. The widgets are
+plain structs whose fields can be changed at any time; the layout is
+recomputed by every Update.
+*/
+package ui
+
+import (
+    "github.com/snorredc/gome"
+    "github.com/snorredc/gome/internal/glfw"
+)
+
+// Widget is an element of a UI. The widgets of this package embed Box for
+// their bounds; other widgets can do the same.
+type Widget interface {
+    // MinSize returns the smallest size the widget can be laid out at.
+    MinSize(t *Theme) (w, h float32)
+    // SetBounds places the widget, at least at its minimum size.
+    SetBounds(r gome.Rect)
+    // Bounds returns the rectangle set by SetBounds.
+    Bounds() gome.Rect
+    // Update handles the input of the frame.
+    Update(u *UI)
+    // Draw draws the widget into its bounds.
+    Draw(u *UI, b *gome.SpriteBatch)
+}
+
+// Container is a widget that contains other widgets, such as Flex. The UI
+// updates, draws and hit-tests the children through the container.
+type Container interface {
+    Widget
+    Children() []Widget
+}
+
+// Box holds the bounds of a widget.
+type Box struct {
+    bounds gome.Rect
+}
+
+// SetBounds sets the bounds.
+func (b *Box) SetBounds(r gome.Rect) {
+    b.bounds = r
+}
+
+// Bounds returns the bounds.
+func (b *Box) Bounds() gome.Rect {
+    return b.bounds
+}
+
+// Anchor is the point of the screen a top-level widget is placed at.
+type Anchor int
+
+const (
+    TopLeft Anchor = iota
+    Top
+    TopRight
+    Left
+    Center
+    Right
+    BottomLeft
+    Bottom
+    BottomRight
+    Fill // stretched over the whole screen
+)
+
+type root struct {
+    widget Widget
+    anchor Anchor
+    x, y   float32
+}
+
+// UI is a set of top-level widgets with the input state shared by them: the
+// widget under the cursor, the one being pressed and the one with the
+// keyboard focus.
+type UI struct {
+    Theme *Theme
+
+    roots   []root
+    cursorX float32
+    cursorY float32
+    hovered Widget
+    active  Widget // pressed and not released yet
+    pressed bool   // active was pressed in this frame
+    clicked Widget
+    focused Widget
+}
+
+// New returns an empty UI drawn with t.
+func New(t *Theme) *UI {
+    return &UI{Theme: t}
+}
+
+// Add adds w as a top-level widget at its minimum size, placed at the anchor
+// a and moved inwards by x and y from there, e.g. 10, 10 from TopLeft is 10
+// pixels right of and below the top left corner. Widgets added later are on
+// top of earlier ones.
+func (u *UI) Add(w Widget, a Anchor, x, y float32) {
+    u.roots = append(u.roots, root{w, a, x, y})
+}
+
+// Remove removes the top-level widget w.
+func (u *UI) Remove(w Widget) {
+    for i, r := range u.roots {
+        if r.widget == w {
+            u.roots = append(u.roots[:i], u.roots[i+1:]...)
+            break
+        }
+    }
+    if u.active != nil && contains(w, u.active) {
+        u.active = nil
+    }
+    if u.focused != nil && contains(w, u.focused) {
+        u.focused = nil
+    }
+}
+
+// Update lays out the widgets on a screen of width by height and handles the
+// input of the frame. It should be called once per frame, after Tick.
+func (u *UI) Update(width, height float32) {
+    for _, r := range u.roots {
+        r.widget.SetBounds(place(r, u.Theme, width, height))
+    }
+
+    x, y := gome.CursorPosition()
+    u.cursorX, u.cursorY = float32(x), float32(y)
+    u.hovered = nil
+    for i := len(u.roots) - 1; i >= 0 && u.hovered == nil; i-- {
+        u.hovered = hit(u.roots[i].widget, u.cursorX, u.cursorY)
+    }
+    u.pressed, u.clicked = false, nil
+    if gome.MouseButtonPressed(glfw.MouseButtonLeft) {
+        u.active, u.pressed = u.hovered, true
+        if u.hovered != u.focused {
+            // clicking elsewhere takes the focus away; a widget that wants
+            // it takes it in its Update
+            u.focused = nil
+        }
+    }
+    released := gome.MouseButtonReleased(glfw.MouseButtonLeft)
+    if released && u.active != nil && u.active == u.hovered {
+        u.clicked = u.active
+    }
+
+    for _, r := range u.roots {
+        update(r.widget, u)
+    }
+    if released {
+        u.active = nil
+    }
+}
+
+// Draw draws the widgets with b, which has to be begun in the coordinates of
+// the screen.
+func (u *UI) Draw(b *gome.SpriteBatch) {
+    for _, r := range u.roots {
+        draw(r.widget, u, b)
+    }
+}
+
+// Cursor returns the position of the cursor.
+func (u *UI) Cursor() (x, y float32) {
+    return u.cursorX, u.cursorY
+}
+
+// Hovered reports whether the cursor is over w and not over a widget on top
+// of it.
+func (u *UI) Hovered(w Widget) bool {
+    return u.hovered == w
+}
+
+// Pressed reports whether the left mouse button was pressed on w in this
+// frame.
+func (u *UI) Pressed(w Widget) bool {
+    return u.pressed && u.active == w
+}
+
+// Active reports whether the left mouse button was pressed on w and is still
+// held, wherever the cursor is now, e.g. for dragging a slider.
+func (u *UI) Active(w Widget) bool {
+    return u.active == w
+}
+
+// Clicked reports whether the left mouse button was pressed and released on
+// w, completing a click in this frame.
+func (u *UI) Clicked(w Widget) bool {
+    return u.clicked == w
+}
+
+// Focus gives the keyboard focus to w, or takes it away if w is nil.
+func (u *UI) Focus(w Widget) {
+    u.focused = w
+}
+
+// Focused reports whether w has the keyboard focus.
+func (u *UI) Focused(w Widget) bool {
+    return u.focused == w
+}
+
+// WantsMouse reports whether the UI uses the mouse in this frame, because
+// the cursor is over a widget or one is being dragged, so the application
+// should ignore mouse input.
+func (u *UI) WantsMouse() bool {
+    return u.hovered != nil || u.active != nil
+}
+
+// WantsKeyboard reports whether a widget has the keyboard focus, so the
+// application should ignore keyboard input.
+func (u *UI) WantsKeyboard() bool {
+    return u.focused != nil
+}
+
+// place returns the bounds of a top-level widget.
+func place(r root, t *Theme, width, height float32) gome.Rect {
+    // containers compute the layout of their children in MinSize
+    w, h := r.widget.MinSize(t)
+    if r.anchor == Fill {
+        return gome.Rect{X: r.x, Y: r.y, W: width - 2*r.x, H: height - 2*r.y}
+    }
+    x, y := r.x, r.y
+    switch r.anchor % 3 {
+    case 1:
+        x = (width-w)/2 + r.x
+    case 2:
+        x = width - w - r.x
+    }
+    switch r.anchor / 3 {
+    case 1:
+        y = (height-h)/2 + r.y
+    case 2:
+        y = height - h - r.y
+    }
+    return gome.Rect{X: x, Y: y, W: w, H: h}
+}
+
+// hit returns the innermost widget of the tree of w at (x, y), or nil.
+func hit(w Widget, x, y float32) Widget {
+    if !inside(w.Bounds(), x, y) {
+        return nil
+    }
+    if c, ok := w.(Container); ok {
+        children := c.Children()
+        for i := len(children) - 1; i >= 0; i-- {
+            if h := hit(children[i], x, y); h != nil {
+                return h
+            }
+        }
+    }
+    return w
+}
+
+func update(w Widget, u *UI) {
+    w.Update(u)
+    if c, ok := w.(Container); ok {
+        for _, child := range c.Children() {
+            update(child, u)
+        }
+    }
+}
+
+func draw(w Widget, u *UI, b *gome.SpriteBatch) {
+    w.Draw(u, b)
+    if c, ok := w.(Container); ok {
+        for _, child := range c.Children() {
+            draw(child, u, b)
+        }
+    }
+}
+
+// contains reports whether w is in the tree of root.
+func contains(root, w Widget) bool {
+    if root == w {
+        return true
+    }
+    if c, ok := root.(Container); ok {
+        for _, child := range c.Children() {
+            if contains(child, w) {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+func inside(r gome.Rect, x, y float32) bool {
+    return x >= r.X && y >= r.Y && x < r.X+r.W && y < r.Y+r.H
+}
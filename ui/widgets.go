@@ -0,0 +1,304 @@
+package ui
+
+import (
+    "github.com/snorredc/gome"
+    "github.com/snorredc/gome/internal/glfw"
+)
+
+// Label is a line of text.
+type Label struct {
+    Box
+    Text  string
+    Color gome.Color // the colour of the text; the zero value uses the theme's
+}
+
+// NewLabel returns a label showing text.
+func NewLabel(text string) *Label {
+    return &Label{Text: text}
+}
+
+// MinSize returns the size of the text.
+func (l *Label) MinSize(t *Theme) (w, h float32) {
+    return t.Font.Measure(l.Text)
+}
+
+// Update does nothing.
+func (l *Label) Update(u *UI) {}
+
+// Draw draws the text at the left of the label, centred vertically.
+func (l *Label) Draw(u *UI, b *gome.SpriteBatch) {
+    c := l.Color
+    if c == (gome.Color{}) {
+        c = u.Theme.Text
+    }
+    r := l.Bounds()
+    _, h := u.Theme.Font.Measure(l.Text)
+    b.DrawText(u.Theme.Font, l.Text, r.X, r.Y+(r.H-h)/2, c)
+}
+
+// Button is a push button with a caption.
+type Button struct {
+    Box
+    Text    string
+    OnClick func() // called when the button is clicked
+}
+
+// NewButton returns a button with the caption text that calls onClick when
+// clicked.
+func NewButton(text string, onClick func()) *Button {
+    return &Button{Text: text, OnClick: onClick}
+}
+
+// MinSize returns the size of the caption with padding.
+func (bt *Button) MinSize(t *Theme) (w, h float32) {
+    w, h = t.Font.Measure(bt.Text)
+    return w + 2*t.Padding, h + 2*t.Padding
+}
+
+// Update calls OnClick if the button was clicked.
+func (bt *Button) Update(u *UI) {
+    if u.Clicked(bt) && bt.OnClick != nil {
+        bt.OnClick()
+    }
+}
+
+// Draw draws the button with its caption centred.
+func (bt *Button) Draw(u *UI, b *gome.SpriteBatch) {
+    t := u.Theme
+    r := bt.Bounds()
+    b.DrawRect(r, t.background(u, bt))
+    w, h := t.Font.Measure(bt.Text)
+    b.DrawText(t.Font, bt.Text, r.X+(r.W-w)/2, r.Y+(r.H-h)/2, t.Text)
+}
+
+// Checkbox is a box that is toggled by clicking it or its caption.
+type Checkbox struct {
+    Box
+    Text     string
+    Checked  bool
+    OnChange func(checked bool) // called when the box is toggled by a click
+}
+
+// NewCheckbox returns a checkbox with the caption text.
+func NewCheckbox(text string, checked bool) *Checkbox {
+    return &Checkbox{Text: text, Checked: checked}
+}
+
+// MinSize returns the size of the box and the caption.
+func (c *Checkbox) MinSize(t *Theme) (w, h float32) {
+    w, h = t.Font.Measure(c.Text)
+    box := t.Font.Height
+    return box + t.Padding + w, h + 2*t.Padding
+}
+
+// Update toggles the box if it was clicked.
+func (c *Checkbox) Update(u *UI) {
+    if u.Clicked(c) {
+        c.Checked = !c.Checked
+        if c.OnChange != nil {
+            c.OnChange(c.Checked)
+        }
+    }
+}
+
+// Draw draws the box at the left and the caption to the right of it.
+func (c *Checkbox) Draw(u *UI, b *gome.SpriteBatch) {
+    t := u.Theme
+    r := c.Bounds()
+    box := t.Font.Height
+    boxRect := gome.Rect{X: r.X, Y: r.Y + (r.H-box)/2, W: box, H: box}
+    b.DrawRect(boxRect, t.background(u, c))
+    if c.Checked {
+        inset := box / 4
+        b.DrawRect(gome.Rect{
+            X: boxRect.X + inset,
+            Y: boxRect.Y + inset,
+            W: box - 2*inset,
+            H: box - 2*inset,
+        }, t.Accent)
+    }
+    _, h := t.Font.Measure(c.Text)
+    b.DrawText(t.Font, c.Text, r.X+box+t.Padding, r.Y+(r.H-h)/2, t.Text)
+}
+
+// Slider selects a value between Min and Max by dragging.
+type Slider struct {
+    Box
+    Min, Max float32
+    Value    float32
+    Step     float32             // if positive, the value is rounded to a multiple of Step from Min
+    Width    float32             // the minimum width; defaults to 120
+    OnChange func(value float32) // called when the value is changed by dragging
+}
+
+// NewSlider returns a slider between min and max at value.
+func NewSlider(min, max, value float32) *Slider {
+    return &Slider{Min: min, Max: max, Value: value}
+}
+
+// MinSize returns Width and the height of a line of text with padding.
+func (s *Slider) MinSize(t *Theme) (w, h float32) {
+    w = s.Width
+    if w <= 0 {
+        w = 120
+    }
+    return w, t.Font.Height + 2*t.Padding
+}
+
+// Update moves the value to the cursor while the slider is being dragged.
+func (s *Slider) Update(u *UI) {
+    if !u.Active(s) || s.Max == s.Min {
+        return
+    }
+    r := s.Bounds()
+    x, _ := u.Cursor()
+    f := clamp01((x - r.X) / r.W)
+    v := s.Min + f*(s.Max-s.Min)
+    if s.Step > 0 {
+        v = s.Min + float32(int((v-s.Min)/s.Step+0.5))*s.Step
+    }
+    if v != s.Value {
+        s.Value = v
+        if s.OnChange != nil {
+            s.OnChange(v)
+        }
+    }
+}
+
+// Draw draws the track, filled up to the value, and the knob.
+func (s *Slider) Draw(u *UI, b *gome.SpriteBatch) {
+    t := u.Theme
+    r := s.Bounds()
+    var f float32
+    if s.Max != s.Min {
+        f = clamp01((s.Value - s.Min) / (s.Max - s.Min))
+    }
+    const track, knob = 4, 8
+    y := r.Y + (r.H-track)/2
+    b.DrawRect(gome.Rect{X: r.X, Y: y, W: r.W, H: track}, t.Widget)
+    b.DrawRect(gome.Rect{X: r.X, Y: y, W: r.W * f, H: track}, t.Accent)
+    b.DrawRect(gome.Rect{X: r.X + (r.W-knob)*f, Y: r.Y, W: knob, H: r.H}, t.background(u, s))
+}
+
+// TextField is a single line of editable text. Clicking it gives it the
+// keyboard focus and starts text input (see gome.BeginTextInput); clicking
+// elsewhere or pressing escape ends it. The text scrolls to keep the cursor
+// visible.
+type TextField struct {
+    Box
+    Buffer      gome.TextBuffer   // the text, with the editing state
+    Placeholder string            // shown dimmed while the field is empty and not focused
+    Width       float32           // the minimum width; defaults to 160
+    OnChange    func(text string) // called when the text is edited
+
+    editing bool // text input was begun for the field
+    first   int  // the first rune shown
+}
+
+// NewTextField returns an empty text field showing placeholder.
+func NewTextField(placeholder string) *TextField {
+    return &TextField{Placeholder: placeholder}
+}
+
+// MinSize returns Width and the height of a line of text with padding.
+func (f *TextField) MinSize(t *Theme) (w, h float32) {
+    w = f.Width
+    if w <= 0 {
+        w = 160
+    }
+    return w, t.Font.Height + 2*t.Padding
+}
+
+// Update takes the focus when the field is clicked and applies the text
+// events while it has the focus.
+func (f *TextField) Update(u *UI) {
+    if u.Pressed(f) {
+        u.Focus(f)
+    }
+    if u.Focused(f) && gome.KeyPressed(glfw.KeyEscape) {
+        u.Focus(nil)
+    }
+    focused := u.Focused(f)
+    if focused != f.editing {
+        if focused {
+            gome.BeginTextInput()
+        } else {
+            gome.EndTextInput()
+        }
+        f.editing = focused
+    }
+    if !focused {
+        return
+    }
+    before := f.Buffer.String()
+    f.Buffer.Update()
+    if text := f.Buffer.String(); text != before && f.OnChange != nil {
+        f.OnChange(text)
+    }
+}
+
+// Draw draws the visible part of the text with the selection and, while the
+// field has the focus, the cursor.
+func (f *TextField) Draw(u *UI, b *gome.SpriteBatch) {
+    t := u.Theme
+    r := f.Bounds()
+    focused := u.Focused(f)
+    if focused {
+        b.DrawRect(r, t.Border)
+        b.DrawRect(gome.Rect{X: r.X + 1, Y: r.Y + 1, W: r.W - 2, H: r.H - 2}, t.Widget)
+    } else {
+        b.DrawRect(r, t.background(u, f))
+    }
+    x, y := r.X+t.Padding, r.Y+(r.H-t.Font.Height)/2
+    avail := r.W - 2*t.Padding
+
+    runes := []rune(f.Buffer.String())
+    if len(runes) == 0 && !focused {
+        b.DrawText(t.Font, f.Placeholder, x, y, t.Dim)
+        return
+    }
+    width := func(from, to int) float32 {
+        w, _ := t.Font.Measure(string(runes[from:to]))
+        return w
+    }
+    cursor := f.Buffer.Cursor()
+    if f.first > cursor {
+        f.first = cursor
+    }
+    for f.first < cursor && width(f.first, cursor) > avail {
+        f.first++
+    }
+    last := f.first
+    for last < len(runes) && width(f.first, last+1) <= avail {
+        last++
+    }
+
+    start, end := f.Buffer.Selection()
+    start, end = clampInt(start, f.first, last), clampInt(end, f.first, last)
+    if focused && start < end {
+        x0, x1 := x+width(f.first, start), x+width(f.first, end)
+        b.DrawRect(gome.Rect{X: x0, Y: y, W: x1 - x0, H: t.Font.Height}, t.Accent.WithAlpha(0.5))
+    }
+    b.DrawText(t.Font, string(runes[f.first:last]), x, y, t.Text)
+    if focused {
+        b.DrawRect(gome.Rect{X: x + width(f.first, cursor), Y: y, W: 1, H: t.Font.Height}, t.Accent)
+    }
+}
+
+func clamp01(f float32) float32 {
+    if f < 0 {
+        return 0
+    } else if f > 1 {
+        return 1
+    }
+    return f
+}
+
+func clampInt(i, min, max int) int {
+    if i < min {
+        return min
+    } else if i > max {
+        return max
+    }
+    return i
+}
@@ -0,0 +1,288 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/gl"
+    "image"
+    "math"
+)
+
+// ScaleMode determines how a virtual resolution is scaled to the window.
+type ScaleMode int
+
+const (
+    Fit          ScaleMode = iota // as large as possible keeping the aspect ratio, with letterbox bars
+    Stretch                       // filling the whole window, distorting the aspect ratio
+    IntegerScale                  // the largest whole multiple that fits, with bars, for crisp pixel art
+)
+
+var virtual struct {
+    width, height int
+    mode          ScaleMode
+    target        *RenderTarget
+
+    // draws the target as a quad where it cannot be blitted
+    program *Program
+    vao     *VertexArray
+
+    // where the target is shown, in framebuffer pixels from the bottom left,
+    // and the framebuffer pixels per screen coordinate of the window
+    x, y, w, h int
+    scaleX     float64
+    scaleY     float64
+    fbHeight   int
+}
+
+// SetVirtualResolution makes the application render at a fixed resolution of
+// width by height pixels, independent of the size of the window. Rendering
+// goes to an internal RenderTarget with a depth/stencil buffer, with the
+// samples of Config.Samples and in sRGB if Config.SRGB is set, which Tick
+// scales to the window according to mode, drawing black bars around it
+// where it does not cover the window. Cursor positions and motion reported by
+// CursorPosition, CursorDelta and Events are in virtual pixels, so they can
+// be used with the rendered scene directly; positions on the bars lie outside
+// 0..width and 0..height. A width or height of 0 turns the virtual resolution
+// off again. An error is returned if the target cannot be created.
+//
+// The target is bound with a width by height viewport when it is set and
+// after every Tick; BindScreen binds it again after rendering elsewhere.
+func SetVirtualResolution(width, height int, mode ScaleMode) error {
+    deleteVirtualTarget()
+    defer BindScreen()
+    if width <= 0 || height <= 0 {
+        return nil
+    }
+    target, err := NewRenderTarget(width, height, &RenderTargetOptions{
+        Samples: app.Config.Samples,
+        Depth:   true,
+        SRGB:    app.Config.SRGB,
+        Filter:  presentFilter(mode),
+    })
+    if err != nil {
+        return err
+    }
+    virtual.width, virtual.height, virtual.mode = width, height, mode
+    virtual.target = target
+    updateVirtualRect()
+    return nil
+}
+
+// VirtualResolution returns the virtual resolution and its scale mode, or a
+// size of 0 if there is none.
+func VirtualResolution() (width, height int, mode ScaleMode) {
+    return virtual.width, virtual.height, virtual.mode
+}
+
+// ScreenSize returns the size in pixels of what the application renders to
+// for the screen: the virtual resolution if one is set, or else the size of
+// the framebuffer of the main window.
+func ScreenSize() (width, height int) {
+    if virtual.target != nil {
+        return virtual.width, virtual.height
+    }
+    return app.window.GetFramebufferSize()
+}
+
+// BindScreen binds the framebuffer the application renders to for the
+// screen, the virtual target (see SetVirtualResolution) or the default
+// framebuffer, and sets the viewport to cover it.
+func BindScreen() {
+    if virtual.target != nil {
+        virtual.target.Bind()
+        return
+    }
+    BindFramebuffer(nil)
+    w, h := app.window.GetFramebufferSize()
+    gl.Viewport(0, 0, int32(w), int32(h))
+}
+
+// ReadScreen returns what the application has rendered for the screen so far
+// in this frame: the contents of the virtual target (see
+// SetVirtualResolution), or else of the back buffer of the main window. It
+// has to be called before Tick, which shows the frame. Alpha is set to
+// opaque. It waits for the GPU, so it suits screenshots and tests rather
+// than every frame.
+func ReadScreen() *image.NRGBA {
+    if v := virtual.target; v != nil {
+        v.Resolve()
+        BindFramebuffer(v.readFramebuffer())
+        img := readPixels(v.Width, v.Height)
+        BindScreen()
+        return img
+    }
+    BindFramebuffer(nil)
+    w, h := app.window.GetFramebufferSize()
+    return readPixels(w, h)
+}
+
+// readPixels reads the bottom left w by h pixels of the bound read buffer
+// into an opaque image, top row first.
+func readPixels(w, h int) *image.NRGBA {
+    gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+    img := image.NewNRGBA(image.Rect(0, 0, w, h))
+    gl.ReadPixels(0, 0, int32(w), int32(h), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+    // GL rows go from the bottom up
+    row := make([]byte, img.Stride)
+    for y := 0; y < h/2; y++ {
+        top, bottom := img.Pix[y*img.Stride:(y+1)*img.Stride], img.Pix[(h-1-y)*img.Stride:(h-y)*img.Stride]
+        copy(row, top)
+        copy(top, bottom)
+        copy(bottom, row)
+    }
+    for i := 3; i < len(img.Pix); i += 4 {
+        img.Pix[i] = 255
+    }
+    return img
+}
+
+func deleteVirtualTarget() {
+    if virtual.target != nil {
+        virtual.target.Delete()
+        virtual.target = nil
+    }
+    if virtual.program != nil {
+        virtual.program.Delete()
+        virtual.vao.Delete()
+        virtual.program, virtual.vao = nil, nil
+    }
+    virtual.width, virtual.height = 0, 0
+}
+
+// updateVirtualRect computes where the virtual target is shown in the window.
+func updateVirtualRect() {
+    v := &virtual
+    fbw, fbh := app.window.GetFramebufferSize()
+    ww, wh := app.window.GetSize()
+    v.scaleX, v.scaleY, v.fbHeight = 1, 1, fbh
+    if ww > 0 && wh > 0 {
+        v.scaleX, v.scaleY = float64(fbw)/float64(ww), float64(fbh)/float64(wh)
+    }
+    sx, sy := float64(fbw)/float64(v.width), float64(fbh)/float64(v.height)
+    switch v.mode {
+    case Stretch:
+        v.x, v.y, v.w, v.h = 0, 0, fbw, fbh
+        return
+    case IntegerScale:
+        if s := math.Floor(math.Min(sx, sy)); s >= 1 {
+            sx, sy = s, s
+            break
+        }
+        // smaller than the virtual resolution, so shrink like Fit
+        fallthrough
+    default:
+        s := math.Min(sx, sy)
+        sx, sy = s, s
+    }
+    v.w, v.h = int(float64(v.width)*sx+0.5), int(float64(v.height)*sy+0.5)
+    v.x, v.y = (fbw-v.w)/2, (fbh-v.h)/2
+}
+
+// presentFilter returns the filter the virtual target is scaled with.
+func presentFilter(mode ScaleMode) uint32 {
+    if mode == IntegerScale {
+        return gl.NEAREST
+    }
+    return gl.LINEAR
+}
+
+// The shaders of the quad that presents the virtual target when the window
+// is multisampled. The quad covers the viewport, with its corners made from
+// gl_VertexID, so it needs no vertex buffer.
+const (
+    presentVertexShader = `#version 150
+out vec2 fragTexcoord;
+void main() {
+    vec2 p = vec2(gl_VertexID & 1, gl_VertexID >> 1);
+    fragTexcoord = p;
+    gl_Position = vec4(p * 2.0 - 1.0, 0.0, 1.0);
+}
+`
+    presentFragmentShader = `#version 150
+uniform sampler2D tex;
+in vec2 fragTexcoord;
+out vec4 outColor;
+void main() {
+    outColor = texture(tex, fragTexcoord);
+}
+`
+)
+
+// presentCaps are disabled while the quad is drawn.
+var presentCaps = []uint32{gl.BLEND, gl.CULL_FACE, gl.DEPTH_TEST, gl.SCISSOR_TEST, gl.STENCIL_TEST}
+
+// presentVirtual scales the virtual target to the default framebuffer. It
+// is called by Tick before the overlay is drawn.
+func presentVirtual() error {
+    v := &virtual
+    if v.target == nil {
+        return nil
+    }
+    updateVirtualRect()
+    v.target.Resolve()
+    BindFramebuffer(nil)
+    gl.ClearColor(0, 0, 0, 1)
+    gl.Clear(gl.COLOR_BUFFER_BIT)
+    applyClearColor()
+
+    if obtainedSamples == 0 {
+        blitFramebuffer(v.target.readFramebuffer(), nil, 0, 0, v.width, v.height, v.x, v.y, v.x+v.w, v.y+v.h, presentFilter(v.mode))
+        return nil
+    }
+    // blits into a multisampled framebuffer are invalid, so draw a quad
+    if v.program == nil {
+        program, err := NewProgram(presentVertexShader, presentFragmentShader)
+        if err != nil {
+            return err
+        }
+        v.program, v.vao = program, NewVertexArray()
+    }
+    // the state of the application is left as it was, bypassing the cache
+    // that may not know it
+    enabled := make([]bool, len(presentCaps))
+    for i, c := range presentCaps {
+        if enabled[i] = gl.IsEnabled(c); enabled[i] {
+            gl.Disable(c)
+        }
+    }
+    gl.Viewport(int32(v.x), int32(v.y), int32(v.w), int32(v.h))
+    UseProgram(v.program)
+    BindTexture(0, gl.TEXTURE_2D, v.target.Texture)
+    BindVertexArray(v.vao)
+    gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+    for i, c := range presentCaps {
+        if enabled[i] {
+            gl.Enable(c)
+        }
+    }
+    return nil
+}
+
+// beginVirtualFrame binds the virtual target for the next frame. It is
+// called at the end of Tick.
+func beginVirtualFrame() {
+    if virtual.target != nil {
+        BindScreen()
+    }
+}
+
+// toVirtual converts a position in screen coordinates of the main window to
+// virtual pixels.
+func toVirtual(x, y float64) (float64, float64) {
+    v := &virtual
+    if v.target == nil || v.w == 0 || v.h == 0 {
+        return x, y
+    }
+    top := v.fbHeight - v.y - v.h
+    x = (x*v.scaleX - float64(v.x)) * float64(v.width) / float64(v.w)
+    y = (y*v.scaleY - float64(top)) * float64(v.height) / float64(v.h)
+    return x, y
+}
+
+// toVirtualDelta converts a motion in screen coordinates of the main window
+// to virtual pixels.
+func toVirtualDelta(dx, dy float64) (float64, float64) {
+    v := &virtual
+    if v.target == nil || v.w == 0 || v.h == 0 {
+        return dx, dy
+    }
+    return dx * v.scaleX * float64(v.width) / float64(v.w), dy * v.scaleY * float64(v.height) / float64(v.h)
+}
@@ -0,0 +1,60 @@
+// +build gomemock
+
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/gl"
+    "testing"
+)
+
+func TestMockPresentVirtual(t *testing.T) {
+    tests := []struct {
+        name    string
+        samples int
+        blits   int
+        quads   int
+    }{
+        {"single-sampled", 0, 1, 0},
+        // blitting into a multisampled framebuffer is invalid
+        {"multisampled", 4, 0, 1},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            a := initMock(t, Config{Width: 640, Height: 480, Samples: tt.samples})
+            if err := SetVirtualResolution(320, 200, Fit); err != nil {
+                t.Fatal(err)
+            }
+            Enable(gl.DEPTH_TEST)
+            Enable(gl.BLEND)
+            defer Disable(gl.BLEND)
+            defer Disable(gl.DEPTH_TEST)
+            ResetMockCommands()
+            if !a.Tick() {
+                t.Fatal(a.GetError())
+            }
+
+            var blits int
+            for _, c := range MockCommands("gl.BlitFramebuffer") {
+                // the resolve of a multisampled target is a blit of the same size
+                if c.Args[2] != c.Args[6] {
+                    blits++
+                }
+            }
+            if blits != tt.blits {
+                t.Errorf("%d scaling blits, want %d", blits, tt.blits)
+            }
+            var quads int
+            for _, c := range MockCommands("gl.DrawArrays") {
+                if c.Args[0] == uint32(gl.TRIANGLE_STRIP) {
+                    quads++
+                }
+            }
+            if quads != tt.quads {
+                t.Errorf("%d quads drawn, want %d", quads, tt.quads)
+            }
+            if !gl.IsEnabled(gl.DEPTH_TEST) || !gl.IsEnabled(gl.BLEND) {
+                t.Error("capabilities of the application not restored")
+            }
+        })
+    }
+}
@@ -0,0 +1,168 @@
+package gome
+
+import (
+    "github.com/snorredc/gome/internal/glfw"
+    "image"
+    "math"
+    "runtime"
+    "time"
+)
+
+// IdleBehavior determines what Tick does while the main window is in the
+// background. It is a set of flags.
+type IdleBehavior int
+
+const (
+    // PauseWhenMinimized makes Tick wait while the window is minimized,
+    // so nothing is updated or drawn until it is restored or closed.
+    PauseWhenMinimized IdleBehavior = 1 << iota
+    // WaitEventsWhenUnfocused makes Tick wait up to IdleTimeout for events
+    // while the window does not have the focus, so the frame rate drops
+    // instead of rendering as fast as possible.
+    WaitEventsWhenUnfocused
+)
+
+// IdleTimeout is the longest Tick waits for events when idling (see
+// SetIdleBehavior).
+var IdleTimeout = 100 * time.Millisecond
+
+type windowStatus struct {
+    idle      IdleBehavior
+    focused   bool
+    minimized bool
+    title     string
+    icons     []image.Image
+    opacity   float32
+    onTop     bool
+
+    // the geometry of the window when it was last not maximized, which
+    // SaveWindowState saves for a maximized window
+    normalX, normalY          int
+    normalWidth, normalHeight int
+}
+
+var windowState windowStatus
+
+// SetIdleBehavior sets what Tick does while the main window is in the
+// background; the default, 0, keeps polling for events and running the main
+// loop at full speed. Tool-style applications that only change in response
+// to input can use PauseWhenMinimized|WaitEventsWhenUnfocused to use next to
+// no CPU and GPU time in the background.
+//
+// Functions queued by Do still run while Tick waits. After a pause, FrameTime
+// reports the whole pause, which a FixedTimestep caps at MaxSteps steps;
+// the timers of After and Every leave the pause out.
+func SetIdleBehavior(b IdleBehavior) {
+    windowState.idle = b
+}
+
+// Focused reports whether the main window has the keyboard focus.
+func Focused() bool {
+    return windowState.focused
+}
+
+// Minimized reports whether the main window is minimized (iconified).
+func Minimized() bool {
+    return windowState.minimized
+}
+
+func initWindowState(w *glfw.Window, title string) {
+    windowState.title = title
+    windowState.opacity = 1
+    windowState.focused = w.GetAttrib(glfw.Focused) == glfw.True
+    windowState.minimized = w.GetAttrib(glfw.Iconified) == glfw.True
+    windowState.normalX, windowState.normalY = w.GetPos()
+    windowState.normalWidth, windowState.normalHeight = w.GetSize()
+}
+
+// pollEvents polls for events, or waits for them according to the idle
+// behaviour. It is called by Tick.
+func pollEvents() {
+    switch {
+    case windowState.idle&PauseWhenMinimized != 0 && windowState.minimized:
+        start := time.Now()
+        for windowState.minimized && !app.shouldClose() {
+            waitEvents(IdleTimeout)
+            runQueued()
+        }
+        // the scheduler does not count the pause (see After)
+        scheduler.paused += time.Since(start)
+    case windowState.idle&WaitEventsWhenUnfocused != 0 && !windowState.focused:
+        waitEvents(IdleTimeout)
+    default:
+        glfw.PollEvents()
+    }
+}
+
+// waitEvents waits up to timeout for events and processes them. Do posts an
+// empty event, so queued functions do not wait for the timeout.
+func waitEvents(timeout time.Duration) {
+    glfw.WaitEventsTimeout(timeout.Seconds())
+}
+
+// SetTitle sets the title of the main window. It only calls into GLFW when
+// the title changes, so it is cheap enough to call every frame, e.g. to show
+// the frame rate.
+func SetTitle(title string) {
+    if title == windowState.title {
+        return
+    }
+    windowState.title = title
+    app.window.SetTitle(title)
+}
+
+// Title returns the title of the main window.
+func Title() string {
+    return windowState.title
+}
+
+// SetIcon sets the icon of the main window to the image among imgs whose size
+// is closest to the one the system needs; a few sizes such as 16x16, 32x32
+// and 48x48 give the best results. No images restore the default icon. It
+// reports whether window icons are supported; macOS has none, and on Wayland
+// the icon comes from the desktop file instead.
+func SetIcon(imgs ...image.Image) bool {
+    windowState.icons = imgs
+    if runtime.GOOS == "darwin" {
+        return false
+    }
+    app.window.SetIcon(imgs)
+    return true
+}
+
+// SetOpacity sets the opacity of the whole main window, including its
+// decorations, between 0 (transparent) and 1 (opaque). It reports whether
+// window opacity is supported, which GLFW tells by the opacity reading back
+// unchanged, give or take the rounding of the system.
+func SetOpacity(opacity float32) bool {
+    if opacity < 0 {
+        opacity = 0
+    } else if opacity > 1 {
+        opacity = 1
+    }
+    windowState.opacity = opacity
+    app.window.SetOpacity(opacity)
+    return math.Abs(float64(app.window.GetOpacity()-opacity)) < 0.01
+}
+
+// Opacity returns the opacity of the main window set by SetOpacity.
+func Opacity() float32 {
+    return windowState.opacity
+}
+
+// SetAlwaysOnTop sets whether the main window stays above other windows. It
+// reports whether this is supported.
+func SetAlwaysOnTop(onTop bool) bool {
+    windowState.onTop = onTop
+    value := glfw.False
+    if onTop {
+        value = glfw.True
+    }
+    app.window.SetAttrib(glfw.Floating, value)
+    return app.window.GetAttrib(glfw.Floating) == value
+}
+
+// AlwaysOnTop returns the setting of SetAlwaysOnTop.
+func AlwaysOnTop() bool {
+    return windowState.onTop
+}
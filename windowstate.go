@@ -0,0 +1,161 @@
+package gome
+
+import (
+    "encoding/json"
+    "github.com/snorredc/gome/internal/glfw"
+    "os"
+)
+
+// savedWindow is the window geometry stored by SaveWindowState.
+type savedWindow struct {
+    X         int    `json:"x"`
+    Y         int    `json:"y"`
+    Width     int    `json:"width"`
+    Height    int    `json:"height"`
+    Monitor   string `json:"monitor,omitempty"`
+    Maximized bool   `json:"maximized,omitempty"`
+}
+
+// minVisible is how much of the window, in screen coordinates, has to be on
+// a monitor for a restored position to be used: enough to grab the title bar.
+const minVisible = 64
+
+// SaveWindowState writes the position and size of the main window and the
+// name of the monitor it is on to the file at path as JSON, to be restored
+// by RestoreWindowState at the next start. A maximized window is saved as
+// such, with the geometry it had before it was maximized, so it is maximized
+// again on its monitor and can still be restored to that size.
+func SaveWindowState(path string) error {
+    var s savedWindow
+    s.X, s.Y = app.window.GetPos()
+    s.Width, s.Height = app.window.GetSize()
+    if m := monitorAt(s.X+s.Width/2, s.Y+s.Height/2); m != nil {
+        s.Monitor = m.GetName()
+    }
+    s.Maximized = app.window.GetAttrib(glfw.Maximized) == glfw.True
+    if s.Maximized {
+        s.X, s.Y = windowState.normalX, windowState.normalY
+        s.Width, s.Height = windowState.normalWidth, windowState.normalHeight
+    }
+    data, err := json.MarshalIndent(&s, "", "    ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(path, append(data, '\n'), 0666)
+}
+
+// RestoreWindowState moves and resizes the main window as saved by
+// SaveWindowState in the file at path. A missing file is not an error. The
+// saved geometry is checked against the connected monitors: a window that
+// would be mostly off screen, e.g. because its monitor was disconnected, is
+// moved onto the saved monitor or else the primary one, and shrunk to fit.
+func RestoreWindowState(path string) error {
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    var s savedWindow
+    if err := json.Unmarshal(data, &s); err != nil {
+        return err
+    }
+    if s.Width <= 0 || s.Height <= 0 {
+        return nil
+    }
+    if !onScreen(s.X, s.Y, s.Width, s.Height) {
+        m := monitorNamed(s.Monitor)
+        if m == nil {
+            m = glfw.GetPrimaryMonitor()
+        }
+        if m == nil {
+            return nil
+        }
+        s.X, s.Y, s.Width, s.Height = fitOnMonitor(m, s.Width, s.Height)
+    }
+    app.window.SetSize(s.Width, s.Height)
+    app.window.SetPos(s.X, s.Y)
+    windowState.normalX, windowState.normalY = s.X, s.Y
+    windowState.normalWidth, windowState.normalHeight = s.Width, s.Height
+    if s.Maximized {
+        app.window.Maximize()
+    }
+    return nil
+}
+
+// monitorArea returns the area of m in screen coordinates.
+func monitorArea(m *glfw.Monitor) (x, y, w, h int, ok bool) {
+    mode := m.GetVideoMode()
+    if mode == nil {
+        return 0, 0, 0, 0, false
+    }
+    x, y = m.GetPos()
+    return x, y, mode.Width, mode.Height, true
+}
+
+// monitorAt returns the monitor containing the point (x, y), or nil.
+func monitorAt(x, y int) *glfw.Monitor {
+    for _, m := range glfw.GetMonitors() {
+        mx, my, mw, mh, ok := monitorArea(m)
+        if ok && x >= mx && y >= my && x < mx+mw && y < my+mh {
+            return m
+        }
+    }
+    return nil
+}
+
+func monitorNamed(name string) *glfw.Monitor {
+    if name == "" {
+        return nil
+    }
+    for _, m := range glfw.GetMonitors() {
+        if m.GetName() == name {
+            return m
+        }
+    }
+    return nil
+}
+
+// onScreen reports whether the top of a window at (x, y) of size (w, h) is on
+// a monitor far enough to be grabbed.
+func onScreen(x, y, w, h int) bool {
+    for _, m := range glfw.GetMonitors() {
+        mx, my, mw, mh, ok := monitorArea(m)
+        if !ok {
+            continue
+        }
+        // the overlap of the top strip of the window with the monitor
+        left, right := maxInt(x, mx), minInt(x+w, mx+mw)
+        top, bottom := maxInt(y, my), minInt(y+minVisible, my+mh)
+        if right-left >= minVisible && bottom-top >= minVisible/2 {
+            return true
+        }
+    }
+    return false
+}
+
+// fitOnMonitor returns the geometry of a window of size (w, h) shrunk to fit
+// onto m, centred.
+func fitOnMonitor(m *glfw.Monitor, w, h int) (x, y, width, height int) {
+    mx, my, mw, mh, ok := monitorArea(m)
+    if !ok {
+        return 0, 0, w, h
+    }
+    width, height = minInt(w, mw), minInt(h, mh)
+    return mx + (mw-width)/2, my + (mh-height)/2, width, height
+}
+
+func minInt(a, b int) int {
+    if a < b {
+        return a
+    }
+    return b
+}
+
+func maxInt(a, b int) int {
+    if a > b {
+        return a
+    }
+    return b
+}